@@ -1563,6 +1563,109 @@ func TestSupervisorRemoteConfigApplyStatus(t *testing.T) {
 	}, 15*time.Second, 100*time.Millisecond, "Remote config status was not set to FAILED for bad config")
 }
 
+func TestSupervisorRollsBackCrashingConfig(t *testing.T) {
+	var agentConfig atomic.Value
+	var healthReport atomic.Value
+	var remoteConfigStatus atomic.Value
+	server := newOpAMPServer(
+		t,
+		defaultConnectingHandler,
+		server.ConnectionCallbacksStruct{
+			OnMessageFunc: func(_ context.Context, _ types.Connection, message *protobufs.AgentToServer) *protobufs.ServerToAgent {
+				if message.EffectiveConfig != nil {
+					config := message.EffectiveConfig.ConfigMap.ConfigMap[""]
+					if config != nil {
+						agentConfig.Store(string(config.Body))
+					}
+				}
+				if message.Health != nil {
+					healthReport.Store(message.Health)
+				}
+				if message.RemoteConfigStatus != nil {
+					remoteConfigStatus.Store(message.RemoteConfigStatus)
+				}
+
+				return &protobufs.ServerToAgent{}
+			},
+		})
+
+	s := newSupervisor(t, "config_rollback", map[string]string{
+		"url":                  server.addr,
+		"config_apply_timeout": "3s",
+	})
+	require.Nil(t, s.Start())
+	defer s.Shutdown()
+
+	waitForSupervisorConnection(server.supervisorConnected, true)
+
+	cfg, hash, inputFile, outputFile := createSimplePipelineCollectorConf(t)
+
+	server.sendToSupervisor(&protobufs.ServerToAgent{
+		RemoteConfig: &protobufs.AgentRemoteConfig{
+			Config: &protobufs.AgentConfigMap{
+				ConfigMap: map[string]*protobufs.AgentConfigFile{
+					"": {Body: cfg.Bytes()},
+				},
+			},
+			ConfigHash: hash,
+		},
+	})
+
+	// The good config becomes the last known good config once applied
+	require.Eventually(t, func() bool {
+		status, ok := remoteConfigStatus.Load().(*protobufs.RemoteConfigStatus)
+		return ok && status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED
+	}, 15*time.Second, 100*time.Millisecond, "Remote config status was not set to APPLIED")
+
+	// The collector exits right after starting with the bad config, and is restarted in a loop
+	badCfg, badHash := createBadCollectorConf(t)
+
+	server.sendToSupervisor(&protobufs.ServerToAgent{
+		RemoteConfig: &protobufs.AgentRemoteConfig{
+			Config: &protobufs.AgentConfigMap{
+				ConfigMap: map[string]*protobufs.AgentConfigFile{
+					"": {Body: badCfg.Bytes()},
+				},
+			},
+			ConfigHash: badHash,
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		status, ok := remoteConfigStatus.Load().(*protobufs.RemoteConfigStatus)
+		return ok && status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED &&
+			bytes.Equal(status.LastRemoteConfigHash, badHash)
+	}, 15*time.Second, 100*time.Millisecond, "Remote config status was not set to FAILED for the crashing config")
+
+	// The last known good config is rolled back to, and the collector runs again
+	require.Eventually(t, func() bool {
+		cfg, ok := agentConfig.Load().(string)
+		return ok && strings.Contains(cfg, "filelog") && !strings.Contains(cfg, "doesntexist")
+	}, 15*time.Second, 100*time.Millisecond, "Collector config was not rolled back")
+
+	require.Eventually(t, func() bool {
+		health, ok := healthReport.Load().(*protobufs.ComponentHealth)
+		return ok && health.Healthy
+	}, 30*time.Second, 100*time.Millisecond, "Collector did not become healthy with the rolled back config")
+
+	n, err := inputFile.WriteString("{\"body\":\"hello, world\"}\n")
+	require.NotZero(t, n, "Could not write to input file")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		logRecord := make([]byte, 1024)
+		n, _ := outputFile.Read(logRecord)
+
+		return n != 0
+	}, 10*time.Second, 100*time.Millisecond, "Log never appeared in output")
+
+	// The server keeps the failed status of the config it sent
+	status, ok := remoteConfigStatus.Load().(*protobufs.RemoteConfigStatus)
+	require.True(t, ok)
+	assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, status.Status)
+	assert.Equal(t, badHash, status.LastRemoteConfigHash)
+}
+
 func TestSupervisorOpAmpServerPort(t *testing.T) {
 	var agentConfig atomic.Value
 	server := newOpAMPServer(
@@ -5,6 +5,9 @@ package config
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
@@ -28,6 +31,7 @@ type Supervisor struct {
 	Agent        Agent        `mapstructure:"agent"`
 	Capabilities Capabilities `mapstructure:"capabilities"`
 	Storage      Storage      `mapstructure:"storage"`
+	Packages     Packages     `mapstructure:"packages"`
 	Telemetry    Telemetry    `mapstructure:"telemetry"`
 }
 
@@ -78,6 +82,18 @@ func (s Supervisor) Validate() error {
 		return err
 	}
 
+	if err := s.Capabilities.Validate(); err != nil {
+		return err
+	}
+
+	if s.Capabilities.AcceptsPackages && s.Packages.PublicKeyFile == "" {
+		return errors.New("packages::public_key_file must be set when capabilities::accepts_packages is enabled")
+	}
+
+	if err := s.Packages.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -95,6 +111,16 @@ type Capabilities struct {
 	ReportsOwnMetrics              bool `mapstructure:"reports_own_metrics"`
 	ReportsHealth                  bool `mapstructure:"reports_health"`
 	ReportsRemoteConfig            bool `mapstructure:"reports_remote_config"`
+	AcceptsPackages                bool `mapstructure:"accepts_packages"`
+	ReportsPackageStatuses         bool `mapstructure:"reports_package_statuses"`
+}
+
+func (c Capabilities) Validate() error {
+	if c.AcceptsPackages != c.ReportsPackageStatuses {
+		return errors.New("capabilities::accepts_packages and capabilities::reports_package_statuses must be enabled together")
+	}
+
+	return nil
 }
 
 func (c Capabilities) SupportedCapabilities() protobufs.AgentCapabilities {
@@ -128,6 +154,14 @@ func (c Capabilities) SupportedCapabilities() protobufs.AgentCapabilities {
 		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings
 	}
 
+	if c.AcceptsPackages {
+		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages
+	}
+
+	if c.ReportsPackageStatuses {
+		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses
+	}
+
 	return supportedCapabilities
 }
 
@@ -166,6 +200,7 @@ type Agent struct {
 	OrphanDetectionInterval time.Duration    `mapstructure:"orphan_detection_interval"`
 	Description             AgentDescription `mapstructure:"description"`
 	ConfigApplyTimeout      time.Duration    `mapstructure:"config_apply_timeout"`
+	ConfigRollback          bool             `mapstructure:"config_rollback"`
 	BootstrapTimeout        time.Duration    `mapstructure:"bootstrap_timeout"`
 	HealthCheckPort         int              `mapstructure:"health_check_port"`
 	OpAMPServerPort         int              `mapstructure:"opamp_server_port"`
@@ -205,6 +240,52 @@ func (a Agent) Validate() error {
 	return nil
 }
 
+// Packages is the config of the agent packages offered by the OpAMP server.
+type Packages struct {
+	// PublicKeyFile is the path to a PEM encoded ed25519 public key, required when
+	// capabilities::accepts_packages is enabled. The signature of every package must be
+	// the ed25519 signature of the SHA-256 hash of the package content made with the
+	// matching private key.
+	PublicKeyFile string `mapstructure:"public_key_file"`
+}
+
+func (p Packages) Validate() error {
+	if p.PublicKeyFile == "" {
+		return nil
+	}
+
+	if _, err := p.LoadPublicKey(); err != nil {
+		return fmt.Errorf("invalid packages::public_key_file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadPublicKey loads the ed25519 public key from PublicKeyFile.
+func (p Packages) LoadPublicKey() (ed25519.PublicKey, error) {
+	by, err := os.ReadFile(p.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(by)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, must be ed25519", key)
+	}
+
+	return publicKey, nil
+}
+
 type AgentDescription struct {
 	IdentifyingAttributes    map[string]string `mapstructure:"identifying_attributes"`
 	NonIdentifyingAttributes map[string]string `mapstructure:"non_identifying_attributes"`
@@ -4,6 +4,12 @@
 package config
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
@@ -422,6 +428,65 @@ func TestValidate(t *testing.T) {
 			},
 			expectedError: "agent::config_apply_timeout must be valid duration",
 		},
+		{
+			name: "Accepts packages without reporting package statuses",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+				},
+				Capabilities: Capabilities{
+					AcceptsPackages: true,
+				},
+			},
+			expectedError: "capabilities::accepts_packages and capabilities::reports_package_statuses must be enabled together",
+		},
+		{
+			name: "Accepts packages without public key file",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+				},
+				Capabilities: Capabilities{
+					AcceptsPackages:        true,
+					ReportsPackageStatuses: true,
+				},
+			},
+			expectedError: "packages::public_key_file must be set when capabilities::accepts_packages is enabled",
+		},
+		{
+			name: "Invalid packages public key file",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+				},
+				Capabilities: Capabilities{
+					AcceptsPackages:        true,
+					ReportsPackageStatuses: true,
+				},
+				Packages: Packages{
+					PublicKeyFile: "${file_path}",
+				},
+			},
+			expectedError: "invalid packages::public_key_file: no PEM data found",
+		},
 	}
 
 	// create some fake files for validating agent config
@@ -433,13 +498,14 @@ func TestValidate(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Fill in path to agent executable
-			tc.config.Agent.Executable = os.Expand(tc.config.Agent.Executable,
-				func(s string) string {
-					if s == "file_path" {
-						return filePath
-					}
-					return ""
-				})
+			expand := func(s string) string {
+				if s == "file_path" {
+					return filePath
+				}
+				return ""
+			}
+			tc.config.Agent.Executable = os.Expand(tc.config.Agent.Executable, expand)
+			tc.config.Packages.PublicKeyFile = os.Expand(tc.config.Packages.PublicKeyFile, expand)
 
 			err := tc.config.Validate()
 
@@ -481,6 +547,8 @@ func TestCapabilities_SupportedCapabilities(t *testing.T) {
 				ReportsOwnMetrics:              true,
 				ReportsHealth:                  true,
 				ReportsRemoteConfig:            true,
+				AcceptsPackages:                true,
+				ReportsPackageStatuses:         true,
 			},
 			expectedAgentCapabilities: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
 				protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig |
@@ -489,7 +557,9 @@ func TestCapabilities_SupportedCapabilities(t *testing.T) {
 				protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
 				protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig |
 				protobufs.AgentCapabilities_AgentCapabilities_AcceptsRestartCommand |
-				protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings,
+				protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings |
+				protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+				protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses,
 		},
 	}
 
@@ -500,6 +570,34 @@ func TestCapabilities_SupportedCapabilities(t *testing.T) {
 	}
 }
 
+func TestPackages_LoadPublicKey(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	ed25519KeyFile := filepath.Join(tmpDir, "ed25519.pem")
+	require.NoError(t, os.WriteFile(ed25519KeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+	ecdsaKeyFile := filepath.Join(tmpDir, "ecdsa.pem")
+	require.NoError(t, os.WriteFile(ecdsaKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	loaded, err := Packages{PublicKeyFile: ed25519KeyFile}.LoadPublicKey()
+	require.NoError(t, err)
+	require.Equal(t, publicKey, loaded)
+
+	_, err = Packages{PublicKeyFile: ecdsaKeyFile}.LoadPublicKey()
+	require.ErrorContains(t, err, "unsupported public key type *ecdsa.PublicKey, must be ed25519")
+
+	_, err = Packages{PublicKeyFile: filepath.Join(tmpDir, "missing.pem")}.LoadPublicKey()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
 func TestLoad(t *testing.T) {
 	tmpDir, err := os.MkdirTemp(os.TempDir(), "*")
 	require.NoError(t, err)
@@ -0,0 +1,391 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	packagesStateFile               = "packages_state.yaml"
+	lastReportedPackageStatusesFile = "last_reported_package_statuses.dat"
+)
+
+var _ types.PackagesStateProvider = (*packageManager)(nil)
+
+// packagesState is the local state of the packages synced from the OpAMP server.
+// Hashes are hex encoded.
+type packagesState struct {
+	AllPackagesHash string                  `yaml:"all_packages_hash"`
+	Packages        map[string]packageState `yaml:"packages"`
+}
+
+type packageState struct {
+	Type    protobufs.PackageType `yaml:"type"`
+	Hash    string                `yaml:"hash"`
+	Version string                `yaml:"version"`
+}
+
+// packageManager implements types.PackagesStateProvider for the agent executable, which is
+// the only package the Supervisor accepts: the top-level package of the agent.
+//
+// New executables are staged next to the agent executable, and swapped with it once their
+// hash and signature are verified. The agent is then restarted with the new executable,
+// and the previous executable is restored when the agent is not healthy afterward.
+type packageManager struct {
+	logger          *zap.Logger
+	storageDir      string
+	agentExecutable string
+	// publicKey verifies the package signatures. Packages cannot be installed without it.
+	publicKey ed25519.PublicKey
+	// applyAgentUpdate restarts the agent with the executable installed, and returns an error
+	// when the agent is not healthy with it.
+	applyAgentUpdate func(ctx context.Context) error
+
+	mu    sync.Mutex
+	state packagesState
+}
+
+func newPackageManager(
+	logger *zap.Logger,
+	storageDir string,
+	agentExecutable string,
+	publicKey ed25519.PublicKey,
+	applyAgentUpdate func(ctx context.Context) error,
+) (*packageManager, error) {
+	p := &packageManager{
+		logger:           logger,
+		storageDir:       storageDir,
+		agentExecutable:  agentExecutable,
+		publicKey:        publicKey,
+		applyAgentUpdate: applyAgentUpdate,
+	}
+
+	by, err := os.ReadFile(p.statePath())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err = yaml.Unmarshal(by, &p.state); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal packages state: %w", err)
+		}
+	}
+	if p.state.Packages == nil {
+		p.state.Packages = map[string]packageState{}
+	}
+
+	return p, nil
+}
+
+func (p *packageManager) AllPackagesHash() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return hex.DecodeString(p.state.AllPackagesHash)
+}
+
+func (p *packageManager) SetAllPackagesHash(hash []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.state.AllPackagesHash = hex.EncodeToString(hash)
+	return p.writeState()
+}
+
+func (p *packageManager) Packages() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.state.Packages))
+	for name := range p.state.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (p *packageManager) PackageState(packageName string) (types.PackageState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pkg, ok := p.state.Packages[packageName]
+	if !ok {
+		return types.PackageState{Exists: false}, nil
+	}
+
+	hash, err := hex.DecodeString(pkg.Hash)
+	if err != nil {
+		return types.PackageState{}, fmt.Errorf("invalid hash of package %s: %w", packageName, err)
+	}
+
+	return types.PackageState{
+		Exists:  true,
+		Type:    pkg.Type,
+		Hash:    hash,
+		Version: pkg.Version,
+	}, nil
+}
+
+func (p *packageManager) SetPackageState(packageName string, state types.PackageState) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pkg, ok := p.state.Packages[packageName]
+	if !ok {
+		return fmt.Errorf("package %s does not exist", packageName)
+	}
+	if pkg.Type != state.Type {
+		return fmt.Errorf("package %s is of type %s, not %s", packageName, pkg.Type, state.Type)
+	}
+
+	pkg.Hash = hex.EncodeToString(state.Hash)
+	pkg.Version = state.Version
+	p.state.Packages[packageName] = pkg
+
+	return p.writeState()
+}
+
+func (p *packageManager) CreatePackage(packageName string, typ protobufs.PackageType) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.state.Packages[packageName]; ok {
+		return fmt.Errorf("package %s already exists", packageName)
+	}
+	if typ != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("cannot create package %s: only the top-level package is supported", packageName)
+	}
+	for name, pkg := range p.state.Packages {
+		if pkg.Type == protobufs.PackageType_PackageType_TopLevel {
+			return fmt.Errorf("cannot create package %s: the top-level package is already %s", packageName, name)
+		}
+	}
+
+	p.state.Packages[packageName] = packageState{Type: typ}
+
+	return p.writeState()
+}
+
+// FileContentHash returns the hash of the agent executable, so that the executable is
+// not downloaded when the agent already runs the offered one.
+func (p *packageManager) FileContentHash(packageName string) ([]byte, error) {
+	p.mu.Lock()
+	_, ok := p.state.Packages[packageName]
+	p.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	f, err := os.Open(p.agentExecutable)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+func (p *packageManager) UpdateContent(ctx context.Context, packageName string, data io.Reader, contentHash, signature []byte) error {
+	p.mu.Lock()
+	_, ok := p.state.Packages[packageName]
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("package %s does not exist", packageName)
+	}
+
+	stagedPath := p.agentExecutable + ".staged"
+	if err := p.stageExecutable(ctx, stagedPath, data, contentHash, signature); err != nil {
+		_ = os.Remove(stagedPath)
+		return err
+	}
+
+	// The agent executable is backed up without moving it, then atomically replaced by the
+	// staged one, so that it always exists, even if the Supervisor crashes during the swap.
+	backupPath := p.agentExecutable + ".bak"
+	if err := backUpFile(p.agentExecutable, backupPath); err != nil {
+		_ = os.Remove(stagedPath)
+		return fmt.Errorf("cannot back up the agent executable: %w", err)
+	}
+	if err := os.Rename(stagedPath, p.agentExecutable); err != nil {
+		_ = os.Remove(stagedPath)
+		_ = os.Remove(backupPath)
+		return fmt.Errorf("cannot replace the agent executable: %w", err)
+	}
+
+	p.logger.Info("Agent executable replaced, restarting the agent", zap.String("package", packageName))
+	if err := p.applyAgentUpdate(ctx); err != nil {
+		p.logger.Error("Agent is not healthy with the new executable, rolling back to the previous executable", zap.Error(err))
+		if restoreErr := p.restoreExecutable(backupPath); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		if restartErr := p.applyAgentUpdate(ctx); restartErr != nil {
+			p.logger.Error("Agent is not healthy with the previous executable", zap.Error(restartErr))
+		}
+		return fmt.Errorf("agent is not healthy with the new executable, rolled back to the previous executable: %w", err)
+	}
+
+	if err := os.Remove(backupPath); err != nil {
+		p.logger.Warn("Could not remove the previous agent executable", zap.Error(err))
+	}
+
+	return nil
+}
+
+// stageExecutable writes the new executable to stagedPath, and verifies its hash and signature.
+func (p *packageManager) stageExecutable(ctx context.Context, stagedPath string, data io.Reader, contentHash, signature []byte) error {
+	info, err := os.Stat(p.agentExecutable)
+	if err != nil {
+		return fmt.Errorf("cannot stat the agent executable: %w", err)
+	}
+
+	f, err := os.OpenFile(stagedPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("cannot create the staged agent executable: %w", err)
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), data)
+	if err == nil {
+		// The staged executable must be on disk before it replaces the current one.
+		err = f.Sync()
+	}
+	if err = errors.Join(err, f.Close()); err != nil {
+		return fmt.Errorf("cannot write the staged agent executable: %w", err)
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	hash := h.Sum(nil)
+	if !bytes.Equal(hash, contentHash) {
+		return fmt.Errorf("content hash mismatch: expected %x, got %x", contentHash, hash)
+	}
+	if len(p.publicKey) != ed25519.PublicKeySize {
+		return errors.New("no public key to verify the package signature")
+	}
+	if !ed25519.Verify(p.publicKey, hash, signature) {
+		return errors.New("invalid package signature")
+	}
+
+	return nil
+}
+
+// backUpFile makes backupPath a hard link to path, or a copy of it when links are not supported.
+func backUpFile(path, backupPath string) error {
+	if err := os.Remove(backupPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Link(path, backupPath); err == nil {
+		return nil
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(backupPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if err == nil {
+		err = dst.Sync()
+	}
+	if err = errors.Join(err, dst.Close()); err != nil {
+		_ = os.Remove(backupPath)
+		return err
+	}
+	return nil
+}
+
+func (p *packageManager) restoreExecutable(backupPath string) error {
+	if err := os.Rename(backupPath, p.agentExecutable); err != nil {
+		return fmt.Errorf("cannot restore the previous agent executable: %w", err)
+	}
+	return nil
+}
+
+// DeletePackage forgets the package. The agent executable is kept, since the agent
+// cannot run without it.
+func (p *packageManager) DeletePackage(packageName string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.state.Packages, packageName)
+
+	return p.writeState()
+}
+
+func (p *packageManager) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	by, err := os.ReadFile(filepath.Join(p.storageDir, lastReportedPackageStatusesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := &protobufs.PackageStatuses{}
+	if err = proto.Unmarshal(by, statuses); err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (p *packageManager) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	by, err := proto.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(p.storageDir, lastReportedPackageStatusesFile), by, 0o600)
+}
+
+func (p *packageManager) statePath() string {
+	return filepath.Join(p.storageDir, packagesStateFile)
+}
+
+// writeState persists the state. The caller must hold p.mu.
+func (p *packageManager) writeState() error {
+	by, err := yaml.Marshal(p.state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p.statePath(), by, 0o600)
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestPackageManager(t *testing.T, publicKey ed25519.PublicKey, applyAgentUpdate func(ctx context.Context) error) (*packageManager, string) {
+	storageDir := t.TempDir()
+	executable := filepath.Join(t.TempDir(), "otelcol")
+	require.NoError(t, os.WriteFile(executable, []byte("current executable"), 0o700))
+
+	p, err := newPackageManager(zap.NewNop(), storageDir, executable, publicKey, applyAgentUpdate)
+	require.NoError(t, err)
+	return p, storageDir
+}
+
+func TestPackageManager_state(t *testing.T) {
+	p, storageDir := newTestPackageManager(t, nil, nil)
+
+	hash, err := p.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+
+	require.NoError(t, p.CreatePackage("otelcol", protobufs.PackageType_PackageType_TopLevel))
+	require.EqualError(t, p.CreatePackage("otelcol", protobufs.PackageType_PackageType_TopLevel), "package otelcol already exists")
+	require.EqualError(t, p.CreatePackage("otelcol-contrib", protobufs.PackageType_PackageType_TopLevel), "cannot create package otelcol-contrib: the top-level package is already otelcol")
+	require.EqualError(t, p.CreatePackage("plugin", protobufs.PackageType_PackageType_Addon), "cannot create package plugin: only the top-level package is supported")
+
+	require.NoError(t, p.SetPackageState("otelcol", types.PackageState{
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    []byte{0x01, 0x02},
+		Version: "0.115.0",
+	}))
+	require.EqualError(t, p.SetPackageState("otelcol", types.PackageState{Type: protobufs.PackageType_PackageType_Addon}), "package otelcol is of type PackageType_TopLevel, not PackageType_Addon")
+	require.EqualError(t, p.SetPackageState("plugin", types.PackageState{}), "package plugin does not exist")
+	require.NoError(t, p.SetAllPackagesHash([]byte{0x03}))
+
+	statuses := &protobufs.PackageStatuses{
+		Packages: map[string]*protobufs.PackageStatus{
+			"otelcol": {Name: "otelcol", Status: protobufs.PackageStatusEnum_PackageStatusEnum_Installed},
+		},
+		ServerProvidedAllPackagesHash: []byte{0x03},
+	}
+	require.NoError(t, p.SetLastReportedStatuses(statuses))
+
+	// The state is restored when the Supervisor restarts.
+	p, err = newPackageManager(zap.NewNop(), storageDir, p.agentExecutable, nil, nil)
+	require.NoError(t, err)
+
+	hash, err = p.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x03}, hash)
+
+	names, err := p.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"otelcol"}, names)
+
+	state, err := p.PackageState("otelcol")
+	require.NoError(t, err)
+	assert.Equal(t, types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    []byte{0x01, 0x02},
+		Version: "0.115.0",
+	}, state)
+
+	lastStatuses, err := p.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(statuses, lastStatuses))
+
+	require.NoError(t, p.DeletePackage("otelcol"))
+	state, err = p.PackageState("otelcol")
+	require.NoError(t, err)
+	assert.False(t, state.Exists)
+	// The agent executable is kept.
+	assert.FileExists(t, p.agentExecutable)
+}
+
+func TestPackageManager_FileContentHash(t *testing.T) {
+	p, _ := newTestPackageManager(t, nil, nil)
+
+	hash, err := p.FileContentHash("otelcol")
+	require.NoError(t, err)
+	assert.Nil(t, hash)
+
+	require.NoError(t, p.CreatePackage("otelcol", protobufs.PackageType_PackageType_TopLevel))
+	hash, err = p.FileContentHash("otelcol")
+	require.NoError(t, err)
+	expected := sha256.Sum256([]byte("current executable"))
+	assert.Equal(t, expected[:], hash)
+}
+
+func TestPackageManager_UpdateContent(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	newExecutable := []byte("new executable")
+	newHash := sha256.Sum256(newExecutable)
+	newSignature := ed25519.Sign(privateKey, newHash[:])
+
+	testCases := []struct {
+		name               string
+		publicKey          ed25519.PublicKey
+		contentHash        []byte
+		signature          []byte
+		agentUpdateErrs    []error
+		expectedErr        string
+		expectedExecutable string
+		expectedUpdates    int
+	}{
+		{
+			name:               "unsigned",
+			publicKey:          publicKey,
+			contentHash:        newHash[:],
+			expectedErr:        "invalid package signature",
+			expectedExecutable: "current executable",
+		},
+		{
+			name:               "no public key",
+			contentHash:        newHash[:],
+			signature:          newSignature,
+			expectedErr:        "no public key to verify the package signature",
+			expectedExecutable: "current executable",
+		},
+		{
+			name:               "signed",
+			publicKey:          publicKey,
+			contentHash:        newHash[:],
+			signature:          newSignature,
+			expectedExecutable: "new executable",
+			expectedUpdates:    1,
+		},
+		{
+			name:               "hash mismatch",
+			publicKey:          publicKey,
+			contentHash:        []byte{0x01},
+			expectedErr:        "content hash mismatch: expected 01, got ",
+			expectedExecutable: "current executable",
+		},
+		{
+			name:               "invalid signature",
+			publicKey:          publicKey,
+			contentHash:        newHash[:],
+			signature:          []byte("forged"),
+			expectedErr:        "invalid package signature",
+			expectedExecutable: "current executable",
+		},
+		{
+			name:               "unhealthy agent",
+			publicKey:          publicKey,
+			contentHash:        newHash[:],
+			signature:          newSignature,
+			agentUpdateErrs:    []error{errors.New("agent is not healthy: exited")},
+			expectedErr:        "agent is not healthy with the new executable, rolled back to the previous executable: agent is not healthy: exited",
+			expectedExecutable: "current executable",
+			expectedUpdates:    2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var executables []string
+			var p *packageManager
+			p, _ = newTestPackageManager(t, tc.publicKey, func(context.Context) error {
+				by, err := os.ReadFile(p.agentExecutable)
+				require.NoError(t, err)
+				executables = append(executables, string(by))
+				if len(executables) == 1 {
+					// The previous executable is kept while the agent runs the new one.
+					backup, err := os.ReadFile(p.agentExecutable + ".bak")
+					require.NoError(t, err)
+					assert.Equal(t, "current executable", string(backup))
+				}
+
+				if len(tc.agentUpdateErrs) >= len(executables) {
+					return tc.agentUpdateErrs[len(executables)-1]
+				}
+				return nil
+			})
+			require.NoError(t, p.CreatePackage("otelcol", protobufs.PackageType_PackageType_TopLevel))
+
+			err := p.UpdateContent(context.Background(), "otelcol", bytes.NewReader(newExecutable), tc.contentHash, tc.signature)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+
+			by, err := os.ReadFile(p.agentExecutable)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedExecutable, string(by))
+			assert.Len(t, executables, tc.expectedUpdates)
+			if tc.expectedUpdates == 2 {
+				// The agent is restarted with the new executable, then with the previous one.
+				assert.Equal(t, []string{"new executable", "current executable"}, executables)
+			}
+			assert.NoFileExists(t, p.agentExecutable+".staged")
+			assert.NoFileExists(t, p.agentExecutable+".bak")
+		})
+	}
+
+	t.Run("package does not exist", func(t *testing.T) {
+		p, _ := newTestPackageManager(t, nil, nil)
+		err := p.UpdateContent(context.Background(), "otelcol", bytes.NewReader(newExecutable), newHash[:], nil)
+		require.EqualError(t, err, "package otelcol does not exist")
+	})
+}
@@ -57,6 +57,7 @@ var (
 	ownTelemetryTpl string
 
 	lastRecvRemoteConfigFile     = "last_recv_remote_config.dat"
+	lastGoodRemoteConfigFile     = "last_good_remote_config.dat"
 	lastRecvOwnMetricsConfigFile = "last_recv_own_metrics_config.dat"
 )
 
@@ -126,6 +127,10 @@ type Supervisor struct {
 
 	// Last received remote config.
 	remoteConfig *protobufs.AgentRemoteConfig
+	// remoteConfigMutex guards remoteConfig and the composition of the merged config from it,
+	// since remote configs are received from the OpAMP client while rollbacks happen in the
+	// agent process loop.
+	remoteConfigMutex sync.Mutex
 
 	// A channel to indicate there is a new config to apply.
 	hasNewConfig chan struct{}
@@ -136,6 +141,18 @@ type Supervisor struct {
 	lastHealthFromClient *protobufs.ComponentHealth
 	// lastHealth is the last health status of the agent.
 	lastHealth *protobufs.ComponentHealth
+	// lastAgentErr is the last error of the agent since the current config was applied.
+	lastAgentErr string
+	// configRollingBack is true while the last known good remote config is applied after
+	// a remote config failed.
+	configRollingBack atomic.Bool
+
+	// packageManager stores the packages offered by the OpAMP server. It is nil unless
+	// the AcceptsPackages capability is enabled.
+	packageManager *packageManager
+	// A channel to restart the agent with a new executable. Whether the agent is healthy
+	// ConfigApplyTimeout after the restart is sent to the received channel.
+	agentUpdates chan chan error
 
 	// The OpAMP client to connect to the OpAMP Server.
 	opampClient client.OpAMPClient
@@ -163,6 +180,7 @@ func NewSupervisor(logger *zap.Logger, cfg config.Supervisor) (*Supervisor, erro
 		logger:                       logger,
 		pidProvider:                  defaultPIDProvider{},
 		hasNewConfig:                 make(chan struct{}, 1),
+		agentUpdates:                 make(chan chan error),
 		agentConfigOwnMetricsSection: &atomic.Value{},
 		cfgState:                     &atomic.Value{},
 		effectiveConfig:              &atomic.Value{},
@@ -218,6 +236,12 @@ func (s *Supervisor) Start() error {
 		return fmt.Errorf("failed loading initial config: %w", err)
 	}
 
+	if s.config.Capabilities.AcceptsPackages {
+		if err = s.createPackageManager(); err != nil {
+			return fmt.Errorf("cannot create package manager: %w", err)
+		}
+	}
+
 	if err = s.startOpAMP(); err != nil {
 		return fmt.Errorf("cannot start OpAMP client: %w", err)
 	}
@@ -249,6 +273,22 @@ func (s *Supervisor) Start() error {
 	return nil
 }
 
+func (s *Supervisor) createPackageManager() error {
+	publicKey, err := s.config.Packages.LoadPublicKey()
+	if err != nil {
+		return fmt.Errorf("cannot load packages public key: %w", err)
+	}
+
+	s.packageManager, err = newPackageManager(
+		s.logger,
+		s.config.Storage.Directory,
+		s.config.Agent.Executable,
+		publicKey,
+		s.applyAgentUpdate,
+	)
+	return err
+}
+
 func (s *Supervisor) createTemplates() error {
 	var err error
 
@@ -440,6 +480,9 @@ func (s *Supervisor) startOpAMPClient() error {
 		},
 		Capabilities: s.config.Capabilities.SupportedCapabilities(),
 	}
+	if s.packageManager != nil {
+		settings.PackagesStateProvider = s.packageManager
+	}
 	ad := s.agentDescription.Load().(*protobufs.AgentDescription)
 	if err = s.opampClient.SetAgentDescription(ad); err != nil {
 		return err
@@ -816,7 +859,7 @@ func (s *Supervisor) loadAndWriteInitialMergedConfig() error {
 		s.logger.Debug("Own metrics is not supported, will not attempt to load config from file")
 	}
 
-	_, err = s.composeMergedConfig(s.remoteConfig)
+	_, err = s.composeMergedRemoteConfig()
 	if err != nil {
 		return fmt.Errorf("could not compose initial merged config: %w", err)
 	}
@@ -883,7 +926,7 @@ func (s *Supervisor) setupOwnMetrics(_ context.Context, settings *protobufs.Tele
 	s.agentConfigOwnMetricsSection.Store(cfg.String())
 
 	// Need to recalculate the Agent config so that the metric config is included in it.
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	configChanged, err := s.composeMergedRemoteConfig()
 	if err != nil {
 		s.logger.Error("Error composing merged config for own metrics. Ignoring agent self metrics config", zap.Error(err))
 		return
@@ -892,6 +935,13 @@ func (s *Supervisor) setupOwnMetrics(_ context.Context, settings *protobufs.Tele
 	return configChanged
 }
 
+// composeMergedRemoteConfig composes the merged config with the last received remote config.
+func (s *Supervisor) composeMergedRemoteConfig() (configChanged bool, err error) {
+	s.remoteConfigMutex.Lock()
+	defer s.remoteConfigMutex.Unlock()
+	return s.composeMergedConfig(s.remoteConfig)
+}
+
 // composeMergedConfig composes the merged config from multiple sources:
 // 1) the remote config from OpAMP Server
 // 2) the own metrics config section
@@ -1063,6 +1113,7 @@ func (s *Supervisor) healthCheck() {
 			}
 		} else {
 			health.LastError = err.Error()
+			s.lastAgentErr = health.LastError
 			s.logger.Error("Agent is not healthy", zap.Error(err))
 		}
 	} else {
@@ -1099,10 +1150,15 @@ func (s *Supervisor) runAgentProcess() {
 	configApplyTimeoutTimer := time.NewTimer(0)
 	configApplyTimeoutTimer.Stop()
 
+	agentUpdateTimeoutTimer := time.NewTimer(0)
+	agentUpdateTimeoutTimer.Stop()
+	var agentUpdateResult chan error
+
 	for {
 		select {
 		case <-s.hasNewConfig:
 			s.lastHealthFromClient = nil
+			s.lastAgentErr = ""
 			if !configApplyTimeoutTimer.Stop() {
 				select {
 				case <-configApplyTimeoutTimer.C: // Try to drain the channel
@@ -1116,6 +1172,26 @@ func (s *Supervisor) runAgentProcess() {
 			s.stopAgentApplyConfig()
 			s.startAgent()
 
+		case result := <-s.agentUpdates:
+			if s.cfgState.Load().(*configState).configMapIsEmpty {
+				// There is no agent to check the executable with, it is used when the agent starts.
+				result <- nil
+				continue
+			}
+			s.lastHealthFromClient = nil
+			s.lastAgentErr = ""
+			agentUpdateResult = result
+			agentUpdateTimeoutTimer.Reset(s.config.Agent.ConfigApplyTimeout)
+
+			s.logger.Debug("Restarting agent due to new executable")
+			restartTimer.Stop()
+			s.stopAgentApplyConfig()
+			s.startAgent()
+
+		case <-agentUpdateTimeoutTimer.C:
+			agentUpdateResult <- s.checkAgentUpdate()
+			agentUpdateResult = nil
+
 		case <-s.commander.Exited():
 			// the agent process exit is expected for restart command and will not attempt to restart
 			if s.agentRestarting.Load() {
@@ -1127,6 +1203,7 @@ func (s *Supervisor) runAgentProcess() {
 				"Agent process PID=%d exited unexpectedly, exit code=%d. Will restart in a bit...",
 				s.commander.Pid(), s.commander.ExitCode(),
 			)
+			s.lastAgentErr = errMsg
 			err := s.opampClient.SetHealth(&protobufs.ComponentHealth{Healthy: false, LastError: errMsg})
 			if err != nil {
 				s.logger.Error("Could not report health to OpAMP server", zap.Error(err))
@@ -1149,11 +1226,7 @@ func (s *Supervisor) runAgentProcess() {
 			s.startAgent()
 
 		case <-configApplyTimeoutTimer.C:
-			if s.lastHealthFromClient == nil || !s.lastHealthFromClient.Healthy {
-				s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, "Config apply timeout exceeded")
-			} else {
-				s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
-			}
+			s.checkAppliedConfig()
 
 		case <-s.healthCheckTicker.C:
 			s.healthCheck()
@@ -1168,6 +1241,140 @@ func (s *Supervisor) runAgentProcess() {
 	}
 }
 
+// checkAppliedConfig reports whether the agent is healthy with the config applied
+// ConfigApplyTimeout ago, and rolls the config back when it is not and rollback is enabled.
+func (s *Supervisor) checkAppliedConfig() {
+	healthy := s.lastHealthFromClient != nil && s.lastHealthFromClient.Healthy
+	s.remoteConfigMutex.Lock()
+	remoteConfig := s.remoteConfig
+	s.remoteConfigMutex.Unlock()
+
+	if s.configRollingBack.Swap(false) {
+		// The server is not told about the rolled back config, so it keeps
+		// the failed status of the config it sent.
+		if healthy {
+			s.logger.Info("Agent is healthy with the last known good config")
+		} else {
+			s.logger.Error("Agent is not healthy with the last known good config", zap.String("error", s.agentHealthError()))
+		}
+		return
+	}
+
+	if !healthy {
+		errMsg := "Config apply timeout exceeded"
+		if healthErr := s.agentHealthError(); healthErr != "" {
+			errMsg += ": " + healthErr
+		}
+		s.reportConfigStatus(remoteConfig, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, errMsg)
+		if s.config.Agent.ConfigRollback {
+			s.rollbackConfig(remoteConfig)
+		}
+		return
+	}
+
+	s.reportConfigStatus(remoteConfig, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+	if s.config.Agent.ConfigRollback && remoteConfig != nil {
+		if err := s.saveRemoteConfig(remoteConfig, lastGoodRemoteConfigFile); err != nil {
+			s.logger.Error("Could not save last known good remote config", zap.Error(err))
+		}
+	}
+}
+
+// applyAgentUpdate restarts the agent with a new executable, and returns an error when the
+// agent is not healthy with it ConfigApplyTimeout after the restart.
+func (s *Supervisor) applyAgentUpdate(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case s.agentUpdates <- result:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.doneChan:
+		return errors.New("supervisor is shutting down")
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.doneChan:
+		return errors.New("supervisor is shutting down")
+	}
+}
+
+// checkAgentUpdate returns an error when the agent is not healthy with the executable
+// installed ConfigApplyTimeout ago.
+func (s *Supervisor) checkAgentUpdate() error {
+	if s.lastHealthFromClient != nil && s.lastHealthFromClient.Healthy {
+		return nil
+	}
+
+	errMsg := "agent is not healthy"
+	if healthErr := s.agentHealthError(); healthErr != "" {
+		errMsg += ": " + healthErr
+	}
+	return errors.New(errMsg)
+}
+
+// agentHealthError returns the last error of the agent since the current config was applied.
+func (s *Supervisor) agentHealthError() string {
+	if s.lastHealthFromClient != nil && s.lastHealthFromClient.LastError != "" {
+		return s.lastHealthFromClient.LastError
+	}
+	return s.lastAgentErr
+}
+
+// rollbackConfig restores the last remote config the agent was healthy with in place of
+// the failed one, and signals the agent to restart with it.
+func (s *Supervisor) rollbackConfig(failedConfig *protobufs.AgentRemoteConfig) {
+	lastGoodConfig, err := s.loadRemoteConfig(lastGoodRemoteConfigFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// No remote config was healthy yet, the agent is rolled back to the local config only.
+		lastGoodConfig = nil
+	case err != nil:
+		s.logger.Error("Cannot load last known good remote config", zap.Error(err))
+		return
+	case bytes.Equal(lastGoodConfig.GetConfigHash(), failedConfig.GetConfigHash()):
+		// The last known good config is the one which failed, there is nothing better to roll back to.
+		return
+	}
+
+	s.remoteConfigMutex.Lock()
+	if s.remoteConfig != failedConfig {
+		// A new remote config was received since the failed one was checked, it is applied instead.
+		s.remoteConfigMutex.Unlock()
+		return
+	}
+
+	s.logger.Warn("Rolling back to the last known good remote config",
+		zap.String("failed_hash", fmt.Sprintf("%x", failedConfig.GetConfigHash())),
+		zap.String("hash", fmt.Sprintf("%x", lastGoodConfig.GetConfigHash())))
+
+	// The restored config is used when the Supervisor restarts, until the server sends a new config.
+	if err = s.restoreLastReceivedConfig(lastGoodConfig); err != nil {
+		s.logger.Error("Could not save last received remote config", zap.Error(err))
+	}
+	s.remoteConfig = lastGoodConfig
+	_, err = s.composeMergedConfig(lastGoodConfig)
+	if err == nil {
+		s.configRollingBack.Store(true)
+	}
+	s.remoteConfigMutex.Unlock()
+	if err != nil {
+		s.logger.Error("Error composing merged config with the last known good remote config", zap.Error(err))
+		return
+	}
+
+	if err = s.opampClient.UpdateEffectiveConfig(context.Background()); err != nil {
+		s.logger.Error("The OpAMP client failed to update the effective config", zap.Error(err))
+	}
+	select {
+	case s.hasNewConfig <- struct{}{}:
+	default:
+	}
+}
+
 func (s *Supervisor) stopAgentApplyConfig() {
 	s.logger.Debug("Stopping the agent to apply new config")
 	cfgState := s.cfgState.Load().(*configState)
@@ -1224,12 +1431,42 @@ func (s *Supervisor) Shutdown() {
 }
 
 func (s *Supervisor) saveLastReceivedConfig(config *protobufs.AgentRemoteConfig) error {
+	return s.saveRemoteConfig(config, lastRecvRemoteConfigFile)
+}
+
+// restoreLastReceivedConfig saves the config rolled back to as the last received one,
+// a nil config removes the last received config so that only the local config is used.
+func (s *Supervisor) restoreLastReceivedConfig(config *protobufs.AgentRemoteConfig) error {
+	if config != nil {
+		return s.saveLastReceivedConfig(config)
+	}
+	err := os.Remove(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *Supervisor) saveRemoteConfig(config *protobufs.AgentRemoteConfig, fileName string) error {
 	cfg, err := proto.Marshal(config)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile), cfg, 0o600)
+	return os.WriteFile(filepath.Join(s.config.Storage.Directory, fileName), cfg, 0o600)
+}
+
+func (s *Supervisor) loadRemoteConfig(fileName string) (*protobufs.AgentRemoteConfig, error) {
+	cfg, err := os.ReadFile(filepath.Join(s.config.Storage.Directory, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &protobufs.AgentRemoteConfig{}
+	if err = proto.Unmarshal(cfg, config); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
 
 func (s *Supervisor) saveLastReceivedOwnTelemetrySettings(set *protobufs.TelemetryConnectionSettings, filePath string) error {
@@ -1241,9 +1478,9 @@ func (s *Supervisor) saveLastReceivedOwnTelemetrySettings(set *protobufs.Telemet
 	return os.WriteFile(filepath.Join(s.config.Storage.Directory, filePath), cfg, 0o600)
 }
 
-func (s *Supervisor) reportConfigStatus(status protobufs.RemoteConfigStatuses, errorMessage string) {
+func (s *Supervisor) reportConfigStatus(remoteConfig *protobufs.AgentRemoteConfig, status protobufs.RemoteConfigStatuses, errorMessage string) {
 	err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
-		LastRemoteConfigHash: s.remoteConfig.ConfigHash,
+		LastRemoteConfigHash: remoteConfig.GetConfigHash(),
 		Status:               status,
 		ErrorMessage:         errorMessage,
 	})
@@ -1267,6 +1504,13 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		configChanged = s.processOwnMetricsConnSettingsMessage(ctx, msg.OwnMetricsConnSettings) || configChanged
 	}
 
+	if msg.PackageSyncer != nil {
+		// Sync downloads and installs the packages in the background.
+		if err := msg.PackageSyncer.Sync(ctx); err != nil {
+			s.logger.Error("Cannot sync packages", zap.Error(err))
+		}
+	}
+
 	// Update the agent config if any messages have touched the config
 	if configChanged {
 		err := s.opampClient.UpdateEffectiveConfig(ctx)
@@ -1312,20 +1556,22 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 
 // processRemoteConfigMessage processes an AgentRemoteConfig message, returning true if the agent config has changed.
 func (s *Supervisor) processRemoteConfigMessage(msg *protobufs.AgentRemoteConfig) bool {
+	s.remoteConfigMutex.Lock()
 	if err := s.saveLastReceivedConfig(msg); err != nil {
 		s.logger.Error("Could not save last received remote config", zap.Error(err))
 	}
 
 	s.remoteConfig = msg
-	s.logger.Debug("Received remote config from server", zap.String("hash", fmt.Sprintf("%x", s.remoteConfig.ConfigHash)))
+	s.configRollingBack.Store(false)
+	s.logger.Debug("Received remote config from server", zap.String("hash", fmt.Sprintf("%x", msg.ConfigHash)))
 
-	var err error
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	configChanged, err := s.composeMergedConfig(msg)
+	s.remoteConfigMutex.Unlock()
 	if err != nil {
 		s.logger.Error("Error composing merged config. Reporting failed remote config status.", zap.Error(err))
-		s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, err.Error())
+		s.reportConfigStatus(msg, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, err.Error())
 	} else {
-		s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, "")
+		s.reportConfigStatus(msg, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, "")
 	}
 
 	return configChanged
@@ -1362,7 +1608,7 @@ func (s *Supervisor) processAgentIdentificationMessage(msg *protobufs.AgentIdent
 	}
 
 	// Need to recalculate the Agent config so that the new agent identification is included in it.
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	configChanged, err := s.composeMergedRemoteConfig()
 	if err != nil {
 		s.logger.Error("Error composing merged config with new instance ID", zap.Error(err))
 		return false
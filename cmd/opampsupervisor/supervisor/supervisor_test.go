@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -1326,6 +1327,244 @@ service:
 	})
 }
 
+func TestSupervisor_checkAppliedConfig(t *testing.T) {
+	goodConfig := &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte("receivers:\n  debug/good:\n")},
+			},
+		},
+		ConfigHash: []byte("good"),
+	}
+	badConfig := &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte("receivers:\n  debug/bad:\n")},
+			},
+		},
+		ConfigHash: []byte("bad"),
+	}
+
+	newSupervisor := func(t *testing.T, rollback bool, statuses *[]*protobufs.RemoteConfigStatus) *Supervisor {
+		agentDesc := &atomic.Value{}
+		agentDesc.Store(&protobufs.AgentDescription{})
+		s := &Supervisor{
+			logger: zap.NewNop(),
+			config: config.Supervisor{
+				Agent:   config.Agent{ConfigRollback: rollback},
+				Storage: config.Storage{Directory: t.TempDir()},
+			},
+			hasNewConfig:                 make(chan struct{}, 1),
+			agentDescription:             agentDesc,
+			agentConfigOwnMetricsSection: &atomic.Value{},
+			cfgState:                     &atomic.Value{},
+			persistentState: &persistentState{
+				InstanceID: uuid.MustParse("018fee23-4a51-7303-a441-73faed7d9deb"),
+			},
+			pidProvider: staticPIDProvider(1234),
+			opampClient: &mockOpAMPClient{
+				setRemoteConfigStatusFunc: func(rcs *protobufs.RemoteConfigStatus) error {
+					*statuses = append(*statuses, rcs)
+					return nil
+				},
+				updateEffectiveConfigFunc: func(_ context.Context) error {
+					return nil
+				},
+			},
+		}
+		require.NoError(t, s.createTemplates())
+		return s
+	}
+
+	t.Run("healthy config is saved as last known good", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		s.remoteConfig = goodConfig
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: true}
+
+		s.checkAppliedConfig()
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, statuses[0].Status)
+		assert.Equal(t, []byte("good"), statuses[0].LastRemoteConfigHash)
+		lastGoodConfig, err := s.loadRemoteConfig(lastGoodRemoteConfigFile)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(goodConfig, lastGoodConfig))
+	})
+
+	t.Run("unhealthy config is rolled back to last known good", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		require.NoError(t, s.saveRemoteConfig(goodConfig, lastGoodRemoteConfigFile))
+		s.remoteConfig = badConfig
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: false, LastError: "receiver debug/bad failed"}
+
+		s.checkAppliedConfig()
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Equal(t, []byte("bad"), statuses[0].LastRemoteConfigHash)
+		assert.Equal(t, "Config apply timeout exceeded: receiver debug/bad failed", statuses[0].ErrorMessage)
+
+		assert.True(t, proto.Equal(goodConfig, s.remoteConfig))
+		assert.Contains(t, s.cfgState.Load().(*configState).mergedConfig, "debug/good")
+		lastRecvConfig, err := s.loadRemoteConfig(lastRecvRemoteConfigFile)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(goodConfig, lastRecvConfig))
+		require.Len(t, s.hasNewConfig, 1)
+
+		// The server keeps the failed status while the last known good config is applied.
+		<-s.hasNewConfig
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: true}
+		s.checkAppliedConfig()
+		assert.Len(t, statuses, 1)
+		assert.False(t, s.configRollingBack.Load())
+	})
+
+	t.Run("crashing agent error is reported", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		s.remoteConfig = badConfig
+		s.lastAgentErr = "Agent process PID=42 exited unexpectedly, exit code=1. Will restart in a bit..."
+
+		s.checkAppliedConfig()
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Equal(t, "Config apply timeout exceeded: Agent process PID=42 exited unexpectedly, exit code=1. Will restart in a bit...", statuses[0].ErrorMessage)
+		// There is no last known good config, the agent is rolled back to the local config.
+		assert.Nil(t, s.remoteConfig)
+		require.Len(t, s.hasNewConfig, 1)
+	})
+
+	t.Run("first remote config is rolled back to the local config", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		require.NoError(t, s.saveLastReceivedConfig(badConfig))
+		s.remoteConfig = badConfig
+		_, err := s.composeMergedConfig(badConfig)
+		require.NoError(t, err)
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: false, LastError: "receiver debug/bad failed"}
+
+		s.checkAppliedConfig()
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Nil(t, s.remoteConfig)
+		assert.True(t, s.configRollingBack.Load())
+		mergedConfig := s.cfgState.Load().(*configState).mergedConfig
+		assert.NotContains(t, mergedConfig, "debug/bad")
+		assert.Contains(t, mergedConfig, "nop")
+		_, err = s.loadRemoteConfig(lastRecvRemoteConfigFile)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		require.Len(t, s.hasNewConfig, 1)
+		_, err = os.Stat(filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("rollback disabled", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, false, &statuses)
+		require.NoError(t, s.saveRemoteConfig(goodConfig, lastGoodRemoteConfigFile))
+		s.remoteConfig = badConfig
+
+		s.checkAppliedConfig()
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Equal(t, "Config apply timeout exceeded", statuses[0].ErrorMessage)
+		assert.True(t, proto.Equal(badConfig, s.remoteConfig))
+		assert.Empty(t, s.hasNewConfig)
+	})
+
+	t.Run("new remote config cancels the rollback", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		s.configRollingBack.Store(true)
+
+		s.processRemoteConfigMessage(goodConfig)
+
+		assert.False(t, s.configRollingBack.Load())
+	})
+
+	t.Run("remote config received after the failure is not rolled back", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		require.NoError(t, s.saveRemoteConfig(goodConfig, lastGoodRemoteConfigFile))
+		newConfig := proto.Clone(badConfig).(*protobufs.AgentRemoteConfig)
+		newConfig.ConfigHash = []byte("new")
+		s.processRemoteConfigMessage(newConfig)
+
+		s.rollbackConfig(badConfig)
+
+		assert.True(t, proto.Equal(newConfig, s.remoteConfig))
+		assert.False(t, s.configRollingBack.Load())
+		assert.Empty(t, s.hasNewConfig)
+	})
+
+	t.Run("rollback concurrent with remote config", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+		var statusesMutex sync.Mutex
+		s.opampClient = &mockOpAMPClient{
+			setRemoteConfigStatusFunc: func(rcs *protobufs.RemoteConfigStatus) error {
+				statusesMutex.Lock()
+				defer statusesMutex.Unlock()
+				statuses = append(statuses, rcs)
+				return nil
+			},
+			updateEffectiveConfigFunc: func(_ context.Context) error {
+				return nil
+			},
+		}
+		require.NoError(t, s.saveRemoteConfig(goodConfig, lastGoodRemoteConfigFile))
+		s.remoteConfig = badConfig
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			s.checkAppliedConfig()
+		}()
+		go func() {
+			defer wg.Done()
+			s.processRemoteConfigMessage(goodConfig)
+		}()
+		wg.Wait()
+
+		// Whichever happened first, the agent ends up with the good config.
+		assert.True(t, proto.Equal(goodConfig, s.remoteConfig))
+		assert.Contains(t, s.cfgState.Load().(*configState).mergedConfig, "debug/good")
+		lastRecvConfig, err := s.loadRemoteConfig(lastRecvRemoteConfigFile)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(goodConfig, lastRecvConfig))
+	})
+}
+
+func TestSupervisor_applyAgentUpdate(t *testing.T) {
+	s := &Supervisor{
+		logger:       zap.NewNop(),
+		agentUpdates: make(chan chan error),
+		doneChan:     make(chan struct{}),
+	}
+
+	go func() {
+		result := <-s.agentUpdates
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: false, LastError: "receiver debug failed"}
+		result <- s.checkAgentUpdate()
+
+		result = <-s.agentUpdates
+		s.lastHealthFromClient = &protobufs.ComponentHealth{Healthy: true}
+		result <- s.checkAgentUpdate()
+	}()
+
+	require.EqualError(t, s.applyAgentUpdate(context.Background()), "agent is not healthy: receiver debug failed")
+	require.NoError(t, s.applyAgentUpdate(context.Background()))
+
+	close(s.doneChan)
+	require.EqualError(t, s.applyAgentUpdate(context.Background()), "supervisor is shutting down")
+}
+
 func TestSupervisor_composeNoopConfig(t *testing.T) {
 	const expectedConfig = `exporters:
     nop: null
@@ -11,10 +11,12 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/component"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/replay"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/traces"
 )
 
@@ -22,13 +24,14 @@ var (
 	tracesCfg  *traces.Config
 	metricsCfg *metrics.Config
 	logsCfg    *logs.Config
+	replayCfg  *replay.Config
 )
 
 // rootCmd is the root command on which will be run children commands
 var rootCmd = &cobra.Command{
 	Use:     "telemetrygen",
 	Short:   "Telemetrygen simulates a client generating traces, metrics, and logs",
-	Example: "telemetrygen traces\ntelemetrygen metrics\ntelemetrygen logs",
+	Example: "telemetrygen traces\ntelemetrygen metrics\ntelemetrygen logs\ntelemetrygen replay",
 }
 
 // tracesCmd is the command responsible for sending traces
@@ -61,8 +64,18 @@ var logsCmd = &cobra.Command{
 	},
 }
 
+// replayCmd is the command responsible for replaying the telemetry written by the file exporter
+var replayCmd = &cobra.Command{
+	Use:     "replay",
+	Short:   fmt.Sprintf("Replays traces, metrics, or logs written by the file exporter. (Stability level: %s)", component.StabilityLevelDevelopment),
+	Example: "telemetrygen replay --signal traces --file traces.json",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return replay.Start(replayCfg)
+	},
+}
+
 func init() {
-	rootCmd.AddCommand(tracesCmd, metricsCmd, logsCmd)
+	rootCmd.AddCommand(tracesCmd, metricsCmd, logsCmd, replayCmd)
 
 	tracesCfg = new(traces.Config)
 	tracesCfg.Flags(tracesCmd.Flags())
@@ -73,6 +86,9 @@ func init() {
 	logsCfg = new(logs.Config)
 	logsCfg.Flags(logsCmd.Flags())
 
+	replayCfg = new(replay.Config)
+	replayCfg.Flags(replayCmd.Flags())
+
 	// Disabling completion command for end user
 	// https://github.com/spf13/cobra/blob/master/shell_completions.md
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
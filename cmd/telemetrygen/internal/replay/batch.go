@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// batch is a batch of telemetry written by the file exporter.
+type batch interface {
+	// timestamp returns the earliest timestamp of the batch, or 0 if the batch has none.
+	timestamp() pcommon.Timestamp
+	// restamp maps the timestamps of the batch to the time of the replay, and salts its trace and span IDs.
+	restamp(r retiming, s salt)
+	// setResourceAttributes sets the attributes on every resource of the batch.
+	setResourceAttributes(attrs map[string]any)
+	export(ctx context.Context, exp exporter) error
+}
+
+// salt makes the trace and span IDs of a replay unique. IDs are XORed with the salt, so that
+// the same ID is always replaced by the same ID and the relationships between spans are kept.
+type salt struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+}
+
+func (s salt) traceIDOf(id pcommon.TraceID) pcommon.TraceID {
+	if id.IsEmpty() {
+		return id
+	}
+	for i := range id {
+		id[i] ^= s.traceID[i]
+	}
+	return id
+}
+
+func (s salt) spanIDOf(id pcommon.SpanID) pcommon.SpanID {
+	if id.IsEmpty() {
+		return id
+	}
+	for i := range id {
+		id[i] ^= s.spanID[i]
+	}
+	return id
+}
+
+// retiming maps the timestamps of a file to the time of a replay loop: the first timestamp of the file
+// is mapped to the start of the loop, and the offsets from it are divided by the replay speed.
+// The offsets are kept when replaying as fast as possible, at speed 0.
+type retiming struct {
+	first pcommon.Timestamp
+	start pcommon.Timestamp
+	speed float64
+}
+
+func (r retiming) timestamp(ts pcommon.Timestamp) pcommon.Timestamp {
+	if ts == 0 {
+		return ts
+	}
+	offset := float64(int64(ts) - int64(r.first))
+	if r.speed > 0 {
+		offset /= r.speed
+	}
+	return pcommon.Timestamp(int64(r.start) + int64(offset))
+}
+
+func minTimestamp(current, ts pcommon.Timestamp) pcommon.Timestamp {
+	if ts != 0 && (current == 0 || ts < current) {
+		return ts
+	}
+	return current
+}
+
+func setResourceAttributes(res pcommon.Resource, attrs map[string]any) {
+	for k, v := range attrs {
+		switch v := v.(type) {
+		case string:
+			res.Attributes().PutStr(k, v)
+		case bool:
+			res.Attributes().PutBool(k, v)
+		}
+	}
+}
+
+type tracesBatch struct {
+	td ptrace.Traces
+}
+
+func (b tracesBatch) timestamp() pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	for i := 0; i < b.td.ResourceSpans().Len(); i++ {
+		sss := b.td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				ts = minTimestamp(ts, spans.At(k).StartTimestamp())
+			}
+		}
+	}
+	return ts
+}
+
+func (b tracesBatch) restamp(r retiming, s salt) {
+	for i := 0; i < b.td.ResourceSpans().Len(); i++ {
+		sss := b.td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetStartTimestamp(r.timestamp(span.StartTimestamp()))
+				span.SetEndTimestamp(r.timestamp(span.EndTimestamp()))
+				span.SetTraceID(s.traceIDOf(span.TraceID()))
+				span.SetSpanID(s.spanIDOf(span.SpanID()))
+				span.SetParentSpanID(s.spanIDOf(span.ParentSpanID()))
+
+				for l := 0; l < span.Events().Len(); l++ {
+					event := span.Events().At(l)
+					event.SetTimestamp(r.timestamp(event.Timestamp()))
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					link := span.Links().At(l)
+					link.SetTraceID(s.traceIDOf(link.TraceID()))
+					link.SetSpanID(s.spanIDOf(link.SpanID()))
+				}
+			}
+		}
+	}
+}
+
+func (b tracesBatch) setResourceAttributes(attrs map[string]any) {
+	for i := 0; i < b.td.ResourceSpans().Len(); i++ {
+		setResourceAttributes(b.td.ResourceSpans().At(i).Resource(), attrs)
+	}
+}
+
+func (b tracesBatch) export(ctx context.Context, exp exporter) error {
+	return exp.exportTraces(ctx, b.td)
+}
+
+type metricsBatch struct {
+	md pmetric.Metrics
+}
+
+// dataPoint is implemented by every type of metric data point.
+type dataPoint interface {
+	StartTimestamp() pcommon.Timestamp
+	SetStartTimestamp(pcommon.Timestamp)
+	Timestamp() pcommon.Timestamp
+	SetTimestamp(pcommon.Timestamp)
+}
+
+// rangeDataPoints calls f with every data point of the batch, and the exemplars of the data point.
+func (b metricsBatch) rangeDataPoints(f func(dp dataPoint, exemplars pmetric.ExemplarSlice)) {
+	noExemplars := pmetric.NewExemplarSlice()
+	for i := 0; i < b.md.ResourceMetrics().Len(); i++ {
+		sms := b.md.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := metric.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), noExemplars)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (b metricsBatch) timestamp() pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	b.rangeDataPoints(func(dp dataPoint, _ pmetric.ExemplarSlice) {
+		ts = minTimestamp(ts, dp.Timestamp())
+	})
+	return ts
+}
+
+func (b metricsBatch) restamp(r retiming, s salt) {
+	b.rangeDataPoints(func(dp dataPoint, exemplars pmetric.ExemplarSlice) {
+		dp.SetStartTimestamp(r.timestamp(dp.StartTimestamp()))
+		dp.SetTimestamp(r.timestamp(dp.Timestamp()))
+		for i := 0; i < exemplars.Len(); i++ {
+			exemplar := exemplars.At(i)
+			exemplar.SetTimestamp(r.timestamp(exemplar.Timestamp()))
+			exemplar.SetTraceID(s.traceIDOf(exemplar.TraceID()))
+			exemplar.SetSpanID(s.spanIDOf(exemplar.SpanID()))
+		}
+	})
+}
+
+func (b metricsBatch) setResourceAttributes(attrs map[string]any) {
+	for i := 0; i < b.md.ResourceMetrics().Len(); i++ {
+		setResourceAttributes(b.md.ResourceMetrics().At(i).Resource(), attrs)
+	}
+}
+
+func (b metricsBatch) export(ctx context.Context, exp exporter) error {
+	return exp.exportMetrics(ctx, b.md)
+}
+
+type logsBatch struct {
+	ld plog.Logs
+}
+
+func (b logsBatch) timestamp() pcommon.Timestamp {
+	var ts pcommon.Timestamp
+	for i := 0; i < b.ld.ResourceLogs().Len(); i++ {
+		sls := b.ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			logs := sls.At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.Timestamp() != 0 {
+					ts = minTimestamp(ts, lr.Timestamp())
+				} else {
+					ts = minTimestamp(ts, lr.ObservedTimestamp())
+				}
+			}
+		}
+	}
+	return ts
+}
+
+func (b logsBatch) restamp(r retiming, s salt) {
+	for i := 0; i < b.ld.ResourceLogs().Len(); i++ {
+		sls := b.ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			logs := sls.At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				lr.SetTimestamp(r.timestamp(lr.Timestamp()))
+				lr.SetObservedTimestamp(r.timestamp(lr.ObservedTimestamp()))
+				lr.SetTraceID(s.traceIDOf(lr.TraceID()))
+				lr.SetSpanID(s.spanIDOf(lr.SpanID()))
+			}
+		}
+	}
+}
+
+func (b logsBatch) setResourceAttributes(attrs map[string]any) {
+	for i := 0; i < b.ld.ResourceLogs().Len(); i++ {
+		setResourceAttributes(b.ld.ResourceLogs().At(i).Resource(), attrs)
+	}
+}
+
+func (b logsBatch) export(ctx context.Context, exp exporter) error {
+	return exp.exportLogs(ctx, b.ld)
+}
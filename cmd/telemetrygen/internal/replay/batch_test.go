@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	testTraceID = pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	testSpanID  = pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	testSalt    = salt{
+		traceID: pcommon.TraceID([16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+		spanID:  pcommon.SpanID([8]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+	}
+)
+
+func testTraces(start pcommon.Timestamp) ptrace.Traces {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+
+	parent := spans.AppendEmpty()
+	parent.SetName("parent")
+	parent.SetTraceID(testTraceID)
+	parent.SetSpanID(testSpanID)
+	parent.SetStartTimestamp(start)
+	parent.SetEndTimestamp(start + pcommon.Timestamp(time.Second))
+	parent.Events().AppendEmpty().SetTimestamp(start + pcommon.Timestamp(time.Millisecond))
+
+	child := spans.AppendEmpty()
+	child.SetName("child")
+	child.SetTraceID(testTraceID)
+	child.SetSpanID(pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+	child.SetParentSpanID(testSpanID)
+	child.SetStartTimestamp(start + pcommon.Timestamp(100*time.Millisecond))
+	child.SetEndTimestamp(start + pcommon.Timestamp(200*time.Millisecond))
+	link := child.Links().AppendEmpty()
+	link.SetTraceID(testTraceID)
+	link.SetSpanID(testSpanID)
+
+	return td
+}
+
+func TestSalt(t *testing.T) {
+	s := testSalt
+	assert.True(t, s.traceIDOf(pcommon.NewTraceIDEmpty()).IsEmpty())
+	assert.True(t, s.spanIDOf(pcommon.NewSpanIDEmpty()).IsEmpty())
+
+	assert.NotEqual(t, testTraceID, s.traceIDOf(testTraceID))
+	assert.Equal(t, s.traceIDOf(testTraceID), s.traceIDOf(testTraceID))
+	assert.Equal(t, testTraceID, s.traceIDOf(s.traceIDOf(testTraceID)))
+
+	assert.NotEqual(t, testSpanID, s.spanIDOf(testSpanID))
+	assert.Equal(t, s.spanIDOf(testSpanID), s.spanIDOf(testSpanID))
+	assert.Equal(t, testSpanID, s.spanIDOf(s.spanIDOf(testSpanID)))
+}
+
+func TestRetiming(t *testing.T) {
+	first := pcommon.Timestamp(time.Hour)
+	start := pcommon.Timestamp(2 * time.Hour)
+	for _, tt := range []struct {
+		speed    float64
+		expected pcommon.Timestamp
+	}{
+		{speed: 0, expected: start + pcommon.Timestamp(time.Minute)},
+		{speed: 1, expected: start + pcommon.Timestamp(time.Minute)},
+		{speed: 2, expected: start + pcommon.Timestamp(30*time.Second)},
+		{speed: 0.5, expected: start + pcommon.Timestamp(2*time.Minute)},
+	} {
+		r := retiming{first: first, start: start, speed: tt.speed}
+		assert.Equal(t, start, r.timestamp(first))
+		assert.Equal(t, tt.expected, r.timestamp(first+pcommon.Timestamp(time.Minute)), "speed %v", tt.speed)
+		assert.Zero(t, r.timestamp(0))
+	}
+}
+
+func TestTracesBatch(t *testing.T) {
+	start := pcommon.Timestamp(time.Hour)
+	b := tracesBatch{testTraces(start)}
+	assert.Equal(t, start, b.timestamp())
+
+	b.restamp(retiming{start: pcommon.Timestamp(time.Minute), speed: 1}, testSalt)
+	b.setResourceAttributes(map[string]any{"service.name": "replay", "replayed": true})
+
+	rs := b.td.ResourceSpans().At(0)
+	name, ok := rs.Resource().Attributes().Get("service.name")
+	assert.True(t, ok)
+	assert.Equal(t, "replay", name.Str())
+	replayed, ok := rs.Resource().Attributes().Get("replayed")
+	assert.True(t, ok)
+	assert.True(t, replayed.Bool())
+
+	spans := rs.ScopeSpans().At(0).Spans()
+	parent, child := spans.At(0), spans.At(1)
+	shifted := start + pcommon.Timestamp(time.Minute)
+	assert.Equal(t, shifted, b.timestamp())
+	assert.Equal(t, shifted, parent.StartTimestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(time.Second), parent.EndTimestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(time.Millisecond), parent.Events().At(0).Timestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(100*time.Millisecond), child.StartTimestamp())
+
+	assert.Equal(t, testSalt.traceIDOf(testTraceID), parent.TraceID())
+	assert.Equal(t, parent.TraceID(), child.TraceID())
+	assert.Equal(t, testSalt.spanIDOf(testSpanID), parent.SpanID())
+	assert.True(t, parent.ParentSpanID().IsEmpty())
+	assert.Equal(t, parent.SpanID(), child.ParentSpanID())
+	assert.Equal(t, parent.TraceID(), child.Links().At(0).TraceID())
+	assert.Equal(t, parent.SpanID(), child.Links().At(0).SpanID())
+}
+
+func TestMetricsBatch(t *testing.T) {
+	start := pcommon.Timestamp(time.Hour)
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+	sum := metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty()
+	sum.SetStartTimestamp(start)
+	sum.SetTimestamp(start + pcommon.Timestamp(time.Second))
+	exemplar := sum.Exemplars().AppendEmpty()
+	exemplar.SetTimestamp(start + pcommon.Timestamp(time.Second))
+	exemplar.SetTraceID(testTraceID)
+	exemplar.SetSpanID(testSpanID)
+
+	gauge := metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	gauge.SetTimestamp(start + pcommon.Timestamp(2*time.Second))
+
+	summary := metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty()
+	summary.SetTimestamp(start + pcommon.Timestamp(3*time.Second))
+
+	b := metricsBatch{md}
+	assert.Equal(t, start+pcommon.Timestamp(time.Second), b.timestamp())
+
+	b.restamp(retiming{start: pcommon.Timestamp(time.Minute), speed: 1}, testSalt)
+	shifted := start + pcommon.Timestamp(time.Minute)
+	assert.Equal(t, shifted, sum.StartTimestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(time.Second), sum.Timestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(time.Second), exemplar.Timestamp())
+	assert.Equal(t, testSalt.traceIDOf(testTraceID), exemplar.TraceID())
+	assert.Equal(t, testSalt.spanIDOf(testSpanID), exemplar.SpanID())
+	assert.Equal(t, pcommon.Timestamp(0), gauge.StartTimestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(2*time.Second), gauge.Timestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(3*time.Second), summary.Timestamp())
+}
+
+func TestLogsBatch(t *testing.T) {
+	start := pcommon.Timestamp(time.Hour)
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+
+	observed := logs.AppendEmpty()
+	observed.SetObservedTimestamp(start)
+
+	lr := logs.AppendEmpty()
+	lr.SetTimestamp(start + pcommon.Timestamp(time.Second))
+	lr.SetObservedTimestamp(start + pcommon.Timestamp(2*time.Second))
+	lr.SetTraceID(testTraceID)
+	lr.SetSpanID(testSpanID)
+
+	b := logsBatch{ld}
+	assert.Equal(t, start, b.timestamp())
+
+	b.restamp(retiming{start: pcommon.Timestamp(time.Minute), speed: 1}, testSalt)
+	shifted := start + pcommon.Timestamp(time.Minute)
+	assert.Equal(t, pcommon.Timestamp(0), observed.Timestamp())
+	assert.Equal(t, shifted, observed.ObservedTimestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(time.Second), lr.Timestamp())
+	assert.Equal(t, shifted+pcommon.Timestamp(2*time.Second), lr.ObservedTimestamp())
+	assert.Equal(t, testSalt.traceIDOf(testTraceID), lr.TraceID())
+	assert.Equal(t, testSalt.spanIDOf(testSpanID), lr.SpanID())
+}
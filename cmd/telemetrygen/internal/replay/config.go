@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+const (
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+	signalLogs    = "logs"
+
+	formatJSON  = "json"
+	formatProto = "proto"
+)
+
+// Config describes the replay scenario.
+type Config struct {
+	common.Config
+	File   string
+	Format string
+	Signal string
+	Speed  float64
+	Loops  int
+}
+
+// Flags registers config flags.
+func (c *Config) Flags(fs *pflag.FlagSet) {
+	c.CommonFlags(fs)
+
+	fs.StringVar(&c.HTTPPath, "otlp-http-url-path", "", "Which URL path to write to (default: /v1/<signal>)")
+
+	fs.StringVar(&c.File, "file", "", "Path of the file written by the file exporter to replay")
+	fs.StringVar(&c.Format, "format", formatJSON, "Format of the file, must be one of 'json' or 'proto'")
+	fs.StringVar(&c.Signal, "signal", "", "Signal stored in the file, must be one of 'traces', 'metrics' or 'logs'")
+	fs.Float64Var(&c.Speed, "speed", 1, "Replay speed relative to the original rate, e.g. 2 replays the data twice as fast. Zero means as fast as possible.")
+	fs.IntVar(&c.Loops, "loops", 1, "Number of times each worker replays the file (ignored if duration is provided)")
+}
+
+// Validate validates the replay scenario parameters.
+func (c *Config) Validate() error {
+	if c.File == "" {
+		return errors.New("`file` must be specified")
+	}
+
+	switch c.Format {
+	case formatJSON, formatProto:
+	default:
+		return fmt.Errorf("`format` must be one of %q or %q, got %q", formatJSON, formatProto, c.Format)
+	}
+
+	switch c.Signal {
+	case signalTraces, signalMetrics, signalLogs:
+	default:
+		return fmt.Errorf("`signal` must be one of %q, %q or %q, got %q", signalTraces, signalMetrics, signalLogs, c.Signal)
+	}
+
+	if c.Speed < 0 {
+		return errors.New("`speed` must be 0 or greater")
+	}
+
+	if c.Rate != 0 {
+		return errors.New("`rate` is not supported when replaying, use `speed` instead")
+	}
+
+	if c.TotalDuration <= 0 && c.Loops <= 0 {
+		return errors.New("either `loops` or `duration` must be greater than 0")
+	}
+
+	return nil
+}
+
+// URLPath returns the URL path the data is sent to with HTTP.
+func (c *Config) URLPath() string {
+	if c.HTTPPath != "" {
+		return c.HTTPPath
+	}
+	return "/v1/" + c.Signal
+}
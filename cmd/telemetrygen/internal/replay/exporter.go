@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+// exporter sends the replayed batches. The OpenTelemetry SDK exporters cannot be used,
+// since they only export the telemetry recorded with the SDK.
+type exporter interface {
+	exportTraces(ctx context.Context, td ptrace.Traces) error
+	exportMetrics(ctx context.Context, md pmetric.Metrics) error
+	exportLogs(ctx context.Context, ld plog.Logs) error
+	shutdown() error
+}
+
+// grpcExporter sends the batches with OTLP/gRPC.
+type grpcExporter struct {
+	conn    *grpc.ClientConn
+	headers metadata.MD
+	traces  ptraceotlp.GRPCClient
+	metrics pmetricotlp.GRPCClient
+	logs    plogotlp.GRPCClient
+}
+
+func newGRPCExporter(cfg *Config) (*grpcExporter, error) {
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		var err error
+		creds, err = common.GetTLSCredentialsForGRPCExporter(cfg.CaFile, cfg.ClientAuth, cfg.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TLS credentials: %w", err)
+		}
+	}
+
+	conn, err := grpc.NewClient(cfg.Endpoint(), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return &grpcExporter{
+		conn:    conn,
+		headers: metadata.New(cfg.GetHeaders()),
+		traces:  ptraceotlp.NewGRPCClient(conn),
+		metrics: pmetricotlp.NewGRPCClient(conn),
+		logs:    plogotlp.NewGRPCClient(conn),
+	}, nil
+}
+
+func (e *grpcExporter) exportTraces(ctx context.Context, td ptrace.Traces) error {
+	_, err := e.traces.Export(metadata.NewOutgoingContext(ctx, e.headers), ptraceotlp.NewExportRequestFromTraces(td))
+	return err
+}
+
+func (e *grpcExporter) exportMetrics(ctx context.Context, md pmetric.Metrics) error {
+	_, err := e.metrics.Export(metadata.NewOutgoingContext(ctx, e.headers), pmetricotlp.NewExportRequestFromMetrics(md))
+	return err
+}
+
+func (e *grpcExporter) exportLogs(ctx context.Context, ld plog.Logs) error {
+	_, err := e.logs.Export(metadata.NewOutgoingContext(ctx, e.headers), plogotlp.NewExportRequestFromLogs(ld))
+	return err
+}
+
+func (e *grpcExporter) shutdown() error {
+	return e.conn.Close()
+}
+
+// httpExporter sends the batches with OTLP/HTTP, encoded with protobuf.
+type httpExporter struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func newHTTPExporter(cfg *Config) (*httpExporter, error) {
+	scheme := "http"
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.Insecure {
+		scheme = "https"
+		tlsCfg, err := common.GetTLSCredentialsForHTTPExporter(cfg.CaFile, cfg.ClientAuth, cfg.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TLS credentials: %w", err)
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	return &httpExporter{
+		client:  &http.Client{Transport: transport},
+		url:     fmt.Sprintf("%s://%s%s", scheme, cfg.Endpoint(), cfg.URLPath()),
+		headers: cfg.GetHeaders(),
+	}, nil
+}
+
+func (e *httpExporter) exportTraces(ctx context.Context, td ptrace.Traces) error {
+	body, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	if err != nil {
+		return err
+	}
+	return e.post(ctx, body)
+}
+
+func (e *httpExporter) exportMetrics(ctx context.Context, md pmetric.Metrics) error {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	if err != nil {
+		return err
+	}
+	return e.post(ctx, body)
+}
+
+func (e *httpExporter) exportLogs(ctx context.Context, ld plog.Logs) error {
+	body, err := plogotlp.NewExportRequestFromLogs(ld).MarshalProto()
+	if err != nil {
+		return err
+	}
+	return e.post(ctx, body)
+}
+
+func (e *httpExporter) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Read the body so that the connection is reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send data to %s, HTTP status code: %d", e.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *httpExporter) shutdown() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
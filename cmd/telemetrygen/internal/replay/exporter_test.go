@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func TestHTTPExporter(t *testing.T) {
+	var received ptraceotlp.ExportRequest
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "value", r.Header.Get("key"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = ptraceotlp.NewExportRequest()
+		assert.NoError(t, received.UnmarshalProto(body))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Config: common.Config{
+			CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+			Insecure:       true,
+			UseHTTP:        true,
+			Headers:        common.KeyValue{"key": "value"},
+		},
+		Signal: signalTraces,
+	}
+	exp, err := newHTTPExporter(cfg)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, exp.shutdown()) }()
+
+	td := testTraces(pcommon.Timestamp(1))
+	require.NoError(t, exp.exportTraces(context.Background(), td))
+	assert.Equal(t, td.SpanCount(), received.Traces().SpanCount())
+
+	status = http.StatusBadRequest
+	require.ErrorContains(t, exp.exportTraces(context.Background(), td), "HTTP status code: 400")
+}
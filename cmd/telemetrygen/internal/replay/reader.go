@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// batchReader reads the batches written by the file exporter: one JSON encoded batch per line,
+// or proto encoded batches each preceded by its size as a 4 bytes big endian unsigned integer.
+type batchReader struct {
+	r         *bufio.Reader
+	format    string
+	unmarshal func([]byte) (batch, error)
+}
+
+func newBatchReader(r io.Reader, format string, signal string) *batchReader {
+	br := &batchReader{
+		r:      bufio.NewReader(r),
+		format: format,
+	}
+
+	switch signal {
+	case signalTraces:
+		var u ptrace.Unmarshaler = &ptrace.JSONUnmarshaler{}
+		if format == formatProto {
+			u = &ptrace.ProtoUnmarshaler{}
+		}
+		br.unmarshal = func(buf []byte) (batch, error) {
+			td, err := u.UnmarshalTraces(buf)
+			return tracesBatch{td}, err
+		}
+	case signalMetrics:
+		var u pmetric.Unmarshaler = &pmetric.JSONUnmarshaler{}
+		if format == formatProto {
+			u = &pmetric.ProtoUnmarshaler{}
+		}
+		br.unmarshal = func(buf []byte) (batch, error) {
+			md, err := u.UnmarshalMetrics(buf)
+			return metricsBatch{md}, err
+		}
+	case signalLogs:
+		var u plog.Unmarshaler = &plog.JSONUnmarshaler{}
+		if format == formatProto {
+			u = &plog.ProtoUnmarshaler{}
+		}
+		br.unmarshal = func(buf []byte) (batch, error) {
+			ld, err := u.UnmarshalLogs(buf)
+			return logsBatch{ld}, err
+		}
+	}
+
+	return br
+}
+
+// next returns the next batch, or io.EOF when all the batches are read.
+func (br *batchReader) next() (batch, error) {
+	buf, err := br.nextMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := br.unmarshal(buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal batch: %w", err)
+	}
+	return b, nil
+}
+
+func (br *batchReader) nextMessage() ([]byte, error) {
+	if br.format == formatProto {
+		var size uint32
+		if err := binary.Read(br.r, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br.r, buf); err != nil {
+			return nil, fmt.Errorf("cannot read batch: %w", err)
+		}
+		return buf, nil
+	}
+
+	for {
+		line, err := br.r.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				err = fmt.Errorf("cannot read batch: %w", err)
+			}
+			return nil, err
+		}
+	}
+}
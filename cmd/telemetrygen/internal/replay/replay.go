@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+// Start starts the replay of the file.
+func Start(cfg *Config) error {
+	logger, err := common.CreateLogger(cfg.SkipSettingGRPCLogger)
+	if err != nil {
+		return err
+	}
+	logger.Info("starting the replay with configuration", zap.Any("config", cfg))
+
+	var exp exporter
+	if cfg.UseHTTP {
+		logger.Info("starting HTTP exporter")
+		exp, err = newHTTPExporter(cfg)
+	} else {
+		logger.Info("starting gRPC exporter")
+		exp, err = newGRPCExporter(cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		logger.Info("stopping the exporter")
+		if tempError := exp.shutdown(); tempError != nil {
+			logger.Error("failed to stop the exporter", zap.Error(tempError))
+		}
+	}()
+
+	if err = Run(cfg, exp, logger); err != nil {
+		logger.Error("failed to execute the replay.", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Run executes the replay scenario.
+func Run(c *Config, exp exporter, logger *zap.Logger) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(c.File); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	loops := c.Loops
+	if c.TotalDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.TotalDuration)
+		defer cancel()
+		loops = 0
+	}
+
+	if c.Speed == 0 {
+		logger.Info("replay isn't being throttled")
+	} else {
+		logger.Info("replay speed relative to the original rate", zap.Float64("speed", c.Speed))
+	}
+
+	wg := sync.WaitGroup{}
+	errs := make([]error, c.WorkerCount)
+
+	for i := 0; i < c.WorkerCount; i++ {
+		wg.Add(1)
+		w := worker{
+			file:               c.File,
+			format:             c.Format,
+			signal:             c.Signal,
+			speed:              c.Speed,
+			loops:              loops,
+			resourceAttributes: c.ResourceAttributes,
+			exporter:           exp,
+			logger:             logger.With(zap.Int("worker", i)),
+		}
+
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = w.replay(ctx)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+type worker struct {
+	file               string         // file to replay
+	format             string         // format of the file
+	signal             string         // signal stored in the file
+	speed              float64        // replay speed relative to the original rate, 0 to replay as fast as possible
+	loops              int            // how many times to replay the file, 0 to replay it until ctx is done
+	resourceAttributes map[string]any // attributes to set on every resource
+	exporter           exporter       // exporter sending the batches
+	logger             *zap.Logger    // logger
+}
+
+// replay replays the file w.loops times, or until ctx is done when w.loops is 0.
+func (w worker) replay(ctx context.Context) error {
+	batches := 0
+	for loop := 0; w.loops == 0 || loop < w.loops; loop++ {
+		n, err := w.replayOnce(ctx)
+		batches += n
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	w.logger.Info("batches replayed", zap.Int("batches", batches))
+	return nil
+}
+
+// replayOnce replays every batch of the file once, with fresh trace and span IDs.
+// The first timestamp of the file is mapped to the start of the loop, and the time between two
+// batches, as well as the offsets of their timestamps from the first one, are divided by w.speed.
+func (w worker) replayOnce(ctx context.Context) (int, error) {
+	var s salt
+	if _, err := rand.Read(s.traceID[:]); err != nil {
+		return 0, err
+	}
+	if _, err := rand.Read(s.spanID[:]); err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(w.file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	reader := newBatchReader(f, w.format, w.signal)
+	var timing retiming
+	batches := 0
+	for {
+		b, err := reader.next()
+		if errors.Is(err, io.EOF) {
+			return batches, nil
+		}
+		if err != nil {
+			return batches, fmt.Errorf("cannot replay %s: %w", w.file, err)
+		}
+
+		if ts := b.timestamp(); ts != 0 {
+			if timing.first == 0 {
+				timing = retiming{first: ts, start: pcommon.NewTimestampFromTime(time.Now()), speed: w.speed}
+			}
+			if w.speed > 0 {
+				if wait := time.Until(timing.timestamp(ts).AsTime()); wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return batches, nil
+					case <-timer.C:
+					}
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			return batches, nil
+		}
+
+		b.restamp(timing, s)
+		b.setResourceAttributes(w.resourceAttributes)
+		if err := b.export(ctx, w.exporter); err != nil {
+			w.logger.Error("failed to export the batch", zap.Error(err))
+		} else {
+			batches++
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replay
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+type mockExporter struct {
+	mu      sync.Mutex
+	traces  []ptrace.Traces
+	metrics []pmetric.Metrics
+	logs    []plog.Logs
+	times   []time.Time
+}
+
+func (m *mockExporter) exportTraces(_ context.Context, td ptrace.Traces) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.traces = append(m.traces, td)
+	m.times = append(m.times, time.Now())
+	return nil
+}
+
+func (m *mockExporter) exportMetrics(_ context.Context, md pmetric.Metrics) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, md)
+	m.times = append(m.times, time.Now())
+	return nil
+}
+
+func (m *mockExporter) exportLogs(_ context.Context, ld plog.Logs) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, ld)
+	m.times = append(m.times, time.Now())
+	return nil
+}
+
+func (m *mockExporter) shutdown() error {
+	return nil
+}
+
+// writeTracesFile writes two batches of traces, 200ms apart, as the file exporter does.
+func writeTracesFile(t *testing.T, format string) string {
+	start := pcommon.NewTimestampFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	batches := []ptrace.Traces{testTraces(start), testTraces(start + pcommon.Timestamp(200*time.Millisecond))}
+
+	path := filepath.Join(t.TempDir(), "traces."+format)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	for _, td := range batches {
+		if format == formatProto {
+			buf, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+			require.NoError(t, err)
+			require.NoError(t, binary.Write(f, binary.BigEndian, uint32(len(buf))))
+			_, err = f.Write(buf)
+			require.NoError(t, err)
+		} else {
+			buf, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+			require.NoError(t, err)
+			_, err = f.Write(append(buf, '\n'))
+			require.NoError(t, err)
+		}
+	}
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *Config
+		errMsg string
+	}{
+		{
+			name:   "No file",
+			cfg:    &Config{Format: formatJSON, Signal: signalTraces, Loops: 1},
+			errMsg: "`file` must be specified",
+		},
+		{
+			name:   "Invalid format",
+			cfg:    &Config{File: "file", Format: "csv", Signal: signalTraces, Loops: 1},
+			errMsg: "`format` must be one of \"json\" or \"proto\", got \"csv\"",
+		},
+		{
+			name:   "Invalid signal",
+			cfg:    &Config{File: "file", Format: formatJSON, Signal: "profiles", Loops: 1},
+			errMsg: "`signal` must be one of \"traces\", \"metrics\" or \"logs\", got \"profiles\"",
+		},
+		{
+			name:   "Negative speed",
+			cfg:    &Config{File: "file", Format: formatJSON, Signal: signalTraces, Speed: -1, Loops: 1},
+			errMsg: "`speed` must be 0 or greater",
+		},
+		{
+			name: "Rate",
+			cfg: &Config{
+				Config: common.Config{Rate: 10},
+				File:   "file", Format: formatJSON, Signal: signalTraces, Loops: 1,
+			},
+			errMsg: "`rate` is not supported when replaying, use `speed` instead",
+		},
+		{
+			name:   "No loops nor duration",
+			cfg:    &Config{File: "file", Format: formatJSON, Signal: signalTraces},
+			errMsg: "either `loops` or `duration` must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.cfg.Validate(), tt.errMsg)
+			require.EqualError(t, Run(tt.cfg, &mockExporter{}, zap.NewNop()), tt.errMsg)
+		})
+	}
+}
+
+func TestURLPath(t *testing.T) {
+	cfg := &Config{Signal: signalMetrics}
+	assert.Equal(t, "/v1/metrics", cfg.URLPath())
+	cfg.HTTPPath = "/custom"
+	assert.Equal(t, "/custom", cfg.URLPath())
+}
+
+func TestReplay(t *testing.T) {
+	for _, format := range []string{formatJSON, formatProto} {
+		t.Run(format, func(t *testing.T) {
+			cfg := &Config{
+				Config: common.Config{
+					WorkerCount:        1,
+					ResourceAttributes: common.KeyValue{"service.name": "replay"},
+				},
+				File:   writeTracesFile(t, format),
+				Format: format,
+				Signal: signalTraces,
+				Loops:  2,
+			}
+			exp := &mockExporter{}
+			before := time.Now()
+
+			require.NoError(t, Run(cfg, exp, zap.NewNop()))
+
+			require.Len(t, exp.traces, 4)
+			ids := map[pcommon.TraceID]bool{}
+			for _, td := range exp.traces {
+				rs := td.ResourceSpans().At(0)
+				name, ok := rs.Resource().Attributes().Get("service.name")
+				require.True(t, ok)
+				assert.Equal(t, "replay", name.Str())
+
+				span := rs.ScopeSpans().At(0).Spans().At(0)
+				assert.NotEqual(t, testTraceID, span.TraceID())
+				assert.False(t, span.StartTimestamp().AsTime().Before(before.Add(-time.Second)))
+				ids[span.TraceID()] = true
+			}
+			// both batches of a loop share the same trace ID, which differs between the loops.
+			assert.Len(t, ids, 2)
+			assert.Equal(t, exp.traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID(),
+				exp.traces[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+		})
+	}
+}
+
+func TestReplaySpeed(t *testing.T) {
+	for _, speed := range []float64{0, 1, 2} {
+		cfg := &Config{
+			Config: common.Config{WorkerCount: 1},
+			File:   writeTracesFile(t, formatJSON),
+			Format: formatJSON,
+			Signal: signalTraces,
+			Speed:  speed,
+			Loops:  1,
+		}
+		exp := &mockExporter{}
+
+		require.NoError(t, Run(cfg, exp, zap.NewNop()))
+
+		// the offsets are kept when replaying as fast as possible
+		offset := 200 * time.Millisecond
+		if speed > 0 {
+			offset = time.Duration(float64(offset) / speed)
+			require.Len(t, exp.times, 2)
+			assert.GreaterOrEqual(t, exp.times[1].Sub(exp.times[0]), offset)
+		}
+		// the timestamps of all the batches of a loop are shifted by the same duration.
+		first := exp.traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).StartTimestamp()
+		second := exp.traces[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).StartTimestamp()
+		assert.Equal(t, offset, time.Duration(second-first), "speed %v", speed)
+	}
+}
+
+func TestReplayDuration(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount:   2,
+			TotalDuration: 500 * time.Millisecond,
+		},
+		File:   writeTracesFile(t, formatJSON),
+		Format: formatJSON,
+		Signal: signalTraces,
+		Speed:  1,
+	}
+	exp := &mockExporter{}
+
+	require.NoError(t, Run(cfg, exp, zap.NewNop()))
+
+	// each worker replays the file until the duration elapses: 2 batches per 200ms.
+	assert.GreaterOrEqual(t, len(exp.traces), 8)
+}
+
+func TestReplayInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.json")
+	require.NoError(t, os.WriteFile(path, []byte("{\"resourceLogs\":[]}\nnot json\n"), 0o600))
+	cfg := &Config{
+		Config: common.Config{WorkerCount: 1},
+		File:   path,
+		Format: formatJSON,
+		Signal: signalLogs,
+		Loops:  1,
+	}
+	exp := &mockExporter{}
+
+	require.ErrorContains(t, Run(cfg, exp, zap.NewNop()), "cannot unmarshal batch")
+	assert.Len(t, exp.logs, 1)
+
+	cfg.File = filepath.Join(t.TempDir(), "missing.json")
+	require.Error(t, Run(cfg, exp, zap.NewNop()))
+}
@@ -22,6 +22,7 @@ type Config struct {
 	StatusCode       string
 	Batch            bool
 	LoadSize         int
+	TopologyFile     string
 
 	SpanDuration time.Duration
 }
@@ -40,6 +41,8 @@ func (c *Config) Flags(fs *pflag.FlagSet) {
 	fs.BoolVar(&c.Batch, "batch", true, "Whether to batch traces")
 	fs.IntVar(&c.LoadSize, "size", 0, "Desired minimum size in MB of string data for each trace generated. This can be used to test traces with large payloads, i.e. when testing the OTLP receiver endpoint max receive size.")
 	fs.DurationVar(&c.SpanDuration, "span-duration", 123*time.Microsecond, "The duration of each generated span.")
+	fs.StringVar(&c.TopologyFile, "topology", "", "Path of a YAML file describing the services and operations the traces go through. "+
+		"When set, child-spans, marshal, service, size, span-duration and status-code are ignored.")
 }
 
 // Validate validates the test scenario parameters.
@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces // import "github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/traces"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	kindServer   = "server"
+	kindConsumer = "consumer"
+	kindInternal = "internal"
+
+	distributionConstant    = "constant"
+	distributionUniform     = "uniform"
+	distributionNormal      = "normal"
+	distributionExponential = "exponential"
+)
+
+// topology describes a service graph. The generated traces start at one of the entrypoints,
+// and follow the calls between the operations of the services.
+type topology struct {
+	Entrypoints []entrypoint `yaml:"entrypoints"`
+	Services    []service    `yaml:"services"`
+
+	totalWeight float64
+}
+
+// entrypoint is an operation starting traces.
+type entrypoint struct {
+	Service   string  `yaml:"service"`
+	Operation string  `yaml:"operation"`
+	Weight    float64 `yaml:"weight"` // how often traces start at this entrypoint relative to the others, 1 by default
+
+	operation *operation
+}
+
+type service struct {
+	Name       string         `yaml:"name"`
+	Attributes map[string]any `yaml:"attributes"` // resource attributes of the service
+	Operations []operation    `yaml:"operations"`
+
+	attributes []attribute.KeyValue
+	tracer     trace.Tracer
+}
+
+type operation struct {
+	Name       string         `yaml:"name"`
+	Kind       string         `yaml:"kind"`       // one of server (default), consumer or internal
+	Duration   distribution   `yaml:"duration"`   // time spent in the operation itself, besides its calls
+	ErrorRate  float64        `yaml:"error_rate"` // probability that the operation fails
+	Attributes map[string]any `yaml:"attributes"` // attributes of the span of the operation
+	Calls      []call         `yaml:"calls"`
+
+	service    *service
+	attributes []attribute.KeyValue
+}
+
+// call is an edge of the service graph. Calls to server operations are recorded as a client span in the
+// calling service, and calls to consumer operations as a producer span. Calls to internal operations,
+// which must be in the same service, are recorded without any client span.
+type call struct {
+	Service   string       `yaml:"service"` // service of the operation, the calling service by default
+	Operation string       `yaml:"operation"`
+	Count     int          `yaml:"count"`      // how many times the operation is called, 1 by default
+	Parallel  bool         `yaml:"parallel"`   // whether the calls are made at the same time, or one after the other
+	Latency   distribution `yaml:"latency"`    // network latency, before the operation starts and after it ends
+	ErrorRate float64      `yaml:"error_rate"` // probability that the call fails before reaching the operation
+
+	callee *operation
+}
+
+// distribution of a duration.
+type distribution struct {
+	Type   string        `yaml:"distribution"` // one of constant (default), uniform, normal or exponential
+	Mean   time.Duration `yaml:"mean"`         // duration for constant, mean of normal and exponential
+	StdDev time.Duration `yaml:"stddev"`       // standard deviation of normal
+	Min    time.Duration `yaml:"min"`          // lower bound of uniform
+	Max    time.Duration `yaml:"max"`          // upper bound of uniform
+}
+
+// loadTopology reads and validates the topology described in the YAML file at path.
+func loadTopology(path string) (*topology, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t := &topology{}
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(t); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("topology file %s is empty", path)
+		}
+		return nil, fmt.Errorf("cannot read topology file %s: %w", path, err)
+	}
+
+	if err = t.resolve(); err != nil {
+		return nil, fmt.Errorf("invalid topology file %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// resolve validates the topology and links the operations to their service and callees.
+func (t *topology) resolve() error {
+	if len(t.Services) == 0 {
+		return errors.New("no services defined")
+	}
+
+	services := make(map[string]*service, len(t.Services))
+	for i := range t.Services {
+		svc := &t.Services[i]
+		if svc.Name == "" {
+			return errors.New("service without a name")
+		}
+		if _, ok := services[svc.Name]; ok {
+			return fmt.Errorf("service %q is defined more than once", svc.Name)
+		}
+		services[svc.Name] = svc
+
+		var err error
+		if svc.attributes, err = toAttributes(svc.Attributes); err != nil {
+			return fmt.Errorf("service %q: %w", svc.Name, err)
+		}
+	}
+
+	for i := range t.Services {
+		svc := &t.Services[i]
+		names := map[string]bool{}
+		for i := range svc.Operations {
+			op := &svc.Operations[i]
+			op.service = svc
+			if op.Name == "" {
+				return fmt.Errorf("service %q: operation without a name", svc.Name)
+			}
+			if names[op.Name] {
+				return fmt.Errorf("service %q: operation %q is defined more than once", svc.Name, op.Name)
+			}
+			names[op.Name] = true
+		}
+	}
+
+	for i := range t.Services {
+		svc := &t.Services[i]
+		for i := range svc.Operations {
+			if err := svc.Operations[i].resolve(services); err != nil {
+				return fmt.Errorf("service %q: operation %q: %w", svc.Name, svc.Operations[i].Name, err)
+			}
+		}
+	}
+
+	if len(t.Entrypoints) == 0 {
+		return errors.New("no entrypoints defined")
+	}
+	for i := range t.Entrypoints {
+		e := &t.Entrypoints[i]
+		op, err := findOperation(services, e.Service, e.Operation)
+		if err != nil {
+			return fmt.Errorf("entrypoint: %w", err)
+		}
+		e.operation = op
+		if e.Weight < 0 {
+			return fmt.Errorf("entrypoint %q of service %q: weight must be 0 or greater", e.Operation, e.Service)
+		}
+		if e.Weight == 0 {
+			e.Weight = 1
+		}
+		t.totalWeight += e.Weight
+	}
+
+	for i := range t.Entrypoints {
+		if err := checkCycles(t.Entrypoints[i].operation, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (op *operation) resolve(services map[string]*service) error {
+	switch op.Kind {
+	case "":
+		op.Kind = kindServer
+	case kindServer, kindConsumer, kindInternal:
+	default:
+		return fmt.Errorf("kind must be one of %q, %q or %q, got %q", kindServer, kindConsumer, kindInternal, op.Kind)
+	}
+	if err := op.Duration.validate(); err != nil {
+		return fmt.Errorf("duration: %w", err)
+	}
+	if op.ErrorRate < 0 || op.ErrorRate > 1 {
+		return errors.New("error_rate must be between 0 and 1")
+	}
+
+	var err error
+	if op.attributes, err = toAttributes(op.Attributes); err != nil {
+		return err
+	}
+
+	for i := range op.Calls {
+		c := &op.Calls[i]
+		if c.Service == "" {
+			c.Service = op.service.Name
+		}
+		if c.callee, err = findOperation(services, c.Service, c.Operation); err != nil {
+			return fmt.Errorf("call: %w", err)
+		}
+		if c.callee.Kind == kindInternal && c.callee.service != op.service {
+			return fmt.Errorf("call: internal operation %q of service %q cannot be called from another service", c.Operation, c.Service)
+		}
+		if c.Count < 0 {
+			return fmt.Errorf("call to %q: count must be 0 or greater", c.Operation)
+		}
+		if c.Count == 0 {
+			c.Count = 1
+		}
+		if err = c.Latency.validate(); err != nil {
+			return fmt.Errorf("call to %q: latency: %w", c.Operation, err)
+		}
+		if c.ErrorRate < 0 || c.ErrorRate > 1 {
+			return fmt.Errorf("call to %q: error_rate must be between 0 and 1", c.Operation)
+		}
+	}
+	return nil
+}
+
+func findOperation(services map[string]*service, serviceName, operationName string) (*operation, error) {
+	svc, ok := services[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %q", serviceName)
+	}
+	for i := range svc.Operations {
+		if svc.Operations[i].Name == operationName {
+			return &svc.Operations[i], nil
+		}
+	}
+	return nil, fmt.Errorf("service %q has no operation %q", serviceName, operationName)
+}
+
+// checkCycles returns an error if op calls itself, directly or through other operations.
+func checkCycles(op *operation, path []*operation) error {
+	for i, o := range path {
+		if o == op {
+			var names []string
+			for _, o := range append(path[i:], op) {
+				names = append(names, o.service.Name+"/"+o.Name)
+			}
+			return fmt.Errorf("operations calling each other in a cycle: %s", strings.Join(names, " -> "))
+		}
+	}
+	for i := range op.Calls {
+		if err := checkCycles(op.Calls[i].callee, append(path, op)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toAttributes(m map[string]any) ([]attribute.KeyValue, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]attribute.KeyValue, 0, len(m))
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case string:
+			attributes = append(attributes, attribute.String(k, v))
+		case bool:
+			attributes = append(attributes, attribute.Bool(k, v))
+		case int:
+			attributes = append(attributes, attribute.Int(k, v))
+		case float64:
+			attributes = append(attributes, attribute.Float64(k, v))
+		default:
+			return nil, fmt.Errorf("attribute %q: unsupported value type %T", k, v)
+		}
+	}
+	return attributes, nil
+}
+
+// setTracers creates the tracer of every service, recording the spans with a resource
+// made of the service name, the service attributes and the given resource attributes.
+func (t *topology) setTracers(resourceAttributes []attribute.KeyValue, processors ...sdktrace.SpanProcessor) {
+	for i := range t.Services {
+		svc := &t.Services[i]
+
+		attributes := []attribute.KeyValue{semconv.ServiceNameKey.String(svc.Name)}
+		attributes = append(attributes, svc.attributes...)
+		// may be overridden by `--otlp-attributes`
+		attributes = append(attributes, resourceAttributes...)
+
+		tracerProvider := sdktrace.NewTracerProvider(
+			sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attributes...)),
+		)
+		for _, p := range processors {
+			tracerProvider.RegisterSpanProcessor(p)
+		}
+		svc.tracer = tracerProvider.Tracer("telemetrygen")
+	}
+}
+
+// entrypoint picks the operation starting the next trace.
+func (t *topology) entrypoint(r *rand.Rand) *operation {
+	n := r.Float64() * t.totalWeight
+	for _, e := range t.Entrypoints {
+		if n < e.Weight {
+			return e.operation
+		}
+		n -= e.Weight
+	}
+	return t.Entrypoints[len(t.Entrypoints)-1].operation
+}
+
+func (d distribution) validate() error {
+	switch d.Type {
+	case "", distributionConstant, distributionExponential:
+		if d.Mean < 0 {
+			return errors.New("mean must be 0 or greater")
+		}
+	case distributionNormal:
+		if d.Mean < 0 || d.StdDev < 0 {
+			return errors.New("mean and stddev must be 0 or greater")
+		}
+	case distributionUniform:
+		if d.Min < 0 || d.Max < d.Min {
+			return errors.New("min must be 0 or greater, and max must be min or greater")
+		}
+	default:
+		return fmt.Errorf("distribution must be one of %q, %q, %q or %q, got %q",
+			distributionConstant, distributionUniform, distributionNormal, distributionExponential, d.Type)
+	}
+	return nil
+}
+
+// sample returns a random duration following the distribution, never negative.
+func (d distribution) sample(r *rand.Rand) time.Duration {
+	var v time.Duration
+	switch d.Type {
+	case distributionUniform:
+		v = d.Min + time.Duration(r.Int63n(int64(d.Max-d.Min)+1))
+	case distributionNormal:
+		v = d.Mean + time.Duration(r.NormFloat64()*float64(d.StdDev))
+	case distributionExponential:
+		v = time.Duration(r.ExpFloat64() * float64(d.Mean))
+	default:
+		v = d.Mean
+	}
+	if v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func writeTopology(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadTopology(t *testing.T) {
+	topo, err := loadTopology(filepath.Join("testdata", "topology.yaml"))
+	require.NoError(t, err)
+
+	require.Len(t, topo.Services, 5)
+	assert.Equal(t, 4.0, topo.totalWeight)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("deployment.environment", "production")}, topo.Services[0].attributes)
+
+	cart := topo.Services[0].Operations[1]
+	assert.Equal(t, kindServer, cart.Kind)
+	assert.Equal(t, "frontend", cart.Calls[0].Service)
+	assert.Equal(t, &topo.Services[0].Operations[2], cart.Calls[0].callee)
+	assert.Equal(t, 1, cart.Calls[0].Count)
+	assert.Equal(t, time.Millisecond, cart.Calls[1].Latency.Mean)
+
+	placeOrder := topo.Services[1].Operations[0]
+	assert.Equal(t, &topo.Services[3].Operations[0], placeOrder.Calls[1].callee)
+	assert.Equal(t, 3, placeOrder.Calls[1].Count)
+	assert.True(t, placeOrder.Calls[1].Parallel)
+	assert.Equal(t, distribution{Type: distributionNormal, Mean: 2 * time.Millisecond, StdDev: 500 * time.Microsecond}, placeOrder.Calls[1].Latency)
+}
+
+func TestLoadTopologyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology string
+		errMsg   string
+	}{
+		{
+			name:     "empty",
+			topology: "",
+			errMsg:   "is empty",
+		},
+		{
+			name:     "unknown field",
+			topology: "services:\n  - name: a\n    operation: []\n",
+			errMsg:   "field operation not found",
+		},
+		{
+			name:     "no services",
+			topology: "entrypoints: []\n",
+			errMsg:   "no services defined",
+		},
+		{
+			name:     "duplicate service",
+			topology: "services:\n  - name: a\n  - name: a\n",
+			errMsg:   `service "a" is defined more than once`,
+		},
+		{
+			name:     "duplicate operation",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n      - name: op\n",
+			errMsg:   `service "a": operation "op" is defined more than once`,
+		},
+		{
+			name:     "invalid kind",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        kind: client\n",
+			errMsg:   `kind must be one of "server", "consumer" or "internal", got "client"`,
+		},
+		{
+			name:     "invalid error rate",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        error_rate: 2\n",
+			errMsg:   "error_rate must be between 0 and 1",
+		},
+		{
+			name:     "invalid distribution",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        duration:\n          distribution: pareto\n",
+			errMsg:   `distribution must be one of "constant", "uniform", "normal" or "exponential", got "pareto"`,
+		},
+		{
+			name:     "invalid uniform distribution",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        duration:\n          distribution: uniform\n          min: 2ms\n          max: 1ms\n",
+			errMsg:   "max must be min or greater",
+		},
+		{
+			name:     "unsupported attribute",
+			topology: "services:\n  - name: a\n    attributes:\n      list: [1, 2]\n",
+			errMsg:   `service "a": attribute "list": unsupported value type []interface {}`,
+		},
+		{
+			name:     "unknown callee",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        calls:\n          - service: b\n            operation: op\n",
+			errMsg:   `call: unknown service "b"`,
+		},
+		{
+			name:     "internal callee in another service",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n        calls:\n          - service: b\n            operation: op\n  - name: b\n    operations:\n      - name: op\n        kind: internal\n",
+			errMsg:   `internal operation "op" of service "b" cannot be called from another service`,
+		},
+		{
+			name:     "no entrypoints",
+			topology: "services:\n  - name: a\n    operations:\n      - name: op\n",
+			errMsg:   "no entrypoints defined",
+		},
+		{
+			name:     "unknown entrypoint",
+			topology: "entrypoints:\n  - service: a\n    operation: other\nservices:\n  - name: a\n    operations:\n      - name: op\n",
+			errMsg:   `entrypoint: service "a" has no operation "other"`,
+		},
+		{
+			name:     "cycle",
+			topology: "entrypoints:\n  - service: a\n    operation: op\nservices:\n  - name: a\n    operations:\n      - name: op\n        calls:\n          - service: b\n            operation: op\n  - name: b\n    operations:\n      - name: op\n        calls:\n          - service: a\n            operation: op\n",
+			errMsg:   "operations calling each other in a cycle: a/op -> b/op -> a/op",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTopology(writeTopology(t, tt.topology))
+			require.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestDistributionSample(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	assert.Equal(t, 5*time.Millisecond, distribution{Mean: 5 * time.Millisecond}.sample(r))
+	assert.Equal(t, time.Duration(0), distribution{}.sample(r))
+
+	for i := 0; i < 1000; i++ {
+		v := distribution{Type: distributionUniform, Min: time.Millisecond, Max: 2 * time.Millisecond}.sample(r)
+		assert.GreaterOrEqual(t, v, time.Millisecond)
+		assert.LessOrEqual(t, v, 2*time.Millisecond)
+
+		assert.GreaterOrEqual(t, distribution{Type: distributionNormal, Mean: time.Millisecond, StdDev: time.Second}.sample(r), time.Duration(0))
+		assert.GreaterOrEqual(t, distribution{Type: distributionExponential, Mean: time.Millisecond}.sample(r), time.Duration(0))
+	}
+}
+
+func runTestTopology(t *testing.T, topo *topology, numTraces int) []sdktrace.ReadOnlySpan {
+	syncer := &mockSyncer{}
+	topo.setTracers([]attribute.KeyValue{attribute.String("k", "v")}, sdktrace.NewSimpleSpanProcessor(syncer))
+
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount:         1,
+			TelemetryAttributes: common.KeyValue{telemetryAttrKeyOne: telemetryAttrValueOne},
+		},
+		NumTraces: numTraces,
+	}
+	require.NoError(t, runTopology(cfg, topo, zap.NewNop()))
+	return syncer.spans
+}
+
+func TestRunTopology(t *testing.T) {
+	topo, err := loadTopology(filepath.Join("testdata", "topology.yaml"))
+	require.NoError(t, err)
+
+	spans := runTestTopology(t, topo, 50)
+
+	traces := map[trace.TraceID][]sdktrace.ReadOnlySpan{}
+	byID := map[trace.SpanID]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		traces[span.SpanContext().TraceID()] = append(traces[span.SpanContext().TraceID()], span)
+		byID[span.SpanContext().SpanID()] = span
+		assert.Contains(t, span.Attributes(), attribute.String(telemetryAttrKeyOne, telemetryAttrValueOne))
+		assert.Contains(t, span.Resource().Attributes(), attribute.String("k", "v"))
+	}
+	assert.Len(t, traces, 50)
+
+	serviceName := func(span sdktrace.ReadOnlySpan) string {
+		v, _ := span.Resource().Set().Value(semconv.ServiceNameKey)
+		return v.AsString()
+	}
+
+	for _, span := range spans {
+		parent, ok := byID[span.Parent().SpanID()]
+		if !span.Parent().IsValid() {
+			assert.Equal(t, "frontend", serviceName(span))
+			assert.Contains(t, []string{"GET /checkout", "GET /cart"}, span.Name())
+			continue
+		}
+		require.True(t, ok)
+		assert.False(t, span.StartTime().Before(parent.StartTime()))
+
+		switch span.SpanKind() {
+		case trace.SpanKindClient, trace.SpanKindProducer:
+			// the client span is recorded by the calling service, and named after the callee
+			assert.Equal(t, serviceName(parent), serviceName(span))
+			attrs := attribute.NewSet(span.Attributes()...)
+			peer, _ := attrs.Value(semconv.PeerServiceKey)
+			assert.NotEqual(t, serviceName(span), peer.AsString())
+		case trace.SpanKindServer, trace.SpanKindConsumer:
+			assert.Contains(t, []trace.SpanKind{trace.SpanKindClient, trace.SpanKindProducer}, parent.SpanKind())
+			assert.Equal(t, parent.Name(), span.Name())
+			assert.NotEqual(t, serviceName(parent), serviceName(span))
+			if span.SpanKind() == trace.SpanKindServer {
+				// the client waits for the server
+				assert.False(t, span.EndTime().After(parent.EndTime()))
+			}
+		case trace.SpanKindInternal:
+			assert.Equal(t, "render", span.Name())
+			assert.Equal(t, "GET /cart", parent.Name())
+		}
+
+		if span.Name() == "orders process" && span.SpanKind() == trace.SpanKindConsumer {
+			v, _ := span.Resource().Set().Value("service.version")
+			assert.Equal(t, "1.2.0", v.AsString())
+		}
+	}
+
+	for _, spans := range traces {
+		var root string
+		reserves := 0
+		for _, span := range spans {
+			if !span.Parent().IsValid() {
+				root = span.Name()
+			}
+			if span.Name() == "Reserve" && span.SpanKind() == trace.SpanKindClient {
+				reserves++
+			}
+		}
+		if root == "GET /checkout" {
+			assert.Equal(t, 3, reserves)
+		} else {
+			assert.Equal(t, 0, reserves)
+		}
+	}
+}
+
+func TestRunTopologyErrors(t *testing.T) {
+	topo, err := loadTopology(writeTopology(t, `
+entrypoints:
+  - service: a
+    operation: root
+services:
+  - name: a
+    operations:
+      - name: root
+        duration:
+          mean: 1ms
+        calls:
+          - service: b
+            operation: fails
+          - service: b
+            operation: unreachable
+            error_rate: 1
+  - name: b
+    operations:
+      - name: fails
+        error_rate: 1
+      - name: unreachable
+`))
+	require.NoError(t, err)
+
+	spans := runTestTopology(t, topo, 1)
+
+	status := map[string]codes.Code{}
+	for _, span := range spans {
+		status[span.SpanKind().String()+" "+span.Name()] = span.Status().Code
+	}
+	assert.Equal(t, map[string]codes.Code{
+		"server root":        codes.Unset,
+		"client fails":       codes.Error,
+		"server fails":       codes.Error,
+		"client unreachable": codes.Error,
+	}, status)
+}
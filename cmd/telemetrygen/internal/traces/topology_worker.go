@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces // import "github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/traces"
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+type topologyWorker struct {
+	running             *atomic.Bool         // pointer to shared flag that indicates it's time to stop the test
+	topology            *topology            // service graph the traces follow
+	numTraces           int                  // how many traces the worker has to generate (only when duration==0)
+	limiter             *rate.Limiter        // limits how many spans per second to generate
+	rand                *rand.Rand           // source of the durations, errors and entrypoints
+	telemetryAttributes []attribute.KeyValue // attributes set on every span
+	wg                  *sync.WaitGroup      // notify when done
+	logger              *zap.Logger
+}
+
+func (w topologyWorker) simulateTraces() {
+	var i int
+
+	for w.running.Load() {
+		w.execute(context.Background(), w.topology.entrypoint(w.rand), time.Now())
+
+		i++
+		if w.numTraces != 0 {
+			if i >= w.numTraces {
+				break
+			}
+		}
+	}
+	w.logger.Info("traces generated", zap.Int("traces", i))
+	w.wg.Done()
+}
+
+// execute records the span of op starting at start, and the spans of the calls it makes.
+// Half of the duration of the operation is spent before the calls, and half after.
+// It returns the end of the span, and whether the operation failed.
+func (w topologyWorker) execute(ctx context.Context, op *operation, start time.Time) (time.Time, bool) {
+	w.wait()
+	ctx, sp := op.service.tracer.Start(ctx, op.Name,
+		trace.WithAttributes(op.attributes...),
+		trace.WithSpanKind(spanKind(op.Kind)),
+		trace.WithTimestamp(start),
+	)
+	sp.SetAttributes(w.telemetryAttributes...)
+
+	duration := op.Duration.sample(w.rand)
+	end := start.Add(duration / 2)
+	for i := range op.Calls {
+		c := &op.Calls[i]
+		callsStart, callsEnd := end, end
+		for j := 0; j < c.Count; j++ {
+			if !c.Parallel {
+				callsStart = callsEnd
+			}
+			if callEnd := w.call(ctx, op.service, c, callsStart); callEnd.After(callsEnd) {
+				callsEnd = callEnd
+			}
+		}
+		end = callsEnd
+	}
+	end = end.Add(duration - duration/2)
+
+	failed := w.rand.Float64() < op.ErrorRate
+	if failed {
+		sp.SetStatus(codes.Error, "")
+	}
+	sp.End(trace.WithTimestamp(end))
+	return end, failed
+}
+
+// call records the call from caller to c.callee starting at start, and returns its end.
+// Failures of the callee are reported on the client span, but don't make the caller fail.
+func (w topologyWorker) call(ctx context.Context, caller *service, c *call, start time.Time) time.Time {
+	callee := c.callee
+	if callee.Kind == kindInternal {
+		end, _ := w.execute(ctx, callee, start)
+		return end
+	}
+
+	kind := trace.SpanKindClient
+	if callee.Kind == kindConsumer {
+		kind = trace.SpanKindProducer
+	}
+
+	w.wait()
+	ctx, sp := caller.tracer.Start(ctx, callee.Name,
+		trace.WithAttributes(semconv.PeerServiceKey.String(callee.service.Name)),
+		trace.WithSpanKind(kind),
+		trace.WithTimestamp(start),
+	)
+	sp.SetAttributes(w.telemetryAttributes...)
+
+	latency := c.Latency.sample(w.rand)
+	end := start.Add(latency)
+	failed := w.rand.Float64() < c.ErrorRate
+	switch {
+	case failed:
+		// the call never reached the callee
+	case kind == trace.SpanKindProducer:
+		// messages are consumed asynchronously: the producer doesn't wait for the consumer.
+		w.execute(ctx, callee, end)
+		end = start
+	default:
+		var calleeEnd time.Time
+		calleeEnd, failed = w.execute(ctx, callee, end)
+		end = calleeEnd.Add(latency)
+	}
+
+	if failed {
+		sp.SetStatus(codes.Error, "")
+	}
+	sp.End(trace.WithTimestamp(end))
+	return end
+}
+
+func (w topologyWorker) wait() {
+	if err := w.limiter.Wait(context.Background()); err != nil {
+		w.logger.Fatal("limiter waited failed, retry", zap.Error(err))
+	}
+}
+
+func spanKind(kind string) trace.SpanKind {
+	switch kind {
+	case kindConsumer:
+		return trace.SpanKindConsumer
+	case kindInternal:
+		return trace.SpanKindInternal
+	default:
+		return trace.SpanKindServer
+	}
+}
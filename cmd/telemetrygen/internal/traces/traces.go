@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -33,6 +34,13 @@ func Start(cfg *Config) error {
 		return err
 	}
 
+	var topo *topology
+	if cfg.TopologyFile != "" {
+		if topo, err = loadTopology(cfg.TopologyFile); err != nil {
+			return err
+		}
+	}
+
 	var exp *otlptrace.Exporter
 	if cfg.UseHTTP {
 		var exporterOpts []otlptracehttp.Option
@@ -78,6 +86,20 @@ func Start(cfg *Config) error {
 		}()
 	}
 
+	if topo != nil {
+		var processors []sdktrace.SpanProcessor
+		if cfg.Batch {
+			processors = append(processors, ssp)
+		}
+		topo.setTracers(cfg.GetAttributes(), processors...)
+
+		if err = runTopology(cfg, topo, logger); err != nil {
+			logger.Error("failed to execute the test scenario.", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
 	var attributes []attribute.KeyValue
 	// may be overridden by `--otlp-attributes service.name="foo"`
 	attributes = append(attributes, semconv.ServiceNameKey.String(cfg.ServiceName))
@@ -163,3 +185,51 @@ func Run(c *Config, logger *zap.Logger) error {
 	wg.Wait()
 	return nil
 }
+
+// runTopology executes the test scenario, generating traces following the topology.
+func runTopology(c *Config, topo *topology, logger *zap.Logger) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if c.TotalDuration > 0 {
+		c.NumTraces = 0
+	}
+
+	limit := rate.Limit(c.Rate)
+	if c.Rate == 0 {
+		limit = rate.Inf
+		logger.Info("generation of traces isn't being throttled")
+	} else {
+		logger.Info("generation of traces is limited", zap.Float64("per-second", float64(limit)))
+	}
+
+	wg := sync.WaitGroup{}
+
+	running := &atomic.Bool{}
+	running.Store(true)
+
+	telemetryAttributes := c.GetTelemetryAttributes()
+
+	for i := 0; i < c.WorkerCount; i++ {
+		wg.Add(1)
+		w := topologyWorker{
+			running:             running,
+			topology:            topo,
+			numTraces:           c.NumTraces,
+			limiter:             rate.NewLimiter(limit, 1),
+			rand:                rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))), //nolint:gosec // only used to simulate traffic
+			telemetryAttributes: telemetryAttributes,
+			wg:                  &wg,
+			logger:              logger.With(zap.Int("worker", i)),
+		}
+
+		go w.simulateTraces()
+	}
+	if c.TotalDuration > 0 {
+		time.Sleep(c.TotalDuration)
+		running.Store(false)
+	}
+	wg.Wait()
+	return nil
+}
@@ -28,6 +28,9 @@ const (
 
 	defaultMetricNameLogs = "log.record.count"
 	defaultMetricDescLogs = "The number of log records observed."
+
+	// samplingThresholdAttribute is the log record attribute holding the threshold used to sample it.
+	samplingThresholdAttribute = "sampling.threshold"
 )
 
 // Config for the connector
@@ -37,6 +40,10 @@ type Config struct {
 	Metrics    map[string]MetricInfo `mapstructure:"metrics"`
 	DataPoints map[string]MetricInfo `mapstructure:"datapoints"`
 	Logs       map[string]MetricInfo `mapstructure:"logs"`
+
+	// AdjustedCounts makes a sampled span, span event or log record add the number of items it
+	// represents to the counts instead of 1. Log records carry it in the sampling.threshold attribute.
+	AdjustedCounts bool `mapstructure:"adjusted_counts"`
 }
 
 // MetricInfo for a data type
@@ -13,6 +13,9 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/connector/connectortest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
@@ -692,3 +695,87 @@ func TestLogsToMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestAdjustedCounts(t *testing.T) {
+	testCases := []struct {
+		name           string
+		adjustedCounts bool
+		expectedSpans  int64
+		expectedEvents int64
+		expectedLogs   int64
+	}{
+		{
+			name:           "disabled",
+			expectedSpans:  3,
+			expectedEvents: 3,
+			expectedLogs:   3,
+		},
+		{
+			name:           "enabled",
+			adjustedCounts: true,
+			expectedSpans:  6,
+			expectedEvents: 6,
+			expectedLogs:   6,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				Spans:          defaultSpansConfig(),
+				SpanEvents:     defaultSpanEventsConfig(),
+				Logs:           defaultLogsConfig(),
+				AdjustedCounts: tc.adjustedCounts,
+			}
+			require.NoError(t, cfg.Validate())
+			factory := NewFactory()
+
+			tracesSink := &consumertest.MetricsSink{}
+			tracesConn, err := factory.CreateTracesToMetrics(context.Background(), connectortest.NewNopSettings(), cfg, tracesSink)
+			require.NoError(t, err)
+			traces := ptrace.NewTraces()
+			spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			// sampled at 25%, not sampled and with an invalid threshold
+			for _, traceState := range []string{"ot=th:c", "", "ot=th:invalid"} {
+				span := spans.AppendEmpty()
+				span.TraceState().FromRaw(traceState)
+				span.Events().AppendEmpty()
+			}
+			require.NoError(t, tracesConn.ConsumeTraces(context.Background(), traces))
+			require.Len(t, tracesSink.AllMetrics(), 1)
+			assert.Equal(t, map[string]int64{
+				defaultMetricNameSpans:      tc.expectedSpans,
+				defaultMetricNameSpanEvents: tc.expectedEvents,
+			}, countValues(tracesSink.AllMetrics()[0]))
+
+			logsSink := &consumertest.MetricsSink{}
+			logsConn, err := factory.CreateLogsToMetrics(context.Background(), connectortest.NewNopSettings(), cfg, logsSink)
+			require.NoError(t, err)
+			logs := plog.NewLogs()
+			records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			for _, tvalue := range []string{"c", "", "invalid"} {
+				record := records.AppendEmpty()
+				if tvalue != "" {
+					record.Attributes().PutStr(samplingThresholdAttribute, tvalue)
+				}
+			}
+			require.NoError(t, logsConn.ConsumeLogs(context.Background(), logs))
+			require.Len(t, logsSink.AllMetrics(), 1)
+			assert.Equal(t, map[string]int64{
+				defaultMetricNameLogs: tc.expectedLogs,
+			}, countValues(logsSink.AllMetrics()[0]))
+		})
+	}
+}
+
+func countValues(md pmetric.Metrics) map[string]int64 {
+	values := make(map[string]int64)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		dps := metrics.At(i).Sum().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			values[metrics.At(i).Name()] += dps.At(j).IntValue()
+		}
+	}
+	return values
+}
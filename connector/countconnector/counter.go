@@ -35,7 +35,8 @@ type attrCounter struct {
 	count uint64
 }
 
-func (c *counter[K]) update(ctx context.Context, attrs pcommon.Map, tCtx K) error {
+// update adds count to the metrics matching the telemetry.
+func (c *counter[K]) update(ctx context.Context, attrs pcommon.Map, tCtx K, count uint64) error {
 	var multiError error
 	for name, md := range c.metricDefs {
 		countAttrs := pcommon.NewMap()
@@ -74,20 +75,20 @@ func (c *counter[K]) update(ctx context.Context, attrs pcommon.Map, tCtx K) erro
 
 		// No conditions, so match all.
 		if md.condition == nil {
-			multiError = errors.Join(multiError, c.increment(name, countAttrs))
+			multiError = errors.Join(multiError, c.increment(name, countAttrs, count))
 			continue
 		}
 
 		if match, err := md.condition.Eval(ctx, tCtx); err != nil {
 			multiError = errors.Join(multiError, err)
 		} else if match {
-			multiError = errors.Join(multiError, c.increment(name, countAttrs))
+			multiError = errors.Join(multiError, c.increment(name, countAttrs, count))
 		}
 	}
 	return multiError
 }
 
-func (c *counter[K]) increment(metricName string, attrs pcommon.Map) error {
+func (c *counter[K]) increment(metricName string, attrs pcommon.Map, count uint64) error {
 	if _, ok := c.counts[metricName]; !ok {
 		c.counts[metricName] = make(map[[16]byte]*attrCounter)
 	}
@@ -101,7 +102,7 @@ func (c *counter[K]) increment(metricName string, attrs pcommon.Map) error {
 		c.counts[metricName][key] = &attrCounter{attrs: attrs}
 	}
 
-	c.counts[metricName][key].count++
+	c.counts[metricName][key].count += count
 	return nil
 }
 
@@ -79,6 +79,7 @@ func createTracesToMetrics(
 		metricsConsumer:      nextConsumer,
 		spansMetricDefs:      spanMetricDefs,
 		spanEventsMetricDefs: spanEventMetricDefs,
+		adjustedCounts:       c.AdjustedCounts,
 	}, nil
 }
 
@@ -151,6 +152,7 @@ func createLogsToMetrics(
 	return &count{
 		metricsConsumer: nextConsumer,
 		logsMetricDefs:  metricDefs,
+		adjustedCounts:  c.AdjustedCounts,
 	}, nil
 }
 
@@ -49,6 +49,10 @@ type Config struct {
 	// DatabaseNameAttribute is the attribute name used to identify the database name from span attributes.
 	// The default value is db.name.
 	DatabaseNameAttribute string `mapstructure:"database_name_attribute"`
+
+	// AdjustedCounts counts a request as many times as the sampling threshold of its spans implies.
+	// The default value is false.
+	AdjustedCounts bool `mapstructure:"adjusted_counts"`
 }
 
 type StoreConfig struct {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/pdatautil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 const (
@@ -258,6 +260,7 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 						e.ClientLatencySec = spanDuration(span)
 						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
 						p.upsertDimensions(clientKind, e.Dimensions, rAttributes, span.Attributes())
+						p.upsertAdjustedCount(e, span)
 
 						if virtualNodeFeatureGate.IsEnabled() {
 							p.upsertPeerAttributes(p.config.VirtualNodePeerAttributes, e.Peer, span.Attributes())
@@ -285,6 +288,7 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 						e.ServerLatencySec = spanDuration(span)
 						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
 						p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+						p.upsertAdjustedCount(e, span)
 					})
 				default:
 					// this span is not part of an edge
@@ -328,6 +332,17 @@ func (p *serviceGraphConnector) upsertPeerAttributes(m []string, peers map[strin
 	}
 }
 
+// upsertAdjustedCount keeps the highest adjusted count of the spans of the edge: the request is only
+// observed when both its client and server spans are sampled, which happens with the lowest of their
+// sampling probabilities when they are sampled consistently.
+func (p *serviceGraphConnector) upsertAdjustedCount(e *store.Edge, span ptrace.Span) {
+	if !p.config.AdjustedCounts {
+		return
+	}
+	adjustedCount := uint64(math.Round(sampling.TraceStateToAdjustedCount(span.TraceState().AsRaw())))
+	e.AdjustedCount = max(e.AdjustedCount, adjustedCount)
+}
+
 func (p *serviceGraphConnector) onComplete(e *store.Edge) {
 	p.logger.Debug(
 		"edge completed",
@@ -380,12 +395,13 @@ func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 
 	p.seriesMutex.Lock()
 	defer p.seriesMutex.Unlock()
+	count := max(e.AdjustedCount, 1)
 	p.updateSeries(metricKey, dimensions)
-	p.updateCountMetrics(metricKey)
+	p.updateCountMetrics(metricKey, count)
 	if e.Failed {
-		p.updateErrorMetrics(metricKey)
+		p.updateErrorMetrics(metricKey, count)
 	}
-	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec)
+	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec, count)
 }
 
 func (p *serviceGraphConnector) updateSeries(key string, dimensions pcommon.Map) {
@@ -408,33 +424,37 @@ func (p *serviceGraphConnector) dimensionsForSeries(key string) (pcommon.Map, bo
 	return pcommon.Map{}, false
 }
 
-func (p *serviceGraphConnector) updateCountMetrics(key string) { p.reqTotal[key]++ }
+func (p *serviceGraphConnector) updateCountMetrics(key string, count uint64) {
+	p.reqTotal[key] += int64(count)
+}
 
-func (p *serviceGraphConnector) updateErrorMetrics(key string) { p.reqFailedTotal[key]++ }
+func (p *serviceGraphConnector) updateErrorMetrics(key string, count uint64) {
+	p.reqFailedTotal[key] += int64(count)
+}
 
-func (p *serviceGraphConnector) updateDurationMetrics(key string, serverDuration, clientDuration float64) {
-	p.updateServerDurationMetrics(key, serverDuration)
-	p.updateClientDurationMetrics(key, clientDuration)
+func (p *serviceGraphConnector) updateDurationMetrics(key string, serverDuration, clientDuration float64, count uint64) {
+	p.updateServerDurationMetrics(key, serverDuration, count)
+	p.updateClientDurationMetrics(key, clientDuration, count)
 }
 
-func (p *serviceGraphConnector) updateServerDurationMetrics(key string, duration float64) {
+func (p *serviceGraphConnector) updateServerDurationMetrics(key string, duration float64, count uint64) {
 	index := sort.SearchFloat64s(p.reqDurationBounds, duration) // Search bucket index
 	if _, ok := p.reqServerDurationSecondsBucketCounts[key]; !ok {
 		p.reqServerDurationSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
 	}
-	p.reqServerDurationSecondsSum[key] += duration
-	p.reqServerDurationSecondsCount[key]++
-	p.reqServerDurationSecondsBucketCounts[key][index]++
+	p.reqServerDurationSecondsSum[key] += duration * float64(count)
+	p.reqServerDurationSecondsCount[key] += count
+	p.reqServerDurationSecondsBucketCounts[key][index] += count
 }
 
-func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration float64) {
+func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration float64, count uint64) {
 	index := sort.SearchFloat64s(p.reqDurationBounds, duration) // Search bucket index
 	if _, ok := p.reqClientDurationSecondsBucketCounts[key]; !ok {
 		p.reqClientDurationSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
 	}
-	p.reqClientDurationSecondsSum[key] += duration
-	p.reqClientDurationSecondsCount[key]++
-	p.reqClientDurationSecondsBucketCounts[key][index]++
+	p.reqClientDurationSecondsSum[key] += duration * float64(count)
+	p.reqClientDurationSecondsCount[key] += count
+	p.reqClientDurationSecondsBucketCounts[key][index] += count
 }
 
 func buildDimensions(e *store.Edge) pcommon.Map {
@@ -456,7 +456,7 @@ func TestUpdateDurationMetrics(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.caseStr, func(_ *testing.T) {
-			p.updateDurationMetrics(metricKey, tc.duration, tc.duration)
+			p.updateDurationMetrics(metricKey, tc.duration, tc.duration, 1)
 		})
 	}
 }
@@ -739,3 +739,63 @@ func TestVirtualNodeClientLabels(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestAdjustedCounts(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		enabled       bool
+		expectedCount uint64
+	}{
+		{
+			name:          "disabled",
+			expectedCount: 1,
+		},
+		{
+			// the client span is sampled at 50% and the server span at 25%
+			name:          "enabled",
+			enabled:       true,
+			expectedCount: 4,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				Store: StoreConfig{
+					MaxItems: 10,
+					TTL:      time.Nanosecond,
+				},
+				AdjustedCounts: tc.enabled,
+			}
+			set := componenttest.NewNopTelemetrySettings()
+			set.Logger = zaptest.NewLogger(t)
+			conn, err := newConnector(set, cfg, newMockMetricsExporter())
+			require.NoError(t, err)
+			assert.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				assert.NoError(t, conn.Shutdown(context.Background()))
+			}()
+
+			td := buildSampleTrace(t, "val")
+			spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+			spans.At(0).TraceState().FromRaw("ot=th:8")
+			spans.At(1).TraceState().FromRaw("ot=th:c")
+			assert.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+			md, err := conn.buildMetrics()
+			require.NoError(t, err)
+			ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 3, ms.Len())
+			for i := 0; i < ms.Len(); i++ {
+				m := ms.At(i)
+				switch m.Type() {
+				case pmetric.MetricTypeSum:
+					assert.Equal(t, int64(tc.expectedCount), m.Sum().DataPoints().At(0).IntValue())
+				case pmetric.MetricTypeHistogram:
+					dp := m.Histogram().DataPoints().At(0)
+					assert.Equal(t, tc.expectedCount, dp.Count())
+					// the client request lasts 1s and the server one 2s
+					assert.Contains(t, []float64{1, 2}, dp.Sum()/float64(dp.Count()))
+				}
+			}
+		})
+	}
+}
@@ -50,6 +50,10 @@ type Edge struct {
 
 	// VirtualNodeLabel is an optional label to be added to the spans
 	VirtualNodeLabel VirtualNodeLabel
+
+	// AdjustedCount is the number of requests represented by the Edge when its spans were sampled,
+	// zero if it is unknown.
+	AdjustedCount uint64
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
@@ -3,7 +3,33 @@
 
 package config // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/customottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+)
+
+const (
+	// defaultExponentialHistogramMaxSize is the default maximum number
+	// of buckets per positive or negative number range.
+	defaultExponentialHistogramMaxSize = 160
+)
+
+var defaultHistogramBuckets = []float64{
+	2, 4, 6, 8, 10, 50, 100, 200, 400, 800, 1000, 1400, 2000, 5000, 10_000, 15_000,
+}
+
+var _ confmap.Unmarshaler = (*Config)(nil)
 
 // Config for the connector. Each configuration field describes the metrics
 // to produce from a specific signal.
@@ -17,6 +43,74 @@ func (c *Config) Validate() error {
 	if len(c.Spans) == 0 && len(c.Datapoints) == 0 && len(c.Logs) == 0 {
 		return fmt.Errorf("no configuration provided, at least one should be specified")
 	}
+	var multiError error // collect all errors at once
+	if len(c.Spans) > 0 {
+		parser, err := ottlspan.NewParser(
+			customottl.SpanFuncs(),
+			component.TelemetrySettings{Logger: zap.NewNop()},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create parser for OTTL spans: %w", err)
+		}
+		for _, span := range c.Spans {
+			if err := validateMetricInfo(span, parser); err != nil {
+				multiError = errors.Join(multiError, fmt.Errorf("failed to validate spans configuration: %w", err))
+			}
+		}
+	}
+	if len(c.Datapoints) > 0 {
+		parser, err := ottldatapoint.NewParser(
+			customottl.DatapointFuncs(),
+			component.TelemetrySettings{Logger: zap.NewNop()},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create parser for OTTL datapoints: %w", err)
+		}
+		for _, dp := range c.Datapoints {
+			if err := validateMetricInfo(dp, parser); err != nil {
+				multiError = errors.Join(multiError, fmt.Errorf("failed to validate datapoints configuration: %w", err))
+			}
+		}
+	}
+	if len(c.Logs) > 0 {
+		parser, err := ottllog.NewParser(
+			customottl.LogFuncs(),
+			component.TelemetrySettings{Logger: zap.NewNop()},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create parser for OTTL logs: %w", err)
+		}
+		for _, log := range c.Logs {
+			if err := validateMetricInfo(log, parser); err != nil {
+				multiError = errors.Join(multiError, fmt.Errorf("failed to validate logs configuration: %w", err))
+			}
+		}
+	}
+	return multiError
+}
+
+// Unmarshal implements the confmap.Unmarshaler interface. It allows
+// unmarshaling the config with a custom logic to allow setting
+// default values when/if required.
+func (c *Config) Unmarshal(collectorCfg *confmap.Conf) error {
+	if collectorCfg == nil {
+		return nil
+	}
+	if err := collectorCfg.Unmarshal(c, confmap.WithIgnoreUnused()); err != nil {
+		return err
+	}
+	for i, info := range c.Spans {
+		info.ensureDefaults()
+		c.Spans[i] = info
+	}
+	for i, info := range c.Datapoints {
+		info.ensureDefaults()
+		c.Datapoints[i] = info
+	}
+	for i, info := range c.Logs {
+		info.ensureDefaults()
+		c.Logs[i] = info
+	}
 	return nil
 }
 
@@ -40,6 +134,138 @@ type MetricInfo struct {
 	Sum                  *Sum                  `mapstructure:"sum"`
 }
 
+func (mi *MetricInfo) ensureDefaults() {
+	if mi.Histogram != nil {
+		// Add default buckets if explicit histogram is defined
+		if len(mi.Histogram.Buckets) == 0 {
+			mi.Histogram.Buckets = defaultHistogramBuckets
+		}
+	}
+	if mi.ExponentialHistogram != nil {
+		if mi.ExponentialHistogram.MaxSize == 0 {
+			mi.ExponentialHistogram.MaxSize = defaultExponentialHistogramMaxSize
+		}
+	}
+}
+
+func (mi *MetricInfo) validateAttributes() error {
+	if err := validateAttributeList(mi.Attributes); err != nil {
+		return err
+	}
+	if err := validateAttributeList(mi.IncludeResourceAttributes); err != nil {
+		return fmt.Errorf("include_resource_attributes: %w", err)
+	}
+	return nil
+}
+
+func validateAttributeList(attrs []Attribute) error {
+	tmp := pcommon.NewValueEmpty()
+	duplicate := map[string]struct{}{}
+	for _, attr := range attrs {
+		if attr.Key == "" {
+			return errors.New("attribute key missing")
+		}
+		if _, ok := duplicate[attr.Key]; ok {
+			return fmt.Errorf("duplicate key found in attributes config: %s", attr.Key)
+		}
+		if err := tmp.FromRaw(attr.DefaultValue); err != nil {
+			return fmt.Errorf("invalid default value specified for attribute %s", attr.Key)
+		}
+		duplicate[attr.Key] = struct{}{}
+	}
+	return nil
+}
+
+func (mi *MetricInfo) validateHistogram() error {
+	if mi.Histogram != nil {
+		if len(mi.Histogram.Buckets) == 0 {
+			return errors.New("histogram buckets missing")
+		}
+		for i := 1; i < len(mi.Histogram.Buckets); i++ {
+			if mi.Histogram.Buckets[i] <= mi.Histogram.Buckets[i-1] {
+				return errors.New("histogram buckets must be sorted in strictly increasing order")
+			}
+		}
+		if mi.Histogram.Value == "" {
+			return errors.New("value OTTL statement is required")
+		}
+	}
+	if mi.ExponentialHistogram != nil {
+		if mi.ExponentialHistogram.MaxSize <= 0 {
+			return fmt.Errorf("max size must be a positive number, %d found", mi.ExponentialHistogram.MaxSize)
+		}
+		if mi.ExponentialHistogram.Value == "" {
+			return errors.New("value OTTL statement is required")
+		}
+	}
+	return nil
+}
+
+func (mi *MetricInfo) validateSum() error {
+	if mi.Sum != nil {
+		if mi.Sum.Value == "" {
+			return errors.New("value must be defined for sum metrics")
+		}
+	}
+	return nil
+}
+
+// validateMetricInfo validates all supported metric types defined for the
+// metric info including any OTTL expressions.
+func validateMetricInfo[K any](mi MetricInfo, parser ottl.Parser[K]) error {
+	if mi.Name == "" {
+		return errors.New("missing required metric name configuration")
+	}
+	if err := mi.validateAttributes(); err != nil {
+		return fmt.Errorf("attributes validation failed: %w", err)
+	}
+	if err := mi.validateHistogram(); err != nil {
+		return fmt.Errorf("histogram validation failed: %w", err)
+	}
+	if err := mi.validateSum(); err != nil {
+		return fmt.Errorf("sum validation failed: %w", err)
+	}
+
+	// Exactly one metric should be defined
+	var (
+		metricsDefinedCount int
+		statements          []string
+	)
+	if mi.Histogram != nil {
+		metricsDefinedCount++
+		if mi.Histogram.Count != "" {
+			statements = append(statements, mi.Histogram.Count)
+		}
+		statements = append(statements, mi.Histogram.Value)
+	}
+	if mi.ExponentialHistogram != nil {
+		metricsDefinedCount++
+		if mi.ExponentialHistogram.Count != "" {
+			statements = append(statements, mi.ExponentialHistogram.Count)
+		}
+		statements = append(statements, mi.ExponentialHistogram.Value)
+	}
+	if mi.Sum != nil {
+		metricsDefinedCount++
+		statements = append(statements, mi.Sum.Value)
+	}
+	if metricsDefinedCount != 1 {
+		return fmt.Errorf("exactly one of the metrics must be defined, %d found", metricsDefinedCount)
+	}
+
+	if _, err := parser.ParseConditions(mi.Conditions); err != nil {
+		return fmt.Errorf("failed to parse OTTL conditions: %w", err)
+	}
+	// Only the syntax of the OTTL expressions is validated here, presence of
+	// the required expressions is checked by the metric specific validations.
+	for _, statement := range statements {
+		if _, err := parser.ParseValueExpression(statement); err != nil {
+			return fmt.Errorf("failed to parse OTTL statement %q: %w", statement, err)
+		}
+	}
+	return nil
+}
+
 type Attribute struct {
 	Key          string `mapstructure:"key"`
 	DefaultValue any    `mapstructure:"default_value"`
@@ -25,6 +25,89 @@ func TestConfig(t *testing.T) {
 			path:      "empty",
 			errorMsgs: []string{"no configuration provided"},
 		},
+		{
+			path:      "without_name",
+			errorMsgs: []string{"missing required metric name configuration"},
+		},
+		{
+			path:      "multiple_metric",
+			errorMsgs: []string{"exactly one of the metrics must be defined, 2 found"},
+		},
+		{
+			path: "invalid_ottl_value_expression",
+			errorMsgs: []string{
+				`failed to parse OTTL statement "invalid(attributes[\"foo\"]"`,
+				`failed to parse OTTL statement "attributes["`,
+				`failed to parse OTTL statement "Unknown()"`,
+			},
+		},
+		{
+			path:      "invalid_ottl_conditions",
+			errorMsgs: []string{"failed to parse OTTL conditions"},
+		},
+		{
+			path: "invalid_histogram",
+			errorMsgs: []string{
+				"histogram buckets must be sorted in strictly increasing order",
+				"value OTTL statement is required",
+			},
+		},
+		{
+			path:      "duplicate_attributes",
+			errorMsgs: []string{"duplicate key found in attributes config: foo"},
+		},
+		{
+			path: "valid_full",
+			expected: &Config{
+				Spans: []MetricInfo{
+					{
+						Name:        "span.exp_histogram",
+						Description: "Exponential histogram with defaults",
+						Unit:        "ms",
+						IncludeResourceAttributes: []Attribute{
+							{Key: "resource.foo"},
+							{Key: "resource.bar", DefaultValue: "bar"},
+						},
+						Attributes: []Attribute{
+							{Key: "foo"},
+							{Key: "bar", DefaultValue: "bar"},
+						},
+						Conditions: []string{`attributes["foo"] != nil`},
+						ExponentialHistogram: &ExponentialHistogram{
+							MaxSize: defaultExponentialHistogramMaxSize,
+							Count:   "Int(AdjustedCount())",
+							Value:   "Milliseconds(end_time - start_time)",
+						},
+					},
+					{
+						Name:        "span.histogram",
+						Description: "Histogram with defaults",
+						Histogram: &Histogram{
+							Buckets: defaultHistogramBuckets,
+							Value:   "Milliseconds(end_time - start_time)",
+						},
+					},
+				},
+				Datapoints: []MetricInfo{
+					{
+						Name: "datapoint.sum",
+						Sum: &Sum{
+							Value: "Double(value_int) + value_double",
+						},
+					},
+				},
+				Logs: []MetricInfo{
+					{
+						Name: "log.histogram",
+						Histogram: &Histogram{
+							Buckets: []float64{1, 10, 100},
+							Count:   "1",
+							Value:   "Len(body)",
+						},
+					},
+				},
+			},
+		},
 	} {
 		t.Run(tc.path, func(t *testing.T) {
 			dir := filepath.Join("..", "testdata", "configs")
@@ -5,46 +5,209 @@ package signaltometricsconnector // import "github.com/open-telemetry/openteleme
 
 import (
 	"context"
+	"fmt"
+	"math"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 type signalToMetrics struct {
 	next   consumer.Metrics
 	logger *zap.Logger
 
+	collectorInstanceInfo model.CollectorInstanceInfo
+	spanMetricDefs        []model.MetricDef[ottlspan.TransformContext]
+	dpMetricDefs          []model.MetricDef[ottldatapoint.TransformContext]
+	logMetricDefs         []model.MetricDef[ottllog.TransformContext]
+
 	component.StartFunc
 	component.ShutdownFunc
 }
 
-func newSignalToMetrics(
-	set connector.Settings,
-	next consumer.Metrics,
-) *signalToMetrics {
-	return &signalToMetrics{
-		logger: set.Logger,
-		next:   next,
-	}
-}
-
 func (sm *signalToMetrics) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
-func (sm *signalToMetrics) ConsumeTraces(context.Context, ptrace.Traces) error {
-	return nil
+func (sm *signalToMetrics) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	if len(sm.spanMetricDefs) == 0 {
+		return nil
+	}
+
+	processedMetrics := pmetric.NewMetrics()
+	processedMetrics.ResourceMetrics().EnsureCapacity(td.ResourceSpans().Len())
+	agg := aggregator.NewAggregator[ottlspan.TransformContext](processedMetrics)
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		resourceSpan := td.ResourceSpans().At(i)
+		resourceAttrs := resourceSpan.Resource().Attributes()
+		for j := 0; j < resourceSpan.ScopeSpans().Len(); j++ {
+			scopeSpan := resourceSpan.ScopeSpans().At(j)
+			for k := 0; k < scopeSpan.Spans().Len(); k++ {
+				span := scopeSpan.Spans().At(k)
+				spanAttrs := span.Attributes()
+				adjustedCount := uint64(math.Round(sampling.TraceStateToAdjustedCount(span.TraceState().AsRaw())))
+				tCtx := ottlspan.NewTransformContext(span, scopeSpan.Scope(), resourceSpan.Resource(), scopeSpan, resourceSpan)
+				for _, md := range sm.spanMetricDefs {
+					if md.Conditions != nil {
+						match, err := md.Conditions.Eval(ctx, tCtx)
+						if err != nil {
+							return fmt.Errorf("failed to evaluate conditions: %w", err)
+						}
+						if !match {
+							sm.logger.Debug("condition not matched, skipping", zap.String("name", md.Key.Name))
+							continue
+						}
+					}
+
+					filteredSpanAttrs := md.FilterAttributes(spanAttrs)
+					filteredResAttrs := md.FilterResourceAttributes(resourceAttrs, &sm.collectorInstanceInfo)
+					if err := agg.Aggregate(ctx, tCtx, md, filteredResAttrs, filteredSpanAttrs, adjustedCount); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	agg.Finalize(sm.spanMetricDefs)
+	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
 
-func (sm *signalToMetrics) ConsumeMetrics(context.Context, pmetric.Metrics) error {
-	return nil
+func (sm *signalToMetrics) ConsumeMetrics(ctx context.Context, m pmetric.Metrics) error {
+	if len(sm.dpMetricDefs) == 0 {
+		return nil
+	}
+
+	processedMetrics := pmetric.NewMetrics()
+	processedMetrics.ResourceMetrics().EnsureCapacity(m.ResourceMetrics().Len())
+	agg := aggregator.NewAggregator[ottldatapoint.TransformContext](processedMetrics)
+	for i := 0; i < m.ResourceMetrics().Len(); i++ {
+		resourceMetric := m.ResourceMetrics().At(i)
+		resourceAttrs := resourceMetric.Resource().Attributes()
+		for j := 0; j < resourceMetric.ScopeMetrics().Len(); j++ {
+			scopeMetric := resourceMetric.ScopeMetrics().At(j)
+			for k := 0; k < scopeMetric.Metrics().Len(); k++ {
+				metrics := scopeMetric.Metrics()
+				metric := metrics.At(k)
+				for _, md := range sm.dpMetricDefs {
+					aggregate := func(dp any, dpAttrs pcommon.Map) error {
+						tCtx := ottldatapoint.NewTransformContext(dp, metric, metrics, scopeMetric.Scope(), resourceMetric.Resource(), scopeMetric, resourceMetric)
+						if md.Conditions != nil {
+							match, err := md.Conditions.Eval(ctx, tCtx)
+							if err != nil {
+								return fmt.Errorf("failed to evaluate conditions: %w", err)
+							}
+							if !match {
+								sm.logger.Debug("condition not matched, skipping", zap.String("name", md.Key.Name))
+								return nil
+							}
+						}
+						filteredResAttrs := md.FilterResourceAttributes(resourceAttrs, &sm.collectorInstanceInfo)
+						return agg.Aggregate(ctx, tCtx, md, filteredResAttrs, md.FilterAttributes(dpAttrs), 1)
+					}
+
+					//exhaustive:enforce
+					switch metric.Type() {
+					case pmetric.MetricTypeGauge:
+						dps := metric.Gauge().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if err := aggregate(dp, dp.Attributes()); err != nil {
+								return err
+							}
+						}
+					case pmetric.MetricTypeSum:
+						dps := metric.Sum().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if err := aggregate(dp, dp.Attributes()); err != nil {
+								return err
+							}
+						}
+					case pmetric.MetricTypeSummary:
+						dps := metric.Summary().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if err := aggregate(dp, dp.Attributes()); err != nil {
+								return err
+							}
+						}
+					case pmetric.MetricTypeHistogram:
+						dps := metric.Histogram().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if err := aggregate(dp, dp.Attributes()); err != nil {
+								return err
+							}
+						}
+					case pmetric.MetricTypeExponentialHistogram:
+						dps := metric.ExponentialHistogram().DataPoints()
+						for l := 0; l < dps.Len(); l++ {
+							dp := dps.At(l)
+							if err := aggregate(dp, dp.Attributes()); err != nil {
+								return err
+							}
+						}
+					case pmetric.MetricTypeEmpty:
+						continue
+					}
+				}
+			}
+		}
+	}
+	agg.Finalize(sm.dpMetricDefs)
+	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
 
-func (sm *signalToMetrics) ConsumeLogs(context.Context, plog.Logs) error {
-	return nil
+func (sm *signalToMetrics) ConsumeLogs(ctx context.Context, logs plog.Logs) error {
+	if len(sm.logMetricDefs) == 0 {
+		return nil
+	}
+
+	processedMetrics := pmetric.NewMetrics()
+	processedMetrics.ResourceMetrics().EnsureCapacity(logs.ResourceLogs().Len())
+	agg := aggregator.NewAggregator[ottllog.TransformContext](processedMetrics)
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		resourceLog := logs.ResourceLogs().At(i)
+		resourceAttrs := resourceLog.Resource().Attributes()
+		for j := 0; j < resourceLog.ScopeLogs().Len(); j++ {
+			scopeLog := resourceLog.ScopeLogs().At(j)
+			for k := 0; k < scopeLog.LogRecords().Len(); k++ {
+				log := scopeLog.LogRecords().At(k)
+				logAttrs := log.Attributes()
+				tCtx := ottllog.NewTransformContext(log, scopeLog.Scope(), resourceLog.Resource(), scopeLog, resourceLog)
+				for _, md := range sm.logMetricDefs {
+					if md.Conditions != nil {
+						match, err := md.Conditions.Eval(ctx, tCtx)
+						if err != nil {
+							return fmt.Errorf("failed to evaluate conditions: %w", err)
+						}
+						if !match {
+							sm.logger.Debug("condition not matched, skipping", zap.String("name", md.Key.Name))
+							continue
+						}
+					}
+					filteredLogAttrs := md.FilterAttributes(logAttrs)
+					filteredResAttrs := md.FilterResourceAttributes(resourceAttrs, &sm.collectorInstanceInfo)
+					if err := agg.Aggregate(ctx, tCtx, md, filteredResAttrs, filteredLogAttrs, 1); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	agg.Finalize(sm.logMetricDefs)
+	return sm.next.ConsumeMetrics(ctx, processedMetrics)
 }
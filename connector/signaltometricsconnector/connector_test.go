@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package signaltometricsconnector
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	semconv "go.opentelemetry.io/collector/semconv/v1.26.0"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
+)
+
+const testDataDir = "testdata"
+
+var testCases = []string{
+	"sum",
+	"histograms",
+	"exponential_histograms",
+}
+
+func TestConnectorWithTraces(t *testing.T) {
+	ctx := context.Background()
+	factory := NewFactory()
+	settings := testSettings(t)
+	next := &consumertest.MetricsSink{}
+
+	traceTestDataDir := filepath.Join(testDataDir, "traces")
+	inputTraces, err := golden.ReadTraces(filepath.Join(traceTestDataDir, "traces.yaml"))
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			next.Reset()
+			cfg := setupConfig(t, filepath.Join(traceTestDataDir, tc, "config.yaml"))
+			connector, err := factory.CreateTracesToMetrics(ctx, settings, cfg, next)
+			require.NoError(t, err)
+			require.NoError(t, connector.ConsumeTraces(ctx, inputTraces))
+			require.Len(t, next.AllMetrics(), 1)
+
+			assertAggregatedMetrics(t, filepath.Join(traceTestDataDir, tc, "output.yaml"), next.AllMetrics()[0])
+		})
+	}
+}
+
+func TestConnectorWithMetrics(t *testing.T) {
+	ctx := context.Background()
+	factory := NewFactory()
+	settings := testSettings(t)
+	next := &consumertest.MetricsSink{}
+
+	metricTestDataDir := filepath.Join(testDataDir, "metrics")
+	inputMetrics, err := golden.ReadMetrics(filepath.Join(metricTestDataDir, "metrics.yaml"))
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			next.Reset()
+			cfg := setupConfig(t, filepath.Join(metricTestDataDir, tc, "config.yaml"))
+			connector, err := factory.CreateMetricsToMetrics(ctx, settings, cfg, next)
+			require.NoError(t, err)
+			require.NoError(t, connector.ConsumeMetrics(ctx, inputMetrics))
+			require.Len(t, next.AllMetrics(), 1)
+
+			assertAggregatedMetrics(t, filepath.Join(metricTestDataDir, tc, "output.yaml"), next.AllMetrics()[0])
+		})
+	}
+}
+
+func TestConnectorWithLogs(t *testing.T) {
+	ctx := context.Background()
+	factory := NewFactory()
+	settings := testSettings(t)
+	next := &consumertest.MetricsSink{}
+
+	logTestDataDir := filepath.Join(testDataDir, "logs")
+	inputLogs, err := golden.ReadLogs(filepath.Join(logTestDataDir, "logs.yaml"))
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			next.Reset()
+			cfg := setupConfig(t, filepath.Join(logTestDataDir, tc, "config.yaml"))
+			connector, err := factory.CreateLogsToMetrics(ctx, settings, cfg, next)
+			require.NoError(t, err)
+			require.NoError(t, connector.ConsumeLogs(ctx, inputLogs))
+			require.Len(t, next.AllMetrics(), 1)
+
+			assertAggregatedMetrics(t, filepath.Join(logTestDataDir, tc, "output.yaml"), next.AllMetrics()[0])
+		})
+	}
+}
+
+func testSettings(t *testing.T) connector.Settings {
+	t.Helper()
+
+	settings := connectortest.NewNopSettings()
+	settings.TelemetrySettings.Logger = zaptest.NewLogger(t, zaptest.Level(zapcore.InfoLevel))
+	settings.TelemetrySettings.Resource.Attributes().PutStr(semconv.AttributeServiceInstanceID, "627cc493-f310-47de-96bd-71410b7dec09")
+	settings.TelemetrySettings.Resource.Attributes().PutStr(semconv.AttributeServiceName, "signaltometrics")
+	settings.TelemetrySettings.Resource.Attributes().PutStr(semconv.AttributeServiceNamespace, "test")
+	return settings
+}
+
+func setupConfig(t *testing.T, path string) *config.Config {
+	t.Helper()
+
+	cm, err := confmaptest.LoadConf(path)
+	require.NoError(t, err)
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig().(*config.Config)
+	require.NoError(t, sub.Unmarshal(&cfg))
+	require.NoError(t, component.ValidateConfig(cfg))
+	return cfg
+}
+
+func assertAggregatedMetrics(t *testing.T, expectedPath string, actual pmetric.Metrics) {
+	t.Helper()
+
+	expected, err := golden.ReadMetrics(expectedPath)
+	require.NoError(t, err)
+	assert.NoError(t, pmetrictest.CompareMetrics(
+		expected, actual,
+		pmetrictest.IgnoreMetricsOrder(),
+		pmetrictest.IgnoreMetricDataPointsOrder(),
+		pmetrictest.IgnoreResourceMetricsOrder(),
+		pmetrictest.IgnoreStartTimestamp(),
+		pmetrictest.IgnoreTimestamp(),
+	))
+}
@@ -5,13 +5,20 @@ package signaltometricsconnector // import "github.com/open-telemetry/openteleme
 
 import (
 	"context"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/consumer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/config"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/customottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 // NewFactory returns a ConnectorFactory.
@@ -32,26 +39,90 @@ func createDefaultConfig() component.Config {
 func createTracesToMetrics(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	nextConsumer consumer.Metrics,
 ) (connector.Traces, error) {
-	return newSignalToMetrics(set, nextConsumer), nil
+	c := cfg.(*config.Config)
+	parser, err := ottlspan.NewParser(customottl.SpanFuncs(), set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTTL statement parser for spans: %w", err)
+	}
+
+	metricDefs, err := parseMetricDefs(c.Spans, parser, set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metric definitions for spans: %w", err)
+	}
+
+	return &signalToMetrics{
+		logger:                set.Logger,
+		collectorInstanceInfo: model.NewCollectorInstanceInfo(set.TelemetrySettings),
+		next:                  nextConsumer,
+		spanMetricDefs:        metricDefs,
+	}, nil
 }
 
 func createMetricsToMetrics(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	nextConsumer consumer.Metrics,
 ) (connector.Metrics, error) {
-	return newSignalToMetrics(set, nextConsumer), nil
+	c := cfg.(*config.Config)
+	parser, err := ottldatapoint.NewParser(customottl.DatapointFuncs(), set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTTL statement parser for datapoints: %w", err)
+	}
+
+	metricDefs, err := parseMetricDefs(c.Datapoints, parser, set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metric definitions for datapoints: %w", err)
+	}
+
+	return &signalToMetrics{
+		logger:                set.Logger,
+		collectorInstanceInfo: model.NewCollectorInstanceInfo(set.TelemetrySettings),
+		next:                  nextConsumer,
+		dpMetricDefs:          metricDefs,
+	}, nil
 }
 
 func createLogsToMetrics(
 	_ context.Context,
 	set connector.Settings,
-	_ component.Config,
+	cfg component.Config,
 	nextConsumer consumer.Metrics,
 ) (connector.Logs, error) {
-	return newSignalToMetrics(set, nextConsumer), nil
+	c := cfg.(*config.Config)
+	parser, err := ottllog.NewParser(customottl.LogFuncs(), set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTTL statement parser for logs: %w", err)
+	}
+
+	metricDefs, err := parseMetricDefs(c.Logs, parser, set.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metric definitions for logs: %w", err)
+	}
+
+	return &signalToMetrics{
+		logger:                set.Logger,
+		collectorInstanceInfo: model.NewCollectorInstanceInfo(set.TelemetrySettings),
+		next:                  nextConsumer,
+		logMetricDefs:         metricDefs,
+	}, nil
+}
+
+func parseMetricDefs[K any](
+	infos []config.MetricInfo,
+	parser ottl.Parser[K],
+	set component.TelemetrySettings,
+) ([]model.MetricDef[K], error) {
+	metricDefs := make([]model.MetricDef[K], 0, len(infos))
+	for _, info := range infos {
+		var md model.MetricDef[K]
+		if err := md.FromMetricInfo(info, parser, set); err != nil {
+			return nil, fmt.Errorf("failed to parse provided metric information: %w", err)
+		}
+		metricDefs = append(metricDefs, md)
+	}
+	return metricDefs, nil
 }
@@ -0,0 +1,287 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aggregator // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/aggregator"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+// Aggregator provides a single interface to update all metrics
+// datastructures. The required datastructure is selected using
+// the metric definition.
+type Aggregator[K any] struct {
+	result pmetric.Metrics
+	// resources maps the hash of the resource attributes to the
+	// datapoints aggregated for the resource.
+	resources map[[16]byte]*resourceAggregation
+	timestamp time.Time
+}
+
+type resourceAggregation struct {
+	attrs              pcommon.Map
+	explicitHistograms map[model.MetricKey]map[[16]byte]*explicitHistogramDP
+	expHistograms      map[model.MetricKey]map[[16]byte]*exponentialHistogramDP
+	sums               map[model.MetricKey]map[[16]byte]*sumDP
+}
+
+func newResourceAggregation(attrs pcommon.Map) *resourceAggregation {
+	return &resourceAggregation{
+		attrs:              attrs,
+		explicitHistograms: make(map[model.MetricKey]map[[16]byte]*explicitHistogramDP),
+		expHistograms:      make(map[model.MetricKey]map[[16]byte]*exponentialHistogramDP),
+		sums:               make(map[model.MetricKey]map[[16]byte]*sumDP),
+	}
+}
+
+// NewAggregator creates a new instance of aggregator which writes the
+// aggregated metrics to the provided metrics on Finalize.
+func NewAggregator[K any](metrics pmetric.Metrics) *Aggregator[K] {
+	return &Aggregator[K]{
+		result:    metrics,
+		resources: make(map[[16]byte]*resourceAggregation),
+		timestamp: time.Now(),
+	}
+}
+
+// Aggregate evaluates the OTTL expressions of the metric definition against
+// the transform context and aggregates the result into the datapoint
+// identified by the resource and the source attributes. The default count
+// is used for histograms if no count expression is configured.
+func (a *Aggregator[K]) Aggregate(
+	ctx context.Context,
+	tCtx K,
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+	defaultCount uint64,
+) error {
+	switch {
+	case md.ExplicitHistogram != nil:
+		val, count, err := getValueCount(
+			ctx, tCtx,
+			md.ExplicitHistogram.Value,
+			md.ExplicitHistogram.Count,
+			defaultCount,
+		)
+		if err != nil {
+			return err
+		}
+		a.aggregateExplicitHistogram(md, resAttrs, srcAttrs, val, count)
+	case md.ExponentialHistogram != nil:
+		val, count, err := getValueCount(
+			ctx, tCtx,
+			md.ExponentialHistogram.Value,
+			md.ExponentialHistogram.Count,
+			defaultCount,
+		)
+		if err != nil {
+			return err
+		}
+		a.aggregateExponentialHistogram(md, resAttrs, srcAttrs, val, count)
+	case md.Sum != nil:
+		raw, err := md.Sum.Value.Eval(ctx, tCtx)
+		if err != nil {
+			return fmt.Errorf("failed to execute OTTL value for sum: %w", err)
+		}
+		switch v := raw.(type) {
+		case int64:
+			a.getSumDP(md, resAttrs, srcAttrs, false).AggregateInt(v)
+		case float64:
+			a.getSumDP(md, resAttrs, srcAttrs, true).AggregateDouble(v)
+		default:
+			return fmt.Errorf("failed to parse sum OTTL value of type %T into int64 or float64: %v", v, v)
+		}
+	}
+	return nil
+}
+
+// Finalize writes the aggregated datapoints to the metrics provided at
+// the creation of the aggregator. The metric definitions are used to
+// decide the order, the type, and the metadata of the produced metrics.
+func (a *Aggregator[K]) Finalize(mds []model.MetricDef[K]) {
+	timestamp := time.Now()
+	for _, res := range a.resources {
+		rm := a.result.ResourceMetrics().AppendEmpty()
+		res.attrs.CopyTo(rm.Resource().Attributes())
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(metadata.ScopeName)
+
+		for _, md := range mds {
+			switch {
+			case md.ExplicitHistogram != nil:
+				dps, ok := res.explicitHistograms[md.Key]
+				if !ok {
+					continue
+				}
+				delete(res.explicitHistograms, md.Key)
+				destExplicitHist := newMetric(sm, md).SetEmptyHistogram()
+				destExplicitHist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				destExplicitHist.DataPoints().EnsureCapacity(len(dps))
+				for _, dp := range dps {
+					dp.Copy(a.timestamp, timestamp, destExplicitHist.DataPoints().AppendEmpty())
+				}
+			case md.ExponentialHistogram != nil:
+				dps, ok := res.expHistograms[md.Key]
+				if !ok {
+					continue
+				}
+				delete(res.expHistograms, md.Key)
+				destExpHist := newMetric(sm, md).SetEmptyExponentialHistogram()
+				destExpHist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				destExpHist.DataPoints().EnsureCapacity(len(dps))
+				for _, dp := range dps {
+					dp.Copy(a.timestamp, timestamp, destExpHist.DataPoints().AppendEmpty())
+				}
+			case md.Sum != nil:
+				dps, ok := res.sums[md.Key]
+				if !ok {
+					continue
+				}
+				delete(res.sums, md.Key)
+				destSum := newMetric(sm, md).SetEmptySum()
+				destSum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+				destSum.DataPoints().EnsureCapacity(len(dps))
+				for _, dp := range dps {
+					dp.Copy(a.timestamp, timestamp, destSum.DataPoints().AppendEmpty())
+				}
+			}
+		}
+	}
+	a.resources = make(map[[16]byte]*resourceAggregation)
+}
+
+func (a *Aggregator[K]) aggregateExplicitHistogram(
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+	value float64,
+	count uint64,
+) {
+	res := a.getResource(resAttrs)
+	if _, ok := res.explicitHistograms[md.Key]; !ok {
+		res.explicitHistograms[md.Key] = make(map[[16]byte]*explicitHistogramDP)
+	}
+	attrID := pdatautil.MapHash(srcAttrs)
+	dp, ok := res.explicitHistograms[md.Key][attrID]
+	if !ok {
+		dp = newExplicitHistogramDP(srcAttrs, md.ExplicitHistogram.Buckets)
+		res.explicitHistograms[md.Key][attrID] = dp
+	}
+	dp.Aggregate(value, count)
+}
+
+func (a *Aggregator[K]) aggregateExponentialHistogram(
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+	value float64,
+	count uint64,
+) {
+	res := a.getResource(resAttrs)
+	if _, ok := res.expHistograms[md.Key]; !ok {
+		res.expHistograms[md.Key] = make(map[[16]byte]*exponentialHistogramDP)
+	}
+	attrID := pdatautil.MapHash(srcAttrs)
+	dp, ok := res.expHistograms[md.Key][attrID]
+	if !ok {
+		dp = newExponentialHistogramDP(srcAttrs, md.ExponentialHistogram.MaxSize)
+		res.expHistograms[md.Key][attrID] = dp
+	}
+	dp.Aggregate(value, count)
+}
+
+func (a *Aggregator[K]) getSumDP(
+	md model.MetricDef[K],
+	resAttrs, srcAttrs pcommon.Map,
+	isDbl bool,
+) *sumDP {
+	res := a.getResource(resAttrs)
+	if _, ok := res.sums[md.Key]; !ok {
+		res.sums[md.Key] = make(map[[16]byte]*sumDP)
+	}
+	attrID := pdatautil.MapHash(srcAttrs)
+	dp, ok := res.sums[md.Key][attrID]
+	if !ok {
+		dp = newSumDP(srcAttrs, isDbl)
+		res.sums[md.Key][attrID] = dp
+	}
+	return dp
+}
+
+func (a *Aggregator[K]) getResource(resAttrs pcommon.Map) *resourceAggregation {
+	resID := pdatautil.MapHash(resAttrs)
+	res, ok := a.resources[resID]
+	if !ok {
+		res = newResourceAggregation(resAttrs)
+		a.resources[resID] = res
+	}
+	return res
+}
+
+func newMetric[K any](sm pmetric.ScopeMetrics, md model.MetricDef[K]) pmetric.Metric {
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(md.Key.Name)
+	m.SetDescription(md.Key.Description)
+	m.SetUnit(md.Unit)
+	return m
+}
+
+func getValueCount[K any](
+	ctx context.Context, tCtx K,
+	valueExpr, countExpr *ottl.ValueExpression[K],
+	defaultCount uint64,
+) (float64, uint64, error) {
+	val, err := valueExpr.Eval(ctx, tCtx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to execute OTTL value for value count: %w", err)
+	}
+	if val == nil {
+		return 0, 0, errors.New("failed to execute OTTL value for value count, value is nil")
+	}
+
+	fVal, err := toFloat64(val)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse value: %w", err)
+	}
+
+	if countExpr == nil {
+		return fVal, defaultCount, nil
+	}
+
+	count, err := countExpr.Eval(ctx, tCtx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to execute OTTL count for value count: %w", err)
+	}
+	if count == nil {
+		return 0, 0, errors.New("failed to execute OTTL count for value count, count is nil")
+	}
+
+	fCount, err := toFloat64(count)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse count: %w", err)
+	}
+	if fCount < 0 {
+		return 0, 0, fmt.Errorf("count must not be negative, %v found", fCount)
+	}
+	return fVal, uint64(fCount), nil
+}
+
+func toFloat64(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("failed to parse %T into int64 or float64: %v", v, v)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package customottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/customottl"
+
+import (
+	"context"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+// NewAdjustedCountFactory returns a factory for the AdjustedCount converter.
+// AdjustedCount returns the number of spans represented by the span in the
+// transform context, as derived from the OTel tracestate threshold.
+func NewAdjustedCountFactory() ottl.Factory[ottlspan.TransformContext] {
+	return ottl.NewFactory("AdjustedCount", nil, createAdjustedCountFunction)
+}
+
+func createAdjustedCountFunction(_ ottl.FunctionContext, _ ottl.Arguments) (ottl.ExprFunc[ottlspan.TransformContext], error) {
+	return adjustedCount()
+}
+
+func adjustedCount() (ottl.ExprFunc[ottlspan.TransformContext], error) {
+	return func(_ context.Context, tCtx ottlspan.TransformContext) (any, error) {
+		return sampling.TraceStateToAdjustedCount(tCtx.GetSpan().TraceState().AsRaw()), nil
+	}, nil
+}
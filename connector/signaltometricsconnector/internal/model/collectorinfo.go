@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package model // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/signaltometricsconnector/internal/model"
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	semconv "go.opentelemetry.io/collector/semconv/v1.26.0"
+)
+
+const (
+	collectorServiceNameKey       = "signaltometrics." + semconv.AttributeServiceName
+	collectorServiceNamespaceKey  = "signaltometrics." + semconv.AttributeServiceNamespace
+	collectorServiceInstanceIDKey = "signaltometrics." + semconv.AttributeServiceInstanceID
+)
+
+// CollectorInstanceInfo holds the attributes that could uniquely identify
+// the current collector instance. These attributes are added to the
+// resource attributes of the produced metrics to honor the single writer
+// principle.
+type CollectorInstanceInfo struct {
+	size              int
+	serviceInstanceID string
+	serviceName       string
+	serviceNamespace  string
+}
+
+// NewCollectorInstanceInfo creates the instance info from the resource of
+// the collector telemetry settings.
+func NewCollectorInstanceInfo(
+	set component.TelemetrySettings,
+) CollectorInstanceInfo {
+	var info CollectorInstanceInfo
+	set.Resource.Attributes().Range(func(k string, v pcommon.Value) bool {
+		switch k {
+		case semconv.AttributeServiceInstanceID:
+			if str := v.Str(); str != "" {
+				info.serviceInstanceID = str
+				info.size++
+			}
+		case semconv.AttributeServiceName:
+			if str := v.Str(); str != "" {
+				info.serviceName = str
+				info.size++
+			}
+		case semconv.AttributeServiceNamespace:
+			if str := v.Str(); str != "" {
+				info.serviceNamespace = str
+				info.size++
+			}
+		}
+		return true
+	})
+	return info
+}
+
+// Size returns the max number of attributes that defines a collector's
+// instance information. Can be used to presize the attributes.
+func (info CollectorInstanceInfo) Size() int {
+	return info.size
+}
+
+// Copy copies the collector instance information into the provided map.
+func (info CollectorInstanceInfo) Copy(to pcommon.Map) {
+	to.EnsureCapacity(info.Size())
+	if info.serviceInstanceID != "" {
+		to.PutStr(collectorServiceInstanceIDKey, info.serviceInstanceID)
+	}
+	if info.serviceName != "" {
+		to.PutStr(collectorServiceNameKey, info.serviceName)
+	}
+	if info.serviceNamespace != "" {
+		to.PutStr(collectorServiceNamespaceKey, info.serviceNamespace)
+	}
+}
@@ -84,6 +84,9 @@ type Config struct {
 
 	// Events defines the configuration for events section of spans.
 	Events EventsConfig `mapstructure:"events"`
+
+	// AdjustedCounts enables sampling-aware metrics: a span sampled at 1-in-N increments the calls and the histogram by N.
+	AdjustedCounts bool `mapstructure:"adjusted_counts"`
 }
 
 type HistogramConfig struct {
@@ -6,6 +6,7 @@ package spanmetricsconnector // import "github.com/open-telemetry/opentelemetry-
 import (
 	"bytes"
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
 	utilattri "github.com/open-telemetry/opentelemetry-collector-contrib/internal/pdatautil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 const (
@@ -397,6 +399,10 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 				if endTime > startTime {
 					duration = float64(endTime-startTime) / float64(unitDivider)
 				}
+				count := uint64(1)
+				if p.config.AdjustedCounts {
+					count = uint64(math.Round(sampling.TraceStateToAdjustedCount(span.TraceState().AsRaw())))
+				}
 				key := p.buildKey(serviceName, span, p.dimensions, resourceAttr)
 
 				attributes, ok := p.metricKeyToDimensions.Get(key)
@@ -408,14 +414,14 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 					// aggregate histogram metrics
 					h := histograms.GetOrCreate(key, attributes)
 					p.addExemplar(span, duration, h)
-					h.Observe(duration)
+					h.Observe(duration, count)
 				}
 				// aggregate sums metrics
 				s := sums.GetOrCreate(key, attributes)
 				if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 					s.AddExemplar(span.TraceID(), span.SpanID(), duration)
 				}
-				s.Add(1)
+				s.Add(count)
 
 				// aggregate events metrics
 				if p.events.Enabled {
@@ -439,7 +445,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 						if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
 							e.AddExemplar(span.TraceID(), span.SpanID(), duration)
 						}
-						e.Add(1)
+						e.Add(count)
 					}
 				}
 			}
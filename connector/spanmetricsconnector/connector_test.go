@@ -1879,3 +1879,61 @@ func (c alwaysIncreasingClock) Now() time.Time {
 	c.Clock.(clockwork.FakeClock).Advance(time.Millisecond)
 	return c.Clock.Now()
 }
+
+func TestAdjustedCounts(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		expectedCount uint64
+	}{
+		{
+			name:          "disabled",
+			expectedCount: 4,
+		},
+		{
+			name:          "enabled",
+			enabled:       true,
+			expectedCount: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.AdjustedCounts = tt.enabled
+			c, err := newConnector(zaptest.NewLogger(t), cfg, clockwork.NewFakeClock())
+			require.NoError(t, err)
+
+			traces := ptrace.NewTraces()
+			rs := traces.ResourceSpans().AppendEmpty()
+			rs.Resource().Attributes().PutStr(serviceNameKey, "service-a")
+			spans := rs.ScopeSpans().AppendEmpty().Spans()
+			// sampled at 50% and 25%, not sampled and with an invalid threshold
+			for _, traceState := range []string{"ot=th:8", "ot=th:c,vendor=value", "", "ot=th:invalid"} {
+				span := spans.AppendEmpty()
+				span.SetName("operation")
+				span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, 0)))
+				span.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(0, 0).Add(time.Millisecond)))
+				span.TraceState().FromRaw(traceState)
+			}
+			require.NoError(t, c.ConsumeTraces(context.Background(), traces))
+
+			metrics := c.buildMetrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 2, metrics.Len())
+			for i := 0; i < metrics.Len(); i++ {
+				metric := metrics.At(i)
+				switch metric.Type() {
+				case pmetric.MetricTypeSum:
+					require.Equal(t, 1, metric.Sum().DataPoints().Len())
+					assert.Equal(t, int64(tt.expectedCount), metric.Sum().DataPoints().At(0).IntValue())
+				case pmetric.MetricTypeHistogram:
+					require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+					dp := metric.Histogram().DataPoints().At(0)
+					assert.Equal(t, tt.expectedCount, dp.Count())
+					assert.InDelta(t, float64(tt.expectedCount), dp.Sum(), 1e-9)
+				default:
+					t.Fatalf("unexpected metric type %v", metric.Type())
+				}
+			}
+		})
+	}
+}
@@ -20,7 +20,7 @@ type HistogramMetrics interface {
 }
 
 type Histogram interface {
-	Observe(value float64)
+	Observe(value float64, count uint64)
 	AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64)
 }
 
@@ -203,13 +203,13 @@ func (m *exponentialHistogramMetrics) ClearExemplars() {
 	}
 }
 
-func (h *explicitHistogram) Observe(value float64) {
-	h.sum += value
-	h.count++
+func (h *explicitHistogram) Observe(value float64, count uint64) {
+	h.sum += value * float64(count)
+	h.count += count
 
 	// Binary search to find the value bucket index.
 	index := sort.SearchFloat64s(h.bounds, value)
-	h.bucketCounts[index]++
+	h.bucketCounts[index] += count
 }
 
 func (h *explicitHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64) {
@@ -222,8 +222,8 @@ func (h *explicitHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcommon.
 	e.SetDoubleValue(value)
 }
 
-func (h *exponentialHistogram) Observe(value float64) {
-	h.histogram.Update(value)
+func (h *exponentialHistogram) Observe(value float64, count uint64) {
+	h.histogram.UpdateByIncr(value, count)
 }
 
 func (h *exponentialHistogram) AddExemplar(traceID pcommon.TraceID, spanID pcommon.SpanID, value float64) {
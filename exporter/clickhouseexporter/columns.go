@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaterializedColumnsConfig defines the columns materialized from attributes, by signal.
+type MaterializedColumnsConfig struct {
+	// Logs are the columns added to the logs table.
+	Logs []MaterializedColumn `mapstructure:"logs"`
+	// Traces are the columns added to the traces table.
+	Traces []MaterializedColumn `mapstructure:"traces"`
+	// Metrics are the columns added to every metrics table.
+	Metrics []MaterializedColumn `mapstructure:"metrics"`
+}
+
+// MaterializedColumn is a column ClickHouse computes from an attribute when rows are inserted,
+// so queries can filter on the column instead of an attributes map.
+type MaterializedColumn struct {
+	// Name is the column name.
+	Name string `mapstructure:"name"`
+	// Type is the ClickHouse type of the column, for example `LowCardinality(String)` or `UInt16`.
+	Type string `mapstructure:"type"`
+	// Attribute is the key of the attribute the column is computed from.
+	Attribute string `mapstructure:"attribute"`
+	// Context is either `resource` for the resource attributes, or `record` for the log record,
+	// span or data point attributes. default is `record`.
+	Context string `mapstructure:"context"`
+}
+
+const (
+	columnContextResource = "resource"
+	columnContextRecord   = "record"
+
+	resourceAttributesColumn = "ResourceAttributes"
+)
+
+var (
+	columnNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	columnTypeRegexp = regexp.MustCompile(`^[A-Za-z0-9_(), ]+$`)
+
+	// builtinLogsColumns are the columns of the logs table created by the exporter.
+	builtinLogsColumns = []string{
+		"Timestamp", "TimestampTime", "TraceId", "SpanId", "TraceFlags", "SeverityText", "SeverityNumber",
+		"ServiceName", "Body", "ResourceSchemaUrl", "ResourceAttributes", "ScopeSchemaUrl", "ScopeName",
+		"ScopeVersion", "ScopeAttributes", "LogAttributes",
+	}
+	// builtinTracesColumns are the columns of the traces table created by the exporter.
+	builtinTracesColumns = []string{
+		"Timestamp", "TraceId", "SpanId", "ParentSpanId", "TraceState", "SpanName", "SpanKind", "ServiceName",
+		"ResourceAttributes", "ScopeName", "ScopeVersion", "SpanAttributes", "Duration", "StatusCode",
+		"StatusMessage", "Events", "Links",
+	}
+	// builtinMetricsColumns are the columns of any of the metrics tables created by the exporter.
+	builtinMetricsColumns = []string{
+		"ResourceAttributes", "ResourceSchemaUrl", "ScopeName", "ScopeVersion", "ScopeAttributes",
+		"ScopeDroppedAttrCount", "ScopeSchemaUrl", "ServiceName", "MetricName", "MetricDescription", "MetricUnit",
+		"Attributes", "StartTimeUnix", "TimeUnix", "Value", "Flags", "Exemplars", "AggregationTemporality",
+		"IsMonotonic", "Count", "Sum", "BucketCounts", "ExplicitBounds", "Min", "Max", "ValueAtQuantiles",
+		"Scale", "ZeroCount", "PositiveOffset", "PositiveBucketCounts", "NegativeOffset", "NegativeBucketCounts",
+	}
+)
+
+func (cfg *MaterializedColumnsConfig) Validate() (err error) {
+	for _, signal := range []struct {
+		name    string
+		columns []MaterializedColumn
+		builtin []string
+	}{
+		{"logs", cfg.Logs, builtinLogsColumns},
+		{"traces", cfg.Traces, builtinTracesColumns},
+		{"metrics", cfg.Metrics, builtinMetricsColumns},
+	} {
+		names := make(map[string]struct{}, len(signal.columns))
+		for i, column := range signal.columns {
+			if e := column.validate(); e != nil {
+				err = errors.Join(err, fmt.Errorf("materialized_columns::%s[%d]: %w", signal.name, i, e))
+				continue
+			}
+			if slices.Contains(signal.builtin, column.Name) {
+				err = errors.Join(err, fmt.Errorf("materialized_columns::%s[%d]: column %q is already a column of the %s table", signal.name, i, column.Name, signal.name))
+				continue
+			}
+			if _, ok := names[column.Name]; ok {
+				err = errors.Join(err, fmt.Errorf("materialized_columns::%s[%d]: duplicate column %q", signal.name, i, column.Name))
+			}
+			names[column.Name] = struct{}{}
+		}
+	}
+	return err
+}
+
+func (c MaterializedColumn) validate() error {
+	if !columnNameRegexp.MatchString(c.Name) {
+		return fmt.Errorf("invalid column name %q", c.Name)
+	}
+	if !columnTypeRegexp.MatchString(c.Type) {
+		return fmt.Errorf("invalid type %q for column %q", c.Type, c.Name)
+	}
+	if c.Attribute == "" {
+		return fmt.Errorf("attribute must be specified for column %q", c.Name)
+	}
+	switch c.Context {
+	case "", columnContextResource, columnContextRecord:
+	default:
+		return fmt.Errorf("invalid context %q for column %q, must be %q or %q", c.Context, c.Name, columnContextResource, columnContextRecord)
+	}
+	return nil
+}
+
+// expression returns the MATERIALIZED expression of the column, reading the attribute from
+// the resource attributes or the given record attributes column.
+func (c MaterializedColumn) expression(recordAttributesColumn string) string {
+	attributesColumn := recordAttributesColumn
+	if c.Context == columnContextResource {
+		attributesColumn = resourceAttributesColumn
+	}
+	value := fmt.Sprintf("%s['%s']", attributesColumn, escapeString(c.Attribute))
+	switch c.Type {
+	case "String":
+		return value
+	case "LowCardinality(String)":
+		return fmt.Sprintf("CAST(%s, '%s')", value, c.Type)
+	default:
+		// attributes which cannot be converted get the default value of the type.
+		return fmt.Sprintf("accurateCastOrDefault(%s, '%s')", value, c.Type)
+	}
+}
+
+func escapeString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+}
+
+// existingColumn is a column of a table, as described by system.columns.
+type existingColumn struct {
+	Type        string
+	DefaultKind string
+	Expression  string
+}
+
+// renderAddMaterializedColumnsSQL returns the statements adding the columns missing from the
+// table, and updating the columns of the table whose type or expression differ.
+func renderAddMaterializedColumnsSQL(cfg *Config, table string, recordAttributesColumn string, columns []MaterializedColumn, existing map[string]existingColumn) []string {
+	var add, modify []string
+	for _, c := range columns {
+		expression := c.expression(recordAttributesColumn)
+		definition := fmt.Sprintf("%s %s MATERIALIZED %s", c.Name, c.Type, expression)
+		current, ok := existing[c.Name]
+		switch {
+		case !ok:
+			add = append(add, "ADD COLUMN IF NOT EXISTS "+definition)
+		case current.Type != c.Type || current.DefaultKind != "MATERIALIZED" || current.Expression != expression:
+			modify = append(modify, "MODIFY COLUMN "+definition)
+		}
+	}
+	var queries []string
+	if len(add) > 0 {
+		queries = append(queries, fmt.Sprintf("ALTER TABLE %s %s\n%s", table, cfg.clusterString(), strings.Join(add, ",\n")))
+	}
+	if len(modify) > 0 {
+		queries = append(queries, fmt.Sprintf("ALTER TABLE %s %s\n%s", table, cfg.clusterString(), strings.Join(modify, ",\n")))
+	}
+	return queries
+}
+
+// language=ClickHouse SQL
+const selectColumnsSQL = `SELECT name, type, default_kind, default_expression FROM system.columns WHERE database = currentDatabase() AND table = ?`
+
+// existingColumns returns the columns of the table.
+func existingColumns(ctx context.Context, db *sql.DB, table string) (map[string]existingColumn, error) {
+	rows, err := db.QueryContext(ctx, selectColumnsSQL, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]existingColumn)
+	for rows.Next() {
+		var (
+			name   string
+			column existingColumn
+		)
+		if err = rows.Scan(&name, &column.Type, &column.DefaultKind, &column.Expression); err != nil {
+			return nil, err
+		}
+		columns[name] = column
+	}
+	return columns, rows.Err()
+}
+
+func addMaterializedColumns(ctx context.Context, cfg *Config, db *sql.DB, table string, recordAttributesColumn string, columns []MaterializedColumn) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	existing, err := existingColumns(ctx, db, table)
+	if err != nil {
+		return fmt.Errorf("select columns of table %s: %w", table, err)
+	}
+	for _, query := range renderAddMaterializedColumnsSQL(cfg, table, recordAttributesColumn, columns, existing) {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("exec add materialized columns to table %s sql: %w", table, err)
+		}
+	}
+	return nil
+}
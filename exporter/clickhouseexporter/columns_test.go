@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMaterializedColumnsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     MaterializedColumnsConfig
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: MaterializedColumnsConfig{
+				Logs:    []MaterializedColumn{{Name: "K8sNamespace", Type: "LowCardinality(String)", Attribute: "k8s.namespace.name", Context: "resource"}},
+				Traces:  []MaterializedColumn{{Name: "HttpStatusCode", Type: "UInt16", Attribute: "http.response.status_code"}},
+				Metrics: []MaterializedColumn{{Name: "Price", Type: "Decimal(10, 2)", Attribute: "price", Context: "record"}},
+			},
+		},
+		{
+			name:    "invalid name",
+			cfg:     MaterializedColumnsConfig{Logs: []MaterializedColumn{{Name: "k8s.namespace", Type: "String", Attribute: "k8s.namespace.name"}}},
+			wantErr: `materialized_columns::logs[0]: invalid column name "k8s.namespace"`,
+		},
+		{
+			name:    "invalid type",
+			cfg:     MaterializedColumnsConfig{Traces: []MaterializedColumn{{Name: "Status", Type: "String'); DROP TABLE otel_traces; --", Attribute: "status"}}},
+			wantErr: `materialized_columns::traces[0]: invalid type "String'); DROP TABLE otel_traces; --" for column "Status"`,
+		},
+		{
+			name:    "missing type",
+			cfg:     MaterializedColumnsConfig{Traces: []MaterializedColumn{{Name: "Status", Attribute: "status"}}},
+			wantErr: `materialized_columns::traces[0]: invalid type "" for column "Status"`,
+		},
+		{
+			name:    "missing attribute",
+			cfg:     MaterializedColumnsConfig{Metrics: []MaterializedColumn{{Name: "HostName", Type: "String"}}},
+			wantErr: `materialized_columns::metrics[0]: attribute must be specified for column "HostName"`,
+		},
+		{
+			name:    "invalid context",
+			cfg:     MaterializedColumnsConfig{Metrics: []MaterializedColumn{{Name: "HostName", Type: "String", Attribute: "host.name", Context: "scope"}}},
+			wantErr: `materialized_columns::metrics[0]: invalid context "scope" for column "HostName", must be "resource" or "record"`,
+		},
+		{
+			name: "duplicate column",
+			cfg: MaterializedColumnsConfig{Logs: []MaterializedColumn{
+				{Name: "HostName", Type: "String", Attribute: "host.name"},
+				{Name: "HostName", Type: "String", Attribute: "host.name", Context: "resource"},
+			}},
+			wantErr: `materialized_columns::logs[1]: duplicate column "HostName"`,
+		},
+		{
+			name: "builtin column",
+			cfg: MaterializedColumnsConfig{
+				Logs:    []MaterializedColumn{{Name: "SeverityText", Type: "String", Attribute: "level"}},
+				Traces:  []MaterializedColumn{{Name: "ServiceName", Type: "String", Attribute: "service.name", Context: "resource"}},
+				Metrics: []MaterializedColumn{{Name: "Value", Type: "Float64", Attribute: "value"}},
+			},
+			wantErr: `materialized_columns::logs[0]: column "SeverityText" is already a column of the logs table` + "\n" +
+				`materialized_columns::traces[0]: column "ServiceName" is already a column of the traces table` + "\n" +
+				`materialized_columns::metrics[0]: column "Value" is already a column of the metrics table`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaterializedColumn_expression(t *testing.T) {
+	tests := []struct {
+		name   string
+		column MaterializedColumn
+		want   string
+	}{
+		{
+			name:   "string",
+			column: MaterializedColumn{Name: "HostName", Type: "String", Attribute: "host.name"},
+			want:   "LogAttributes['host.name']",
+		},
+		{
+			name:   "low cardinality string",
+			column: MaterializedColumn{Name: "K8sNamespace", Type: "LowCardinality(String)", Attribute: "k8s.namespace.name", Context: "resource"},
+			want:   "CAST(ResourceAttributes['k8s.namespace.name'], 'LowCardinality(String)')",
+		},
+		{
+			name:   "number",
+			column: MaterializedColumn{Name: "HttpStatusCode", Type: "UInt16", Attribute: "http.response.status_code", Context: "record"},
+			want:   "accurateCastOrDefault(LogAttributes['http.response.status_code'], 'UInt16')",
+		},
+		{
+			name:   "escaped attribute",
+			column: MaterializedColumn{Name: "Quoted", Type: "String", Attribute: `it's\here`},
+			want:   `LogAttributes['it\'s\\here']`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.column.expression("LogAttributes"))
+		})
+	}
+}
+
+func TestRenderAddMaterializedColumnsSQL(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.ClusterName = "my_cluster"
+	})
+	columns := []MaterializedColumn{
+		{Name: "K8sNamespace", Type: "LowCardinality(String)", Attribute: "k8s.namespace.name", Context: "resource"},
+		{Name: "HttpStatusCode", Type: "UInt16", Attribute: "http.response.status_code"},
+	}
+
+	assert.Empty(t, renderAddMaterializedColumnsSQL(cfg, "otel_traces", "SpanAttributes", nil, nil))
+	assert.Equal(t, []string{
+		"ALTER TABLE otel_traces ON CLUSTER my_cluster\n" +
+			"ADD COLUMN IF NOT EXISTS K8sNamespace LowCardinality(String) MATERIALIZED CAST(ResourceAttributes['k8s.namespace.name'], 'LowCardinality(String)'),\n" +
+			"ADD COLUMN IF NOT EXISTS HttpStatusCode UInt16 MATERIALIZED accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt16')",
+	}, renderAddMaterializedColumnsSQL(cfg, "otel_traces", "SpanAttributes", columns, nil))
+
+	tests := []struct {
+		name     string
+		existing existingColumn
+		want     []string
+	}{
+		{
+			name:     "unchanged",
+			existing: existingColumn{Type: "UInt16", DefaultKind: "MATERIALIZED", Expression: "accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt16')"},
+		},
+		{
+			name:     "changed type",
+			existing: existingColumn{Type: "UInt32", DefaultKind: "MATERIALIZED", Expression: "accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt32')"},
+			want: []string{
+				"ALTER TABLE otel_traces ON CLUSTER my_cluster\n" +
+					"MODIFY COLUMN HttpStatusCode UInt16 MATERIALIZED accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt16')",
+			},
+		},
+		{
+			name:     "changed expression",
+			existing: existingColumn{Type: "UInt16", DefaultKind: "MATERIALIZED", Expression: "accurateCastOrDefault(ResourceAttributes['http.response.status_code'], 'UInt16')"},
+			want: []string{
+				"ALTER TABLE otel_traces ON CLUSTER my_cluster\n" +
+					"MODIFY COLUMN HttpStatusCode UInt16 MATERIALIZED accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt16')",
+			},
+		},
+		{
+			name:     "not materialized",
+			existing: existingColumn{Type: "UInt16"},
+			want: []string{
+				"ALTER TABLE otel_traces ON CLUSTER my_cluster\n" +
+					"MODIFY COLUMN HttpStatusCode UInt16 MATERIALIZED accurateCastOrDefault(SpanAttributes['http.response.status_code'], 'UInt16')",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderAddMaterializedColumnsSQL(cfg, "otel_traces", "SpanAttributes", columns[1:], map[string]existingColumn{"HttpStatusCode": tt.existing}))
+		})
+	}
+}
+
+func TestExporters_addMaterializedColumns(t *testing.T) {
+	previousDriverName := driverName
+	t.Cleanup(func() { driverName = previousDriverName })
+	var alters []string
+	initClickhouseTestServerWithRows(t, func(query string, _ []driver.Value) error {
+		if strings.HasPrefix(query, "ALTER TABLE") {
+			alters = append(alters, strings.SplitN(query, "\n", 3)[:2]...)
+		}
+		return nil
+	}, func(query string, values []driver.Value) [][]driver.Value {
+		// the traces table already has the column, the metrics tables have an outdated one.
+		switch {
+		case query != selectColumnsSQL:
+			return nil
+		case values[0] == "otel_traces":
+			return [][]driver.Value{{"HttpRoute", "String", "MATERIALIZED", "SpanAttributes['http.route']"}}
+		case strings.HasPrefix(values[0].(string), "otel_metrics_"):
+			return [][]driver.Value{{"HostName", "LowCardinality(String)", "MATERIALIZED", "CAST(ResourceAttributes['host.name'], 'LowCardinality(String)')"}}
+		}
+		return nil
+	})
+	cfg := withTestExporterConfig(func(cfg *Config) {
+		cfg.MaterializedColumns = MaterializedColumnsConfig{
+			Logs:    []MaterializedColumn{{Name: "HostName", Type: "String", Attribute: "host.name", Context: "resource"}},
+			Traces:  []MaterializedColumn{{Name: "HttpRoute", Type: "String", Attribute: "http.route"}},
+			Metrics: []MaterializedColumn{{Name: "HostName", Type: "String", Attribute: "host.name", Context: "resource"}},
+		}
+	})(defaultEndpoint)
+	cfg.buildMetricTableNames()
+
+	logsExporter, err := newLogsExporter(zaptest.NewLogger(t), cfg)
+	require.NoError(t, err)
+	require.NoError(t, logsExporter.start(context.Background(), nil))
+	require.NoError(t, logsExporter.shutdown(context.Background()))
+
+	tracesExporter, err := newTracesExporter(zaptest.NewLogger(t), cfg)
+	require.NoError(t, err)
+	require.NoError(t, tracesExporter.start(context.Background(), nil))
+	require.NoError(t, tracesExporter.shutdown(context.Background()))
+
+	metricsExporter, err := newMetricsExporter(zaptest.NewLogger(t), cfg)
+	require.NoError(t, err)
+	require.NoError(t, metricsExporter.start(context.Background(), nil))
+	require.NoError(t, metricsExporter.shutdown(context.Background()))
+
+	expected := []string{
+		"ALTER TABLE otel_logs ",
+		"ADD COLUMN IF NOT EXISTS HostName String MATERIALIZED ResourceAttributes['host.name']",
+	}
+	for _, table := range []string{"otel_metrics_gauge", "otel_metrics_sum", "otel_metrics_summary", "otel_metrics_histogram", "otel_metrics_exponential_histogram"} {
+		expected = append(expected,
+			"ALTER TABLE "+table+" ",
+			"MODIFY COLUMN HostName String MATERIALIZED ResourceAttributes['host.name']",
+		)
+	}
+	assert.Equal(t, expected, alters)
+}
@@ -60,6 +60,9 @@ type Config struct {
 	AsyncInsert bool `mapstructure:"async_insert"`
 	// MetricsTables defines the table names for metric types.
 	MetricsTables MetricTablesConfig `mapstructure:"metrics_tables"`
+	// MaterializedColumns defines the columns materialized from attributes, which are added to the tables
+	// when CreateSchema is set.
+	MaterializedColumns MaterializedColumnsConfig `mapstructure:"materialized_columns"`
 }
 
 type MetricTablesConfig struct {
@@ -83,6 +83,23 @@ func TestLoadConfig(t *testing.T) {
 				AsyncInsert: true,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "materialized-columns"),
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.MaterializedColumns = MaterializedColumnsConfig{
+					Logs: []MaterializedColumn{
+						{Name: "K8sNamespace", Type: "LowCardinality(String)", Attribute: "k8s.namespace.name", Context: "resource"},
+					},
+					Traces: []MaterializedColumn{
+						{Name: "HttpStatusCode", Type: "UInt16", Attribute: "http.response.status_code"},
+					},
+					Metrics: []MaterializedColumn{
+						{Name: "HostName", Type: "String", Attribute: "host.name", Context: "resource"},
+					},
+				}
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -246,7 +246,7 @@ func createLogsTable(ctx context.Context, cfg *Config, db *sql.DB) error {
 	if _, err := db.ExecContext(ctx, renderCreateLogsTableSQL(cfg)); err != nil {
 		return fmt.Errorf("exec create logs table sql: %w", err)
 	}
-	return nil
+	return addMaterializedColumns(ctx, cfg, db, cfg.LogsTableName, "LogAttributes", cfg.MaterializedColumns.Logs)
 }
 
 func renderCreateLogsTableSQL(cfg *Config) string {
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -261,32 +262,44 @@ func mustPushLogsData(t *testing.T, exporter *logsExporter, ld plog.Logs) {
 }
 
 func initClickhouseTestServer(t *testing.T, recorder recorder) {
+	initClickhouseTestServerWithRows(t, recorder, nil)
+}
+
+func initClickhouseTestServerWithRows(t *testing.T, recorder recorder, querier querier) {
 	driverName = t.Name()
 	sql.Register(t.Name(), &testClickhouseDriver{
 		recorder: recorder,
+		querier:  querier,
 	})
 }
 
 type recorder func(query string, values []driver.Value) error
 
+// querier returns the rows of a query.
+type querier func(query string, values []driver.Value) [][]driver.Value
+
 type testClickhouseDriver struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriver) Open(_ string) (driver.Conn, error) {
 	return &testClickhouseDriverConn{
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
 type testClickhouseDriverConn struct {
 	recorder recorder
+	querier  querier
 }
 
 func (t *testClickhouseDriverConn) Prepare(query string) (driver.Stmt, error) {
 	return &testClickhouseDriverStmt{
 		query:    query,
 		recorder: t.recorder,
+		querier:  t.querier,
 	}, nil
 }
 
@@ -305,6 +318,7 @@ func (*testClickhouseDriverConn) CheckNamedValue(_ *driver.NamedValue) error {
 type testClickhouseDriverStmt struct {
 	query    string
 	recorder recorder
+	querier  querier
 }
 
 func (*testClickhouseDriverStmt) Close() error {
@@ -319,8 +333,36 @@ func (t *testClickhouseDriverStmt) Exec(args []driver.Value) (driver.Result, err
 	return nil, t.recorder(t.query, args)
 }
 
-func (t *testClickhouseDriverStmt) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, nil
+func (t *testClickhouseDriverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &testClickhouseDriverRows{}
+	if t.querier != nil {
+		rows.values = t.querier(t.query, args)
+	}
+	return rows, nil
+}
+
+type testClickhouseDriverRows struct {
+	values [][]driver.Value
+}
+
+func (t *testClickhouseDriverRows) Columns() []string {
+	if len(t.values) == 0 {
+		return nil
+	}
+	return make([]string, len(t.values[0]))
+}
+
+func (*testClickhouseDriverRows) Close() error {
+	return nil
+}
+
+func (t *testClickhouseDriverRows) Next(dest []driver.Value) error {
+	if len(t.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, t.values[0])
+	t.values = t.values[1:]
+	return nil
 }
 
 type testClickhouseDriverTx struct{}
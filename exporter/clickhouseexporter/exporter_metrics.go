@@ -52,7 +52,22 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 	}
 
 	ttlExpr := generateTTLExpr(e.cfg.TTL, "toDateTime(TimeUnix)")
-	return internal.NewMetricsTable(ctx, e.tablesConfig, e.cfg.clusterString(), e.cfg.tableEngineString(), ttlExpr, e.client)
+	if err := internal.NewMetricsTable(ctx, e.tablesConfig, e.cfg.clusterString(), e.cfg.tableEngineString(), ttlExpr, e.client); err != nil {
+		return err
+	}
+
+	for _, table := range []string{
+		e.cfg.MetricsTables.Gauge.Name,
+		e.cfg.MetricsTables.Sum.Name,
+		e.cfg.MetricsTables.Summary.Name,
+		e.cfg.MetricsTables.Histogram.Name,
+		e.cfg.MetricsTables.ExponentialHistogram.Name,
+	} {
+		if err := addMaterializedColumns(ctx, e.cfg, e.client, table, "Attributes", e.cfg.MaterializedColumns.Metrics); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func generateMetricTablesConfigMapper(cfg *Config) internal.MetricTablesConfigMapper {
@@ -282,6 +282,9 @@ func createTracesTable(ctx context.Context, cfg *Config, db *sql.DB) error {
 	if _, err := db.ExecContext(ctx, renderCreateTracesTableSQL(cfg)); err != nil {
 		return fmt.Errorf("exec create traces table sql: %w", err)
 	}
+	if err := addMaterializedColumns(ctx, cfg, db, cfg.TracesTableName, "SpanAttributes", cfg.MaterializedColumns.Traces); err != nil {
+		return err
+	}
 	if _, err := db.ExecContext(ctx, renderCreateTraceIDTsTableSQL(cfg)); err != nil {
 		return fmt.Errorf("exec create traceID timestamp table sql: %w", err)
 	}
@@ -102,6 +102,15 @@ type Producer struct {
 	// broker request. Defaults to 0 for unlimited. Similar to
 	// `queue.buffering.max.messages` in the JVM producer.
 	FlushMaxMessages int `mapstructure:"flush_max_messages"`
+
+	// Idempotent makes the brokers discard the duplicates of the messages retried by the
+	// producer, so each message is written once. Requires RequiredAcks to be WaitForAll.
+	Idempotent bool `mapstructure:"idempotent"`
+
+	// TransactionalID makes the producer send each exported batch in a transaction, so the
+	// messages of a batch are committed atomically. Requires Idempotent to be enabled.
+	// The ID of the producer of each signal is suffixed with the signal, e.g. "<id>-traces".
+	TransactionalID string `mapstructure:"transactional_id"`
 }
 
 // MetadataRetry defines retry configuration for Metadata.
@@ -127,6 +136,21 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if cfg.Producer.Idempotent {
+		if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+			return fmt.Errorf("producer.required_acks has to be -1 when producer.idempotent is enabled. configured value %v", cfg.Producer.RequiredAcks)
+		}
+		if cfg.ProtocolVersion != "" {
+			version, err := sarama.ParseKafkaVersion(cfg.ProtocolVersion)
+			if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+				return fmt.Errorf("protocol_version has to be at least 0.11.0.0 when producer.idempotent is enabled. configured value %v", cfg.ProtocolVersion)
+			}
+		}
+	}
+	if cfg.Producer.TransactionalID != "" && !cfg.Producer.Idempotent {
+		return fmt.Errorf("producer.idempotent has to be enabled when producer.transactional_id is set")
+	}
+
 	return validateSASLConfig(cfg.Authentication.SASL)
 }
 
@@ -294,6 +294,60 @@ func TestValidate_sasl_version(t *testing.T) {
 	assert.EqualError(t, err, "auth.sasl.version has to be either 0 or 1. configured value 42")
 }
 
+func TestValidate_idempotent(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{
+			name: "idempotent",
+			config: Config{
+				Producer: Producer{Compression: "none", RequiredAcks: sarama.WaitForAll, Idempotent: true},
+			},
+		},
+		{
+			name: "transactional",
+			config: Config{
+				ProtocolVersion: "2.0.0",
+				Producer:        Producer{Compression: "none", RequiredAcks: sarama.WaitForAll, Idempotent: true, TransactionalID: "otelcol"},
+			},
+		},
+		{
+			name: "required_acks",
+			config: Config{
+				Producer: Producer{Compression: "none", RequiredAcks: sarama.WaitForLocal, Idempotent: true},
+			},
+			expected: "producer.required_acks has to be -1 when producer.idempotent is enabled. configured value 1",
+		},
+		{
+			name: "protocol_version",
+			config: Config{
+				ProtocolVersion: "0.10.2.0",
+				Producer:        Producer{Compression: "none", RequiredAcks: sarama.WaitForAll, Idempotent: true},
+			},
+			expected: "protocol_version has to be at least 0.11.0.0 when producer.idempotent is enabled. configured value 0.10.2.0",
+		},
+		{
+			name: "transactional_not_idempotent",
+			config: Config{
+				Producer: Producer{Compression: "none", RequiredAcks: sarama.WaitForAll, TransactionalID: "otelcol"},
+			},
+			expected: "producer.idempotent has to be enabled when producer.transactional_id is set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected)
+			}
+		})
+	}
+}
+
 func Test_saramaProducerCompressionCodec(t *testing.T) {
 	tests := map[string]struct {
 		compression         string
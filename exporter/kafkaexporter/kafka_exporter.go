@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/IBM/sarama"
 	"go.opentelemetry.io/collector/component"
@@ -30,6 +31,9 @@ type kafkaTracesProducer struct {
 	producer  sarama.SyncProducer
 	marshaler TracesMarshaler
 	logger    *zap.Logger
+
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 type kafkaErrors struct {
@@ -46,7 +50,7 @@ func (e *kafkaTracesProducer) tracesPusher(ctx context.Context, td ptrace.Traces
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -83,7 +87,7 @@ func (e *kafkaTracesProducer) start(ctx context.Context, host component.Host) er
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "traces")
 	if err != nil {
 		return err
 	}
@@ -97,6 +101,9 @@ type kafkaMetricsProducer struct {
 	producer  sarama.SyncProducer
 	marshaler MetricsMarshaler
 	logger    *zap.Logger
+
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric.Metrics) error {
@@ -104,7 +111,7 @@ func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -141,7 +148,7 @@ func (e *kafkaMetricsProducer) start(ctx context.Context, host component.Host) e
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "metrics")
 	if err != nil {
 		return err
 	}
@@ -155,6 +162,9 @@ type kafkaLogsProducer struct {
 	producer  sarama.SyncProducer
 	marshaler LogsMarshaler
 	logger    *zap.Logger
+
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) error {
@@ -162,7 +172,7 @@ func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) er
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -199,7 +209,7 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, "logs")
 	if err != nil {
 		return err
 	}
@@ -207,7 +217,83 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 	return nil
 }
 
-func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer, error) {
+// sendMessages sends the messages, in a transaction when the producer is transactional.
+func sendMessages(producer sarama.SyncProducer, txnMu *sync.Mutex, messages []*sarama.ProducerMessage) error {
+	if !producer.IsTransactional() {
+		return producer.SendMessages(messages)
+	}
+
+	// A producer runs a single transaction at a time, while the exporter is called concurrently
+	// by the consumers of the sending queue.
+	txnMu.Lock()
+	defer txnMu.Unlock()
+	if err := producer.BeginTxn(); err != nil {
+		return err
+	}
+	if err := producer.SendMessages(messages); err != nil {
+		return errors.Join(err, abortTxn(producer))
+	}
+	if err := producer.CommitTxn(); err != nil {
+		return errors.Join(err, abortTxn(producer))
+	}
+	return nil
+}
+
+// abortTxn aborts the current transaction. A producer in an unrecoverable state can neither abort
+// the transaction nor begin a new one, so that it is replaced by a new producer instead.
+func abortTxn(producer sarama.SyncProducer) error {
+	if producer.TxnStatus()&sarama.ProducerTxnFlagFatalError == 0 {
+		return producer.AbortTxn()
+	}
+	if p, ok := producer.(*txnProducer); ok {
+		return p.replace()
+	}
+	return nil
+}
+
+// txnProducer is a transactional producer replacing its underlying producer after a fatal
+// transaction error. It is only used by one transaction at a time.
+type txnProducer struct {
+	sarama.SyncProducer
+	newProducer func() (sarama.SyncProducer, error)
+}
+
+func (p *txnProducer) IsTransactional() bool {
+	return true
+}
+
+// BeginTxn begins a transaction, creating the producer first if it could not be replaced.
+func (p *txnProducer) BeginTxn() error {
+	if p.SyncProducer == nil {
+		producer, err := p.newProducer()
+		if err != nil {
+			return fmt.Errorf("failed to create producer: %w", err)
+		}
+		p.SyncProducer = producer
+	}
+	return p.SyncProducer.BeginTxn()
+}
+
+// replace closes the producer, which cannot be used after a fatal transaction error, and creates a new one.
+func (p *txnProducer) replace() error {
+	err := p.SyncProducer.Close()
+	p.SyncProducer = nil
+	producer, newErr := p.newProducer()
+	if newErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to create producer: %w", newErr))
+	}
+	p.SyncProducer = producer
+	return err
+}
+
+func (p *txnProducer) Close() error {
+	if p.SyncProducer == nil {
+		return nil
+	}
+	return p.SyncProducer.Close()
+}
+
+func newSaramaProducer(ctx context.Context, config Config, signal string) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 
 	c.ClientID = config.ClientID
@@ -224,6 +310,16 @@ func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer,
 	c.Producer.MaxMessageBytes = config.Producer.MaxMessageBytes
 	c.Producer.Flush.MaxMessages = config.Producer.FlushMaxMessages
 
+	if config.Producer.Idempotent {
+		c.Producer.Idempotent = true
+		// The idempotent producer keeps the messages in order by sending one request at a time.
+		c.Net.MaxOpenRequests = 1
+	}
+	if config.Producer.TransactionalID != "" {
+		// The producers of the signals use different IDs so they don't fence each other.
+		c.Producer.Transaction.ID = config.Producer.TransactionalID + "-" + signal
+	}
+
 	if config.ResolveCanonicalBootstrapServersOnly {
 		c.Net.ResolveCanonicalBootstrapServers = true
 	}
@@ -250,6 +346,14 @@ func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer,
 	if err != nil {
 		return nil, err
 	}
+	if producer.IsTransactional() {
+		return &txnProducer{
+			SyncProducer: producer,
+			newProducer: func() (sarama.SyncProducer, error) {
+				return sarama.NewSyncProducer(config.Brokers, c)
+			},
+		}, nil
+	}
 	return producer, nil
 }
 
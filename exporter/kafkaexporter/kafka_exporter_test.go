@@ -6,6 +6,7 @@ package kafkaexporter
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/IBM/sarama"
@@ -410,6 +411,186 @@ func TestLogsDataPusher_marshal_error(t *testing.T) {
 	assert.ErrorContains(t, err, expErr.Error())
 }
 
+func TestTracesPusher_transactional(t *testing.T) {
+	producer := newTxnRecordingProducer(t)
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTraces(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"begin", "commit"}, producer.calls)
+}
+
+func TestMetricsDataPusher_transactional_err(t *testing.T) {
+	producer := newTxnRecordingProducer(t)
+	expErr := fmt.Errorf("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaMetricsProducer{
+		producer:  producer,
+		marshaler: newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding, false),
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.metricsDataPusher(context.Background(), testdata.GenerateMetrics(2))
+	assert.EqualError(t, err, expErr.Error())
+	assert.Equal(t, []string{"begin", "abort"}, producer.calls)
+}
+
+func TestLogsDataPusher_transactional_commit_err(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        sarama.ProducerTxnStatusFlag
+		expectedCalls []string
+	}{
+		{
+			name:          "abortable",
+			status:        sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagAbortableError,
+			expectedCalls: []string{"begin", "commit", "abort"},
+		},
+		{
+			name:          "fatal",
+			status:        sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError,
+			expectedCalls: []string{"begin", "commit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := newTxnRecordingProducer(t)
+			producer.ExpectSendMessageAndSucceed()
+			expErr := fmt.Errorf("failed to commit")
+			producer.commitErr = expErr
+			producer.commitStatus = tt.status
+
+			p := kafkaLogsProducer{
+				producer:  producer,
+				marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+				logger:    zap.NewNop(),
+			}
+			t.Cleanup(func() {
+				require.NoError(t, p.Close(context.Background()))
+			})
+			err := p.logsDataPusher(context.Background(), testdata.GenerateLogs(1))
+			assert.ErrorIs(t, err, expErr)
+			assert.Equal(t, tt.expectedCalls, producer.calls)
+		})
+	}
+}
+
+func TestLogsDataPusher_transactional_fatal_err_replaces_producer(t *testing.T) {
+	failing := newTxnRecordingProducer(t)
+	failing.ExpectSendMessageAndSucceed()
+	expErr := fmt.Errorf("failed to commit")
+	failing.commitErr = expErr
+	failing.commitStatus = sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError
+
+	replacement := newTxnRecordingProducer(t)
+	replacement.ExpectSendMessageAndSucceed()
+
+	p := kafkaLogsProducer{
+		producer: &txnProducer{
+			SyncProducer: failing,
+			newProducer: func() (sarama.SyncProducer, error) {
+				return replacement, nil
+			},
+		},
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+
+	err := p.logsDataPusher(context.Background(), testdata.GenerateLogs(1))
+	assert.ErrorIs(t, err, expErr)
+	assert.Equal(t, []string{"begin", "commit", "close"}, failing.calls)
+
+	// the data is sent again with the new producer
+	err = p.logsDataPusher(context.Background(), testdata.GenerateLogs(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"begin", "commit"}, replacement.calls)
+}
+
+func TestTxnProducer_replace_err(t *testing.T) {
+	failing := newTxnRecordingProducer(t)
+	replacement := newTxnRecordingProducer(t)
+	replacement.ExpectSendMessageAndSucceed()
+	newProducerErr := fmt.Errorf("brokers not available")
+	producer := &txnProducer{
+		SyncProducer: failing,
+		newProducer: func() (sarama.SyncProducer, error) {
+			return nil, newProducerErr
+		},
+	}
+
+	assert.ErrorIs(t, producer.replace(), newProducerErr)
+	assert.Equal(t, []string{"close"}, failing.calls)
+	assert.ErrorIs(t, producer.BeginTxn(), newProducerErr)
+
+	// the producer is created when the next transaction begins
+	producer.newProducer = func() (sarama.SyncProducer, error) {
+		return replacement, nil
+	}
+	require.NoError(t, sendMessages(producer, &sync.Mutex{}, []*sarama.ProducerMessage{{Topic: "otlp_logs"}}))
+	assert.Equal(t, []string{"begin", "commit"}, replacement.calls)
+	require.NoError(t, producer.Close())
+}
+
+// txnRecordingProducer is a transactional mock producer recording the transaction calls.
+type txnRecordingProducer struct {
+	*mocks.SyncProducer
+	calls        []string
+	commitErr    error
+	commitStatus sarama.ProducerTxnStatusFlag
+}
+
+func newTxnRecordingProducer(t *testing.T) *txnRecordingProducer {
+	c := sarama.NewConfig()
+	c.Producer.Idempotent = true
+	c.Producer.RequiredAcks = sarama.WaitForAll
+	c.Net.MaxOpenRequests = 1
+	c.Producer.Transaction.ID = "otelcol"
+	return &txnRecordingProducer{SyncProducer: mocks.NewSyncProducer(t, c)}
+}
+
+func (p *txnRecordingProducer) BeginTxn() error {
+	p.calls = append(p.calls, "begin")
+	return p.SyncProducer.BeginTxn()
+}
+
+func (p *txnRecordingProducer) CommitTxn() error {
+	p.calls = append(p.calls, "commit")
+	if p.commitErr != nil {
+		return p.commitErr
+	}
+	return p.SyncProducer.CommitTxn()
+}
+
+func (p *txnRecordingProducer) AbortTxn() error {
+	p.calls = append(p.calls, "abort")
+	return p.SyncProducer.AbortTxn()
+}
+
+func (p *txnRecordingProducer) Close() error {
+	p.calls = append(p.calls, "close")
+	return p.SyncProducer.Close()
+}
+
+func (p *txnRecordingProducer) TxnStatus() sarama.ProducerTxnStatusFlag {
+	if p.commitErr != nil {
+		return p.commitStatus
+	}
+	return p.SyncProducer.TxnStatus()
+}
+
 type tracesErrorMarshaler struct {
 	err error
 }
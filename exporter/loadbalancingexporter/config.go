@@ -4,12 +4,13 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
-	"time"
+	"errors"
 
-	"github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 )
 
 type routingKey int
@@ -39,6 +40,19 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+	// RoutingKeyExpression is an OTTL value expression whose value is used as the routing key,
+	// evaluated for each span, log record or metric data point. It cannot be set with RoutingKey.
+	RoutingKeyExpression string `mapstructure:"routing_key_expression"`
+}
+
+var errRoutingKeyAndExpression = errors.New("routing_key and routing_key_expression cannot be both set")
+
+// Validate checks that the routing key is set in one way only.
+func (cfg *Config) Validate() error {
+	if cfg.RoutingKey != "" && cfg.RoutingKeyExpression != "" {
+		return errRoutingKeyAndExpression
+	}
+	return nil
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -47,38 +61,16 @@ type Protocol struct {
 }
 
 // ResolverSettings defines the configurations for the backend resolver
-type ResolverSettings struct {
-	Static      *StaticResolver      `mapstructure:"static"`
-	DNS         *DNSResolver         `mapstructure:"dns"`
-	K8sSvc      *K8sSvcResolver      `mapstructure:"k8s"`
-	AWSCloudMap *AWSCloudMapResolver `mapstructure:"aws_cloud_map"`
-}
+type ResolverSettings = tracerouter.ResolverSettings
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
-type StaticResolver struct {
-	Hostnames []string `mapstructure:"hostnames"`
-}
+type StaticResolver = tracerouter.StaticResolver
 
 // DNSResolver defines the configuration for the DNS resolver
-type DNSResolver struct {
-	Hostname string        `mapstructure:"hostname"`
-	Port     string        `mapstructure:"port"`
-	Interval time.Duration `mapstructure:"interval"`
-	Timeout  time.Duration `mapstructure:"timeout"`
-}
+type DNSResolver = tracerouter.DNSResolver
 
-// K8sSvcResolver defines the configuration for the DNS resolver
-type K8sSvcResolver struct {
-	Service string        `mapstructure:"service"`
-	Ports   []int32       `mapstructure:"ports"`
-	Timeout time.Duration `mapstructure:"timeout"`
-}
+// K8sSvcResolver defines the configuration for the k8s service resolver
+type K8sSvcResolver = tracerouter.K8sSvcResolver
 
-type AWSCloudMapResolver struct {
-	NamespaceName string                   `mapstructure:"namespace"`
-	ServiceName   string                   `mapstructure:"service_name"`
-	HealthStatus  types.HealthStatusFilter `mapstructure:"health_status"`
-	Interval      time.Duration            `mapstructure:"interval"`
-	Timeout       time.Duration            `mapstructure:"timeout"`
-	Port          *uint16                  `mapstructure:"port"`
-}
+// AWSCloudMapResolver defines the configuration for the AWS Cloud Map resolver
+type AWSCloudMapResolver = tracerouter.AWSCloudMapResolver
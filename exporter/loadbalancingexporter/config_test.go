@@ -25,3 +25,17 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, sub.Unmarshal(cfg))
 	require.NotNil(t, cfg)
 }
+
+func TestValidateConfig(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.RoutingKey = svcRoutingStr
+	require.NoError(t, cfg.Validate())
+
+	cfg.RoutingKeyExpression = `attributes["tenant"]`
+	require.Equal(t, errRoutingKeyAndExpression, cfg.Validate())
+
+	cfg.RoutingKey = ""
+	require.NoError(t, cfg.Validate())
+}
@@ -14,25 +14,21 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 )
 
 const (
 	defaultPort = "4317"
 )
 
-var (
-	errNoResolver                = errors.New("no resolvers specified for the exporter")
-	errMultipleResolversProvided = errors.New("only one resolver should be specified")
-)
-
 type componentFactory func(ctx context.Context, endpoint string) (component.Component, error)
 
 type loadBalancer struct {
 	logger *zap.Logger
 	host   component.Host
 
-	res  resolver
-	ring *hashRing
+	res  tracerouter.Resolver
+	ring *tracerouter.HashRing
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
@@ -45,90 +41,13 @@ type loadBalancer struct {
 func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory componentFactory, telemetry *metadata.TelemetryBuilder) (*loadBalancer, error) {
 	oCfg := cfg.(*Config)
 
-	count := 0
-	if oCfg.Resolver.DNS != nil {
-		count++
-	}
-	if oCfg.Resolver.Static != nil {
-		count++
-	}
-	if oCfg.Resolver.AWSCloudMap != nil {
-		count++
-	}
-	if oCfg.Resolver.K8sSvc != nil {
-		count++
-	}
-	if count > 1 {
-		return nil, errMultipleResolversProvided
-	}
-
-	var res resolver
-	if oCfg.Resolver.Static != nil {
-		var err error
-		res, err = newStaticResolver(
-			oCfg.Resolver.Static.Hostnames,
-			telemetry,
-		)
-		if err != nil {
-			return nil, err
-		}
-	}
-	if oCfg.Resolver.DNS != nil {
-		dnsLogger := logger.With(zap.String("resolver", "dns"))
-
-		var err error
-		res, err = newDNSResolver(
-			dnsLogger,
-			oCfg.Resolver.DNS.Hostname,
-			oCfg.Resolver.DNS.Port,
-			oCfg.Resolver.DNS.Interval,
-			oCfg.Resolver.DNS.Timeout,
-			telemetry,
-		)
-		if err != nil {
-			return nil, err
-		}
-	}
-	if oCfg.Resolver.K8sSvc != nil {
-		k8sLogger := logger.With(zap.String("resolver", "k8s service"))
-
-		clt, err := newInClusterClient()
-		if err != nil {
-			return nil, err
-		}
-		res, err = newK8sResolver(
-			clt,
-			k8sLogger,
-			oCfg.Resolver.K8sSvc.Service,
-			oCfg.Resolver.K8sSvc.Ports,
-			oCfg.Resolver.K8sSvc.Timeout,
-			telemetry,
-		)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if oCfg.Resolver.AWSCloudMap != nil {
-		awsCloudMapLogger := logger.With(zap.String("resolver", "aws_cloud_map"))
-		var err error
-		res, err = newCloudMapResolver(
-			awsCloudMapLogger,
-			&oCfg.Resolver.AWSCloudMap.NamespaceName,
-			&oCfg.Resolver.AWSCloudMap.ServiceName,
-			oCfg.Resolver.AWSCloudMap.Port,
-			&oCfg.Resolver.AWSCloudMap.HealthStatus,
-			oCfg.Resolver.AWSCloudMap.Interval,
-			oCfg.Resolver.AWSCloudMap.Timeout,
-			telemetry,
-		)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if res == nil {
-		return nil, errNoResolver
+	res, err := tracerouter.NewResolver(logger, oCfg.Resolver, tracerouter.ResolverTelemetry{
+		NumResolutions:    telemetry.LoadbalancerNumResolutions,
+		NumBackends:       telemetry.LoadbalancerNumBackends,
+		NumBackendUpdates: telemetry.LoadbalancerNumBackendUpdates,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &loadBalancer{
@@ -140,15 +59,15 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 }
 
 func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
-	lb.res.onChange(lb.onBackendChanges)
+	lb.res.OnChange(lb.onBackendChanges)
 	lb.host = host
-	return lb.res.start(ctx)
+	return lb.res.Start(ctx)
 }
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
-	newRing := newHashRing(resolved)
+	newRing := tracerouter.NewHashRing(resolved)
 
-	if !newRing.equal(lb.ring) {
+	if !newRing.Equal(lb.ring) {
 		lb.updateLock.Lock()
 		defer lb.updateLock.Unlock()
 
@@ -218,7 +137,7 @@ func endpointFound(endpoint string, endpoints []string) bool {
 }
 
 func (lb *loadBalancer) Shutdown(ctx context.Context) error {
-	err := lb.res.shutdown(ctx)
+	err := lb.res.Shutdown(ctx)
 	lb.stopped = true
 
 	for _, e := range lb.exporters {
@@ -234,7 +153,7 @@ func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
-	endpoint := lb.ring.endpointFor(identifier)
+	endpoint := lb.ring.EndpointFor(identifier)
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
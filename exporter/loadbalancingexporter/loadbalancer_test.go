@@ -16,6 +16,8 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 )
 
 func TestNewLoadBalancerNoResolver(t *testing.T) {
@@ -28,7 +30,7 @@ func TestNewLoadBalancerNoResolver(t *testing.T) {
 
 	// verify
 	require.Nil(t, p)
-	require.Equal(t, errNoResolver, err)
+	require.Equal(t, tracerouter.ErrNoResolver, err)
 }
 
 func TestNewLoadBalancerInvalidStaticResolver(t *testing.T) {
@@ -45,7 +47,7 @@ func TestNewLoadBalancerInvalidStaticResolver(t *testing.T) {
 
 	// verify
 	require.Nil(t, p)
-	require.Equal(t, errNoEndpoints, err)
+	require.EqualError(t, err, "no endpoints specified for the static resolver")
 }
 
 func TestNewLoadBalancerInvalidDNSResolver(t *testing.T) {
@@ -64,7 +66,7 @@ func TestNewLoadBalancerInvalidDNSResolver(t *testing.T) {
 
 	// verify
 	require.Nil(t, p)
-	require.Equal(t, errNoHostname, err)
+	require.EqualError(t, err, "no hostname specified to resolve the backends")
 }
 
 func TestNewLoadBalancerInvalidK8sResolver(t *testing.T) {
@@ -83,7 +85,7 @@ func TestNewLoadBalancerInvalidK8sResolver(t *testing.T) {
 
 	// verify
 	assert.Nil(t, p)
-	assert.True(t, clientcmd.IsConfigurationInvalid(err) || errors.Is(err, errNoSvc))
+	assert.True(t, clientcmd.IsConfigurationInvalid(err) || err.Error() == "no service specified to resolve the backends")
 }
 
 func TestLoadBalancerStart(t *testing.T) {
@@ -119,12 +121,8 @@ func TestWithDNSResolver(t *testing.T) {
 	require.NotNil(t, p)
 	require.NoError(t, err)
 
-	// test
-	res, ok := p.res.(*dnsResolver)
-
 	// verify
-	assert.NotNil(t, res)
-	assert.True(t, ok)
+	assert.NotNil(t, p.res)
 }
 
 func TestWithDNSResolverNoEndpoints(t *testing.T) {
@@ -171,7 +169,7 @@ func TestMultipleResolvers(t *testing.T) {
 
 	// verify
 	assert.Nil(t, p)
-	assert.Equal(t, errMultipleResolversProvided, err)
+	assert.Equal(t, tracerouter.ErrMultipleResolversProvided, err)
 }
 
 func TestStartFailureStaticResolver(t *testing.T) {
@@ -225,14 +223,14 @@ func TestOnBackendChanges(t *testing.T) {
 
 	// test
 	p.onBackendChanges([]string{"endpoint-1"})
-	require.Len(t, p.ring.items, defaultWeight)
+	require.True(t, p.ring.Equal(tracerouter.NewHashRing([]string{"endpoint-1"})))
 
 	// this should resolve to two endpoints
 	endpoints := []string{"endpoint-1", "endpoint-2"}
 	p.onBackendChanges(endpoints)
 
 	// verify
-	assert.Len(t, p.ring.items, 2*defaultWeight)
+	assert.True(t, p.ring.Equal(tracerouter.NewHashRing(endpoints)))
 }
 
 func TestRemoveExtraExporters(t *testing.T) {
@@ -393,7 +391,9 @@ func TestFailedExporterInRing(t *testing.T) {
 	delete(p.exporters, endpointWithPort(resEndpoint))
 
 	// sanity check
-	require.Contains(t, p.res.(*staticResolver).endpoints, resEndpoint)
+	resolved, err := p.res.Resolve(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, resolved, resEndpoint)
 
 	// test
 	// this trace ID will reach the endpoint-2 -- see the consistent hashing tests for more info
@@ -426,7 +426,7 @@ func TestNewLoadBalancerInvalidNamespaceAwsResolver(t *testing.T) {
 
 	// verify
 	assert.Nil(t, p)
-	assert.True(t, clientcmd.IsConfigurationInvalid(err) || errors.Is(err, errNoNamespace))
+	assert.True(t, clientcmd.IsConfigurationInvalid(err) || err.Error() == "no Cloud Map namespace specified to resolve the backends")
 }
 
 func TestNewLoadBalancerInvalidServiceAwsResolver(t *testing.T) {
@@ -446,7 +446,7 @@ func TestNewLoadBalancerInvalidServiceAwsResolver(t *testing.T) {
 
 	// verify
 	assert.Nil(t, p)
-	assert.True(t, clientcmd.IsConfigurationInvalid(err) || errors.Is(err, errNoServiceName))
+	assert.True(t, clientcmd.IsConfigurationInvalid(err) || err.Error() == "no Cloud Map service_name specified to resolve the backends")
 }
 
 func newNopMockExporter() *wrappedExporter {
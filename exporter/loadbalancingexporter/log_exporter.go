@@ -5,7 +5,10 @@ package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
@@ -15,18 +18,25 @@ import (
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
 )
 
 var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
 	loadBalancer *loadBalancer
+	routingKey   routingKey
+	routingExpr  *ottl.ValueExpression[ottllog.TransformContext]
 
 	logger     *zap.Logger
 	started    bool
@@ -53,11 +63,30 @@ func newLogsExporter(params exporter.Settings, cfg component.Config) (*logExport
 		return nil, err
 	}
 
-	return &logExporterImp{
+	logExporter := logExporterImp{
 		loadBalancer: lb,
+		routingKey:   traceIDRouting,
 		telemetry:    telemetry,
 		logger:       params.Logger,
-	}, nil
+	}
+
+	switch cfg.(*Config).RoutingKey {
+	case traceIDRoutingStr, "":
+	case svcRoutingStr:
+		logExporter.routingKey = svcRouting
+	case resourceRoutingStr:
+		logExporter.routingKey = resourceRouting
+	case streamIDRoutingStr:
+		logExporter.routingKey = streamIDRouting
+	default:
+		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
+	}
+
+	logExporter.routingExpr, err = newRoutingExpression(cfg.(*Config), params.TelemetrySettings, ottllog.NewParser)
+	if err != nil {
+		return nil, err
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -80,6 +109,10 @@ func (e *logExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	if e.routingExpr != nil || e.routingKey != traceIDRouting {
+		return e.consumeLogsByRoutingID(ctx, ld)
+	}
+
 	var errs error
 	batches := batchpersignal.SplitLogs(ld)
 	for _, batch := range batches {
@@ -121,6 +154,165 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	return err
 }
 
+// consumeLogsByRoutingID sends the logs sharing the same routing identifier to the same backend,
+// the batches of each backend being merged into a single request.
+func (e *logExporterImp) consumeLogsByRoutingID(ctx context.Context, ld plog.Logs) error {
+	var batches map[string]plog.Logs
+	var err error
+	switch {
+	case e.routingExpr != nil:
+		batches, err = splitLogsByExpression(ctx, ld, e.routingExpr)
+	case e.routingKey == svcRouting:
+		batches, err = splitLogsByResourceServiceName(ld)
+	case e.routingKey == resourceRouting:
+		batches = splitLogsByResourceID(ld)
+	case e.routingKey == streamIDRouting:
+		batches = splitLogsByStreamID(ld)
+	}
+	if err != nil {
+		return err
+	}
+
+	logsByExporter := map[*wrappedExporter]plog.Logs{}
+	for routingID, batch := range batches {
+		exp, _, err := e.loadBalancer.exporterAndEndpoint([]byte(routingID))
+		if err != nil {
+			return err
+		}
+
+		expLogs, ok := logsByExporter[exp]
+		if !ok {
+			exp.consumeWG.Add(1)
+			expLogs = plog.NewLogs()
+			logsByExporter[exp] = expLogs
+		}
+		batch.ResourceLogs().MoveAndAppendTo(expLogs.ResourceLogs())
+	}
+
+	var errs error
+	for exp, expLogs := range logsByExporter {
+		start := time.Now()
+		err := exp.ConsumeLogs(ctx, expLogs)
+		duration := time.Since(start)
+
+		exp.consumeWG.Done()
+		errs = multierr.Append(errs, err)
+		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
+		if err == nil {
+			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.successAttr))
+		} else {
+			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.failureAttr))
+			e.logger.Debug("failed to export logs", zap.Error(err))
+		}
+	}
+
+	return errs
+}
+
+func splitLogsByResourceServiceName(ld plog.Logs) (map[string]plog.Logs, error) {
+	results := map[string]plog.Logs{}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+
+		svc, ok := rl.Resource().Attributes().Get(conventions.AttributeServiceName)
+		if !ok {
+			return nil, errors.New("unable to get service name")
+		}
+
+		appendResourceLogs(results, svc.Str(), rl)
+	}
+
+	return results, nil
+}
+
+func splitLogsByResourceID(ld plog.Logs) map[string]plog.Logs {
+	results := map[string]plog.Logs{}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		appendResourceLogs(results, identity.OfResource(rl.Resource()).String(), rl)
+	}
+
+	return results
+}
+
+// splitLogsByStreamID groups the log records by the identity of their resource, their scope
+// and their attributes.
+func splitLogsByStreamID(ld plog.Logs) map[string]plog.Logs {
+	var lastScope plog.ScopeLogs
+	var scope identity.Scope
+	results, _ := splitLogsByRecord(ld, func(rl plog.ResourceLogs, sl plog.ScopeLogs, lr plog.LogRecord) (string, error) {
+		// the identity of the scope is only computed once for all its records
+		if sl != lastScope {
+			lastScope = sl
+			scope = identity.OfScope(identity.OfResource(rl.Resource()), sl.Scope())
+		}
+		sum := scope.Hash()
+		attrs := pdatautil.MapHash(lr.Attributes())
+		sum.Write(attrs[:])
+		return strconv.FormatUint(sum.Sum64(), 16), nil
+	})
+	return results
+}
+
+// splitLogsByRecord groups the log records by the routing identifier returned for each of them.
+func splitLogsByRecord(ld plog.Logs, routingID func(plog.ResourceLogs, plog.ScopeLogs, plog.LogRecord) (string, error)) (map[string]plog.Logs, error) {
+	results := map[string]plog.Logs{}
+	scopes := map[string]plog.ScopeLogs{}
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			// the scopes of the batches are only reused within the same input scope
+			clear(scopes)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				key, err := routingID(rl, sl, lr)
+				if err != nil {
+					return nil, err
+				}
+
+				slClone, ok := scopes[key]
+				if !ok {
+					batch, ok := results[key]
+					if !ok {
+						batch = plog.NewLogs()
+						results[key] = batch
+					}
+					slClone = cloneScopeLogs(batch, rl, sl)
+					scopes[key] = slClone
+				}
+				lr.CopyTo(slClone.LogRecords().AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func appendResourceLogs(results map[string]plog.Logs, key string, rl plog.ResourceLogs) {
+	batch, ok := results[key]
+	if !ok {
+		batch = plog.NewLogs()
+		results[key] = batch
+	}
+	rl.CopyTo(batch.ResourceLogs().AppendEmpty())
+}
+
+// cloneScopeLogs appends the resource and scope of the log records to the logs, without the records.
+func cloneScopeLogs(ld plog.Logs, rl plog.ResourceLogs, sl plog.ScopeLogs) plog.ScopeLogs {
+	rlClone := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().CopyTo(rlClone.Resource())
+	rlClone.SetSchemaUrl(rl.SchemaUrl())
+
+	slClone := rlClone.ScopeLogs().AppendEmpty()
+	sl.Scope().CopyTo(slClone.Scope())
+	slClone.SetSchemaUrl(sl.SchemaUrl())
+	return slClone
+}
+
 func traceIDFromLogs(ld plog.Logs) pcommon.TraceID {
 	rl := ld.ResourceLogs()
 	if rl.Len() == 0 {
@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -23,7 +22,8 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
-	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 )
 
 func TestNewLogsExporter(t *testing.T) {
@@ -40,7 +40,17 @@ func TestNewLogsExporter(t *testing.T) {
 		{
 			"empty",
 			&Config{},
-			errNoResolver,
+			tracerouter.ErrNoResolver,
+		},
+		{
+			"service",
+			serviceBasedRoutingConfig(),
+			nil,
+		},
+		{
+			"resource",
+			resourceBasedRoutingConfig(),
+			nil,
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -53,6 +63,140 @@ func TestNewLogsExporter(t *testing.T) {
 	}
 }
 
+func TestNewLogsExporterUnsupportedRoutingKey(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.RoutingKey = metricNameRoutingStr
+
+	_, err := newLogsExporter(exportertest.NewNopSettings(), cfg)
+	require.EqualError(t, err, `unsupported routing_key: "metric"`)
+}
+
+func TestSplitLogsByResourceServiceName(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, svc := range []string{"svc-a", "svc-b", "svc-a"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", svc)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(svc)
+	}
+
+	batches, err := splitLogsByResourceServiceName(ld)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches["svc-a"].LogRecordCount())
+	assert.Equal(t, 1, batches["svc-b"].LogRecordCount())
+
+	ld.ResourceLogs().AppendEmpty()
+	_, err = splitLogsByResourceServiceName(ld)
+	require.EqualError(t, err, "unable to get service name")
+}
+
+func TestSplitLogsByResourceID(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, host := range []string{"host-a", "host-b", "host-a"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "svc")
+		rl.Resource().Attributes().PutStr("host.name", host)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+
+	batches := splitLogsByResourceID(ld)
+	require.Len(t, batches, 2)
+	for _, batch := range batches {
+		host, _ := batch.ResourceLogs().At(0).Resource().Attributes().Get("host.name")
+		if host.Str() == "host-a" {
+			assert.Equal(t, 2, batch.LogRecordCount())
+		} else {
+			assert.Equal(t, 1, batch.LogRecordCount())
+		}
+	}
+}
+
+func TestSplitLogsByStreamID(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "svc")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	for i, source := range []string{"a", "b", "a", "a"} {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Attributes().PutStr("source", source)
+		lr.Body().SetInt(int64(i))
+	}
+
+	batches := splitLogsByStreamID(ld)
+	require.Len(t, batches, 2)
+	for _, batch := range batches {
+		// the records of a stream share the resource and scope of the batch
+		require.Equal(t, 1, batch.ResourceLogs().Len())
+		require.Equal(t, 1, batch.ResourceLogs().At(0).ScopeLogs().Len())
+		assert.Equal(t, "scope", batch.ResourceLogs().At(0).ScopeLogs().At(0).Scope().Name())
+		records := batch.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		source, _ := records.At(0).Attributes().Get("source")
+		if source.Str() == "a" {
+			require.Equal(t, 3, records.Len())
+			assert.Equal(t, int64(3), records.At(2).Body().Int())
+		} else {
+			require.Equal(t, 1, records.Len())
+		}
+	}
+}
+
+func TestConsumeLogsServiceBased(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	sinks := map[string]*consumertest.LogsSink{
+		"endpoint-1:4317": new(consumertest.LogsSink),
+		"endpoint-2:4317": new(consumertest.LogsSink),
+	}
+	componentFactory := func(_ context.Context, endpoint string) (component.Component, error) {
+		return newMockLogsExporter(sinks[endpoint].ConsumeLogs), nil
+	}
+
+	lb, err := newLoadBalancer(ts.Logger, serviceBasedRoutingConfig(), componentFactory, tb)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newLogsExporter(ts, serviceBasedRoutingConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1", "endpoint-2"})
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// the logs without trace ID of a service are always sent to the same backend
+	for i := 0; i < 10; i++ {
+		ld := plog.NewLogs()
+		for _, svc := range []string{"svc-a", "svc-b", "svc-c", "svc-d"} {
+			rl := ld.ResourceLogs().AppendEmpty()
+			rl.Resource().Attributes().PutStr("service.name", svc)
+			rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		}
+		require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+	}
+
+	total := 0
+	seen := map[string]string{}
+	for endpoint, sink := range sinks {
+		total += sink.LogRecordCount()
+		for _, ld := range sink.AllLogs() {
+			for i := 0; i < ld.ResourceLogs().Len(); i++ {
+				svc, _ := ld.ResourceLogs().At(i).Resource().Attributes().Get("service.name")
+				if previous, ok := seen[svc.Str()]; ok {
+					assert.Equal(t, previous, endpoint, "logs of %s sent to several backends", svc.Str())
+				}
+				seen[svc.Str()] = endpoint
+			}
+		}
+	}
+	assert.Equal(t, 40, total)
+	assert.Len(t, seen, 4)
+}
+
 func TestLogExporterStart(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	for _, tt := range []struct {
@@ -343,7 +487,7 @@ func TestConsumeLogs_ConcurrentResolverChange(t *testing.T) {
 	// update endpoint while consuming logs
 	<-consumeStarted
 	endpoints = []string{"endpoint-2"}
-	endpoint, err := lb.res.resolve(context.Background())
+	endpoint, err := lb.res.Resolve(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, endpoints, endpoint)
 	<-consumeDone
@@ -359,12 +503,11 @@ func TestRollingUpdatesWhenConsumeLogs(t *testing.T) {
 	// simulate rolling updates, the dns resolver should resolve in the following order
 	// ["127.0.0.1"] -> ["127.0.0.1", "127.0.0.2"] -> ["127.0.0.2"]
 	ts, tb := getTelemetryAssets(t)
-	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 5*time.Second, 1*time.Second, tb)
-	require.NoError(t, err)
+	res := &mockResolver{triggerCallbacks: true, resInterval: 10 * time.Millisecond}
 
 	mu := sync.Mutex{}
 	var lastResolved []string
-	res.onChange(func(s []string) {
+	res.OnChange(func(s []string) {
 		mu.Lock()
 		lastResolved = s
 		mu.Unlock()
@@ -372,35 +515,27 @@ func TestRollingUpdatesWhenConsumeLogs(t *testing.T) {
 
 	resolverCh := make(chan struct{}, 1)
 	counter := &atomic.Int64{}
-	resolve := [][]net.IPAddr{
-		{
-			{IP: net.IPv4(127, 0, 0, 1)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 1)},
-			{IP: net.IPv4(127, 0, 0, 2)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 2)},
-		},
+	resolve := [][]string{
+		{"127.0.0.1"},
+		{"127.0.0.1", "127.0.0.2"},
+		{"127.0.0.2"},
 	}
-	res.resolver = &mockDNSResolver{
-		onLookupIPAddr: func(context.Context, string) ([]net.IPAddr, error) {
-			defer func() {
-				counter.Add(1)
-			}()
+	res.onResolve = func(context.Context) ([]string, error) {
+		defer func() {
+			counter.Add(1)
+		}()
 
-			if counter.Load() <= 2 {
-				return resolve[counter.Load()], nil
-			}
+		if counter.Load() <= 2 {
+			return resolve[counter.Load()], nil
+		}
 
-			if counter.Load() == 3 {
-				// stop as soon as rolling updates end
-				resolverCh <- struct{}{}
-			}
+		if counter.Load() == 3 {
+			// stop as soon as rolling updates end
+			resolverCh <- struct{}{}
+		}
 
-			return resolve[2], nil
-		},
+		return resolve[2], nil
 	}
-	res.resInterval = 10 * time.Millisecond
 
 	cfg := &Config{
 		Resolver: ResolverSettings{
@@ -448,7 +583,7 @@ func TestRollingUpdatesWhenConsumeLogs(t *testing.T) {
 	lb.updateLock.Lock()
 	lb.exporters = defaultExporters
 	lb.updateLock.Unlock()
-	lb.res.onChange(func(_ []string) {
+	lb.res.OnChange(func(_ []string) {
 		lb.updateLock.Lock()
 		lb.exporters = defaultExporters
 		lb.updateLock.Unlock()
@@ -23,6 +23,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 )
 
 var _ exporter.Metrics = (*metricExporterImp)(nil)
@@ -30,6 +32,7 @@ var _ exporter.Metrics = (*metricExporterImp)(nil)
 type metricExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
+	routingExpr  *ottl.ValueExpression[ottldatapoint.TransformContext]
 
 	logger     *zap.Logger
 	stopped    bool
@@ -75,6 +78,11 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
 	}
+
+	metricExporter.routingExpr, err = newRoutingExpression(cfg.(*Config), params.TelemetrySettings, ottldatapoint.NewParser)
+	if err != nil {
+		return nil, err
+	}
 	return &metricExporter, nil
 }
 
@@ -96,18 +104,24 @@ func (e *metricExporterImp) Shutdown(ctx context.Context) error {
 func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
 	var batches map[string]pmetric.Metrics
 
-	switch e.routingKey {
-	case svcRouting:
+	switch {
+	case e.routingExpr != nil:
+		var err error
+		batches, err = splitMetricsByExpression(ctx, md, e.routingExpr)
+		if err != nil {
+			return err
+		}
+	case e.routingKey == svcRouting:
 		var err error
 		batches, err = splitMetricsByResourceServiceName(md)
 		if err != nil {
 			return err
 		}
-	case resourceRouting:
+	case e.routingKey == resourceRouting:
 		batches = splitMetricsByResourceID(md)
-	case metricNameRouting:
+	case e.routingKey == metricNameRouting:
 		batches = splitMetricsByMetricName(md)
-	case streamIDRouting:
+	case e.routingKey == streamIDRouting:
 		batches = splitMetricsByStreamID(md)
 	}
 
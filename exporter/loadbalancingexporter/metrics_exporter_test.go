@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"net"
 	"os"
 	"path/filepath"
 	"sync"
@@ -29,6 +28,7 @@ import (
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"gopkg.in/yaml.v2"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
 )
@@ -64,7 +64,7 @@ func TestNewMetricsExporter(t *testing.T) {
 		{
 			"empty routing key",
 			&Config{},
-			errNoResolver,
+			tracerouter.ErrNoResolver,
 		},
 		{
 			"service",
@@ -86,7 +86,7 @@ func TestNewMetricsExporter(t *testing.T) {
 			&Config{
 				RoutingKey: traceIDRoutingStr,
 			},
-			errNoResolver,
+			tracerouter.ErrNoResolver,
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -591,7 +591,7 @@ func TestConsumeMetrics_ConcurrentResolverChange(t *testing.T) {
 	// update endpoint while consuming logs
 	<-consumeStarted
 	endpoints = []string{"endpoint-2"}
-	endpoint, err := lb.res.resolve(context.Background())
+	endpoint, err := lb.res.Resolve(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, endpoints, endpoint)
 	<-consumeDone
@@ -710,12 +710,11 @@ func TestRollingUpdatesWhenConsumeMetrics(t *testing.T) {
 
 	// simulate rolling updates, the dns resolver should resolve in the following order
 	// ["127.0.0.1"] -> ["127.0.0.1", "127.0.0.2"] -> ["127.0.0.2"]
-	res, err := newDNSResolver(ts.Logger, "service-1", "", 5*time.Second, 1*time.Second, tb)
-	require.NoError(t, err)
+	res := &mockResolver{triggerCallbacks: true, resInterval: 10 * time.Millisecond}
 
 	mu := sync.Mutex{}
 	var lastResolved []string
-	res.onChange(func(s []string) {
+	res.OnChange(func(s []string) {
 		mu.Lock()
 		lastResolved = s
 		mu.Unlock()
@@ -723,35 +722,27 @@ func TestRollingUpdatesWhenConsumeMetrics(t *testing.T) {
 
 	resolverCh := make(chan struct{}, 1)
 	counter := &atomic.Int64{}
-	resolve := [][]net.IPAddr{
-		{
-			{IP: net.IPv4(127, 0, 0, 1)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 1)},
-			{IP: net.IPv4(127, 0, 0, 2)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 2)},
-		},
+	resolve := [][]string{
+		{"127.0.0.1"},
+		{"127.0.0.1", "127.0.0.2"},
+		{"127.0.0.2"},
 	}
-	res.resolver = &mockDNSResolver{
-		onLookupIPAddr: func(context.Context, string) ([]net.IPAddr, error) {
-			defer func() {
-				counter.Add(1)
-			}()
+	res.onResolve = func(context.Context) ([]string, error) {
+		defer func() {
+			counter.Add(1)
+		}()
 
-			if counter.Load() <= 2 {
-				return resolve[counter.Load()], nil
-			}
+		if counter.Load() <= 2 {
+			return resolve[counter.Load()], nil
+		}
 
-			if counter.Load() == 3 {
-				// stop as soon as rolling updates end
-				resolverCh <- struct{}{}
-			}
+		if counter.Load() == 3 {
+			// stop as soon as rolling updates end
+			resolverCh <- struct{}{}
+		}
 
-			return resolve[2], nil
-		},
+		return resolve[2], nil
 	}
-	res.resInterval = 10 * time.Millisecond
 
 	cfg := &Config{
 		Resolver: ResolverSettings{
@@ -797,7 +788,7 @@ func TestRollingUpdatesWhenConsumeMetrics(t *testing.T) {
 	lb.updateLock.Lock()
 	lb.exporters = defaultExporters
 	lb.updateLock.Unlock()
-	lb.res.onChange(func(_ []string) {
+	lb.res.OnChange(func(_ []string) {
 		lb.updateLock.Lock()
 		lb.exporters = defaultExporters
 		lb.updateLock.Unlock()
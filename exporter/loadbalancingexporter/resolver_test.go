@@ -3,39 +3,75 @@
 
 package loadbalancingexporter
 
-import "context"
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+)
 
 type mockResolver struct {
-	onStart           func(context.Context) error
-	onShutdown        func(context.Context) error
-	onResolve         func(ctx context.Context) ([]string, error)
+	onStart          func(context.Context) error
+	onShutdown       func(context.Context) error
+	onResolve        func(ctx context.Context) ([]string, error)
+	triggerCallbacks bool
+	// resInterval, when set, resolves periodically once started, like the DNS resolver.
+	resInterval time.Duration
+
+	callbacksLock     sync.Mutex
 	onChangeCallbacks []func([]string)
-	triggerCallbacks  bool
+	stopCh            chan struct{}
+	shutdownWg        sync.WaitGroup
 }
 
-func (m *mockResolver) start(ctx context.Context) error {
+func (m *mockResolver) Start(ctx context.Context) error {
 	if m.onStart != nil {
 		if err := m.onStart(ctx); err != nil {
 			return err
 		}
 	}
 
-	_, err := m.resolve(ctx)
+	_, err := m.Resolve(ctx)
 	if err != nil {
 		return err
 	}
 
+	if m.resInterval > 0 {
+		m.stopCh = make(chan struct{})
+		m.shutdownWg.Add(1)
+		go m.periodicallyResolve()
+	}
 	return nil
 }
 
-func (m *mockResolver) shutdown(ctx context.Context) error {
+func (m *mockResolver) periodicallyResolve() {
+	defer m.shutdownWg.Done()
+
+	ticker := time.NewTicker(m.resInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_, _ = m.Resolve(context.Background())
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+func (m *mockResolver) Shutdown(ctx context.Context) error {
+	if m.stopCh != nil {
+		close(m.stopCh)
+		m.shutdownWg.Wait()
+	}
 	if m.onShutdown != nil {
 		return m.onShutdown(ctx)
 	}
 	return nil
 }
 
-func (m *mockResolver) resolve(ctx context.Context) ([]string, error) {
+func (m *mockResolver) Resolve(ctx context.Context) ([]string, error) {
 	var resolved []string
 	var err error
 	if m.onResolve != nil {
@@ -43,15 +79,20 @@ func (m *mockResolver) resolve(ctx context.Context) ([]string, error) {
 	}
 
 	if m.triggerCallbacks {
-		for _, callback := range m.onChangeCallbacks {
+		m.callbacksLock.Lock()
+		callbacks := m.onChangeCallbacks
+		m.callbacksLock.Unlock()
+		for _, callback := range callbacks {
 			callback(resolved)
 		}
 	}
 	return resolved, err
 }
 
-func (m *mockResolver) onChange(f func([]string)) {
+func (m *mockResolver) OnChange(f func([]string)) {
+	m.callbacksLock.Lock()
+	defer m.callbacksLock.Unlock()
 	m.onChangeCallbacks = append(m.onChangeCallbacks, f)
 }
 
-var _ resolver = (*mockResolver)(nil)
+var _ tracerouter.Resolver = (*mockResolver)(nil)
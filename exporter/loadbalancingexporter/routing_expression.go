@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// newRoutingExpression parses the routing_key_expression of the config with the parser of an OTTL
+// context, such as ottlspan.NewParser, returning nil if no expression is configured.
+func newRoutingExpression[K any, O any](
+	cfg *Config,
+	set component.TelemetrySettings,
+	newParser func(map[string]ottl.Factory[K], component.TelemetrySettings, ...O) (ottl.Parser[K], error),
+) (*ottl.ValueExpression[K], error) {
+	if cfg.RoutingKeyExpression == "" {
+		return nil, nil
+	}
+	parser, err := newParser(ottlfuncs.StandardConverters[K](), set)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := parser.ParseValueExpression(cfg.RoutingKeyExpression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing_key_expression: %w", err)
+	}
+	return expr, nil
+}
+
+// routingKeyFromExpression evaluates the expression and returns its value as a routing key.
+// Records for which the expression evaluates to nil, such as missing attributes, share the
+// empty routing key.
+func routingKeyFromExpression[K any](ctx context.Context, expr *ottl.ValueExpression[K], tCtx K) (string, error) {
+	v, err := expr.Eval(ctx, tCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate routing_key_expression: %w", err)
+	}
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case pcommon.Value:
+		return val.AsString(), nil
+	case pcommon.Map:
+		value := pcommon.NewValueMap()
+		val.CopyTo(value.Map())
+		return value.AsString(), nil
+	case pcommon.Slice:
+		value := pcommon.NewValueSlice()
+		val.CopyTo(value.Slice())
+		return value.AsString(), nil
+	}
+	value := pcommon.NewValueEmpty()
+	if err = value.FromRaw(v); err != nil {
+		return fmt.Sprint(v), nil
+	}
+	return value.AsString(), nil
+}
+
+// splitTracesByExpression groups the spans by the value of the routing expression.
+func splitTracesByExpression(ctx context.Context, td ptrace.Traces, expr *ottl.ValueExpression[ottlspan.TransformContext]) (map[string]ptrace.Traces, error) {
+	results := map[string]ptrace.Traces{}
+	scopes := map[string]ptrace.ScopeSpans{}
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			// the scopes of the batches are only reused within the same input scope
+			clear(scopes)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				key, err := routingKeyFromExpression(ctx, expr, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs))
+				if err != nil {
+					return nil, err
+				}
+
+				ssClone, ok := scopes[key]
+				if !ok {
+					batch, ok := results[key]
+					if !ok {
+						batch = ptrace.NewTraces()
+						results[key] = batch
+					}
+					rsClone := batch.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(rsClone.Resource())
+					rsClone.SetSchemaUrl(rs.SchemaUrl())
+					ssClone = rsClone.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(ssClone.Scope())
+					ssClone.SetSchemaUrl(ss.SchemaUrl())
+					scopes[key] = ssClone
+				}
+				span.CopyTo(ssClone.Spans().AppendEmpty())
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// splitLogsByExpression groups the log records by the value of the routing expression.
+func splitLogsByExpression(ctx context.Context, ld plog.Logs, expr *ottl.ValueExpression[ottllog.TransformContext]) (map[string]plog.Logs, error) {
+	return splitLogsByRecord(ld, func(rl plog.ResourceLogs, sl plog.ScopeLogs, lr plog.LogRecord) (string, error) {
+		return routingKeyFromExpression(ctx, expr, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource(), sl, rl))
+	})
+}
+
+// splitMetricsByExpression groups the metric data points by the value of the routing expression.
+func splitMetricsByExpression(ctx context.Context, md pmetric.Metrics, expr *ottl.ValueExpression[ottldatapoint.TransformContext]) (map[string]pmetric.Metrics, error) {
+	results := map[string]pmetric.Metrics{}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+				routingKey := func(dp any) (string, error) {
+					return routingKeyFromExpression(ctx, expr, ottldatapoint.NewTransformContext(dp, m, sm.Metrics(), sm.Scope(), rm.Resource(), sm, rm))
+				}
+				add := func(key string, newMD pmetric.Metrics) {
+					existing, ok := results[key]
+					if ok {
+						metrics.Merge(existing, newMD)
+					} else {
+						results[key] = newMD
+					}
+				}
+
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					gauge := m.Gauge()
+
+					for l := 0; l < gauge.DataPoints().Len(); l++ {
+						dp := gauge.DataPoints().At(l)
+						key, err := routingKey(dp)
+						if err != nil {
+							return nil, err
+						}
+
+						newMD, mClone := cloneMetricWithoutType(rm, sm, m)
+						dp.CopyTo(mClone.SetEmptyGauge().DataPoints().AppendEmpty())
+						add(key, newMD)
+					}
+				case pmetric.MetricTypeSum:
+					sum := m.Sum()
+
+					for l := 0; l < sum.DataPoints().Len(); l++ {
+						dp := sum.DataPoints().At(l)
+						key, err := routingKey(dp)
+						if err != nil {
+							return nil, err
+						}
+
+						newMD, mClone := cloneMetricWithoutType(rm, sm, m)
+						sumClone := mClone.SetEmptySum()
+						sumClone.SetIsMonotonic(sum.IsMonotonic())
+						sumClone.SetAggregationTemporality(sum.AggregationTemporality())
+						dp.CopyTo(sumClone.DataPoints().AppendEmpty())
+						add(key, newMD)
+					}
+				case pmetric.MetricTypeHistogram:
+					histogram := m.Histogram()
+
+					for l := 0; l < histogram.DataPoints().Len(); l++ {
+						dp := histogram.DataPoints().At(l)
+						key, err := routingKey(dp)
+						if err != nil {
+							return nil, err
+						}
+
+						newMD, mClone := cloneMetricWithoutType(rm, sm, m)
+						histogramClone := mClone.SetEmptyHistogram()
+						histogramClone.SetAggregationTemporality(histogram.AggregationTemporality())
+						dp.CopyTo(histogramClone.DataPoints().AppendEmpty())
+						add(key, newMD)
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					expHistogram := m.ExponentialHistogram()
+
+					for l := 0; l < expHistogram.DataPoints().Len(); l++ {
+						dp := expHistogram.DataPoints().At(l)
+						key, err := routingKey(dp)
+						if err != nil {
+							return nil, err
+						}
+
+						newMD, mClone := cloneMetricWithoutType(rm, sm, m)
+						expHistogramClone := mClone.SetEmptyExponentialHistogram()
+						expHistogramClone.SetAggregationTemporality(expHistogram.AggregationTemporality())
+						dp.CopyTo(expHistogramClone.DataPoints().AppendEmpty())
+						add(key, newMD)
+					}
+				case pmetric.MetricTypeSummary:
+					summary := m.Summary()
+
+					for l := 0; l < summary.DataPoints().Len(); l++ {
+						dp := summary.DataPoints().At(l)
+						key, err := routingKey(dp)
+						if err != nil {
+							return nil, err
+						}
+
+						newMD, mClone := cloneMetricWithoutType(rm, sm, m)
+						dp.CopyTo(mClone.SetEmptySummary().DataPoints().AppendEmpty())
+						add(key, newMD)
+					}
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+)
+
+func expressionBasedRoutingConfig(expression string) *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKeyExpression: expression,
+	}
+}
+
+func TestNewExportersWithRoutingExpression(t *testing.T) {
+	set := exportertest.NewNopSettings()
+
+	cfg := expressionBasedRoutingConfig(`resource.attributes["tenant"]`)
+	_, err := newTracesExporter(set, cfg)
+	require.NoError(t, err)
+	_, err = newMetricsExporter(set, cfg)
+	require.NoError(t, err)
+	_, err = newLogsExporter(set, cfg)
+	require.NoError(t, err)
+
+	invalid := expressionBasedRoutingConfig(`resource.attributes["tenant"`)
+	_, err = newTracesExporter(set, invalid)
+	assert.ErrorContains(t, err, "invalid routing_key_expression")
+	_, err = newMetricsExporter(set, invalid)
+	assert.ErrorContains(t, err, "invalid routing_key_expression")
+	_, err = newLogsExporter(set, invalid)
+	assert.ErrorContains(t, err, "invalid routing_key_expression")
+
+}
+
+func TestRoutingKeyFromExpression(t *testing.T) {
+	for _, tt := range []struct {
+		expression string
+		expected   string
+	}{
+		{expression: `attributes["str"]`, expected: "value"},
+		{expression: `attributes["int"]`, expected: "42"},
+		{expression: `attributes["map"]`, expected: `{"key":"value"}`},
+		{expression: `attributes["missing"]`, expected: ""},
+		{expression: `Concat([resource.attributes["service.name"], attributes["str"]], "/")`, expected: "svc/value"},
+		{expression: `severity_number`, expected: "9"},
+	} {
+		t.Run(tt.expression, func(t *testing.T) {
+			expr, err := newRoutingExpression(&Config{RoutingKeyExpression: tt.expression}, componenttest.NewNopTelemetrySettings(), ottllog.NewParser)
+			require.NoError(t, err)
+
+			ld := plog.NewLogs()
+			rl := ld.ResourceLogs().AppendEmpty()
+			rl.Resource().Attributes().PutStr("service.name", "svc")
+			sl := rl.ScopeLogs().AppendEmpty()
+			lr := sl.LogRecords().AppendEmpty()
+			lr.SetSeverityNumber(plog.SeverityNumberInfo)
+			lr.Attributes().PutStr("str", "value")
+			lr.Attributes().PutInt("int", 42)
+			lr.Attributes().PutEmptyMap("map").PutStr("key", "value")
+
+			key, err := routingKeyFromExpression(context.Background(), expr, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource(), sl, rl))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, key)
+		})
+	}
+}
+
+func TestSplitTracesByExpression(t *testing.T) {
+	expr, err := newRoutingExpression(expressionBasedRoutingConfig(`attributes["tenant"]`), componenttest.NewNopTelemetrySettings(), ottlspan.NewParser)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "svc")
+	ss := rs.ScopeSpans().AppendEmpty()
+	for _, tenant := range []string{"a", "b", "a"} {
+		ss.Spans().AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	batches, err := splitTracesByExpression(context.Background(), td, expr)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	require.Equal(t, 1, batches["a"].ResourceSpans().Len())
+	assert.Equal(t, 2, batches["a"].SpanCount())
+	assert.Equal(t, 1, batches["b"].SpanCount())
+	svc, _ := batches["b"].ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "svc", svc.Str())
+}
+
+func TestSplitLogsByExpression(t *testing.T) {
+	expr, err := newRoutingExpression(expressionBasedRoutingConfig(`body`), componenttest.NewNopTelemetrySettings(), ottllog.NewParser)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	for _, body := range []string{"a", "b", "a"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr(body)
+	}
+
+	batches, err := splitLogsByExpression(context.Background(), ld, expr)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	assert.Equal(t, 2, batches["a"].LogRecordCount())
+	assert.Equal(t, 2, batches["a"].ResourceLogs().Len())
+	assert.Equal(t, 1, batches["b"].LogRecordCount())
+}
+
+func TestSplitMetricsByExpression(t *testing.T) {
+	expr, err := newRoutingExpression(expressionBasedRoutingConfig(`attributes["tenant"]`), componenttest.NewNopTelemetrySettings(), ottldatapoint.NewParser)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	for _, tenant := range []string{"a", "b"} {
+		sum.Sum().DataPoints().AppendEmpty().Attributes().PutStr("tenant", tenant)
+		histogram.Histogram().DataPoints().AppendEmpty().Attributes().PutStr("tenant", tenant)
+	}
+
+	batches, err := splitMetricsByExpression(context.Background(), md, expr)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	for tenant, batch := range batches {
+		assert.Equal(t, 2, batch.MetricCount())
+		assert.Equal(t, 2, batch.DataPointCount())
+		metrics := batch.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		assert.True(t, metrics.At(0).Sum().IsMonotonic())
+		assert.Equal(t, pmetric.AggregationTemporalityDelta, metrics.At(1).Histogram().AggregationTemporality())
+		value, _ := metrics.At(0).Sum().DataPoints().At(0).Attributes().Get("tenant")
+		assert.Equal(t, tenant, value.Str())
+	}
+}
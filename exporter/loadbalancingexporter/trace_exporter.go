@@ -21,6 +21,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 var _ exporter.Traces = (*traceExporterImp)(nil)
@@ -30,6 +32,7 @@ type exporterTraces map[*wrappedExporter]ptrace.Traces
 type traceExporterImp struct {
 	loadBalancer *loadBalancer
 	routingKey   routingKey
+	routingExpr  *ottl.ValueExpression[ottlspan.TransformContext]
 
 	logger     *zap.Logger
 	stopped    bool
@@ -71,6 +74,11 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
 	}
+
+	traceExporter.routingExpr, err = newRoutingExpression(cfg.(*Config), params.TelemetrySettings, ottlspan.NewParser)
+	if err != nil {
+		return nil, err
+	}
 	return &traceExporter, nil
 }
 
@@ -90,30 +98,47 @@ func (e *traceExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
-	batches := batchpersignal.SplitTraces(td)
-
 	exporterSegregatedTraces := make(exporterTraces)
 	endpoints := make(map[*wrappedExporter]string)
-	for _, batch := range batches {
-		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
+	route := func(rid string, batch ptrace.Traces) error {
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
 		if err != nil {
 			return err
 		}
 
-		for rid := range routingID {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+		_, ok := exporterSegregatedTraces[exp]
+		if !ok {
+			exp.consumeWG.Add(1)
+			exporterSegregatedTraces[exp] = ptrace.NewTraces()
+		}
+		exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
+
+		endpoints[exp] = endpoint
+		return nil
+	}
+
+	if e.routingExpr != nil {
+		batches, err := splitTracesByExpression(ctx, td, e.routingExpr)
+		if err != nil {
+			return err
+		}
+		for rid, batch := range batches {
+			if err := route(rid, batch); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, batch := range batchpersignal.SplitTraces(td) {
+			routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
 			if err != nil {
 				return err
 			}
 
-			_, ok := exporterSegregatedTraces[exp]
-			if !ok {
-				exp.consumeWG.Add(1)
-				exporterSegregatedTraces[exp] = ptrace.NewTraces()
+			for rid := range routingID {
+				if err := route(rid, batch); err != nil {
+					return err
+				}
 			}
-			exporterSegregatedTraces[exp] = mergeTraces(exporterSegregatedTraces[exp], batch)
-
-			endpoints[exp] = endpoint
 		}
 	}
 
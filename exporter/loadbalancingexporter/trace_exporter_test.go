@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"net"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -25,6 +24,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 )
 
 func TestNewTracesExporter(t *testing.T) {
@@ -41,7 +42,7 @@ func TestNewTracesExporter(t *testing.T) {
 		{
 			"empty",
 			&Config{},
-			errNoResolver,
+			tracerouter.ErrNoResolver,
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
@@ -199,7 +200,7 @@ func TestConsumeTraces_ConcurrentResolverChange(t *testing.T) {
 	// update endpoint while consuming traces
 	<-consumeStarted
 	endpoints = []string{"endpoint-2"}
-	endpoint, err := lb.res.resolve(context.Background())
+	endpoint, err := lb.res.Resolve(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, endpoints, endpoint)
 	<-consumeDone
@@ -428,12 +429,11 @@ func TestRollingUpdatesWhenConsumeTraces(t *testing.T) {
 
 	// simulate rolling updates, the dns resolver should resolve in the following order
 	// ["127.0.0.1"] -> ["127.0.0.1", "127.0.0.2"] -> ["127.0.0.2"]
-	res, err := newDNSResolver(ts.Logger, "service-1", "", 5*time.Second, 1*time.Second, tb)
-	require.NoError(t, err)
+	res := &mockResolver{triggerCallbacks: true, resInterval: 10 * time.Millisecond}
 
 	mu := sync.Mutex{}
 	var lastResolved []string
-	res.onChange(func(s []string) {
+	res.OnChange(func(s []string) {
 		mu.Lock()
 		lastResolved = s
 		mu.Unlock()
@@ -441,35 +441,27 @@ func TestRollingUpdatesWhenConsumeTraces(t *testing.T) {
 
 	resolverCh := make(chan struct{}, 1)
 	counter := &atomic.Int64{}
-	resolve := [][]net.IPAddr{
-		{
-			{IP: net.IPv4(127, 0, 0, 1)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 1)},
-			{IP: net.IPv4(127, 0, 0, 2)},
-		}, {
-			{IP: net.IPv4(127, 0, 0, 2)},
-		},
+	resolve := [][]string{
+		{"127.0.0.1"},
+		{"127.0.0.1", "127.0.0.2"},
+		{"127.0.0.2"},
 	}
-	res.resolver = &mockDNSResolver{
-		onLookupIPAddr: func(context.Context, string) ([]net.IPAddr, error) {
-			defer func() {
-				counter.Add(1)
-			}()
+	res.onResolve = func(context.Context) ([]string, error) {
+		defer func() {
+			counter.Add(1)
+		}()
 
-			if counter.Load() <= 2 {
-				return resolve[counter.Load()], nil
-			}
+		if counter.Load() <= 2 {
+			return resolve[counter.Load()], nil
+		}
 
-			if counter.Load() == 3 {
-				// stop as soon as rolling updates end
-				resolverCh <- struct{}{}
-			}
+		if counter.Load() == 3 {
+			// stop as soon as rolling updates end
+			resolverCh <- struct{}{}
+		}
 
-			return resolve[2], nil
-		},
+		return resolve[2], nil
 	}
-	res.resInterval = 10 * time.Millisecond
 
 	cfg := &Config{
 		Resolver: ResolverSettings{
@@ -517,7 +509,7 @@ func TestRollingUpdatesWhenConsumeTraces(t *testing.T) {
 	lb.updateLock.Lock()
 	lb.exporters = defaultExporters
 	lb.updateLock.Unlock()
-	lb.res.onChange(func(_ []string) {
+	lb.res.OnChange(func(_ []string) {
 		lb.updateLock.Lock()
 		lb.exporters = defaultExporters
 		lb.updateLock.Unlock()
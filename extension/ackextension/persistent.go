@@ -0,0 +1,384 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ackextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/ackextension"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// numPartitionsKey is the storage key holding the number of known partitions.
+	numPartitionsKey = "num_partitions"
+	// partitionKeyPrefix prefixes the key holding the ID of the partition in a slot of the index,
+	// the slots being numbered from 0 to the number of known partitions.
+	partitionKeyPrefix = "partition_"
+	// nextAckKeyPrefix prefixes the key holding the highest ack ID reserved for a partition.
+	nextAckKeyPrefix = "next_ack_"
+	// ackedKeyPrefix prefixes the key holding the ack IDs of a partition that were acked but not queried yet.
+	ackedKeyPrefix = "acked_"
+
+	// ackedFlushInterval is the interval at which the acked IDs of the partitions changed since the last flush are persisted.
+	ackedFlushInterval = time.Second
+	// ackIDBlockSize is the number of ack IDs reserved at once, the storage being written once per block.
+	ackIDBlockSize = 1000
+)
+
+var errInvalidAckState = errors.New("invalid ack state found in storage")
+
+// persistentAckExtension is the implementation of the AckExtension backed by a storage extension.
+// The state of the partitions is kept in memory and written through to the storage, so that
+// ack IDs are not generated twice and acked events can be queried after a restart.
+// Only the highest reserved ack ID and the acked IDs of each partition are persisted: events that were
+// being processed when the collector stopped will never be acked and are reported as not acked.
+// The ack IDs are reserved by blocks, so that the storage is not written for every event: after a restart,
+// the ack IDs resume from the end of the last reserved block.
+// The acked IDs of a partition are persisted together, so they are flushed periodically and on shutdown
+// rather than on every ack: the acks of the last flush interval are lost if the collector crashes.
+// Limits are enforced the same way as for the in-memory implementation.
+// The known partitions are indexed by slot, so that adding or evicting a partition writes a few keys only.
+// Their order of use is not persisted: after a restart, they are evicted in the order of their slots.
+type persistentAckExtension struct {
+	storageID   component.ID
+	componentID component.ID
+	logger      *zap.Logger
+	client      storage.Client
+
+	partitionMap                  *lru.Cache[string, *persistentAckPartition]
+	maxNumPendingAcksPerPartition uint64
+
+	// indexLock serializes the additions of partitions, and thus their evictions, with the changes of the index.
+	indexLock sync.Mutex
+	// slots holds the IDs of the known partitions by slot, slotOf the slot of each partition.
+	slots  []string
+	slotOf map[string]int
+
+	// dirty holds the partitions whose acked IDs changed since the last flush.
+	dirtyLock sync.Mutex
+	dirty     map[*persistentAckPartition]struct{}
+
+	cancelFlush context.CancelFunc
+	flushDone   chan struct{}
+}
+
+func newPersistentAckExtension(set extension.Settings, conf *Config) *persistentAckExtension {
+	ext := &persistentAckExtension{
+		storageID:                     *conf.StorageID,
+		componentID:                   set.ID,
+		logger:                        set.Logger,
+		maxNumPendingAcksPerPartition: conf.MaxNumPendingAcksPerPartition,
+		slotOf:                        make(map[string]int),
+		dirty:                         make(map[*persistentAckPartition]struct{}),
+	}
+	ext.partitionMap, _ = lru.NewWithEvict[string, *persistentAckPartition](int(conf.MaxNumPartition), ext.onPartitionEvicted)
+	return ext
+}
+
+// persistentAckPartition guards an ackPartition so that its state is persisted in the same order it is changed.
+type persistentAckPartition struct {
+	sync.Mutex
+	*ackPartition
+	partitionID string
+	// acked holds the ack IDs in ackMap that were acked.
+	acked map[uint64]struct{}
+	// reserved is the highest ack ID reserved in the storage.
+	reserved uint64
+	// evicted is set once the partition is evicted and its state deleted, so that it is not written again.
+	evicted bool
+}
+
+func newPersistentAckPartition(partitionID string, maxPendingAcks uint64) *persistentAckPartition {
+	p := &persistentAckPartition{
+		ackPartition: &ackPartition{},
+		partitionID:  partitionID,
+		acked:        make(map[uint64]struct{}),
+	}
+	p.ackMap, _ = lru.NewWithEvict[uint64, bool](int(maxPendingAcks), func(ackID uint64, _ bool) {
+		// called while holding the partition lock, either on eviction or removal by computeAcks
+		delete(p.acked, ackID)
+	})
+	return p
+}
+
+// Start gets a client from the storage extension and restores the partitions persisted in it.
+func (p *persistentAckExtension) Start(ctx context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[p.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", p.storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", p.storageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindExtension, p.componentID, "")
+	if err != nil {
+		return fmt.Errorf("failed to get storage client: %w", err)
+	}
+	p.client = client
+
+	if err = p.restore(ctx); err != nil {
+		return err
+	}
+
+	var flushCtx context.Context
+	flushCtx, p.cancelFlush = context.WithCancel(context.Background())
+	p.flushDone = make(chan struct{})
+	go p.flushAckedPeriodically(flushCtx)
+	return nil
+}
+
+// Shutdown persists the acked IDs not flushed yet and closes the storage client.
+func (p *persistentAckExtension) Shutdown(ctx context.Context) error {
+	if p.client == nil {
+		return nil
+	}
+	if p.cancelFlush != nil {
+		p.cancelFlush()
+		<-p.flushDone
+		p.flushAcked()
+	}
+	return p.client.Close(ctx)
+}
+
+// ProcessEvent marks the beginning of processing an event. It generates an ack ID for the associated partition ID.
+func (p *persistentAckExtension) ProcessEvent(partitionID string) (ackID uint64) {
+	for {
+		partition, ok := p.partitionMap.Get(partitionID)
+		if !ok {
+			partition = p.addPartition(partitionID)
+		}
+
+		partition.Lock()
+		if partition.evicted {
+			// the partition was evicted concurrently, its state must not be written again
+			partition.Unlock()
+			continue
+		}
+		ackID = partition.nextAck()
+		if ackID > partition.reserved {
+			partition.reserved = ackID + ackIDBlockSize - 1
+			if err := p.client.Set(context.Background(), nextAckKeyPrefix+partitionID, binary.BigEndian.AppendUint64(nil, partition.reserved)); err != nil {
+				p.logger.Warn("failed to reserve the ack IDs", zap.String("partition", partitionID), zap.Error(err))
+			}
+		}
+		partition.Unlock()
+		return ackID
+	}
+}
+
+// Ack acknowledges an event has been processed.
+func (p *persistentAckExtension) Ack(partitionID string, ackID uint64) {
+	partition, ok := p.partitionMap.Get(partitionID)
+	if !ok {
+		return
+	}
+
+	partition.Lock()
+	defer partition.Unlock()
+	if _, ok := partition.ackMap.Get(ackID); !ok {
+		return
+	}
+	partition.ackMap.Add(ackID, true)
+	partition.acked[ackID] = struct{}{}
+	p.markDirty(partition)
+}
+
+// QueryAcks checks the statuses of given ackIDs for a partition.
+// ackIDs that are not generated from ProcessEvent or have been removed as a result of previous calls to QueryAcks will return false.
+func (p *persistentAckExtension) QueryAcks(partitionID string, ackIDs []uint64) map[uint64]bool {
+	partition, ok := p.partitionMap.Get(partitionID)
+	if !ok {
+		result := make(map[uint64]bool, len(ackIDs))
+		for _, ackID := range ackIDs {
+			result[ackID] = false
+		}
+		return result
+	}
+
+	partition.Lock()
+	defer partition.Unlock()
+	numAcked := len(partition.acked)
+	result := partition.computeAcks(ackIDs)
+	if len(partition.acked) != numAcked {
+		p.markDirty(partition)
+	}
+	return result
+}
+
+// markDirty records that the acked IDs of the partition must be flushed.
+func (p *persistentAckExtension) markDirty(partition *persistentAckPartition) {
+	p.dirtyLock.Lock()
+	defer p.dirtyLock.Unlock()
+	p.dirty[partition] = struct{}{}
+}
+
+func (p *persistentAckExtension) flushAckedPeriodically(ctx context.Context) {
+	defer close(p.flushDone)
+	ticker := time.NewTicker(ackedFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.flushAcked()
+		}
+	}
+}
+
+// flushAcked persists the acked IDs of the partitions changed since the last flush.
+func (p *persistentAckExtension) flushAcked() {
+	p.dirtyLock.Lock()
+	dirty := p.dirty
+	p.dirty = make(map[*persistentAckPartition]struct{})
+	p.dirtyLock.Unlock()
+
+	for partition := range dirty {
+		p.persistAcked(partition)
+	}
+}
+
+// persistAcked writes the acked IDs of the partition, unless it was evicted.
+func (p *persistentAckExtension) persistAcked(partition *persistentAckPartition) {
+	partition.Lock()
+	defer partition.Unlock()
+	if partition.evicted {
+		return
+	}
+	content := make([]byte, 0, 8*len(partition.acked))
+	for ackID := range partition.acked {
+		content = binary.BigEndian.AppendUint64(content, ackID)
+	}
+	if err := p.client.Set(context.Background(), ackedKeyPrefix+partition.partitionID, content); err != nil {
+		p.logger.Warn("failed to persist the acked IDs", zap.String("partition", partition.partitionID), zap.Error(err))
+	}
+}
+
+// addPartition adds a new partition to the map and to the index, unless it was added concurrently.
+func (p *persistentAckExtension) addPartition(partitionID string) *persistentAckPartition {
+	p.indexLock.Lock()
+	defer p.indexLock.Unlock()
+	if partition, ok := p.partitionMap.Get(partitionID); ok {
+		return partition
+	}
+
+	partition := newPersistentAckPartition(partitionID, p.maxNumPendingAcksPerPartition)
+	// the least recently used partition is evicted, if needed, before returning
+	p.partitionMap.Add(partitionID, partition)
+
+	slot := len(p.slots)
+	p.slots = append(p.slots, partitionID)
+	p.slotOf[partitionID] = slot
+	err := p.client.Batch(context.Background(),
+		storage.SetOperation(partitionKey(slot), []byte(partitionID)),
+		storage.SetOperation(numPartitionsKey, binary.BigEndian.AppendUint64(nil, uint64(len(p.slots)))),
+	)
+	if err != nil {
+		p.logger.Warn("failed to persist the partition", zap.String("partition", partitionID), zap.Error(err))
+	}
+	return partition
+}
+
+// onPartitionEvicted deletes the state of the partition and moves the partition in the last slot
+// of the index to the slot it frees. Evictions only happen while adding partitions, with indexLock held.
+// The partition is flagged first, so that its state is not written again once deleted.
+func (p *persistentAckExtension) onPartitionEvicted(partitionID string, partition *persistentAckPartition) {
+	partition.Lock()
+	partition.evicted = true
+	partition.Unlock()
+
+	ops := []storage.Operation{
+		storage.DeleteOperation(nextAckKeyPrefix + partitionID),
+		storage.DeleteOperation(ackedKeyPrefix + partitionID),
+	}
+	if slot, ok := p.slotOf[partitionID]; ok {
+		last := len(p.slots) - 1
+		if slot != last {
+			moved := p.slots[last]
+			p.slots[slot] = moved
+			p.slotOf[moved] = slot
+			ops = append(ops, storage.SetOperation(partitionKey(slot), []byte(moved)))
+		}
+		p.slots = p.slots[:last]
+		delete(p.slotOf, partitionID)
+		ops = append(ops,
+			storage.DeleteOperation(partitionKey(last)),
+			storage.SetOperation(numPartitionsKey, binary.BigEndian.AppendUint64(nil, uint64(len(p.slots)))),
+		)
+	}
+	if err := p.client.Batch(context.Background(), ops...); err != nil {
+		p.logger.Warn("failed to delete the evicted partition", zap.String("partition", partitionID), zap.Error(err))
+	}
+}
+
+func partitionKey(slot int) string {
+	return partitionKeyPrefix + strconv.Itoa(slot)
+}
+
+func (p *persistentAckExtension) restore(ctx context.Context) error {
+	content, err := p.client.Get(ctx, numPartitionsKey)
+	if err != nil {
+		return fmt.Errorf("failed to read the partitions: %w", err)
+	}
+	if content == nil {
+		return nil
+	}
+	if len(content) != 8 {
+		return errInvalidAckState
+	}
+	ops := make([]storage.Operation, binary.BigEndian.Uint64(content))
+	for slot := range ops {
+		ops[slot] = storage.GetOperation(partitionKey(slot))
+	}
+	if err = p.client.Batch(ctx, ops...); err != nil {
+		return fmt.Errorf("failed to read the partitions: %w", err)
+	}
+	for slot, op := range ops {
+		if op.Value == nil {
+			return fmt.Errorf("partition slot %d: %w", slot, errInvalidAckState)
+		}
+		p.slots = append(p.slots, string(op.Value))
+		p.slotOf[string(op.Value)] = slot
+	}
+
+	// partitions beyond the limit, if it was lowered, are evicted from the index as they are added
+	p.indexLock.Lock()
+	defer p.indexLock.Unlock()
+	for _, partitionID := range slices.Clone(p.slots) {
+		nextAck := storage.GetOperation(nextAckKeyPrefix + partitionID)
+		acked := storage.GetOperation(ackedKeyPrefix + partitionID)
+		if err = p.client.Batch(ctx, nextAck, acked); err != nil {
+			return fmt.Errorf("failed to read partition %q: %w", partitionID, err)
+		}
+		if (nextAck.Value != nil && len(nextAck.Value) != 8) || len(acked.Value)%8 != 0 {
+			return fmt.Errorf("partition %q: %w", partitionID, errInvalidAckState)
+		}
+
+		partition := newPersistentAckPartition(partitionID, p.maxNumPendingAcksPerPartition)
+		if nextAck.Value != nil {
+			// the ack IDs of the last reserved block may have been generated before the restart
+			partition.reserved = binary.BigEndian.Uint64(nextAck.Value)
+			partition.id.Store(partition.reserved)
+		}
+		for i := 0; i < len(acked.Value); i += 8 {
+			ackID := binary.BigEndian.Uint64(acked.Value[i:])
+			partition.ackMap.Add(ackID, true)
+			partition.acked[ackID] = struct{}{}
+		}
+		p.partitionMap.Add(partitionID, partition)
+	}
+	return nil
+}
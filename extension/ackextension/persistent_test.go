@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ackextension
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/ackextension/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func startPersistentAckExtension(t *testing.T, storageExt *storagetest.TestStorage, maxNumPartition uint64) *persistentAckExtension {
+	t.Helper()
+
+	conf := Config{
+		StorageID:                     &storageExt.ID,
+		MaxNumPartition:               maxNumPartition,
+		MaxNumPendingAcksPerPartition: defaultMaxNumPendingAcksPerPartition,
+	}
+	set := extension.Settings{
+		ID:                component.NewID(metadata.Type),
+		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+	}
+	ext := newPersistentAckExtension(set, &conf)
+	require.NoError(t, ext.Start(context.Background(), storagetest.NewStorageHost().WithExtension(storageExt.ID, storageExt)))
+	return ext
+}
+
+func TestPersistentExtensionAck_ProcessEvents_EventsAcked(t *testing.T) {
+	ext := startPersistentAckExtension(t, storagetest.NewInMemoryStorageExtension("test"), defaultMaxNumPartition)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+
+	// send events through different partitions
+	for i := 0; i < 10; i++ {
+		// each partition has 3 events
+		for j := 0; j < 3; j++ {
+			ext.ProcessEvent(fmt.Sprintf("part-%d", i))
+		}
+	}
+
+	// ack the second event of all partitions
+	for i := 0; i < 10; i++ {
+		ext.Ack(fmt.Sprintf("part-%d", i), 2)
+	}
+
+	for i := 0; i < 10; i++ {
+		result := ext.QueryAcks(fmt.Sprintf("part-%d", i), []uint64{1, 2, 3})
+		require.Equal(t, map[uint64]bool{1: false, 2: true, 3: false}, result)
+
+		// acks are removed once queried
+		result = ext.QueryAcks(fmt.Sprintf("part-%d", i), []uint64{2})
+		require.Equal(t, map[uint64]bool{2: false}, result)
+	}
+}
+
+func TestPersistentExtensionAck_Restart(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+
+	ext := startPersistentAckExtension(t, storageExt, defaultMaxNumPartition)
+	for j := 0; j < 3; j++ {
+		ext.ProcessEvent("part-1")
+	}
+	ext.ProcessEvent("part-2")
+	ext.Ack("part-1", 1)
+	ext.Ack("part-1", 3)
+	ext.Ack("part-2", 1)
+	require.Equal(t, map[uint64]bool{1: true}, ext.QueryAcks("part-2", []uint64{1}))
+	require.NoError(t, ext.Shutdown(context.Background()))
+
+	ext = startPersistentAckExtension(t, storageExt, defaultMaxNumPartition)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+
+	// acks that were not queried are still available
+	require.Equal(t, map[uint64]bool{1: true, 2: false, 3: true}, ext.QueryAcks("part-1", []uint64{1, 2, 3}))
+	require.Equal(t, map[uint64]bool{1: false}, ext.QueryAcks("part-2", []uint64{1}))
+
+	// ack IDs are not generated again, they resume after the last reserved block
+	require.Equal(t, uint64(ackIDBlockSize+1), ext.ProcessEvent("part-1"))
+	require.Equal(t, uint64(ackIDBlockSize+1), ext.ProcessEvent("part-2"))
+	require.Equal(t, uint64(1), ext.ProcessEvent("part-3"))
+}
+
+func TestPersistentExtensionAck_PartitionEviction(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+
+	ext := startPersistentAckExtension(t, storageExt, 2)
+	for i := 0; i < 3; i++ {
+		ackID := ext.ProcessEvent(fmt.Sprintf("part-%d", i))
+		ext.Ack(fmt.Sprintf("part-%d", i), ackID)
+	}
+	require.NoError(t, ext.Shutdown(context.Background()))
+
+	ext = startPersistentAckExtension(t, storageExt, 2)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+
+	// the least recently used partition was evicted along with its state
+	require.Equal(t, []string{"part-1", "part-2"}, ext.partitionMap.Keys())
+	require.Equal(t, map[uint64]bool{1: false}, ext.QueryAcks("part-0", []uint64{1}))
+	require.Equal(t, map[uint64]bool{1: true}, ext.QueryAcks("part-1", []uint64{1}))
+	require.Equal(t, map[uint64]bool{1: true}, ext.QueryAcks("part-2", []uint64{1}))
+	require.Equal(t, uint64(1), ext.ProcessEvent("part-0"))
+}
+
+func TestPersistentExtensionAck_StartErrors(t *testing.T) {
+	storageID := storagetest.NewStorageID("missing")
+	conf := Config{
+		StorageID:                     &storageID,
+		MaxNumPartition:               defaultMaxNumPartition,
+		MaxNumPendingAcksPerPartition: defaultMaxNumPendingAcksPerPartition,
+	}
+	set := extension.Settings{
+		ID:                component.NewID(metadata.Type),
+		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+	}
+
+	ext := newPersistentAckExtension(set, &conf)
+	require.ErrorContains(t, ext.Start(context.Background(), componenttest.NewNopHost()), "storage extension 'test_storage/missing' not found")
+
+	nonStorageID := storagetest.NewNonStorageID("non")
+	conf.StorageID = &nonStorageID
+	ext = newPersistentAckExtension(set, &conf)
+	host := storagetest.NewStorageHost().WithNonStorageExtension("non")
+	require.ErrorContains(t, ext.Start(context.Background(), host), "non-storage extension")
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func TestPersistentExtensionAck_InvalidState(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	ext := startPersistentAckExtension(t, storageExt, defaultMaxNumPartition)
+	ext.ProcessEvent("part-1")
+	require.NoError(t, ext.client.Set(context.Background(), nextAckKeyPrefix+"part-1", []byte{1}))
+	require.NoError(t, ext.Shutdown(context.Background()))
+
+	conf := Config{
+		StorageID:                     &storageExt.ID,
+		MaxNumPartition:               defaultMaxNumPartition,
+		MaxNumPendingAcksPerPartition: defaultMaxNumPendingAcksPerPartition,
+	}
+	ext = newPersistentAckExtension(extension.Settings{
+		ID:                component.NewID(metadata.Type),
+		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+	}, &conf)
+	require.ErrorIs(t, ext.Start(context.Background(), storagetest.NewStorageHost().WithExtension(storageExt.ID, storageExt)), errInvalidAckState)
+}
+
+func TestPersistentExtensionAck_PartitionIndex(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+
+	ext := startPersistentAckExtension(t, storageExt, 3)
+	for i := 0; i < 4; i++ {
+		ext.ProcessEvent(fmt.Sprintf("part-%d", i))
+	}
+	// the slot of the evicted partition is reused by the partition in the last slot
+	require.Equal(t, []string{"part-2", "part-1", "part-3"}, ext.slots)
+	value, err := ext.client.Get(context.Background(), partitionKey(3))
+	require.NoError(t, err)
+	require.Nil(t, value)
+	require.NoError(t, ext.Shutdown(context.Background()))
+
+	// lowering the limit evicts the partitions in the first slots
+	ext = startPersistentAckExtension(t, storageExt, 2)
+	require.Equal(t, []string{"part-1", "part-3"}, ext.partitionMap.Keys())
+	require.ElementsMatch(t, []string{"part-1", "part-3"}, ext.slots)
+	require.Equal(t, uint64(1), ext.ProcessEvent("part-2"))
+	require.Equal(t, uint64(ackIDBlockSize+1), ext.ProcessEvent("part-3"))
+	require.NoError(t, ext.Shutdown(context.Background()))
+
+	ext = startPersistentAckExtension(t, storageExt, 2)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+	require.ElementsMatch(t, []string{"part-2", "part-3"}, ext.partitionMap.Keys())
+}
+
+func TestPersistentExtensionAck_FlushAcked(t *testing.T) {
+	ext := startPersistentAckExtension(t, storagetest.NewInMemoryStorageExtension("test"), 1)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+
+	ackID := ext.ProcessEvent("part-0")
+	ext.Ack("part-0", ackID)
+	ext.Ack("part-0", ackID)
+
+	// the acked IDs are written once per flush rather than on every ack
+	value, err := ext.client.Get(context.Background(), ackedKeyPrefix+"part-0")
+	require.NoError(t, err)
+	require.Nil(t, value)
+	ext.flushAcked()
+	value, err = ext.client.Get(context.Background(), ackedKeyPrefix+"part-0")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, value)
+
+	// the state of a partition evicted before the flush is not written again
+	ext.Ack("part-0", ackID)
+	evicted, ok := ext.partitionMap.Get("part-0")
+	require.True(t, ok)
+	ext.ProcessEvent("part-1")
+	require.True(t, evicted.evicted)
+	ext.markDirty(evicted)
+	ext.flushAcked()
+	value, err = ext.client.Get(context.Background(), ackedKeyPrefix+"part-0")
+	require.NoError(t, err)
+	require.Nil(t, value)
+	value, err = ext.client.Get(context.Background(), nextAckKeyPrefix+"part-0")
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestPersistentExtensionAck_ReserveAckIDs(t *testing.T) {
+	ext := startPersistentAckExtension(t, storagetest.NewInMemoryStorageExtension("test"), defaultMaxNumPartition)
+	defer func() {
+		require.NoError(t, ext.Shutdown(context.Background()))
+	}()
+	reserved := func() []byte {
+		value, err := ext.client.Get(context.Background(), nextAckKeyPrefix+"part-0")
+		require.NoError(t, err)
+		return value
+	}
+
+	// the storage is only written when a new block of ack IDs is reserved
+	require.Equal(t, uint64(1), ext.ProcessEvent("part-0"))
+	require.Equal(t, binary.BigEndian.AppendUint64(nil, ackIDBlockSize), reserved())
+	require.NoError(t, ext.client.Delete(context.Background(), nextAckKeyPrefix+"part-0"))
+	for i := 2; i <= ackIDBlockSize; i++ {
+		ext.ProcessEvent("part-0")
+	}
+	require.Nil(t, reserved())
+	require.Equal(t, uint64(ackIDBlockSize+1), ext.ProcessEvent("part-0"))
+	require.Equal(t, binary.BigEndian.AppendUint64(nil, 2*ackIDBlockSize), reserved())
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+// Supported formats of the lookup table files
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// Config holds the configuration for the lookup tables extension.
+type Config struct {
+	// Tables defines the lookup tables by name.
+	Tables map[string]TableConfig `mapstructure:"tables"`
+}
+
+// TableConfig defines a lookup table and the source it is loaded from.
+type TableConfig struct {
+	// Key is the column or the field of the rows holding their key. It can be omitted
+	// for JSON files holding an object of rows keyed by their key.
+	Key string `mapstructure:"key"`
+
+	// RefreshInterval is the interval at which the table is reloaded from its source.
+	// A value of 0 disables the refresh of the table.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// File loads the table from a local CSV or JSON file.
+	File *FileConfig `mapstructure:"file"`
+
+	// SQL loads the table from the result of a database query.
+	SQL *SQLConfig `mapstructure:"sql"`
+}
+
+// FileConfig defines a lookup table loaded from a local file.
+type FileConfig struct {
+	// Path of the file.
+	Path string `mapstructure:"path"`
+
+	// Format of the file, either csv or json. If empty, it is inferred from the file extension.
+	// CSV files must start with a header naming the columns. JSON files hold either an array
+	// of objects, or an object of rows keyed by their key.
+	Format string `mapstructure:"format"`
+}
+
+// SQLConfig defines a lookup table loaded from a database query.
+type SQLConfig struct {
+	// Driver is the name of the database driver, see the sqlquery receiver for the supported drivers.
+	Driver string `mapstructure:"driver"`
+
+	// DataSource is the driver specific connection string.
+	DataSource string `mapstructure:"datasource"`
+
+	// Query returns the rows of the table.
+	Query string `mapstructure:"query"`
+
+	// Telemetry configures the logging of the queries.
+	Telemetry sqlquery.TelemetryConfig `mapstructure:"telemetry"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid.
+func (cfg *Config) Validate() error {
+	if len(cfg.Tables) == 0 {
+		return errors.New("at least one lookup table must be defined")
+	}
+	for name, table := range cfg.Tables {
+		if err := table.validate(); err != nil {
+			return fmt.Errorf("table %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (cfg TableConfig) validate() error {
+	if (cfg.File == nil) == (cfg.SQL == nil) {
+		return errors.New("exactly one of file or sql must be specified")
+	}
+	if cfg.RefreshInterval < 0 {
+		return errors.New("refresh_interval must not be negative")
+	}
+	if cfg.File != nil {
+		if cfg.File.Path == "" {
+			return errors.New("file path must be specified")
+		}
+		format := cfg.File.format()
+		if format != formatCSV && format != formatJSON {
+			return fmt.Errorf("unsupported file format %q, available values: %s, %s", format, formatCSV, formatJSON)
+		}
+		if format == formatCSV && cfg.Key == "" {
+			return errors.New("key must be specified for CSV files")
+		}
+		return nil
+	}
+	if cfg.SQL.Driver == "" {
+		return errors.New("sql driver must be specified")
+	}
+	if cfg.SQL.DataSource == "" {
+		return errors.New("sql datasource must be specified")
+	}
+	if cfg.SQL.Query == "" {
+		return errors.New("sql query must be specified")
+	}
+	if cfg.Key == "" {
+		return errors.New("key must be specified for SQL queries")
+	}
+	return nil
+}
+
+// format returns the configured format of the file, or the one of its extension.
+func (cfg *FileConfig) format() string {
+	if cfg.Format != "" {
+		return strings.ToLower(cfg.Format)
+	}
+	switch {
+	case strings.HasSuffix(strings.ToLower(cfg.Path), ".json"):
+		return formatJSON
+	case strings.HasSuffix(strings.ToLower(cfg.Path), ".csv"):
+		return formatCSV
+	}
+	return ""
+}
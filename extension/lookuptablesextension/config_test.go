@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id                   component.ID
+		expected             component.Config
+		validateErrorMessage string
+	}{
+		{
+			id:                   component.NewID(metadata.Type),
+			validateErrorMessage: "at least one lookup table must be defined",
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "file"),
+			expected: &Config{
+				Tables: map[string]TableConfig{
+					"owners": {
+						Key:  "service",
+						File: &FileConfig{Path: "./testdata/owners.csv"},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "sql"),
+			expected: &Config{
+				Tables: map[string]TableConfig{
+					"hosts": {
+						Key:             "hostname",
+						RefreshInterval: 5 * time.Minute,
+						SQL: &SQLConfig{
+							Driver:     "postgres",
+							DataSource: "host=localhost port=5432 user=otel password=otel sslmode=disable",
+							Query:      "SELECT hostname, site, rack FROM hosts",
+						},
+					},
+				},
+			},
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "file_and_sql"),
+			validateErrorMessage: `table "owners": exactly one of file or sql must be specified`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "missing_csv_key"),
+			validateErrorMessage: `table "owners": key must be specified for CSV files`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "unsupported_format"),
+			validateErrorMessage: `table "owners": unsupported file format "", available values: csv, json`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "missing_sql_query"),
+			validateErrorMessage: `table "hosts": sql query must be specified`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.validateErrorMessage != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.validateErrorMessage)
+				return
+			}
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
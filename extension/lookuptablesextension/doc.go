@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package lookuptablesextension loads lookup tables from files or databases, for the components
+// enriching telemetry with them.
+package lookuptablesextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension"
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+// lookupTable is a lookup table with the source it is loaded from.
+type lookupTable struct {
+	name            string
+	source          tableSource
+	refreshInterval time.Duration
+	table           *lookuptable.Table
+}
+
+type lookupTablesExtension struct {
+	config *Config
+	logger *zap.Logger
+
+	sqlOpenerFunc      sqlquery.SQLOpenerFunc
+	clientProviderFunc sqlquery.ClientProviderFunc
+
+	tables       []*lookupTable
+	tablesByName map[string]*lookuptable.Table
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	_ extension.Extension  = (*lookupTablesExtension)(nil)
+	_ lookuptable.Provider = (*lookupTablesExtension)(nil)
+)
+
+func newLookupTablesExtension(config *Config, logger *zap.Logger, sqlOpenerFunc sqlquery.SQLOpenerFunc, clientProviderFunc sqlquery.ClientProviderFunc) *lookupTablesExtension {
+	e := &lookupTablesExtension{
+		config:             config,
+		logger:             logger,
+		sqlOpenerFunc:      sqlOpenerFunc,
+		clientProviderFunc: clientProviderFunc,
+		tablesByName:       make(map[string]*lookuptable.Table, len(config.Tables)),
+	}
+	for name, cfg := range config.Tables {
+		table := lookuptable.NewTable()
+		e.tablesByName[name] = table
+		e.tables = append(e.tables, &lookupTable{name: name, refreshInterval: cfg.RefreshInterval, table: table})
+	}
+	// the tables are loaded in a deterministic order
+	sort.Slice(e.tables, func(i, j int) bool {
+		return e.tables[i].name < e.tables[j].name
+	})
+	return e
+}
+
+// Table returns the lookup table of the given name.
+func (e *lookupTablesExtension) Table(name string) (*lookuptable.Table, bool) {
+	table, ok := e.tablesByName[name]
+	return table, ok
+}
+
+// Start loads the lookup tables and starts refreshing them.
+func (e *lookupTablesExtension) Start(ctx context.Context, _ component.Host) error {
+	for _, t := range e.tables {
+		cfg := e.config.Tables[t.name]
+		if cfg.File != nil {
+			t.source = &fileSource{path: cfg.File.Path, format: cfg.File.format(), key: cfg.Key}
+		} else {
+			source, err := newSQLSource(cfg.SQL, cfg.Key, e.logger, e.sqlOpenerFunc, e.clientProviderFunc)
+			if err != nil {
+				return fmt.Errorf("table %q: %w", t.name, err)
+			}
+			t.source = source
+		}
+
+		rows, err := t.source.load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load table %q: %w", t.name, err)
+		}
+		t.table.Replace(rows)
+	}
+
+	var refreshCtx context.Context
+	refreshCtx, e.cancel = context.WithCancel(context.Background())
+	for _, t := range e.tables {
+		if t.refreshInterval > 0 {
+			e.wg.Add(1)
+			go e.refresh(refreshCtx, t)
+		}
+	}
+	return nil
+}
+
+// refresh periodically reloads the table. The previous rows are kept if the table cannot be reloaded.
+func (e *lookupTablesExtension) refresh(ctx context.Context, t *lookupTable) {
+	defer e.wg.Done()
+	ticker := time.NewTicker(t.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rows, err := t.source.load(ctx)
+			if err != nil {
+				e.logger.Warn("Failed to refresh lookup table, keeping the previous rows", zap.String("table", t.name), zap.Error(err))
+				continue
+			}
+			t.table.Replace(rows)
+			e.logger.Debug("Refreshed lookup table", zap.String("table", t.name), zap.Int("rows", len(rows)))
+		}
+	}
+}
+
+// Shutdown stops refreshing the lookup tables and closes their sources.
+func (e *lookupTablesExtension) Shutdown(context.Context) error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.wg.Wait()
+
+	var errs []error
+	for _, t := range e.tables {
+		if t.source != nil {
+			errs = append(errs, t.source.close())
+			t.source = nil
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+)
+
+func TestTables(t *testing.T) {
+	cfg := &Config{
+		Tables: map[string]TableConfig{
+			"owners": {Key: "service", File: &FileConfig{Path: filepath.Join("testdata", "owners.csv")}},
+			"hosts":  {Key: "hostname", File: &FileConfig{Path: filepath.Join("testdata", "hosts.json")}},
+		},
+	}
+	ext, err := NewFactory().Create(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, ext.Shutdown(context.Background())) }()
+
+	provider, ok := ext.(lookuptable.Provider)
+	require.True(t, ok)
+
+	owners, ok := provider.Table("owners")
+	require.True(t, ok)
+	row, ok := owners.Lookup("checkout")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"team": "payments", "tier": "1"}, row.AsRaw())
+
+	hosts, ok := provider.Table("hosts")
+	require.True(t, ok)
+	row, ok = hosts.Lookup("node-2")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"site": "fra1", "rack": "r01", "cores": int64(32)}, row.AsRaw())
+
+	_, ok = provider.Table("regions")
+	assert.False(t, ok)
+}
+
+func TestStartWithInvalidTable(t *testing.T) {
+	cfg := &Config{
+		Tables: map[string]TableConfig{
+			"owners": {Key: "service", File: &FileConfig{Path: filepath.Join("testdata", "missing.csv")}},
+		},
+	}
+	e := newLookupTablesExtension(cfg, zap.NewNop(), nil, nil)
+	assert.ErrorContains(t, e.Start(context.Background(), componenttest.NewNopHost()), `failed to load table "owners"`)
+	require.NoError(t, e.Shutdown(context.Background()))
+}
+
+func TestRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "owners.csv")
+	require.NoError(t, os.WriteFile(path, []byte("service,team\ncheckout,payments\n"), 0o600))
+	cfg := &Config{
+		Tables: map[string]TableConfig{
+			"owners": {Key: "service", RefreshInterval: 10 * time.Millisecond, File: &FileConfig{Path: path}},
+		},
+	}
+	e := newLookupTablesExtension(cfg, zap.NewNop(), nil, nil)
+	require.NoError(t, e.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, e.Shutdown(context.Background())) }()
+
+	team := func() string {
+		row, ok := e.tables[0].table.Lookup("checkout")
+		if !ok {
+			return ""
+		}
+		value, _ := row.Get("team")
+		return value.Str()
+	}
+	assert.Equal(t, "payments", team())
+
+	require.NoError(t, os.WriteFile(path, []byte("service,team\ncheckout,platform\n"), 0o600))
+	require.Eventually(t, func() bool {
+		return team() == "platform"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the previous rows are kept if the table cannot be reloaded
+	require.NoError(t, os.Remove(path))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "platform", team())
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension"
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+// NewFactory returns a new factory for the lookup tables extension.
+func NewFactory() extension.Factory {
+	return newFactory(sql.Open, sqlquery.NewDbClient)
+}
+
+func newFactory(sqlOpenerFunc sqlquery.SQLOpenerFunc, clientProviderFunc sqlquery.ClientProviderFunc) extension.Factory {
+	return extension.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		func(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
+			return newLookupTablesExtension(cfg.(*Config), set.Logger, sqlOpenerFunc, clientProviderFunc), nil
+		},
+		metadata.ExtensionStability,
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{}
+}
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/lookuptablesextension"
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+// tableSource loads the rows of a lookup table, indexed by their key.
+type tableSource interface {
+	load(ctx context.Context) (map[string]pcommon.Map, error)
+	close() error
+}
+
+// fileSource loads a lookup table from a CSV or JSON file.
+type fileSource struct {
+	path   string
+	format string
+	key    string
+}
+
+var _ tableSource = (*fileSource)(nil)
+
+func (s *fileSource) load(context.Context) (map[string]pcommon.Map, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if s.format == formatJSON {
+		return loadJSON(f, s.key)
+	}
+	return loadCSV(f, s.key)
+}
+
+func (s *fileSource) close() error {
+	return nil
+}
+
+// loadCSV loads rows from CSV data starting with a header naming the columns.
+func loadCSV(r io.Reader, key string) (map[string]pcommon.Map, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	keyIndex := -1
+	for i, column := range header {
+		if column == key {
+			keyIndex = i
+		}
+	}
+	if keyIndex < 0 {
+		return nil, fmt.Errorf("key column %q not found", key)
+	}
+
+	rows := map[string]pcommon.Map{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := pcommon.NewMap()
+		row.EnsureCapacity(len(record) - 1)
+		for i, value := range record {
+			if i != keyIndex {
+				row.PutStr(header[i], value)
+			}
+		}
+		rows[record[keyIndex]] = row
+	}
+}
+
+// loadJSON loads rows from either a JSON array of objects holding their key, or a JSON
+// object of rows keyed by their key.
+func loadJSON(r io.Reader, key string) (map[string]pcommon.Map, error) {
+	var content any
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+
+	rows := map[string]pcommon.Map{}
+	switch content := content.(type) {
+	case map[string]any:
+		for k, value := range content {
+			fields, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("row %q is not an object", k)
+			}
+			row, err := jsonRow(fields, "")
+			if err != nil {
+				return nil, fmt.Errorf("row %q: %w", k, err)
+			}
+			rows[k] = row
+		}
+	case []any:
+		if key == "" {
+			return nil, errors.New("key must be specified for arrays of rows")
+		}
+		for i, value := range content {
+			fields, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("row %d is not an object", i)
+			}
+			k, ok := fields[key]
+			if !ok {
+				return nil, fmt.Errorf("row %d has no %q field", i, key)
+			}
+			row, err := jsonRow(fields, key)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", i, err)
+			}
+			rows[fmt.Sprint(k)] = row
+		}
+	default:
+		return nil, errors.New("expected an array or an object of rows")
+	}
+	return rows, nil
+}
+
+// jsonRow converts the fields of a JSON object, except for the key, to a row.
+func jsonRow(fields map[string]any, key string) (pcommon.Map, error) {
+	row := pcommon.NewMap()
+	row.EnsureCapacity(len(fields))
+	for name, value := range fields {
+		if name == key {
+			continue
+		}
+		if err := row.PutEmpty(name).FromRaw(jsonValue(value)); err != nil {
+			return pcommon.Map{}, fmt.Errorf("field %q: %w", name, err)
+		}
+	}
+	return row, nil
+}
+
+// jsonValue converts the numbers decoded as json.Number to int64 or float64 values.
+func jsonValue(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+	case map[string]any:
+		for k, v := range value {
+			value[k] = jsonValue(v)
+		}
+	case []any:
+		for i, v := range value {
+			value[i] = jsonValue(v)
+		}
+	}
+	return value
+}
+
+// sqlSource loads a lookup table from the result of a database query.
+type sqlSource struct {
+	db     *sql.DB
+	client sqlquery.DbClient
+	key    string
+	logger *zap.Logger
+}
+
+var _ tableSource = (*sqlSource)(nil)
+
+func newSQLSource(cfg *SQLConfig, key string, logger *zap.Logger, sqlOpenerFunc sqlquery.SQLOpenerFunc, clientProviderFunc sqlquery.ClientProviderFunc) (*sqlSource, error) {
+	db, err := sqlOpenerFunc(cfg.Driver, cfg.DataSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return &sqlSource{
+		db:     db,
+		client: clientProviderFunc(sqlquery.DbWrapper{Db: db}, cfg.Query, logger, cfg.Telemetry),
+		key:    key,
+		logger: logger,
+	}, nil
+}
+
+func (s *sqlSource) load(ctx context.Context) (map[string]pcommon.Map, error) {
+	results, err := s.client.QueryRows(ctx)
+	if err != nil {
+		if len(results) == 0 {
+			return nil, err
+		}
+		// the rows are still returned if some of their values could not be converted
+		s.logger.Warn("Some values of the lookup table could not be read", zap.Error(err))
+	}
+
+	rows := make(map[string]pcommon.Map, len(results))
+	for _, result := range results {
+		k, ok := result[s.key]
+		if !ok {
+			return nil, fmt.Errorf("key column %q not found", s.key)
+		}
+		row := pcommon.NewMap()
+		row.EnsureCapacity(len(result) - 1)
+		for column, value := range result {
+			if column != s.key {
+				row.PutStr(column, value)
+			}
+		}
+		rows[k] = row
+	}
+	return rows, nil
+}
+
+func (s *sqlSource) close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptablesextension
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+func rowsAsRaw(rows map[string]pcommon.Map) map[string]map[string]any {
+	raw := make(map[string]map[string]any, len(rows))
+	for k, row := range rows {
+		raw[k] = row.AsRaw()
+	}
+	return raw
+}
+
+func TestFileSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   fileSource
+		expected map[string]map[string]any
+	}{
+		{
+			name:   "csv",
+			source: fileSource{path: filepath.Join("testdata", "owners.csv"), format: formatCSV, key: "service"},
+			expected: map[string]map[string]any{
+				"checkout": {"team": "payments", "tier": "1"},
+				"cart":     {"team": "shopping", "tier": "2"},
+			},
+		},
+		{
+			name:   "json array",
+			source: fileSource{path: filepath.Join("testdata", "hosts.json"), format: formatJSON, key: "hostname"},
+			expected: map[string]map[string]any{
+				"node-1": {"site": "ams1", "rack": "r12", "cores": int64(64)},
+				"node-2": {"site": "fra1", "rack": "r01", "cores": int64(32)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.source.load(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rowsAsRaw(rows))
+		})
+	}
+
+	_, err := (&fileSource{path: filepath.Join("testdata", "missing.csv"), format: formatCSV, key: "service"}).load(context.Background())
+	assert.ErrorContains(t, err, "no such file or directory")
+}
+
+func TestLoadCSV(t *testing.T) {
+	_, err := loadCSV(strings.NewReader(""), "service")
+	assert.EqualError(t, err, "failed to read header: EOF")
+
+	_, err = loadCSV(strings.NewReader("name,team\ncheckout,payments\n"), "service")
+	assert.EqualError(t, err, `key column "service" not found`)
+
+	_, err = loadCSV(strings.NewReader("service,team\ncheckout\n"), "service")
+	assert.ErrorContains(t, err, "wrong number of fields")
+}
+
+func TestLoadJSON(t *testing.T) {
+	rows, err := loadJSON(strings.NewReader(`{"checkout": {"team": "payments", "slo": 99.9, "oncall": ["alice", "bob"]}}`), "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]any{
+		"checkout": {"team": "payments", "slo": 99.9, "oncall": []any{"alice", "bob"}},
+	}, rowsAsRaw(rows))
+
+	rows, err = loadJSON(strings.NewReader(`[{"id": 42, "team": "payments"}]`), "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]any{"42": {"team": "payments"}}, rowsAsRaw(rows))
+
+	for _, tt := range []struct {
+		content string
+		key     string
+		err     string
+	}{
+		{content: `"checkout"`, err: "expected an array or an object of rows"},
+		{content: `{"checkout": "payments"}`, err: `row "checkout" is not an object`},
+		{content: `[{"service": "checkout"}]`, err: "key must be specified for arrays of rows"},
+		{content: `["checkout"]`, key: "service", err: "row 0 is not an object"},
+		{content: `[{"team": "payments"}]`, key: "service", err: `row 0 has no "service" field`},
+	} {
+		_, err = loadJSON(strings.NewReader(tt.content), tt.key)
+		assert.EqualError(t, err, tt.err, tt.content)
+	}
+}
+
+func TestSQLSource(t *testing.T) {
+	opener := func(string, string) (*sql.DB, error) {
+		return nil, nil
+	}
+	client := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{
+				{"hostname": "node-1", "site": "ams1"},
+				{"hostname": "node-2", "site": "fra1"},
+			},
+			{
+				{"name": "node-1", "site": "ams1"},
+			},
+		},
+	}
+	clientProvider := func(sqlquery.Db, string, *zap.Logger, sqlquery.TelemetryConfig) sqlquery.DbClient {
+		return client
+	}
+
+	source, err := newSQLSource(&SQLConfig{Driver: "postgres", DataSource: "host=localhost", Query: "SELECT hostname, site FROM hosts"}, "hostname", zap.NewNop(), opener, clientProvider)
+	require.NoError(t, err)
+
+	rows, err := source.load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]any{
+		"node-1": {"site": "ams1"},
+		"node-2": {"site": "fra1"},
+	}, rowsAsRaw(rows))
+
+	_, err = source.load(context.Background())
+	assert.EqualError(t, err, `key column "hostname" not found`)
+
+	client.Err = errors.New("connection refused")
+	_, err = source.load(context.Background())
+	assert.EqualError(t, err, "connection refused")
+	assert.NoError(t, source.close())
+
+	_, err = newSQLSource(&SQLConfig{Driver: "unknown"}, "hostname", zap.NewNop(), func(string, string) (*sql.DB, error) {
+		return nil, errors.New(`sql: unknown driver "unknown"`)
+	}, clientProvider)
+	assert.EqualError(t, err, `failed to open database: sql: unknown driver "unknown"`)
+}
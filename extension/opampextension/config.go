@@ -35,6 +35,9 @@ type Config struct {
 	// Agent descriptions contains options to modify the AgentDescription message
 	AgentDescription AgentDescription `mapstructure:"agent_description"`
 
+	// RemoteConfig contains options for the config accepted from the OpAMP server.
+	RemoteConfig RemoteConfig `mapstructure:"remote_config"`
+
 	// PPID is the process ID of the parent for the collector. If the PPID is specified,
 	// the extension will continuously poll for the status of the parent process, and emit a fatal error
 	// when the parent process is no longer running.
@@ -51,11 +54,23 @@ type AgentDescription struct {
 	NonIdentifyingAttributes map[string]string `mapstructure:"non_identifying_attributes"`
 }
 
+type RemoteConfig struct {
+	// File is the path the config accepted from the OpAMP server is written to.
+	// The collector must load its config from this file, e.g. with `--config file:<path>`.
+	File string `mapstructure:"file"`
+
+	// LocalConfig holds the URIs of the config the collector loads before File, as given to `--config`.
+	// The remote configs are validated merged into this config.
+	LocalConfig []string `mapstructure:"local_config"`
+}
+
 type Capabilities struct {
 	// ReportsEffectiveConfig enables the OpAMP ReportsEffectiveConfig Capability. (default: true)
 	ReportsEffectiveConfig bool `mapstructure:"reports_effective_config"`
 	// ReportsHealth enables the OpAMP ReportsHealth Capability. (default: true)
 	ReportsHealth bool `mapstructure:"reports_health"`
+	// AcceptsRemoteConfig enables the OpAMP AcceptsRemoteConfig and ReportsRemoteConfig Capabilities. (default: false)
+	AcceptsRemoteConfig bool `mapstructure:"accepts_remote_config"`
 }
 
 func (caps Capabilities) toAgentCapabilities() protobufs.AgentCapabilities {
@@ -68,6 +83,10 @@ func (caps Capabilities) toAgentCapabilities() protobufs.AgentCapabilities {
 	if caps.ReportsHealth {
 		agentCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth
 	}
+	if caps.AcceptsRemoteConfig {
+		agentCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig
+	}
 
 	return agentCapabilities
 }
@@ -198,5 +217,14 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if cfg.Capabilities.AcceptsRemoteConfig {
+		if cfg.RemoteConfig.File == "" {
+			return errors.New("remote_config::file must be set when capabilities::accepts_remote_config is enabled")
+		}
+		if len(cfg.RemoteConfig.LocalConfig) == 0 {
+			return errors.New("remote_config::local_config must be set when capabilities::accepts_remote_config is enabled")
+		}
+	}
+
 	return nil
 }
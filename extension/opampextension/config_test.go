@@ -155,6 +155,7 @@ func TestConfig_Validate(t *testing.T) {
 		Server       *OpAMPServer
 		InstanceUID  string
 		Capabilities Capabilities
+		RemoteConfig RemoteConfig
 	}
 	tests := []struct {
 		name    string
@@ -277,6 +278,41 @@ func TestConfig_Validate(t *testing.T) {
 				return assert.Equal(t, "opamp server must have only ws or http set", err.Error())
 			},
 		},
+		{
+			name: "accepts remote config without remote config file",
+			fields: fields{
+				Server: &OpAMPServer{
+					WS: &commonFields{
+						Endpoint: "wss://127.0.0.1:4320/v1/opamp",
+					},
+				},
+				Capabilities: Capabilities{
+					AcceptsRemoteConfig: true,
+				},
+			},
+			wantErr: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.Equal(t, "remote_config::file must be set when capabilities::accepts_remote_config is enabled", err.Error())
+			},
+		},
+		{
+			name: "accepts remote config without local config",
+			fields: fields{
+				Server: &OpAMPServer{
+					WS: &commonFields{
+						Endpoint: "wss://127.0.0.1:4320/v1/opamp",
+					},
+				},
+				Capabilities: Capabilities{
+					AcceptsRemoteConfig: true,
+				},
+				RemoteConfig: RemoteConfig{
+					File: "/etc/otelcol/remote.yaml",
+				},
+			},
+			wantErr: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.Equal(t, "remote_config::local_config must be set when capabilities::accepts_remote_config is enabled", err.Error())
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -284,6 +320,7 @@ func TestConfig_Validate(t *testing.T) {
 				Server:       tt.fields.Server,
 				InstanceUID:  tt.fields.InstanceUID,
 				Capabilities: tt.fields.Capabilities,
+				RemoteConfig: tt.fields.RemoteConfig,
 			}
 			tt.wantErr(t, cfg.Validate())
 		})
@@ -294,6 +331,7 @@ func TestCapabilities_toAgentCapabilities(t *testing.T) {
 	type fields struct {
 		ReportsEffectiveConfig bool
 		ReportsHealth          bool
+		AcceptsRemoteConfig    bool
 	}
 	tests := []struct {
 		name   string
@@ -313,8 +351,10 @@ func TestCapabilities_toAgentCapabilities(t *testing.T) {
 			fields: fields{
 				ReportsEffectiveConfig: true,
 				ReportsHealth:          true,
+				AcceptsRemoteConfig:    true,
 			},
-			want: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus | protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig | protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth,
+			want: protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus | protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig | protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth |
+				protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig | protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig,
 		},
 	}
 	for _, tt := range tests {
@@ -322,6 +362,7 @@ func TestCapabilities_toAgentCapabilities(t *testing.T) {
 			caps := Capabilities{
 				ReportsEffectiveConfig: tt.fields.ReportsEffectiveConfig,
 				ReportsHealth:          tt.fields.ReportsHealth,
+				AcceptsRemoteConfig:    tt.fields.AcceptsRemoteConfig,
 			}
 			assert.Equalf(t, tt.want, caps.toAgentCapabilities(), "toAgentCapabilities()")
 		})
@@ -13,11 +13,30 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/opampextension/internal/metadata"
 )
 
-func NewFactory() extension.Factory {
+// FactoryOption applies changes to opampExtensionFactory.
+type FactoryOption func(factory *opampExtensionFactory)
+
+// WithConfigReloader sets the function making the collector reload its config once a remote
+// config is written. By default, the collector is sent SIGHUP, which is not supported on windows.
+func WithConfigReloader(reloadConfig func() error) FactoryOption {
+	return func(factory *opampExtensionFactory) {
+		factory.reloadConfig = reloadConfig
+	}
+}
+
+type opampExtensionFactory struct {
+	reloadConfig func() error
+}
+
+func NewFactory(options ...FactoryOption) extension.Factory {
+	f := &opampExtensionFactory{reloadConfig: reloadCollectorConfig}
+	for _, o := range options {
+		o(f)
+	}
 	return extension.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
-		createExtension,
+		f.createExtension,
 		metadata.ExtensionStability,
 	)
 }
@@ -33,6 +52,6 @@ func createDefaultConfig() component.Config {
 	}
 }
 
-func createExtension(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
-	return newOpampAgent(cfg.(*Config), set)
+func (f *opampExtensionFactory) createExtension(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
+	return newOpampAgent(cfg.(*Config), set, f.reloadConfig)
 }
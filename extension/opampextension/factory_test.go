@@ -5,6 +5,7 @@ package opampextension
 
 import (
 	"context"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,18 +15,58 @@ import (
 )
 
 func TestFactory_CreateDefaultConfig(t *testing.T) {
-	cfg := NewFactory().CreateDefaultConfig()
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
 	assert.Equal(t, createDefaultConfig().(*Config), cfg)
 
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
-	ext, err := createExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	ext, err := f.Create(context.Background(), extensiontest.NewNopSettings(), cfg)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
 
 func TestFactory_Create(t *testing.T) {
-	cfg := NewFactory().CreateDefaultConfig()
-	ext, err := createExtension(context.Background(), extensiontest.NewNopSettings(), cfg)
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	ext, err := f.Create(context.Background(), extensiontest.NewNopSettings(), cfg)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
+
+func TestFactory_ConfigReloader(t *testing.T) {
+	called := false
+	tests := []struct {
+		name    string
+		options []FactoryOption
+		check   func(t *testing.T, reloadConfig func() error)
+	}{
+		{
+			name: "default config reloader",
+			check: func(t *testing.T, reloadConfig func() error) {
+				// the collector is sent SIGHUP, except on windows
+				assert.Equal(t, runtime.GOOS != "windows", reloadConfig != nil)
+			},
+		},
+		{
+			name:    "with config reloader",
+			options: []FactoryOption{WithConfigReloader(func() error { called = true; return nil })},
+			check: func(t *testing.T, reloadConfig func() error) {
+				require.NoError(t, reloadConfig())
+				assert.True(t, called)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFactory(tt.options...)
+			cfg := f.CreateDefaultConfig().(*Config)
+			cfg.Capabilities.AcceptsRemoteConfig = true
+			cfg.RemoteConfig.File = "remote.yaml"
+
+			ext, err := f.Create(context.Background(), extensiontest.NewNopSettings(), cfg)
+			require.NoError(t, err)
+			assert.True(t, ext.(*opampAgent).capabilities.AcceptsRemoteConfig)
+			tt.check(t, ext.(*opampAgent).reloadConfig)
+		})
+	}
+}
@@ -62,6 +62,27 @@ type opampAgent struct {
 	opampClient client.OpAMPClient
 
 	customCapabilityRegistry *customCapabilityRegistry
+
+	// rcMux guards the remote config state below.
+	rcMux sync.Mutex
+	// remoteConfigHash is the hash of the remote config the collector runs with.
+	remoteConfigHash []byte
+	// pendingRemoteConfigHash is the hash of the remote config the collector is started with,
+	// until the collector is ready.
+	pendingRemoteConfigHash []byte
+	// failedRemoteConfigHash is the hash of the last remote config the collector could not start
+	// with, and failedRemoteConfigMessage why.
+	failedRemoteConfigHash    []byte
+	failedRemoteConfigMessage string
+	// deferredRemoteConfig is the last remote config received before the collector was ready.
+	deferredRemoteConfig *protobufs.AgentRemoteConfig
+	// ready is set once the collector is ready, and reloading once it is asked to reload its config.
+	ready     bool
+	reloading bool
+	// factories gives access to the component factories, to validate the remote configs.
+	factories factoryHost
+	// reloadConfig makes the collector reload its config.
+	reloadConfig func() error
 }
 
 var (
@@ -70,7 +91,15 @@ var (
 	_ extensioncapabilities.ConfigWatcher          = (*opampAgent)(nil)
 )
 
+var errNoConfigReloader = errors.New("capabilities::accepts_remote_config is enabled but the collector config cannot be reloaded: " +
+	"this is not supported on windows unless the distribution sets a config reloader with WithConfigReloader")
+
 func (o *opampAgent) Start(ctx context.Context, host component.Host) error {
+	if o.capabilities.AcceptsRemoteConfig && o.reloadConfig == nil {
+		// The remote configs cannot be applied without reloading the collector config.
+		return errNoConfigReloader
+	}
+
 	o.reportFunc = func(event *componentstatus.Event) {
 		componentstatus.ReportStatus(host, event)
 	}
@@ -130,6 +159,13 @@ func (o *opampAgent) Start(ctx context.Context, host component.Host) error {
 
 	o.setHealth(&protobufs.ComponentHealth{Healthy: false})
 
+	if o.capabilities.AcceptsRemoteConfig {
+		o.factories, _ = host.(factoryHost)
+		if err := o.loadRemoteConfigState(); err != nil {
+			return err
+		}
+	}
+
 	o.logger.Debug("Starting OpAMP client...")
 
 	if err := o.opampClient.Start(context.Background(), settings); err != nil {
@@ -151,6 +187,10 @@ func (o *opampAgent) Shutdown(ctx context.Context) error {
 		return nil
 	}
 
+	if o.capabilities.AcceptsRemoteConfig {
+		o.remoteConfigShutdown()
+	}
+
 	o.logger.Debug("Stopping OpAMP client...")
 	err := o.opampClient.Stop(ctx)
 	// Opamp-go considers this an error, but the collector does not.
@@ -190,6 +230,9 @@ func (o *opampAgent) Register(capability string, opts ...opampcustommessages.Cus
 
 func (o *opampAgent) Ready() error {
 	o.setHealth(&protobufs.ComponentHealth{Healthy: true})
+	if o.capabilities.AcceptsRemoteConfig {
+		o.remoteConfigReady()
+	}
 	return nil
 }
 
@@ -205,7 +248,7 @@ func (o *opampAgent) updateEffectiveConfig(conf *confmap.Conf) {
 	o.effectiveConfig = conf
 }
 
-func newOpampAgent(cfg *Config, set extension.Settings) (*opampAgent, error) {
+func newOpampAgent(cfg *Config, set extension.Settings, reloadConfig func() error) (*opampAgent, error) {
 	agentType := set.BuildInfo.Command
 
 	sn, ok := set.Resource.Attributes().Get(semconv.AttributeServiceName)
@@ -250,6 +293,7 @@ func newOpampAgent(cfg *Config, set extension.Settings) (*opampAgent, error) {
 		capabilities:             cfg.Capabilities,
 		opampClient:              opampClient,
 		customCapabilityRegistry: newCustomCapabilityRegistry(set.Logger, opampClient),
+		reloadConfig:             reloadConfig,
 	}
 
 	return agent, nil
@@ -365,6 +409,10 @@ func (o *opampAgent) onMessage(_ context.Context, msg *types.MessageData) {
 		}
 	}
 
+	if msg.RemoteConfig != nil && o.capabilities.AcceptsRemoteConfig {
+		o.processRemoteConfig(msg.RemoteConfig)
+	}
+
 	if msg.CustomMessage != nil {
 		o.customCapabilityRegistry.ProcessMessage(msg.CustomMessage)
 	}
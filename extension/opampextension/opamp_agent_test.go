@@ -26,7 +26,7 @@ func TestNewOpampAgent(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := extensiontest.NewNopSettings()
 	set.BuildInfo = component.BuildInfo{Version: "test version", Command: "otelcoltest"}
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, "otelcoltest", o.agentType)
 	assert.Equal(t, "test version", o.agentVersion)
@@ -44,7 +44,7 @@ func TestNewOpampAgentAttributes(t *testing.T) {
 	set.Resource.Attributes().PutStr(semconv.AttributeServiceName, "otelcol-distro")
 	set.Resource.Attributes().PutStr(semconv.AttributeServiceVersion, "distro.0")
 	set.Resource.Attributes().PutStr(semconv.AttributeServiceInstanceID, "f8999bc1-4c9b-4619-9bae-7f009d2411ec")
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, "otelcol-distro", o.agentType)
 	assert.Equal(t, "distro.0", o.agentVersion)
@@ -140,7 +140,7 @@ func TestCreateAgentDescription(t *testing.T) {
 			set.Resource.Attributes().PutStr(semconv.AttributeServiceVersion, serviceVersion)
 			set.Resource.Attributes().PutStr(semconv.AttributeServiceInstanceID, serviceInstanceUUID)
 
-			o, err := newOpampAgent(cfg, set)
+			o, err := newOpampAgent(cfg, set, nil)
 			require.NoError(t, err)
 			assert.Nil(t, o.agentDescription)
 
@@ -154,7 +154,7 @@ func TestCreateAgentDescription(t *testing.T) {
 func TestUpdateAgentIdentity(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := extensiontest.NewNopSettings()
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 
 	olduid := o.instanceID
@@ -170,7 +170,7 @@ func TestUpdateAgentIdentity(t *testing.T) {
 func TestComposeEffectiveConfig(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := extensiontest.NewNopSettings()
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 	assert.Empty(t, o.effectiveConfig)
 
@@ -193,7 +193,7 @@ func TestComposeEffectiveConfig(t *testing.T) {
 func TestShutdown(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := extensiontest.NewNopSettings()
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 
 	// Shutdown with no OpAMP client
@@ -203,13 +203,24 @@ func TestShutdown(t *testing.T) {
 func TestStart(t *testing.T) {
 	cfg := createDefaultConfig()
 	set := extensiontest.NewNopSettings()
-	o, err := newOpampAgent(cfg.(*Config), set)
+	o, err := newOpampAgent(cfg.(*Config), set, nil)
 	assert.NoError(t, err)
 
 	assert.NoError(t, o.Start(context.TODO(), componenttest.NewNopHost()))
 	assert.NoError(t, o.Shutdown(context.TODO()))
 }
 
+func TestStartWithoutConfigReloader(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Capabilities.AcceptsRemoteConfig = true
+	cfg.RemoteConfig.File = "remote.yaml"
+	o, err := newOpampAgent(cfg, extensiontest.NewNopSettings(), nil)
+	require.NoError(t, err)
+
+	// the capability is not dropped silently when the collector config cannot be reloaded
+	assert.ErrorIs(t, o.Start(context.TODO(), componenttest.NewNopHost()), errNoConfigReloader)
+}
+
 func TestParseInstanceIDString(t *testing.T) {
 	testCases := []struct {
 		name         string
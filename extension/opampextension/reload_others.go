@@ -0,0 +1,16 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package opampextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/opampextension"
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadCollectorConfig makes the collector reload its config, as it does when it receives SIGHUP.
+var reloadCollectorConfig = func() error {
+	return syscall.Kill(os.Getpid(), syscall.SIGHUP)
+}
@@ -0,0 +1,9 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package opampextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/opampextension"
+
+// reloadCollectorConfig is not set on windows, where the collector cannot be sent SIGHUP.
+var reloadCollectorConfig func() error
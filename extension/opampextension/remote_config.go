@@ -0,0 +1,412 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampextension // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/opampextension"
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/provider/envprovider"
+	"go.opentelemetry.io/collector/confmap/provider/fileprovider"
+	"go.opentelemetry.io/collector/confmap/provider/yamlprovider"
+	"go.opentelemetry.io/collector/otelcol"
+	"go.opentelemetry.io/collector/service/pipelines"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// remoteConfigHashFileSuffix is appended to the remote config file path to get the path of
+	// the file storing the hash of the remote config the collector runs with.
+	remoteConfigHashFileSuffix = ".hash"
+	// remoteConfigPendingFileSuffix is appended to the remote config file path to get the path of
+	// the file storing the hash of the remote config the collector is reloading with.
+	remoteConfigPendingFileSuffix = ".pending"
+	// remoteConfigPreviousFileSuffix is appended to the remote config file path to get the path of
+	// the previous remote config, restored if the collector cannot start with the new one.
+	remoteConfigPreviousFileSuffix = ".prev"
+	// remoteConfigFailedFileSuffix is appended to the remote config file path to get the path of
+	// the file storing the hash of the last remote config the collector could not start with, and why.
+	remoteConfigFailedFileSuffix = ".failed"
+)
+
+// factoryHost is implemented by the collector host, and gives access to the component
+// factories needed to validate the remote configs.
+type factoryHost interface {
+	GetFactory(component.Kind, component.Type) component.Factory
+}
+
+// placeholderConfig stands for the config of the components the remote config doesn't set.
+// These components already run, so their config is valid.
+type placeholderConfig struct{}
+
+// processRemoteConfig validates the remote config, writes it to the remote config file, and makes
+// the collector reload its config. The collector reload stops this extension, and the new instance
+// reports the remote config as applied once the collector is ready. If the collector cannot start
+// with the remote config, the new instance restores the previous remote config file.
+func (o *opampAgent) processRemoteConfig(rc *protobufs.AgentRemoteConfig) {
+	o.rcMux.Lock()
+	defer o.rcMux.Unlock()
+
+	if !o.ready || o.reloading {
+		// Remote configs are only applied to a running collector.
+		o.deferredRemoteConfig = rc
+		return
+	}
+
+	if bytes.Equal(rc.ConfigHash, o.remoteConfigHash) {
+		o.logger.Debug("Remote config is already applied")
+		return
+	}
+	if bytes.Equal(rc.ConfigHash, o.failedRemoteConfigHash) {
+		o.logger.Debug("Remote config already failed to apply")
+		o.reportRemoteConfigStatus(rc.ConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, o.failedRemoteConfigMessage)
+		return
+	}
+
+	conf, err := composeRemoteConfig(rc.GetConfig())
+	if err == nil {
+		err = o.validateRemoteConfig(conf)
+	}
+	if err != nil {
+		o.reportRemoteConfigStatus(rc.ConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, fmt.Sprintf("Invalid remote config: %v", err))
+		return
+	}
+
+	if err = o.writeRemoteConfig(conf, rc.ConfigHash); err != nil {
+		o.reportRemoteConfigStatus(rc.ConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, fmt.Sprintf("Cannot write remote config: %v", err))
+		return
+	}
+
+	o.reportRemoteConfigStatus(rc.ConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING, "")
+	o.logger.Info("Remote config written, reloading the collector config", zap.String("file", o.cfg.RemoteConfig.File))
+	if err = o.reloadConfig(); err != nil {
+		o.restoreRemoteConfig(rc.ConfigHash, fmt.Sprintf("Cannot reload the collector config: %v", err))
+		return
+	}
+	o.reloading = true
+}
+
+// composeRemoteConfig merges the config files of the remote config in the order of their names.
+func composeRemoteConfig(configMap *protobufs.AgentConfigMap) (*confmap.Conf, error) {
+	files := configMap.GetConfigMap()
+	if len(files) == 0 {
+		return nil, errors.New("no config file")
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conf := confmap.New()
+	for _, name := range names {
+		var m map[string]any
+		if err := yaml.Unmarshal(files[name].Body, &m); err != nil {
+			return nil, fmt.Errorf("cannot parse config file %q: %w", name, err)
+		}
+		if err := conf.Merge(confmap.NewFromStringMap(m)); err != nil {
+			return nil, fmt.Errorf("cannot merge config file %q: %w", name, err)
+		}
+	}
+
+	return conf, nil
+}
+
+// validateRemoteConfig validates the collector config resulting from merging the remote config
+// into the local config, as the collector does when it loads its config. The components set by
+// the remote config are validated with their factory, the others are only checked to exist.
+func (o *opampAgent) validateRemoteConfig(remote *confmap.Conf) error {
+	if o.factories == nil {
+		return errors.New("the collector does not provide the component factories to validate the config")
+	}
+
+	// The config the collector runs with includes the current remote config, which the new one replaces.
+	conf, err := o.loadLocalConfig(context.Background())
+	if err != nil {
+		return fmt.Errorf("cannot load the local config: %w", err)
+	}
+	if err = conf.Merge(remote); err != nil {
+		return err
+	}
+
+	cfg := &otelcol.Config{}
+	sections := []struct {
+		kind    component.Kind
+		name    string
+		configs *map[component.ID]component.Config
+	}{
+		{component.KindReceiver, "receivers", &cfg.Receivers},
+		{component.KindProcessor, "processors", &cfg.Processors},
+		{component.KindExporter, "exporters", &cfg.Exporters},
+		{component.KindConnector, "connectors", &cfg.Connectors},
+		{component.KindExtension, "extensions", &cfg.Extensions},
+	}
+	for _, section := range sections {
+		configs, err := o.unmarshalComponentConfigs(section.kind, section.name, conf, remote)
+		if err != nil {
+			return err
+		}
+		*section.configs = configs
+	}
+
+	var svc struct {
+		Extensions []component.ID   `mapstructure:"extensions"`
+		Pipelines  pipelines.Config `mapstructure:"pipelines"`
+	}
+	service, err := conf.Sub("service")
+	if err != nil {
+		return err
+	}
+	if err = service.Unmarshal(&svc, confmap.WithIgnoreUnused()); err != nil {
+		return fmt.Errorf("error reading service configuration: %w", err)
+	}
+	cfg.Service.Extensions = svc.Extensions
+	cfg.Service.Pipelines = svc.Pipelines
+	// The telemetry of the collector is not validated, keep it from printing its own errors.
+	cfg.Service.Telemetry.Metrics.Level = configtelemetry.LevelNone
+
+	return cfg.Validate()
+}
+
+// loadLocalConfig loads the config the collector loads before the remote config file.
+func (o *opampAgent) loadLocalConfig(ctx context.Context) (*confmap.Conf, error) {
+	resolver, err := confmap.NewResolver(confmap.ResolverSettings{
+		URIs: o.cfg.RemoteConfig.LocalConfig,
+		ProviderFactories: []confmap.ProviderFactory{
+			envprovider.NewFactory(),
+			fileprovider.NewFactory(),
+			yamlprovider.NewFactory(),
+		},
+		ProviderSettings: confmap.ProviderSettings{Logger: o.logger},
+		DefaultScheme:    "env",
+	})
+	if err != nil {
+		return nil, err
+	}
+	conf, err := resolver.Resolve(ctx)
+	return conf, errors.Join(err, resolver.Shutdown(ctx))
+}
+
+func (o *opampAgent) unmarshalComponentConfigs(kind component.Kind, section string, conf, remote *confmap.Conf) (map[component.ID]component.Config, error) {
+	sub, err := conf.Sub(section)
+	if err != nil {
+		return nil, err
+	}
+	rawCfgs := make(map[component.ID]map[string]any)
+	if err = sub.Unmarshal(&rawCfgs); err != nil {
+		return nil, fmt.Errorf("error reading %s configuration: %w", section, err)
+	}
+
+	configs := make(map[component.ID]component.Config, len(rawCfgs))
+	for id := range rawCfgs {
+		if !remote.IsSet(section + confmap.KeyDelimiter + id.String()) {
+			configs[id] = &placeholderConfig{}
+			continue
+		}
+
+		factory := o.factories.GetFactory(kind, id.Type())
+		if factory == nil {
+			return nil, fmt.Errorf("%s::%s: unknown type %q", section, id, id.Type())
+		}
+		idConf, err := sub.Sub(id.String())
+		if err != nil {
+			return nil, err
+		}
+		cfg := factory.CreateDefaultConfig()
+		if err = idConf.Unmarshal(&cfg); err != nil {
+			return nil, fmt.Errorf("%s::%s: %w", section, id, err)
+		}
+		configs[id] = cfg
+	}
+	return configs, nil
+}
+
+// writeRemoteConfig keeps the running remote config, replaces the remote config file so that
+// the collector never loads a partially written file, and records the hash of the new remote
+// config as pending until the collector is ready.
+func (o *opampAgent) writeRemoteConfig(conf *confmap.Conf, hash []byte) error {
+	by, err := yaml.Marshal(conf.ToStringMap())
+	if err != nil {
+		return err
+	}
+
+	file := o.cfg.RemoteConfig.File
+	previous, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err = writeFileAtomic(file+remoteConfigPreviousFileSuffix, previous); err != nil {
+		return err
+	}
+	if err = writeFileAtomic(file+remoteConfigPendingFileSuffix, []byte(hex.EncodeToString(hash))); err != nil {
+		return err
+	}
+	return writeFileAtomic(file, by)
+}
+
+// confirmRemoteConfig records the pending remote config as the one the collector runs with.
+func (o *opampAgent) confirmRemoteConfig() {
+	file := o.cfg.RemoteConfig.File
+	hash := o.pendingRemoteConfigHash
+	o.pendingRemoteConfigHash = nil
+
+	if err := writeFileAtomic(file+remoteConfigHashFileSuffix, []byte(hex.EncodeToString(hash))); err != nil {
+		o.logger.Error("Cannot write remote config hash", zap.Error(err))
+	}
+	for _, suffix := range []string{remoteConfigPreviousFileSuffix, remoteConfigPendingFileSuffix, remoteConfigFailedFileSuffix} {
+		if err := os.Remove(file + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			o.logger.Error("Cannot remove remote config file", zap.String("file", file+suffix), zap.Error(err))
+		}
+	}
+
+	o.remoteConfigHash = hash
+	o.failedRemoteConfigHash = nil
+	o.failedRemoteConfigMessage = ""
+	o.logger.Info("Remote config applied", zap.String("hash", hex.EncodeToString(hash)))
+	o.reportRemoteConfigStatus(hash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+}
+
+// restoreRemoteConfig restores the previous remote config file, so that the collector is
+// started with it again, and records the remote config with the given hash as failed.
+func (o *opampAgent) restoreRemoteConfig(hash []byte, errorMessage string) {
+	file := o.cfg.RemoteConfig.File
+	o.pendingRemoteConfigHash = nil
+	o.logger.Error("Restoring the previous remote config", zap.String("reason", errorMessage))
+
+	if err := os.Rename(file+remoteConfigPreviousFileSuffix, file); err != nil {
+		o.logger.Error("Cannot restore the previous remote config", zap.Error(err))
+	}
+	if err := os.Remove(file + remoteConfigPendingFileSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		o.logger.Error("Cannot remove the pending remote config hash", zap.Error(err))
+	}
+	if err := writeFileAtomic(file+remoteConfigFailedFileSuffix, []byte(hex.EncodeToString(hash)+"\n"+errorMessage)); err != nil {
+		o.logger.Error("Cannot write the failed remote config hash", zap.Error(err))
+	}
+
+	o.failedRemoteConfigHash = hash
+	o.failedRemoteConfigMessage = errorMessage
+	o.reportRemoteConfigStatus(hash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, errorMessage)
+}
+
+// loadRemoteConfigState loads the hash of the remote config the collector runs with, of the
+// remote config the collector is being started with if any, and of the last failed remote config.
+func (o *opampAgent) loadRemoteConfigState() error {
+	file := o.cfg.RemoteConfig.File
+
+	var err error
+	if o.remoteConfigHash, err = readHashFile(file + remoteConfigHashFileSuffix); err != nil {
+		return fmt.Errorf("cannot read remote config hash: %w", err)
+	}
+	if o.pendingRemoteConfigHash, err = readHashFile(file + remoteConfigPendingFileSuffix); err != nil {
+		return fmt.Errorf("cannot read pending remote config hash: %w", err)
+	}
+
+	by, err := os.ReadFile(file + remoteConfigFailedFileSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read failed remote config hash: %w", err)
+	}
+	hash, message, _ := strings.Cut(string(by), "\n")
+	if o.failedRemoteConfigHash, err = hex.DecodeString(hash); err != nil {
+		return fmt.Errorf("invalid failed remote config hash: %w", err)
+	}
+	o.failedRemoteConfigMessage = message
+	return nil
+}
+
+// remoteConfigReady reports the status of the remote config once the collector is ready,
+// and applies the remote config received while the collector was starting.
+func (o *opampAgent) remoteConfigReady() {
+	o.rcMux.Lock()
+	o.ready = true
+	o.reportRemoteConfigState()
+	rc := o.deferredRemoteConfig
+	o.deferredRemoteConfig = nil
+	o.rcMux.Unlock()
+
+	if rc != nil {
+		o.processRemoteConfig(rc)
+	}
+}
+
+// remoteConfigShutdown restores the previous remote config if the collector is stopped before it
+// was ready with the pending remote config, which happens when the collector cannot start with it.
+func (o *opampAgent) remoteConfigShutdown() {
+	o.rcMux.Lock()
+	defer o.rcMux.Unlock()
+
+	if o.pendingRemoteConfigHash != nil {
+		o.restoreRemoteConfig(o.pendingRemoteConfigHash, "The collector could not start with the remote config")
+	}
+}
+
+// reportRemoteConfigState reports the status of the remote config once the collector is ready.
+func (o *opampAgent) reportRemoteConfigState() {
+	switch {
+	case o.pendingRemoteConfigHash != nil:
+		o.confirmRemoteConfig()
+	case o.failedRemoteConfigHash != nil:
+		o.reportRemoteConfigStatus(o.failedRemoteConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, o.failedRemoteConfigMessage)
+	case o.remoteConfigHash != nil:
+		o.reportRemoteConfigStatus(o.remoteConfigHash, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+	}
+}
+
+func readHashFile(file string) ([]byte, error) {
+	by, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(string(bytes.TrimSpace(by)))
+}
+
+// writeFileAtomic replaces file with a file holding data, so that file is never partially written.
+func writeFileAtomic(file string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err = errors.Join(err, tmp.Close()); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (o *opampAgent) reportRemoteConfigStatus(hash []byte, status protobufs.RemoteConfigStatuses, errorMessage string) {
+	err := o.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: hash,
+		Status:               status,
+		ErrorMessage:         errorMessage,
+	})
+	if err != nil {
+		o.logger.Error("Could not report OpAMP remote config status", zap.Error(err))
+	}
+}
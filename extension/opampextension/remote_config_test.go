@@ -0,0 +1,330 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package opampextension
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+)
+
+type mockRemoteConfigClient struct {
+	client.OpAMPClient
+	statuses []*protobufs.RemoteConfigStatus
+}
+
+func (m *mockRemoteConfigClient) SetRemoteConfigStatus(status *protobufs.RemoteConfigStatus) error {
+	m.statuses = append(m.statuses, status)
+	return nil
+}
+
+func (m *mockRemoteConfigClient) Stop(context.Context) error {
+	return nil
+}
+
+type testComponentConfig struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func (cfg *testComponentConfig) Validate() error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint must be set")
+	}
+	return nil
+}
+
+type testFactory struct{}
+
+func (testFactory) Type() component.Type {
+	return component.MustNewType("test")
+}
+
+func (testFactory) CreateDefaultConfig() component.Config {
+	return &testComponentConfig{}
+}
+
+// factoriesHost provides the test factory for every kind of component.
+type factoriesHost struct {
+	component.Host
+}
+
+func (factoriesHost) GetFactory(_ component.Kind, componentType component.Type) component.Factory {
+	if componentType == (testFactory{}).Type() {
+		return testFactory{}
+	}
+	return nil
+}
+
+func remoteConfig(hash string, files map[string]string) *protobufs.AgentRemoteConfig {
+	configMap := map[string]*protobufs.AgentConfigFile{}
+	for name, body := range files {
+		configMap[name] = &protobufs.AgentConfigFile{Body: []byte(body), ContentType: "text/yaml"}
+	}
+	return &protobufs.AgentRemoteConfig{
+		Config:     &protobufs.AgentConfigMap{ConfigMap: configMap},
+		ConfigHash: []byte(hash),
+	}
+}
+
+func TestComposeRemoteConfig(t *testing.T) {
+	conf, err := composeRemoteConfig(remoteConfig("hash", map[string]string{
+		"b": "exporters:\n  debug:\n    verbosity: basic\n",
+		"a": "receivers:\n  otlp:\n    protocols:\n      grpc:\nexporters:\n  debug:\n    verbosity: detailed\n",
+	}).Config)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"receivers": map[string]any{"otlp": map[string]any{"protocols": map[string]any{"grpc": nil}}},
+		"exporters": map[string]any{"debug": map[string]any{"verbosity": "basic"}},
+	}, conf.ToStringMap())
+
+	_, err = composeRemoteConfig(remoteConfig("hash", nil).Config)
+	require.EqualError(t, err, "no config file")
+
+	_, err = composeRemoteConfig(remoteConfig("hash", map[string]string{"": "receivers: ["}).Config)
+	require.ErrorContains(t, err, `cannot parse config file "": yaml:`)
+}
+
+const (
+	localConfig = `
+receivers:
+  test/local:
+    endpoint: localhost:4317
+exporters:
+  test:
+    endpoint: localhost:4318
+service:
+  pipelines:
+    traces:
+      receivers: [test/local]
+      exporters: [test]
+`
+	previousRemoteConfig = "exporters:\n  test/previous:\n    endpoint: localhost:14318\n"
+	validRemoteConfig    = `
+receivers:
+  test/remote:
+    endpoint: localhost:14317
+service:
+  pipelines:
+    traces:
+      receivers: [test/local, test/remote]
+`
+)
+
+func TestProcessRemoteConfig(t *testing.T) {
+	newAgent := func(t *testing.T, file string, reloadErr error) (*opampAgent, *mockRemoteConfigClient, *int) {
+		mockClient := &mockRemoteConfigClient{}
+		reloads := 0
+		localFile := filepath.Join(filepath.Dir(file), "local.yaml")
+		require.NoError(t, os.WriteFile(localFile, []byte(localConfig), 0o600))
+		o := &opampAgent{
+			cfg:          &Config{RemoteConfig: RemoteConfig{File: file, LocalConfig: []string{"file:" + localFile}}},
+			logger:       zap.NewNop(),
+			capabilities: Capabilities{AcceptsRemoteConfig: true},
+			opampClient:  mockClient,
+			factories:    factoriesHost{componenttest.NewNopHost()},
+			reloadConfig: func() error {
+				reloads++
+				return reloadErr
+			},
+		}
+		require.NoError(t, o.loadRemoteConfigState())
+		return o, mockClient, &reloads
+	}
+	newFile := func(t *testing.T) string {
+		file := filepath.Join(t.TempDir(), "remote.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(previousRemoteConfig), 0o600))
+		return file
+	}
+	statuses := func(mockClient *mockRemoteConfigClient) []protobufs.RemoteConfigStatuses {
+		var statuses []protobufs.RemoteConfigStatuses
+		for _, status := range mockClient.statuses {
+			statuses = append(statuses, status.Status)
+		}
+		return statuses
+	}
+
+	t.Run("applies remote config once the collector is ready", func(t *testing.T) {
+		file := newFile(t)
+		o, mockClient, reloads := newAgent(t, file, nil)
+
+		// The remote config received while the collector starts is applied once it is ready.
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+		assert.Equal(t, 0, *reloads)
+		o.remoteConfigReady()
+
+		conf, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(conf), "test/remote")
+		assert.FileExists(t, file+remoteConfigPreviousFileSuffix)
+		assert.NoFileExists(t, file+remoteConfigHashFileSuffix)
+		assert.Equal(t, 1, *reloads)
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING}, statuses(mockClient))
+		assert.Equal(t, []byte("hash1"), mockClient.statuses[0].LastRemoteConfigHash)
+
+		// The remote config is not applied again while the collector reloads.
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+		assert.Equal(t, 1, *reloads)
+		require.NoError(t, o.Shutdown(context.Background()))
+
+		// The extension restarted by the reload reports the remote config as applied once the collector is ready.
+		restarted, restartedClient, _ := newAgent(t, file, nil)
+		assert.Equal(t, []byte("hash1"), restarted.pendingRemoteConfigHash)
+		assert.Empty(t, restartedClient.statuses)
+		restarted.remoteConfigReady()
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED}, statuses(restartedClient))
+		assert.Equal(t, []byte("hash1"), restarted.remoteConfigHash)
+		assert.NoFileExists(t, file+remoteConfigPreviousFileSuffix)
+		assert.NoFileExists(t, file+remoteConfigPendingFileSuffix)
+		require.NoError(t, restarted.Shutdown(context.Background()))
+
+		// The collector restarted later still reports it as applied.
+		restarted, restartedClient, _ = newAgent(t, file, nil)
+		restarted.remoteConfigReady()
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED}, statuses(restartedClient))
+		restarted.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+		assert.Len(t, restartedClient.statuses, 1)
+	})
+
+	t.Run("collector cannot start with the remote config", func(t *testing.T) {
+		file := newFile(t)
+		o, _, _ := newAgent(t, file, nil)
+		o.remoteConfigReady()
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+
+		// The collector stops before it is ready with the remote config.
+		restarted, restartedClient, _ := newAgent(t, file, nil)
+		require.NoError(t, restarted.Shutdown(context.Background()))
+
+		conf, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, previousRemoteConfig, string(conf))
+		assert.NoFileExists(t, file+remoteConfigPendingFileSuffix)
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED}, statuses(restartedClient))
+		assert.Equal(t, "The collector could not start with the remote config", restartedClient.statuses[0].ErrorMessage)
+
+		// The collector restarted with the previous remote config reports the failure, and doesn't apply it again.
+		restarted, restartedClient, reloads := newAgent(t, file, nil)
+		restarted.remoteConfigReady()
+		restarted.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+		assert.Equal(t, 0, *reloads)
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{
+			protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+			protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+		}, statuses(restartedClient))
+		assert.Equal(t, []byte("hash1"), restartedClient.statuses[1].LastRemoteConfigHash)
+	})
+
+	t.Run("invalid remote config", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			config string
+			errMsg string
+		}{
+			{
+				name:   "cannot parse",
+				config: "receivers: [",
+				errMsg: `Invalid remote config: cannot parse config file ""`,
+			},
+			{
+				name:   "invalid component config",
+				config: "receivers:\n  test/remote:\n    endpoint: \"\"\n",
+				errMsg: "Invalid remote config: receivers::test/remote: endpoint must be set",
+			},
+			{
+				name:   "unknown component type",
+				config: "exporters:\n  unknown:\n",
+				errMsg: `Invalid remote config: exporters::unknown: unknown type "unknown"`,
+			},
+			{
+				name:   "unknown component field",
+				config: "exporters:\n  test:\n    address: localhost\n",
+				errMsg: "Invalid remote config: exporters::test: decoding failed",
+			},
+			{
+				name:   "unconfigured component",
+				config: "service:\n  pipelines:\n    traces:\n      exporters: [test/other]\n",
+				errMsg: `Invalid remote config: service::pipelines::traces: references exporter "test/other" which is not configured`,
+			},
+			{
+				name:   "component of the replaced remote config",
+				config: "service:\n  pipelines:\n    traces:\n      exporters: [test/previous]\n",
+				errMsg: `Invalid remote config: service::pipelines::traces: references exporter "test/previous" which is not configured`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				file := newFile(t)
+				o, mockClient, reloads := newAgent(t, file, nil)
+				o.remoteConfigReady()
+
+				o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": tt.config}))
+
+				conf, err := os.ReadFile(file)
+				require.NoError(t, err)
+				assert.Equal(t, previousRemoteConfig, string(conf))
+				assert.Equal(t, 0, *reloads)
+				require.Equal(t, []protobufs.RemoteConfigStatuses{protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED}, statuses(mockClient))
+				assert.Contains(t, mockClient.statuses[0].ErrorMessage, tt.errMsg)
+			})
+		}
+	})
+
+	t.Run("no component factories", func(t *testing.T) {
+		o, mockClient, _ := newAgent(t, newFile(t), nil)
+		o.factories = nil
+		o.remoteConfigReady()
+
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+
+		require.Len(t, mockClient.statuses, 1)
+		assert.Equal(t, "Invalid remote config: the collector does not provide the component factories to validate the config", mockClient.statuses[0].ErrorMessage)
+	})
+
+	t.Run("local config cannot be loaded", func(t *testing.T) {
+		o, mockClient, _ := newAgent(t, newFile(t), nil)
+		o.cfg.RemoteConfig.LocalConfig = []string{"file:" + filepath.Join(t.TempDir(), "missing.yaml")}
+		o.remoteConfigReady()
+
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+
+		require.Len(t, mockClient.statuses, 1)
+		assert.Contains(t, mockClient.statuses[0].ErrorMessage, "Invalid remote config: cannot load the local config:")
+	})
+
+	t.Run("reload fails", func(t *testing.T) {
+		file := newFile(t)
+		o, mockClient, reloads := newAgent(t, file, errors.New("not supported"))
+		o.remoteConfigReady()
+
+		o.processRemoteConfig(remoteConfig("hash1", map[string]string{"": validRemoteConfig}))
+
+		assert.Equal(t, 1, *reloads)
+		conf, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, previousRemoteConfig, string(conf))
+		assert.Equal(t, []protobufs.RemoteConfigStatuses{
+			protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLYING,
+			protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+		}, statuses(mockClient))
+		assert.Equal(t, "Cannot reload the collector config: not supported", mockClient.statuses[1].ErrorMessage)
+	})
+
+	t.Run("no remote config", func(t *testing.T) {
+		o, mockClient, _ := newAgent(t, filepath.Join(t.TempDir(), "remote.yaml"), nil)
+		assert.Nil(t, o.remoteConfigHash)
+		assert.Nil(t, o.pendingRemoteConfigHash)
+		o.remoteConfigReady()
+		assert.Empty(t, mockClient.statuses)
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lookuptable provides tables of attributes indexed by key, shared between the extensions
+// loading them and the components enriching telemetry with them.
+package lookuptable // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Table holds rows of attributes indexed by key. The rows are replaced as a whole when the
+// source of the table is refreshed, so that lookups never see a partially loaded table.
+type Table struct {
+	rows atomic.Pointer[map[string]pcommon.Map]
+}
+
+// NewTable returns an empty table.
+func NewTable() *Table {
+	t := &Table{}
+	t.Replace(map[string]pcommon.Map{})
+	return t
+}
+
+// Replace replaces all the rows of the table. The rows must not be modified afterwards.
+func (t *Table) Replace(rows map[string]pcommon.Map) {
+	t.rows.Store(&rows)
+}
+
+// Lookup returns the row of the given key. The returned map is shared and must not be modified.
+func (t *Table) Lookup(key string) (pcommon.Map, bool) {
+	row, ok := (*t.rows.Load())[key]
+	return row, ok
+}
+
+// Len returns the number of rows of the table.
+func (t *Table) Len() int {
+	return len(*t.rows.Load())
+}
+
+// Provider is implemented by the extensions holding lookup tables, for the components referencing
+// them by ID.
+type Provider interface {
+	// Table returns the table of the given name.
+	Table(name string) (*Table, bool)
+}
+
+// GetProvider returns the lookup tables of the extension with the given ID.
+func GetProvider(host component.Host, id component.ID) (Provider, error) {
+	ext, ok := host.GetExtensions()[id]
+	if !ok {
+		return nil, fmt.Errorf("lookup tables extension %q not found", id)
+	}
+	provider, ok := ext.(Provider)
+	if !ok {
+		return nil, fmt.Errorf("extension %q does not provide lookup tables", id)
+	}
+	return provider, nil
+}
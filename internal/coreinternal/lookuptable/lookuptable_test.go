@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookuptable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestTable(t *testing.T) {
+	table := NewTable()
+	assert.Equal(t, 0, table.Len())
+	_, ok := table.Lookup("checkout")
+	assert.False(t, ok)
+
+	row := pcommon.NewMap()
+	row.PutStr("team", "payments")
+	table.Replace(map[string]pcommon.Map{"checkout": row})
+	assert.Equal(t, 1, table.Len())
+	actual, ok := table.Lookup("checkout")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"team": "payments"}, actual.AsRaw())
+}
+
+type nopExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+type providerExtension struct {
+	nopExtension
+	tables map[string]*Table
+}
+
+func (e *providerExtension) Table(name string) (*Table, bool) {
+	table, ok := e.tables[name]
+	return table, ok
+}
+
+type testHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *testHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestGetProvider(t *testing.T) {
+	owners := NewTable()
+	tablesID := component.MustNewID("lookup_tables")
+	otherID := component.MustNewID("other")
+	host := &testHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			tablesID: &providerExtension{tables: map[string]*Table{"owners": owners}},
+			otherID:  &nopExtension{},
+		},
+	}
+
+	provider, err := GetProvider(host, tablesID)
+	require.NoError(t, err)
+	actual, ok := provider.Table("owners")
+	require.True(t, ok)
+	assert.Same(t, owners, actual)
+
+	_, err = GetProvider(host, otherID)
+	assert.EqualError(t, err, `extension "other" does not provide lookup tables`)
+
+	_, err = GetProvider(host, component.MustNewIDWithName("lookup_tables", "missing"))
+	assert.EqualError(t, err, `lookup tables extension "lookup_tables/missing" not found`)
+}
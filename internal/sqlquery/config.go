@@ -39,11 +39,14 @@ func (c Config) Validate() error {
 }
 
 type Query struct {
-	SQL                string      `mapstructure:"sql"`
-	Metrics            []MetricCfg `mapstructure:"metrics"`
-	Logs               []LogsCfg   `mapstructure:"logs"`
-	TrackingColumn     string      `mapstructure:"tracking_column"`
-	TrackingStartValue string      `mapstructure:"tracking_start_value"`
+	SQL                 string      `mapstructure:"sql"`
+	Metrics             []MetricCfg `mapstructure:"metrics"`
+	Logs                []LogsCfg   `mapstructure:"logs"`
+	TrackingColumn      string      `mapstructure:"tracking_column"`
+	TrackingStartValue  string      `mapstructure:"tracking_start_value"`
+	TrackingColumns     []string    `mapstructure:"tracking_columns"`
+	TrackingStartValues []string    `mapstructure:"tracking_start_values"`
+	MaxRowsPerQuery     uint64      `mapstructure:"max_rows_per_query"`
 }
 
 func (q Query) Validate() error {
@@ -54,6 +57,18 @@ func (q Query) Validate() error {
 	if len(q.Logs) == 0 && len(q.Metrics) == 0 {
 		errs = append(errs, errors.New("at least one of 'query.logs' and 'query.metrics' must not be empty"))
 	}
+	if q.TrackingColumn != "" && len(q.TrackingColumns) > 0 {
+		errs = append(errs, errors.New("only one of 'tracking_column' and 'tracking_columns' can be set"))
+	}
+	if q.TrackingStartValue != "" && len(q.TrackingColumns) > 0 {
+		errs = append(errs, errors.New("'tracking_start_value' cannot be used with 'tracking_columns', use 'tracking_start_values' instead"))
+	}
+	if len(q.TrackingStartValues) > 0 && len(q.TrackingStartValues) != len(q.TrackingColumns) {
+		errs = append(errs, fmt.Errorf("'tracking_start_values' must have one value per tracking column, got %d values for %d columns", len(q.TrackingStartValues), len(q.TrackingColumns)))
+	}
+	if q.MaxRowsPerQuery > 0 && len(q.TrackingColumnNames()) == 0 {
+		errs = append(errs, errors.New("'max_rows_per_query' requires 'tracking_column' or 'tracking_columns' to be set"))
+	}
 	for _, logs := range q.Logs {
 		if err := logs.Validate(); err != nil {
 			errs = append(errs, err)
@@ -67,9 +82,31 @@ func (q Query) Validate() error {
 	return errors.Join(errs...)
 }
 
+// TrackingColumnNames returns the columns tracking the processed rows, set by either
+// `tracking_column` or `tracking_columns`.
+func (q Query) TrackingColumnNames() []string {
+	if q.TrackingColumn != "" {
+		return []string{q.TrackingColumn}
+	}
+	return q.TrackingColumns
+}
+
+// TrackingStartParameters returns the initial values of the tracking parameters, one per tracking column.
+func (q Query) TrackingStartParameters() []string {
+	if q.TrackingColumn != "" {
+		return []string{q.TrackingStartValue}
+	}
+	if len(q.TrackingStartValues) == 0 {
+		return make([]string, len(q.TrackingColumns))
+	}
+	return q.TrackingStartValues
+}
+
 type LogsCfg struct {
 	BodyColumn       string   `mapstructure:"body_column"`
 	AttributeColumns []string `mapstructure:"attribute_columns"`
+	TimestampColumn  string   `mapstructure:"timestamp_column"`
+	SeverityColumn   string   `mapstructure:"severity_column"`
 }
 
 func (config LogsCfg) Validate() error {
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+var (
+	// ErrNoResolver is returned when no resolver is configured.
+	ErrNoResolver = errors.New("no resolvers specified")
+	// ErrMultipleResolversProvided is returned when more than one resolver is configured.
+	ErrMultipleResolversProvided = errors.New("only one resolver should be specified")
+
+	errEndpointSet = errors.New("the endpoint must not be set, the endpoints of the peers are found by the resolver")
+)
+
+// Config defines how the traces are sent to the peers.
+type Config struct {
+	// Protocol holds the settings of the exporters sending traces to the peers.
+	Protocol Protocol `mapstructure:"protocol"`
+	// Resolver holds the settings used to find the peers.
+	Resolver ResolverSettings `mapstructure:"resolver"`
+}
+
+// Protocol holds the settings of the exporters sending traces to the peers.
+type Protocol struct {
+	OTLP OTLPConfig `mapstructure:"otlp"`
+}
+
+// OTLPConfig holds the settings of the OTLP exporters sending traces to the peers.
+// The endpoint of each exporter is the endpoint of a peer found by the resolver.
+type OTLPConfig struct {
+	exporterhelper.TimeoutConfig `mapstructure:",squash"`
+	exporterhelper.QueueConfig   `mapstructure:"sending_queue"`
+	RetryConfig                  configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+
+	configgrpc.ClientConfig `mapstructure:",squash"`
+}
+
+// ResolverSettings defines the configurations for the backend resolver
+type ResolverSettings struct {
+	Static      *StaticResolver      `mapstructure:"static"`
+	DNS         *DNSResolver         `mapstructure:"dns"`
+	K8sSvc      *K8sSvcResolver      `mapstructure:"k8s"`
+	AWSCloudMap *AWSCloudMapResolver `mapstructure:"aws_cloud_map"`
+}
+
+// StaticResolver defines the configuration for the resolver providing a fixed list of backends
+type StaticResolver struct {
+	Hostnames []string `mapstructure:"hostnames"`
+}
+
+// DNSResolver defines the configuration for the DNS resolver
+type DNSResolver struct {
+	Hostname string        `mapstructure:"hostname"`
+	Port     string        `mapstructure:"port"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// K8sSvcResolver defines the configuration for the DNS resolver
+type K8sSvcResolver struct {
+	Service string        `mapstructure:"service"`
+	Ports   []int32       `mapstructure:"ports"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type AWSCloudMapResolver struct {
+	NamespaceName string                   `mapstructure:"namespace"`
+	ServiceName   string                   `mapstructure:"service_name"`
+	HealthStatus  types.HealthStatusFilter `mapstructure:"health_status"`
+	Interval      time.Duration            `mapstructure:"interval"`
+	Timeout       time.Duration            `mapstructure:"timeout"`
+	Port          *uint16                  `mapstructure:"port"`
+}
+
+// validateCount checks that exactly one resolver is configured.
+func (cfg ResolverSettings) validateCount() error {
+	count := 0
+	if cfg.Static != nil {
+		count++
+	}
+	if cfg.DNS != nil {
+		count++
+	}
+	if cfg.K8sSvc != nil {
+		count++
+	}
+	if cfg.AWSCloudMap != nil {
+		count++
+	}
+	switch {
+	case count == 0:
+		return ErrNoResolver
+	case count > 1:
+		return ErrMultipleResolversProvided
+	}
+	return nil
+}
+
+// NewDefaultConfig returns the config with the default settings of the OTLP exporter.
+func NewDefaultConfig() Config {
+	otlpCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	return Config{
+		Protocol: Protocol{
+			OTLP: OTLPConfig{
+				TimeoutConfig: otlpCfg.TimeoutConfig,
+				QueueConfig:   otlpCfg.QueueConfig,
+				RetryConfig:   otlpCfg.RetryConfig,
+				ClientConfig:  otlpCfg.ClientConfig,
+			},
+		},
+	}
+}
+
+// Validate checks that at most one resolver is configured, with the settings it requires.
+// Whether a resolver is required is left to the component, which may not always route traces.
+func (cfg *Config) Validate() error {
+	res := cfg.Resolver
+	if res == (ResolverSettings{}) {
+		return nil
+	}
+	if err := res.validateCount(); err != nil {
+		return err
+	}
+	switch {
+	case res.Static != nil && len(res.Static.Hostnames) == 0:
+		return errNoEndpoints
+	case res.DNS != nil && res.DNS.Hostname == "":
+		return errNoHostname
+	case res.K8sSvc != nil && res.K8sSvc.Service == "":
+		return errNoSvc
+	case res.AWSCloudMap != nil && res.AWSCloudMap.NamespaceName == "":
+		return errNoNamespace
+	case res.AWSCloudMap != nil && res.AWSCloudMap.ServiceName == "":
+		return errNoServiceName
+	}
+	return nil
+}
+
+// Validate checks that the endpoint is left to the resolver.
+func (cfg *OTLPConfig) Validate() error {
+	if cfg.Endpoint != "" {
+		return errEndpointSet
+	}
+	return nil
+}
+
+// exporterConfig returns the config of the OTLP exporter sending traces to the peer at endpoint.
+func (cfg *OTLPConfig) exporterConfig(endpoint string) *otlpexporter.Config {
+	otlpCfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+	otlpCfg.TimeoutConfig = cfg.TimeoutConfig
+	otlpCfg.QueueConfig = cfg.QueueConfig
+	otlpCfg.RetryConfig = cfg.RetryConfig
+	otlpCfg.ClientConfig = cfg.ClientConfig
+	otlpCfg.Endpoint = endpoint
+	return otlpCfg
+}
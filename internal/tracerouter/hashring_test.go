@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter
+package tracerouter
 
 import (
 	"fmt"
@@ -15,7 +15,7 @@ func TestNewHashRing(t *testing.T) {
 	endpoints := []string{"endpoint-1", "endpoint-2"}
 
 	// test
-	ring := newHashRing(endpoints)
+	ring := NewHashRing(endpoints)
 
 	// verify
 	assert.Len(t, ring.items, 2*defaultWeight)
@@ -24,7 +24,7 @@ func TestNewHashRing(t *testing.T) {
 func TestEndpointFor(t *testing.T) {
 	// prepare
 	endpoints := []string{"endpoint-1", "endpoint-2"}
-	ring := newHashRing(endpoints)
+	ring := NewHashRing(endpoints)
 
 	for _, tt := range []struct {
 		id       []byte
@@ -38,7 +38,7 @@ func TestEndpointFor(t *testing.T) {
 	} {
 		t.Run(fmt.Sprintf("Endpoint for id %s", string(tt.id)), func(t *testing.T) {
 			// test
-			endpoint := ring.endpointFor(tt.id)
+			endpoint := ring.EndpointFor(tt.id)
 
 			// verify
 			assert.Equal(t, tt.expected, endpoint)
@@ -154,7 +154,7 @@ func TestPositionsForEndpoints(t *testing.T) {
 }
 
 func TestEqual(t *testing.T) {
-	original := &hashRing{
+	original := &HashRing{
 		[]ringItem{
 			{pos: position(123), endpoint: "endpoint-1"},
 		},
@@ -162,12 +162,12 @@ func TestEqual(t *testing.T) {
 
 	for _, tt := range []struct {
 		name      string
-		candidate *hashRing
+		candidate *HashRing
 		outcome   bool
 	}{
 		{
 			"empty",
-			&hashRing{[]ringItem{}},
+			&HashRing{[]ringItem{}},
 			false,
 		},
 		{
@@ -177,7 +177,7 @@ func TestEqual(t *testing.T) {
 		},
 		{
 			"equal",
-			&hashRing{
+			&HashRing{
 				[]ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 				},
@@ -186,7 +186,7 @@ func TestEqual(t *testing.T) {
 		},
 		{
 			"different length",
-			&hashRing{
+			&HashRing{
 				[]ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 					{pos: position(124), endpoint: "endpoint-2"},
@@ -196,7 +196,7 @@ func TestEqual(t *testing.T) {
 		},
 		{
 			"different position",
-			&hashRing{
+			&HashRing{
 				[]ringItem{
 					{pos: position(124), endpoint: "endpoint-1"},
 				},
@@ -205,7 +205,7 @@ func TestEqual(t *testing.T) {
 		},
 		{
 			"different endpoint",
-			&hashRing{
+			&HashRing{
 				[]ringItem{
 					{pos: position(123), endpoint: "endpoint-2"},
 				},
@@ -214,7 +214,7 @@ func TestEqual(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.outcome, original.equal(tt.candidate))
+			assert.Equal(t, tt.outcome, original.Equal(tt.candidate))
 		})
 	}
 }
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Resolver determines the contract for sources of backend endpoint information
+type Resolver interface {
+	// Resolve returns the current list of endpoints.
+	// returns either a non-nil error and a nil list of endpoints, or a non-nil list of endpoints and nil error.
+	Resolve(context.Context) ([]string, error)
+
+	// Start signals the resolver to start its work
+	Start(context.Context) error
+
+	// Shutdown signals the resolver to finish its work. This should block until the current resolutions are finished.
+	// Once this is invoked, callbacks will not be triggered anymore and will need to be registered again in case the consumer
+	// decides to restart the resolver.
+	Shutdown(context.Context) error
+
+	// OnChange registers a function to call back whenever the list of backends is updated.
+	// Make sure to register the callbacks before starting the exporter.
+	OnChange(func([]string))
+}
+
+// ResolverTelemetry holds the instruments recording the resolutions of the backends.
+type ResolverTelemetry struct {
+	// NumResolutions counts the resolutions, with the resolver and whether it succeeded as attributes.
+	NumResolutions metric.Int64Counter
+	// NumBackends records the number of backends after each update.
+	NumBackends metric.Int64Gauge
+	// NumBackendUpdates counts the updates of the list of backends.
+	NumBackendUpdates metric.Int64Counter
+}
+
+// NopResolverTelemetry returns the telemetry of a resolver that records nothing.
+func NopResolverTelemetry() ResolverTelemetry {
+	meter := noop.NewMeterProvider().Meter("")
+	numResolutions, _ := meter.Int64Counter("")
+	numBackends, _ := meter.Int64Gauge("")
+	numBackendUpdates, _ := meter.Int64Counter("")
+	return ResolverTelemetry{
+		NumResolutions:    numResolutions,
+		NumBackends:       numBackends,
+		NumBackendUpdates: numBackendUpdates,
+	}
+}
+
+// NewResolver creates the resolver configured in cfg. The k8s resolver uses the in-cluster client.
+func NewResolver(logger *zap.Logger, cfg ResolverSettings, telemetry ResolverTelemetry) (Resolver, error) {
+	if err := cfg.validateCount(); err != nil {
+		return nil, err
+	}
+
+	var (
+		res Resolver
+		err error
+	)
+	switch {
+	case cfg.Static != nil:
+		res, err = newStaticResolver(cfg.Static.Hostnames, telemetry)
+	case cfg.DNS != nil:
+		res, err = newDNSResolver(
+			logger.With(zap.String("resolver", "dns")),
+			cfg.DNS.Hostname,
+			cfg.DNS.Port,
+			cfg.DNS.Interval,
+			cfg.DNS.Timeout,
+			telemetry,
+		)
+	case cfg.K8sSvc != nil:
+		var clt kubernetes.Interface
+		if clt, err = newInClusterClient(); err != nil {
+			return nil, err
+		}
+		res, err = newK8sResolver(
+			clt,
+			logger.With(zap.String("resolver", "k8s service")),
+			cfg.K8sSvc.Service,
+			cfg.K8sSvc.Ports,
+			cfg.K8sSvc.Timeout,
+			telemetry,
+		)
+	default:
+		res, err = newCloudMapResolver(
+			logger.With(zap.String("resolver", "aws_cloud_map")),
+			&cfg.AWSCloudMap.NamespaceName,
+			&cfg.AWSCloudMap.ServiceName,
+			cfg.AWSCloudMap.Port,
+			&cfg.AWSCloudMap.HealthStatus,
+			cfg.AWSCloudMap.Interval,
+			cfg.AWSCloudMap.Timeout,
+			telemetry,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NewK8sResolver creates the k8s resolver configured in cfg using clt, instead of the in-cluster client.
+func NewK8sResolver(clt kubernetes.Interface, logger *zap.Logger, cfg K8sSvcResolver, telemetry ResolverTelemetry) (Resolver, error) {
+	res, err := newK8sResolver(clt, logger, cfg.Service, cfg.Ports, cfg.Timeout, telemetry)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
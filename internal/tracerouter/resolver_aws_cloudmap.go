@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 
 import (
 	"context"
@@ -18,8 +18,6 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
 
 const (
@@ -61,7 +59,7 @@ type cloudMapResolver struct {
 	shutdownWg         sync.WaitGroup
 	changeCallbackLock sync.RWMutex
 	discoveryFn        func(params *servicediscovery.DiscoverInstancesInput) (*servicediscovery.DiscoverInstancesOutput, error)
-	telemetry          *metadata.TelemetryBuilder
+	telemetry          ResolverTelemetry
 }
 
 func newCloudMapResolver(
@@ -72,7 +70,7 @@ func newCloudMapResolver(
 	healthStatus *types.HealthStatusFilter,
 	interval time.Duration,
 	timeout time.Duration,
-	tb *metadata.TelemetryBuilder,
+	tb ResolverTelemetry,
 ) (*cloudMapResolver, error) { // Using the SDK's default configuration, loading additional config
 	// and credentials values from the environment variables, shared
 	// credentials, and shared configuration files
@@ -119,8 +117,8 @@ func newCloudMapResolver(
 	}, nil
 }
 
-func (r *cloudMapResolver) start(ctx context.Context) error {
-	if _, err := r.resolve(ctx); err != nil {
+func (r *cloudMapResolver) Start(ctx context.Context) error {
+	if _, err := r.Resolve(ctx); err != nil {
 		r.logger.Warn("failed initial resolve", zap.Error(err))
 	}
 
@@ -135,7 +133,7 @@ func (r *cloudMapResolver) start(ctx context.Context) error {
 	return nil
 }
 
-func (r *cloudMapResolver) shutdown(_ context.Context) error {
+func (r *cloudMapResolver) Shutdown(_ context.Context) error {
 	r.changeCallbackLock.Lock()
 	r.onChangeCallbacks = nil
 	r.changeCallbackLock.Unlock()
@@ -152,7 +150,7 @@ func (r *cloudMapResolver) periodicallyResolve() {
 		select {
 		case <-ticker.C:
 			ctx, cancel := context.WithTimeout(context.Background(), r.resTimeout)
-			if _, err := r.resolve(ctx); err != nil {
+			if _, err := r.Resolve(ctx); err != nil {
 				r.logger.Warn("failed to resolve", zap.Error(err))
 			} else {
 				r.logger.Debug("resolved successfully")
@@ -164,7 +162,7 @@ func (r *cloudMapResolver) periodicallyResolve() {
 	}
 }
 
-func (r *cloudMapResolver) resolve(ctx context.Context) ([]string, error) {
+func (r *cloudMapResolver) Resolve(ctx context.Context) ([]string, error) {
 	r.shutdownWg.Add(1)
 	defer r.shutdownWg.Done()
 
@@ -177,11 +175,11 @@ func (r *cloudMapResolver) resolve(ctx context.Context) ([]string, error) {
 		QueryParameters:    nil,
 	})
 	if err != nil {
-		r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(awsResolverFailureAttrSet))
+		r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(awsResolverFailureAttrSet))
 		return nil, err
 	}
 
-	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(awsResolverSuccessAttrSet))
+	r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(awsResolverSuccessAttrSet))
 
 	r.logger.Debug("resolver has discovered instances ",
 		zap.Int("Instance Count", len(discoverInstancesOutput.Instances)))
@@ -212,8 +210,8 @@ func (r *cloudMapResolver) resolve(ctx context.Context) ([]string, error) {
 	r.updateLock.Lock()
 	r.endpoints = backends
 	r.updateLock.Unlock()
-	r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(awsResolverAttrSet))
-	r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(awsResolverAttrSet))
+	r.telemetry.NumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(awsResolverAttrSet))
+	r.telemetry.NumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(awsResolverAttrSet))
 
 	// propagate the change
 	r.changeCallbackLock.RLock()
@@ -225,7 +223,7 @@ func (r *cloudMapResolver) resolve(ctx context.Context) ([]string, error) {
 	return r.endpoints, nil
 }
 
-func (r *cloudMapResolver) onChange(f func([]string)) {
+func (r *cloudMapResolver) OnChange(f func([]string)) {
 	r.changeCallbackLock.Lock()
 	defer r.changeCallbackLock.Unlock()
 	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter
+package tracerouter
 
 import (
 	"context"
@@ -27,7 +27,7 @@ var port uint16 = 1234
 
 func TestInitialCloudMapResolution(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 
 	res := &cloudMapResolver{
 		logger:        zap.NewNop(),
@@ -43,12 +43,12 @@ func TestInitialCloudMapResolution(t *testing.T) {
 
 	// test
 	var resolved []string
-	res.onChange(func(endpoints []string) {
+	res.OnChange(func(endpoints []string) {
 		resolved = endpoints
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// verify
@@ -60,7 +60,7 @@ func TestInitialCloudMapResolution(t *testing.T) {
 
 func TestInitialCloudMapResolutionWithPort(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 
 	res := &cloudMapResolver{
 		logger:        zap.NewNop(),
@@ -77,12 +77,12 @@ func TestInitialCloudMapResolutionWithPort(t *testing.T) {
 
 	// test
 	var resolved []string
-	res.onChange(func(endpoints []string) {
+	res.OnChange(func(endpoints []string) {
 		resolved = endpoints
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// verify
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 
 import (
 	"context"
@@ -15,11 +15,9 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
 
-var _ resolver = (*dnsResolver)(nil)
+var _ Resolver = (*dnsResolver)(nil)
 
 const (
 	defaultResInterval = 5 * time.Second
@@ -51,7 +49,7 @@ type dnsResolver struct {
 	updateLock         sync.Mutex
 	shutdownWg         sync.WaitGroup
 	changeCallbackLock sync.RWMutex
-	telemetry          *metadata.TelemetryBuilder
+	telemetry          ResolverTelemetry
 }
 
 type netResolver interface {
@@ -64,7 +62,7 @@ func newDNSResolver(
 	port string,
 	interval time.Duration,
 	timeout time.Duration,
-	tb *metadata.TelemetryBuilder,
+	tb ResolverTelemetry,
 ) (*dnsResolver, error) {
 	if len(hostname) == 0 {
 		return nil, errNoHostname
@@ -88,8 +86,8 @@ func newDNSResolver(
 	}, nil
 }
 
-func (r *dnsResolver) start(ctx context.Context) error {
-	if _, err := r.resolve(ctx); err != nil {
+func (r *dnsResolver) Start(ctx context.Context) error {
+	if _, err := r.Resolve(ctx); err != nil {
 		r.logger.Warn("failed to resolve", zap.Error(err))
 	}
 
@@ -101,7 +99,7 @@ func (r *dnsResolver) start(ctx context.Context) error {
 	return nil
 }
 
-func (r *dnsResolver) shutdown(_ context.Context) error {
+func (r *dnsResolver) Shutdown(_ context.Context) error {
 	r.changeCallbackLock.Lock()
 	r.onChangeCallbacks = nil
 	r.changeCallbackLock.Unlock()
@@ -118,7 +116,7 @@ func (r *dnsResolver) periodicallyResolve() {
 		select {
 		case <-ticker.C:
 			ctx, cancel := context.WithTimeout(context.Background(), r.resTimeout)
-			if _, err := r.resolve(ctx); err != nil {
+			if _, err := r.Resolve(ctx); err != nil {
 				r.logger.Warn("failed to resolve", zap.Error(err))
 			} else {
 				r.logger.Debug("resolved successfully")
@@ -130,17 +128,17 @@ func (r *dnsResolver) periodicallyResolve() {
 	}
 }
 
-func (r *dnsResolver) resolve(ctx context.Context) ([]string, error) {
+func (r *dnsResolver) Resolve(ctx context.Context) ([]string, error) {
 	r.shutdownWg.Add(1)
 	defer r.shutdownWg.Done()
 
 	addrs, err := r.resolver.LookupIPAddr(ctx, r.hostname)
 	if err != nil {
-		r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(dnsResolverFailureAttrSet))
+		r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(dnsResolverFailureAttrSet))
 		return nil, err
 	}
 
-	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(dnsResolverSuccessAttrSet))
+	r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(dnsResolverSuccessAttrSet))
 
 	backends := make([]string, len(addrs))
 	for i, ip := range addrs {
@@ -171,8 +169,8 @@ func (r *dnsResolver) resolve(ctx context.Context) ([]string, error) {
 	r.updateLock.Lock()
 	r.endpoints = backends
 	r.updateLock.Unlock()
-	r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(dnsResolverAttrSet))
-	r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(dnsResolverAttrSet))
+	r.telemetry.NumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(dnsResolverAttrSet))
+	r.telemetry.NumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(dnsResolverAttrSet))
 
 	// propagate the change
 	r.changeCallbackLock.RLock()
@@ -184,7 +182,7 @@ func (r *dnsResolver) resolve(ctx context.Context) ([]string, error) {
 	return r.endpoints, nil
 }
 
-func (r *dnsResolver) onChange(f func([]string)) {
+func (r *dnsResolver) OnChange(f func([]string)) {
 	r.changeCallbackLock.Lock()
 	defer r.changeCallbackLock.Unlock()
 	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
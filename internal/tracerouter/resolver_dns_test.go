@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter
+package tracerouter
 
 import (
 	"context"
@@ -19,7 +19,7 @@ import (
 
 func TestInitialDNSResolution(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 5*time.Second, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -35,12 +35,12 @@ func TestInitialDNSResolution(t *testing.T) {
 
 	// test
 	var resolved []string
-	res.onChange(func(endpoints []string) {
+	res.OnChange(func(endpoints []string) {
 		resolved = endpoints
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// verify
@@ -52,7 +52,7 @@ func TestInitialDNSResolution(t *testing.T) {
 
 func TestInitialDNSResolutionWithPort(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "55690", 5*time.Second, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -68,12 +68,12 @@ func TestInitialDNSResolutionWithPort(t *testing.T) {
 
 	// test
 	var resolved []string
-	res.onChange(func(endpoints []string) {
+	res.OnChange(func(endpoints []string) {
 		resolved = endpoints
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// verify
@@ -85,7 +85,7 @@ func TestInitialDNSResolutionWithPort(t *testing.T) {
 
 func TestErrNoHostname(t *testing.T) {
 	// test
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "", "", 5*time.Second, 1*time.Second, tb)
 
 	// verify
@@ -95,7 +95,7 @@ func TestErrNoHostname(t *testing.T) {
 
 func TestCantResolve(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 5*time.Second, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -107,16 +107,16 @@ func TestCantResolve(t *testing.T) {
 	}
 
 	// test
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 
 	// verify
 	assert.NoError(t, err)
-	assert.NoError(t, res.shutdown(context.Background()))
+	assert.NoError(t, res.Shutdown(context.Background()))
 }
 
 func TestOnChange(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 5*time.Second, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -131,17 +131,17 @@ func TestOnChange(t *testing.T) {
 
 	// test
 	counter := &atomic.Int64{}
-	res.onChange(func(_ []string) {
+	res.OnChange(func(_ []string) {
 		counter.Add(1)
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 	require.Equal(t, int64(1), counter.Load())
 
 	// now, we run it with the same IPs being resolved, which shouldn't trigger a onChange call
-	_, err = res.resolve(context.Background())
+	_, err = res.Resolve(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, int64(1), counter.Load())
 
@@ -150,7 +150,7 @@ func TestOnChange(t *testing.T) {
 		{IP: net.IPv4(127, 0, 0, 2)},
 		{IP: net.IPv4(127, 0, 0, 3)},
 	}
-	_, err = res.resolve(context.Background())
+	_, err = res.Resolve(context.Background())
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), counter.Load())
 }
@@ -184,7 +184,7 @@ func TestEqualStringSlice(t *testing.T) {
 
 func TestPeriodicallyResolve(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 10*time.Millisecond, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -222,15 +222,15 @@ func TestPeriodicallyResolve(t *testing.T) {
 	}
 
 	wg := sync.WaitGroup{}
-	res.onChange(func(_ []string) {
+	res.OnChange(func(_ []string) {
 		wg.Done()
 	})
 
 	// test
 	wg.Add(3)
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// wait for three resolutions: from the start, and two periodic resolutions
@@ -243,7 +243,7 @@ func TestPeriodicallyResolve(t *testing.T) {
 
 func TestPeriodicallyResolveFailure(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 10*time.Millisecond, 1*time.Second, tb)
 	require.NoError(t, err)
 
@@ -272,9 +272,9 @@ func TestPeriodicallyResolveFailure(t *testing.T) {
 
 	// test
 	wg.Add(2)
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// wait for two resolutions: from the start, and one periodic
@@ -287,26 +287,26 @@ func TestPeriodicallyResolveFailure(t *testing.T) {
 
 func TestShutdownClearsCallbacks(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	res, err := newDNSResolver(zap.NewNop(), "service-1", "", 5*time.Second, 1*time.Second, tb)
 	require.NoError(t, err)
 
 	res.resolver = &mockDNSResolver{}
-	res.onChange(func(_ []string) {})
-	require.NoError(t, res.start(context.Background()))
+	res.OnChange(func(_ []string) {})
+	require.NoError(t, res.Start(context.Background()))
 
 	// sanity check
 	require.Len(t, res.onChangeCallbacks, 1)
 
 	// test
-	err = res.shutdown(context.Background())
+	err = res.Shutdown(context.Background())
 
 	// verify
 	assert.NoError(t, err)
 	assert.Empty(t, res.onChangeCallbacks)
 
 	// check that we can add a new onChange before a new start
-	res.onChange(func(_ []string) {})
+	res.OnChange(func(_ []string) {})
 	assert.Len(t, res.onChangeCallbacks, 1)
 }
 
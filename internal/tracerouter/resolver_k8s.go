@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 
 import (
 	"context"
@@ -27,11 +27,9 @@ import (
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
 
-var _ resolver = (*k8sResolver)(nil)
+var _ Resolver = (*k8sResolver)(nil)
 
 var (
 	errNoSvc = errors.New("no service specified to resolve the backends")
@@ -67,7 +65,7 @@ type k8sResolver struct {
 	shutdownWg         sync.WaitGroup
 	changeCallbackLock sync.RWMutex
 
-	telemetry *metadata.TelemetryBuilder
+	telemetry ResolverTelemetry
 }
 
 func newK8sResolver(clt kubernetes.Interface,
@@ -75,7 +73,7 @@ func newK8sResolver(clt kubernetes.Interface,
 	service string,
 	ports []int32,
 	timeout time.Duration,
-	tb *metadata.TelemetryBuilder,
+	tb ResolverTelemetry,
 ) (*k8sResolver, error) {
 	if len(service) == 0 {
 		return nil, errNoSvc
@@ -132,12 +130,12 @@ func newK8sResolver(clt kubernetes.Interface,
 		lwTimeout:      timeout,
 		telemetry:      tb,
 	}
-	h.callback = r.resolve
+	h.callback = r.Resolve
 
 	return r, nil
 }
 
-func (r *k8sResolver) start(_ context.Context) error {
+func (r *k8sResolver) Start(_ context.Context) error {
 	var initErr error
 	r.once.Do(func() {
 		if r.epsListWatcher != nil {
@@ -164,7 +162,7 @@ func (r *k8sResolver) start(_ context.Context) error {
 	return nil
 }
 
-func (r *k8sResolver) shutdown(_ context.Context) error {
+func (r *k8sResolver) Shutdown(_ context.Context) error {
 	r.changeCallbackLock.Lock()
 	r.onChangeCallbacks = nil
 	r.changeCallbackLock.Unlock()
@@ -182,7 +180,7 @@ func newInClusterClient() (kubernetes.Interface, error) {
 	return kubernetes.NewForConfig(cfg)
 }
 
-func (r *k8sResolver) resolve(ctx context.Context) ([]string, error) {
+func (r *k8sResolver) Resolve(ctx context.Context) ([]string, error) {
 	r.shutdownWg.Add(1)
 	defer r.shutdownWg.Done()
 
@@ -198,7 +196,7 @@ func (r *k8sResolver) resolve(ctx context.Context) ([]string, error) {
 		}
 		return true
 	})
-	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(k8sResolverSuccessAttrSet))
+	r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(k8sResolverSuccessAttrSet))
 
 	// keep it always in the same order
 	sort.Strings(backends)
@@ -211,8 +209,8 @@ func (r *k8sResolver) resolve(ctx context.Context) ([]string, error) {
 	r.updateLock.Lock()
 	r.endpoints = backends
 	r.updateLock.Unlock()
-	r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(k8sResolverAttrSet))
-	r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(k8sResolverAttrSet))
+	r.telemetry.NumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(k8sResolverAttrSet))
+	r.telemetry.NumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(k8sResolverAttrSet))
 
 	// propagate the change
 	r.changeCallbackLock.RLock()
@@ -223,7 +221,7 @@ func (r *k8sResolver) resolve(ctx context.Context) ([]string, error) {
 	return r.Endpoints(), nil
 }
 
-func (r *k8sResolver) onChange(f func([]string)) {
+func (r *k8sResolver) OnChange(f func([]string)) {
 	r.changeCallbackLock.Lock()
 	defer r.changeCallbackLock.Unlock()
 	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 
 import (
 	"context"
@@ -11,8 +11,6 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
 
 var _ cache.ResourceEventHandler = (*handler)(nil)
@@ -21,7 +19,7 @@ type handler struct {
 	endpoints *sync.Map
 	callback  func(ctx context.Context) ([]string, error)
 	logger    *zap.Logger
-	telemetry *metadata.TelemetryBuilder
+	telemetry ResolverTelemetry
 }
 
 func (h handler) OnAdd(obj any, _ bool) {
@@ -32,7 +30,7 @@ func (h handler) OnAdd(obj any, _ bool) {
 		endpoints = convertToEndpoints(object)
 	default: // unsupported
 		h.logger.Warn("Got an unexpected Kubernetes data type during the inclusion of a new pods for the service", zap.Any("obj", obj))
-		h.telemetry.LoadbalancerNumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
+		h.telemetry.NumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
 		return
 	}
 	changed := false
@@ -52,7 +50,7 @@ func (h handler) OnUpdate(oldObj, newObj any) {
 		newEps, ok := newObj.(*corev1.Endpoints)
 		if !ok {
 			h.logger.Warn("Got an unexpected Kubernetes data type during the update of the pods for a service", zap.Any("obj", newObj))
-			h.telemetry.LoadbalancerNumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
+			h.telemetry.NumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
 			return
 		}
 
@@ -82,7 +80,7 @@ func (h handler) OnUpdate(oldObj, newObj any) {
 		}
 	default: // unsupported
 		h.logger.Warn("Got an unexpected Kubernetes data type during the update of the pods for a service", zap.Any("obj", oldObj))
-		h.telemetry.LoadbalancerNumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
+		h.telemetry.NumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
 		return
 	}
 }
@@ -99,7 +97,7 @@ func (h handler) OnDelete(obj any) {
 		}
 	default: // unsupported
 		h.logger.Warn("Got an unexpected Kubernetes data type during the removal of the pods for a service", zap.Any("obj", obj))
-		h.telemetry.LoadbalancerNumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
+		h.telemetry.NumResolutions.Add(context.Background(), 1, metric.WithAttributeSet(k8sResolverFailureAttrSet))
 		return
 	}
 	if len(endpoints) != 0 {
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter
+package tracerouter
 
 import (
 	"context"
@@ -56,11 +56,11 @@ func TestK8sResolve(t *testing.T) {
 		}
 
 		cl := fake.NewSimpleClientset(endpoint)
-		_, tb := getTelemetryAssets(t)
+		tb := NopResolverTelemetry()
 		res, err := newK8sResolver(cl, zap.NewNop(), service, ports, defaultListWatchTimeout, tb)
 		require.NoError(t, err)
 
-		require.NoError(t, res.start(context.Background()))
+		require.NoError(t, res.Start(context.Background()))
 		// verify endpoints should be the same as expectInit
 		assert.NoError(t, err)
 		assert.Equal(t, expectInit, res.Endpoints())
@@ -70,7 +70,7 @@ func TestK8sResolve(t *testing.T) {
 				clientset: cl,
 				resolver:  res,
 			}, func(*testing.T) {
-				require.NoError(t, res.shutdown(context.Background()))
+				require.NoError(t, res.Shutdown(context.Background()))
 			}
 	}
 	tests := []struct {
@@ -103,7 +103,7 @@ func TestK8sResolve(t *testing.T) {
 				return err
 			},
 			verifyFn: func(ctx *suiteContext, _ args) error {
-				if _, err := ctx.resolver.resolve(context.Background()); err != nil {
+				if _, err := ctx.resolver.Resolve(context.Background()); err != nil {
 					return err
 				}
 
@@ -140,7 +140,7 @@ func TestK8sResolve(t *testing.T) {
 				assert.Fail(t, "should not call onChange")
 			},
 			verifyFn: func(ctx *suiteContext, _ args) error {
-				if _, err := ctx.resolver.resolve(context.Background()); err != nil {
+				if _, err := ctx.resolver.Resolve(context.Background()); err != nil {
 					return err
 				}
 
@@ -175,7 +175,7 @@ func TestK8sResolve(t *testing.T) {
 				return err
 			},
 			verifyFn: func(ctx *suiteContext, _ args) error {
-				if _, err := ctx.resolver.resolve(context.Background()); err != nil {
+				if _, err := ctx.resolver.Resolve(context.Background()); err != nil {
 					return err
 				}
 
@@ -199,7 +199,7 @@ func TestK8sResolve(t *testing.T) {
 					Delete(context.TODO(), args.service, metav1.DeleteOptions{})
 			},
 			verifyFn: func(suiteCtx *suiteContext, _ args) error {
-				if _, err := suiteCtx.resolver.resolve(context.Background()); err != nil {
+				if _, err := suiteCtx.resolver.Resolve(context.Background()); err != nil {
 					return err
 				}
 				assert.Empty(t, suiteCtx.resolver.Endpoints(), "resolver failed, endpoints should empty")
@@ -214,7 +214,7 @@ func TestK8sResolve(t *testing.T) {
 			defer teardownSuite(t)
 
 			if tt.onChangeFn != nil {
-				suiteCtx.resolver.onChange(tt.onChangeFn)
+				suiteCtx.resolver.OnChange(tt.onChangeFn)
 			}
 
 			err := tt.simulateFn(suiteCtx, tt.args)
@@ -280,7 +280,7 @@ func Test_newK8sResolver(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, tb := getTelemetryAssets(t)
+			tb := NopResolverTelemetry()
 			got, err := newK8sResolver(fake.NewSimpleClientset(), tt.args.logger, tt.args.service, tt.args.ports, defaultListWatchTimeout, tb)
 			if tt.wantErr != nil {
 				require.ErrorIs(t, err, tt.wantErr)
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 
 import (
 	"context"
@@ -11,11 +11,9 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
 
-var _ resolver = (*staticResolver)(nil)
+var _ Resolver = (*staticResolver)(nil)
 
 var (
 	errNoEndpoints               = errors.New("no endpoints specified for the static resolver")
@@ -29,10 +27,10 @@ type staticResolver struct {
 	onChangeCallbacks []func([]string)
 	once              sync.Once // we trigger the onChange only once
 
-	telemetry *metadata.TelemetryBuilder
+	telemetry ResolverTelemetry
 }
 
-func newStaticResolver(endpoints []string, tb *metadata.TelemetryBuilder) (*staticResolver, error) {
+func newStaticResolver(endpoints []string, tb ResolverTelemetry) (*staticResolver, error) {
 	if len(endpoints) == 0 {
 		return nil, errNoEndpoints
 	}
@@ -50,12 +48,12 @@ func newStaticResolver(endpoints []string, tb *metadata.TelemetryBuilder) (*stat
 	}, nil
 }
 
-func (r *staticResolver) start(ctx context.Context) error {
-	_, err := r.resolve(ctx) // right now, this can't fail
+func (r *staticResolver) Start(ctx context.Context) error {
+	_, err := r.Resolve(ctx) // right now, this can't fail
 	return err
 }
 
-func (r *staticResolver) shutdown(context.Context) error {
+func (r *staticResolver) Shutdown(context.Context) error {
 	r.endpoints = nil
 
 	for _, callback := range r.onChangeCallbacks {
@@ -65,11 +63,11 @@ func (r *staticResolver) shutdown(context.Context) error {
 	return nil
 }
 
-func (r *staticResolver) resolve(ctx context.Context) ([]string, error) {
-	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(staticResolverSuccessAttrSet))
+func (r *staticResolver) Resolve(ctx context.Context) ([]string, error) {
+	r.telemetry.NumResolutions.Add(ctx, 1, metric.WithAttributeSet(staticResolverSuccessAttrSet))
 	r.once.Do(func() {
-		r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(r.endpoints)), metric.WithAttributeSet(staticResolverAttrSet))
-		r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(staticResolverAttrSet))
+		r.telemetry.NumBackends.Record(ctx, int64(len(r.endpoints)), metric.WithAttributeSet(staticResolverAttrSet))
+		r.telemetry.NumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(staticResolverAttrSet))
 		for _, callback := range r.onChangeCallbacks {
 			callback(r.endpoints)
 		}
@@ -77,6 +75,6 @@ func (r *staticResolver) resolve(ctx context.Context) ([]string, error) {
 	return r.endpoints, nil
 }
 
-func (r *staticResolver) onChange(f func([]string)) {
+func (r *staticResolver) OnChange(f func([]string)) {
 	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
 }
@@ -1,7 +1,7 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-package loadbalancingexporter
+package tracerouter
 
 import (
 	"context"
@@ -13,19 +13,19 @@ import (
 
 func TestInitialResolution(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	provided := []string{"endpoint-2", "endpoint-1"}
 	res, err := newStaticResolver(provided, tb)
 	require.NoError(t, err)
 
 	// test
 	var resolved []string
-	res.onChange(func(endpoints []string) {
+	res.OnChange(func(endpoints []string) {
 		resolved = endpoints
 	})
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
 
 	// verify
@@ -35,22 +35,22 @@ func TestInitialResolution(t *testing.T) {
 
 func TestResolvedOnlyOnce(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	expected := []string{"endpoint-1", "endpoint-2"}
 	res, err := newStaticResolver(expected, tb)
 	require.NoError(t, err)
 
 	counter := 0
-	res.onChange(func(_ []string) {
+	res.OnChange(func(_ []string) {
 		counter++
 	})
 
 	// test
-	require.NoError(t, res.start(context.Background()))
+	require.NoError(t, res.Start(context.Background()))
 	defer func() {
-		require.NoError(t, res.shutdown(context.Background()))
+		require.NoError(t, res.Shutdown(context.Background()))
 	}()
-	resolved, err := res.resolve(context.Background()) // second resolution, should be noop
+	resolved, err := res.Resolve(context.Background()) // second resolution, should be noop
 
 	// verify
 	assert.NoError(t, err)
@@ -60,7 +60,7 @@ func TestResolvedOnlyOnce(t *testing.T) {
 
 func TestFailOnMissingEndpoints(t *testing.T) {
 	// prepare
-	_, tb := getTelemetryAssets(t)
+	tb := NopResolverTelemetry()
 	var expected []string
 
 	// test
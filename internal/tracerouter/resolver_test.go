@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracerouter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockNetResolver struct {
+	addrs []net.IPAddr
+	err   error
+}
+
+func (m *mockNetResolver) LookupIPAddr(context.Context, string) ([]net.IPAddr, error) {
+	return m.addrs, m.err
+}
+
+func TestNewResolver(t *testing.T) {
+	tb := NopResolverTelemetry()
+	_, err := NewResolver(zap.NewNop(), ResolverSettings{}, tb)
+	require.ErrorIs(t, err, ErrNoResolver)
+
+	_, err = NewResolver(zap.NewNop(), ResolverSettings{
+		Static: &StaticResolver{Hostnames: []string{"a"}},
+		K8sSvc: &K8sSvcResolver{Service: "peers"},
+	}, tb)
+	require.ErrorIs(t, err, ErrMultipleResolversProvided)
+
+	_, err = NewResolver(zap.NewNop(), ResolverSettings{Static: &StaticResolver{}}, tb)
+	require.ErrorIs(t, err, errNoEndpoints)
+
+	_, err = NewResolver(zap.NewNop(), ResolverSettings{DNS: &DNSResolver{}}, tb)
+	require.ErrorIs(t, err, errNoHostname)
+
+	res, err := NewResolver(zap.NewNop(), ResolverSettings{DNS: &DNSResolver{Hostname: "peers"}}, tb)
+	require.NoError(t, err)
+	assert.Equal(t, defaultResInterval, res.(*dnsResolver).resInterval)
+	assert.Equal(t, defaultResTimeout, res.(*dnsResolver).resTimeout)
+}
+
+func TestStaticResolverKeepsHostnames(t *testing.T) {
+	hostnames := []string{"b:4317", "a:4317"}
+	res, err := newStaticResolver(hostnames, NopResolverTelemetry())
+	require.NoError(t, err)
+
+	var resolved []string
+	res.OnChange(func(endpoints []string) { resolved = endpoints })
+	require.NoError(t, res.Start(context.Background()))
+
+	assert.Equal(t, []string{"a:4317", "b:4317"}, resolved)
+	assert.Equal(t, []string{"b:4317", "a:4317"}, hostnames, "the configured hostnames must not be modified")
+	require.NoError(t, res.Shutdown(context.Background()))
+}
+
+func TestDNSResolverKeepsPeersOnFailure(t *testing.T) {
+	netResolver := &mockNetResolver{addrs: []net.IPAddr{
+		{IP: net.IPv4(192, 168, 0, 2)},
+		{IP: net.IPv4(192, 168, 0, 1)},
+		{IP: net.ParseIP("2001:db8::1")},
+	}}
+	res, err := newDNSResolver(zap.NewNop(), "peers", "4317", time.Hour, time.Second, NopResolverTelemetry())
+	require.NoError(t, err)
+	res.resolver = netResolver
+
+	var changes [][]string
+	res.OnChange(func(endpoints []string) { changes = append(changes, endpoints) })
+	require.NoError(t, res.Start(context.Background()))
+	defer func() {
+		require.NoError(t, res.Shutdown(context.Background()))
+	}()
+	require.Equal(t, [][]string{{"192.168.0.1:4317", "192.168.0.2:4317", "[2001:db8::1]:4317"}}, changes)
+
+	// the router is only notified of changes
+	_, err = res.Resolve(context.Background())
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	// the peers are kept when they cannot be resolved
+	netResolver.err = errors.New("no such host")
+	_, err = res.Resolve(context.Background())
+	require.Error(t, err)
+	require.Len(t, changes, 1)
+
+	netResolver.err = nil
+	netResolver.addrs = netResolver.addrs[:1]
+	_, err = res.Resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.0.2:4317"}, changes[1])
+}
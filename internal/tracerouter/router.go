@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracerouter sends traces to the peer responsible for their trace ID. The trace IDs are
+// distributed between the peers with a consistent hash ring, so that the instances of a collector
+// tier finding the same peers send the spans of a trace to the same peer.
+package tracerouter // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+const defaultPort = "4317"
+
+// Router sends traces to the peers found by its resolver.
+type Router struct {
+	logger   *zap.Logger
+	settings exporter.Settings
+	cfg      OTLPConfig
+	factory  exporter.Factory
+	res      Resolver
+	host     component.Host
+
+	updateLock sync.RWMutex
+	stopped    bool
+	ring       *HashRing
+	exporters  map[string]exporter.Traces
+}
+
+// NewRouter creates a Router for the peers found by the resolver of the config.
+// The exporters sending traces to the peers are created with the settings of the component.
+func NewRouter(set exporter.Settings, cfg Config) (*Router, error) {
+	res, err := NewResolver(set.Logger, cfg.Resolver, NopResolverTelemetry())
+	if err != nil {
+		return nil, err
+	}
+	return NewRouterWithResolver(set, cfg.Protocol.OTLP, res), nil
+}
+
+// NewRouterWithResolver creates a Router for the peers found by res.
+func NewRouterWithResolver(set exporter.Settings, cfg OTLPConfig, res Resolver) *Router {
+	return &Router{
+		logger:    set.Logger,
+		settings:  set,
+		cfg:       cfg,
+		factory:   otlpexporter.NewFactory(),
+		res:       res,
+		exporters: map[string]exporter.Traces{},
+	}
+}
+
+// Start starts the resolver, the exporters are created as the peers are resolved.
+func (r *Router) Start(ctx context.Context, host component.Host) error {
+	r.host = host
+	r.res.OnChange(r.onPeersChange)
+	return r.res.Start(ctx)
+}
+
+// Shutdown stops the resolver and the exporters.
+func (r *Router) Shutdown(ctx context.Context) error {
+	// the peers reported by the resolver while it stops are ignored
+	r.updateLock.Lock()
+	r.stopped = true
+	r.updateLock.Unlock()
+
+	err := r.res.Shutdown(ctx)
+
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+	for endpoint, exp := range r.exporters {
+		err = errors.Join(err, exp.Shutdown(ctx))
+		delete(r.exporters, endpoint)
+	}
+	r.ring = nil
+	return err
+}
+
+// Endpoint returns the endpoint, including the port, of the peer responsible
+// for the trace ID, or an empty string if no peers were resolved yet.
+func (r *Router) Endpoint(traceID pcommon.TraceID) string {
+	r.updateLock.RLock()
+	defer r.updateLock.RUnlock()
+
+	endpoint := r.ring.EndpointFor(traceID[:])
+	if endpoint == "" {
+		return ""
+	}
+	return endpointWithPort(endpoint)
+}
+
+// ConsumeTraces sends the traces to the peer at endpoint, as returned by Endpoint.
+func (r *Router) ConsumeTraces(ctx context.Context, endpoint string, td ptrace.Traces) error {
+	r.updateLock.RLock()
+	exp, ok := r.exporters[endpoint]
+	r.updateLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("no exporter for the peer %q", endpoint)
+	}
+	return exp.ConsumeTraces(ctx, td)
+}
+
+func (r *Router) onPeersChange(endpoints []string) {
+	ring := NewHashRing(endpoints)
+
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+	if r.stopped || ring.Equal(r.ring) {
+		return
+	}
+	r.ring = ring
+
+	ctx := context.Background()
+	resolved := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpoint = endpointWithPort(endpoint)
+		resolved[endpoint] = true
+		if _, ok := r.exporters[endpoint]; ok {
+			continue
+		}
+		exp, err := r.newExporter(ctx, endpoint)
+		if err != nil {
+			r.logger.Error("failed to create the exporter for peer", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		r.exporters[endpoint] = exp
+	}
+
+	for endpoint, exp := range r.exporters {
+		if !resolved[endpoint] {
+			// Shutdown the exporter asynchronously to avoid blocking the resolver
+			go func() {
+				_ = exp.Shutdown(ctx)
+			}()
+			delete(r.exporters, endpoint)
+		}
+	}
+}
+
+func (r *Router) newExporter(ctx context.Context, endpoint string) (exporter.Traces, error) {
+	set := r.settings
+	// segregate the telemetry of the exporter of each peer
+	name := endpoint
+	if set.ID.Name() != "" {
+		name = set.ID.Name() + "_" + endpoint
+	}
+	set.ID = component.NewIDWithName(set.ID.Type(), name)
+	set.Logger = set.Logger.With(zap.String("endpoint", endpoint))
+
+	exp, err := r.factory.CreateTraces(ctx, set, r.cfg.exporterConfig(endpoint))
+	if err != nil {
+		return nil, err
+	}
+	if err = exp.Start(ctx, r.host); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+func endpointWithPort(endpoint string) string {
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return net.JoinHostPort(endpoint, defaultPort)
+	}
+	return endpoint
+}
@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracerouter
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// mockExporters records the traces sent to each peer.
+type mockExporters struct {
+	mu    sync.Mutex
+	sinks map[string]*consumertest.TracesSink
+}
+
+func (m *mockExporters) factory() exporter.Factory {
+	return exporter.NewFactory(component.MustNewType("otlp"), func() component.Config {
+		return &otlpexporter.Config{}
+	}, exporter.WithTraces(func(_ context.Context, _ exporter.Settings, cfg component.Config) (exporter.Traces, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		sink := new(consumertest.TracesSink)
+		m.sinks[cfg.(*otlpexporter.Config).Endpoint] = sink
+		return mockExporter{Traces: sink}, nil
+	}, component.StabilityLevelBeta))
+}
+
+type mockExporter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	consumer.Traces
+}
+
+func newTestRouter(t *testing.T, hostnames ...string) (*Router, *mockExporters) {
+	cfg := NewDefaultConfig()
+	cfg.Resolver.Static = &StaticResolver{Hostnames: hostnames}
+	r, err := NewRouter(exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+
+	exporters := &mockExporters{sinks: map[string]*consumertest.TracesSink{}}
+	r.factory = exporters.factory()
+	return r, exporters
+}
+
+func tracesWithID(id pcommon.TraceID) ptrace.Traces {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetTraceID(id)
+	return td
+}
+
+func TestRouter(t *testing.T) {
+	r, exporters := newTestRouter(t, "peer-1", "peer-2:4318")
+
+	id := pcommon.TraceID([16]byte{1, 2, 3})
+	assert.Empty(t, r.Endpoint(id), "no peers before the router starts")
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Len(t, exporters.sinks, 2)
+	assert.Contains(t, exporters.sinks, "peer-1:4317", "the default port is added")
+	assert.Contains(t, exporters.sinks, "peer-2:4318")
+
+	// the peers find the same owner for a trace
+	endpoint := r.Endpoint(id)
+	assert.Equal(t, endpointWithPort(NewHashRing([]string{"peer-2:4318", "peer-1"}).EndpointFor(id[:])), endpoint)
+
+	require.NoError(t, r.ConsumeTraces(context.Background(), endpoint, tracesWithID(id)))
+	assert.Equal(t, 1, exporters.sinks[endpoint].SpanCount())
+	require.EqualError(t, r.ConsumeTraces(context.Background(), "unknown:4317", tracesWithID(id)), `no exporter for the peer "unknown:4317"`)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Empty(t, r.Endpoint(id))
+}
+
+func TestRouterPeersChange(t *testing.T) {
+	r, exporters := newTestRouter(t, "peer-1")
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+	peer1 := exporters.sinks["peer-1:4317"]
+
+	r.onPeersChange([]string{"peer-1", "peer-2"})
+	assert.Len(t, r.exporters, 2)
+	assert.Same(t, peer1, exporters.sinks["peer-1:4317"], "the exporters of the known peers are kept")
+
+	r.onPeersChange([]string{"peer-2"})
+	assert.Len(t, r.exporters, 1)
+	assert.Contains(t, r.exporters, "peer-2:4317")
+	id := pcommon.TraceID([16]byte{1})
+	assert.Equal(t, "peer-2:4317", r.Endpoint(id))
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := NewDefaultConfig()
+	require.NoError(t, cfg.Validate())
+
+	cfg.Resolver.Static = &StaticResolver{}
+	require.ErrorIs(t, cfg.Validate(), errNoEndpoints)
+	cfg.Resolver.Static.Hostnames = []string{"peer"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Resolver.DNS = &DNSResolver{Hostname: "peers"}
+	require.ErrorIs(t, cfg.Validate(), ErrMultipleResolversProvided)
+	cfg.Resolver.Static = nil
+	require.NoError(t, cfg.Validate())
+	cfg.Resolver.DNS.Hostname = ""
+	require.ErrorIs(t, cfg.Validate(), errNoHostname)
+
+	cfg.Resolver.DNS = nil
+	cfg.Resolver.K8sSvc = &K8sSvcResolver{}
+	require.ErrorIs(t, cfg.Validate(), errNoSvc)
+	cfg.Resolver.K8sSvc.Service = "peers.tier"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Resolver.K8sSvc = nil
+	cfg.Resolver.AWSCloudMap = &AWSCloudMapResolver{ServiceName: "peers"}
+	require.ErrorIs(t, cfg.Validate(), errNoNamespace)
+	cfg.Resolver.AWSCloudMap = &AWSCloudMapResolver{NamespaceName: "tier"}
+	require.ErrorIs(t, cfg.Validate(), errNoServiceName)
+
+	require.NoError(t, cfg.Protocol.OTLP.Validate())
+	cfg.Protocol.OTLP.Endpoint = "peer:4317"
+	require.ErrorIs(t, cfg.Protocol.OTLP.Validate(), errEndpointSet)
+}
+
+func TestExporterConfig(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Protocol.OTLP.TLSSetting.Insecure = true
+
+	otlpCfg := cfg.Protocol.OTLP.exporterConfig("peer:4317")
+	require.NoError(t, otlpCfg.Validate())
+	assert.Equal(t, "peer:4317", otlpCfg.Endpoint)
+	assert.True(t, otlpCfg.TLSSetting.Insecure)
+	assert.Empty(t, cfg.Protocol.OTLP.Endpoint)
+}
@@ -2343,6 +2343,70 @@ func Test_parseCondition(t *testing.T) {
 	}
 }
 
+func Test_ParseValueExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		tCtx        any
+		expected    any
+		expectedErr string
+	}{
+		{
+			name:       "string literal",
+			expression: `"foo"`,
+			expected:   "foo",
+		},
+		{
+			name:       "int literal",
+			expression: `1`,
+			expected:   int64(1),
+		},
+		{
+			name:       "math expression",
+			expression: `1 + 2 * 3`,
+			expected:   int64(7),
+		},
+		{
+			name:       "path",
+			expression: `name`,
+			tCtx:       "bar",
+			expected:   "bar",
+		},
+		{
+			name:        "invalid syntax",
+			expression:  `1 +`,
+			expectedErr: "expression has invalid syntax",
+		},
+		{
+			name:        "editor",
+			expression:  `set(name, "foo")`,
+			expectedErr: "converter names must start with an uppercase letter",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewParser(
+				CreateFactoryMap[any](),
+				testParsePath[any],
+				componenttest.NewNopTelemetrySettings(),
+				WithEnumParser[any](testParseEnum),
+			)
+			require.NoError(t, err)
+
+			expr, err := p.ParseValueExpression(tt.expression)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			val, err := expr.Eval(context.Background(), tt.tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, val)
+		})
+	}
+}
+
 func Test_Statement_Execute(t *testing.T) {
 	tests := []struct {
 		name              string
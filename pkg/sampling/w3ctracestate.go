@@ -175,3 +175,18 @@ func (w3c *W3CTraceState) Serialize(w io.StringWriter) error {
 	}
 	return ser.err
 }
+
+// TraceStateToAdjustedCount returns the adjusted count of an item sampled with the threshold
+// of the OpenTelemetry value of the W3C tracestate, i.e. the number of items it represents.
+// Items whose tracestate is empty, invalid or has no threshold represent themselves only, so
+// that 1 is returned for them.
+func TraceStateToAdjustedCount(tracestate string) float64 {
+	if tracestate == "" {
+		return 1
+	}
+	w3c, err := NewW3CTraceState(tracestate)
+	if err != nil || len(w3c.OTelValue().TValue()) == 0 {
+		return 1
+	}
+	return w3c.OTelValue().AdjustedCount()
+}
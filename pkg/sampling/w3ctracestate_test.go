@@ -158,3 +158,28 @@ func TestParseW3CTraceState(t *testing.T) {
 		})
 	}
 }
+
+func TestTraceStateToAdjustedCount(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected float64
+	}{
+		{"", 1},
+		{"ot=th:0", 1},
+		{"ot=th:8", 2},
+		{"ot=th:c", 4},
+		{"ot=th:c;rv:abcdabcdabcdff,other=value", 4},
+		{"ot=rv:abcdabcdabcdff", 1},
+		{"ot=p:8", 1},
+		{"other=value", 1},
+		{"invalid=p:8;th:8", 1},
+		{"ot=404:0", 1},
+		{"ot=th:;", 1},
+		{"ot=th:xyz", 1},
+		{"-1=2", 1},
+	} {
+		t.Run(testName(test.in), func(t *testing.T) {
+			require.InDelta(t, test.expected, TraceStateToAdjustedCount(test.in), 1e-9)
+		})
+	}
+}
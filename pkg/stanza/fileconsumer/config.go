@@ -86,7 +86,7 @@ type Config struct {
 	DeleteAfterRead         bool            `mapstructure:"delete_after_read,omitempty"`
 	IncludeFileRecordNumber bool            `mapstructure:"include_file_record_number,omitempty"`
 	Compression             string          `mapstructure:"compression,omitempty"`
-	PollsToArchive          int             `mapstructure:"-"` // TODO: activate this config once archiving is set up
+	PollsToArchive          int             `mapstructure:"polls_to_archive,omitempty"`
 	AcquireFSLock           bool            `mapstructure:"acquire_fs_lock,omitempty"`
 }
 
@@ -185,6 +185,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		pollInterval:     c.PollInterval,
 		maxBatchFiles:    c.MaxConcurrentFiles / 2,
 		maxBatches:       c.MaxBatches,
+		pollsToArchive:   c.PollsToArchive,
 		telemetryBuilder: telemetryBuilder,
 		noTracking:       o.noTracking,
 	}, nil
@@ -211,6 +212,15 @@ func (c Config) validate() error {
 		return errors.New("'max_batches' must not be negative")
 	}
 
+	if c.PollsToArchive < 0 {
+		return errors.New("'polls_to_archive' must not be negative")
+	}
+
+	if !reader.IsValidCompression(c.Compression) {
+		return fmt.Errorf("invalid 'compression' %q, must be one of %q, %q, %q, %q or %q", c.Compression,
+			reader.CompressionGzip, reader.CompressionZstd, reader.CompressionXz, reader.CompressionBzip2, reader.CompressionAuto)
+	}
+
 	enc, err := decode.LookupEncoding(c.Encoding)
 	if err != nil {
 		return err
@@ -420,6 +420,14 @@ func TestUnmarshal(t *testing.T) {
 					return newMockOperatorConfig(cfg)
 				}(),
 			},
+			{
+				Name: "polls_to_archive_10",
+				Expect: func() *mockOperatorConfig {
+					cfg := NewConfig()
+					cfg.PollsToArchive = 10
+					return newMockOperatorConfig(cfg)
+				}(),
+			},
 			{
 				Name: "header_config",
 				Expect: func() *mockOperatorConfig {
@@ -582,6 +590,42 @@ func TestBuild(t *testing.T) {
 				require.Equal(t, 6, m.maxBatches)
 			},
 		},
+		{
+			"InvalidPollsToArchive",
+			func(cfg *Config) {
+				cfg.PollsToArchive = -1
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"ValidPollsToArchive",
+			func(cfg *Config) {
+				cfg.PollsToArchive = 100
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, 100, m.pollsToArchive)
+			},
+		},
+		{
+			"InvalidCompression",
+			func(cfg *Config) {
+				cfg.Compression = "lz4"
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"AutoCompression",
+			func(cfg *Config) {
+				cfg.Compression = "auto"
+			},
+			require.NoError,
+			func(t *testing.T, m *Manager) {
+				require.Equal(t, "auto", m.readerFactory.Compression)
+			},
+		},
 		{
 			"HeaderConfigNoFlag",
 			func(cfg *Config) {
@@ -204,25 +204,46 @@ func (m *Manager) makeFingerprint(path string) (*fingerprint.Fingerprint, *os.Fi
 // discarding any that have a duplicate fingerprint to other files that have already
 // been read this polling interval
 func (m *Manager) makeReaders(ctx context.Context, paths []string) {
+	var unknownFiles []*os.File
+	var unknownFingerprints []*fingerprint.Fingerprint
 	for _, path := range paths {
 		fp, file := m.makeFingerprint(path)
 		if fp == nil {
 			continue
 		}
 
-		// Exclude duplicate paths with the same content. This can happen when files are
-		// being rotated with copy/truncate strategy. (After copy, prior to truncate.)
-		if r := m.tracker.GetCurrentFile(fp); r != nil {
-			m.set.Logger.Debug("Skipping duplicate file", zap.String("path", file.Name()))
-			// re-add the reader as Match() removes duplicates
-			m.tracker.Add(r)
-			if err := file.Close(); err != nil {
-				m.set.Logger.Debug("problem closing file", zap.Error(err))
-			}
+		if m.isDuplicate(fp, file) {
 			continue
 		}
 
-		r, err := m.newReader(ctx, file, fp)
+		r, err := m.newReaderFromKnownFile(ctx, file, fp)
+		if err != nil {
+			m.set.Logger.Error("Failed to create reader", zap.Error(err))
+			continue
+		}
+		if r == nil {
+			unknownFiles = append(unknownFiles, file)
+			unknownFingerprints = append(unknownFingerprints, fp)
+			continue
+		}
+
+		m.tracker.Add(r)
+	}
+
+	if len(unknownFiles) == 0 {
+		return
+	}
+
+	// The files may have been seen before the polls tracked in memory, look for them in the archive
+	archived := m.tracker.FindFiles(unknownFingerprints)
+	for i, file := range unknownFiles {
+		fp := unknownFingerprints[i]
+		// Files with the same content may have been found in the same batch
+		if m.isDuplicate(fp, file) {
+			continue
+		}
+
+		r, err := m.newReader(ctx, file, fp, archived[i])
 		if err != nil {
 			m.set.Logger.Error("Failed to create reader", zap.Error(err))
 			continue
@@ -232,7 +253,27 @@ func (m *Manager) makeReaders(ctx context.Context, paths []string) {
 	}
 }
 
-func (m *Manager) newReader(ctx context.Context, file *os.File, fp *fingerprint.Fingerprint) (*reader.Reader, error) {
+// isDuplicate checks whether a file with the same fingerprint was already found in this poll,
+// in which case the file is closed.
+func (m *Manager) isDuplicate(fp *fingerprint.Fingerprint, file *os.File) bool {
+	// Exclude duplicate paths with the same content. This can happen when files are
+	// being rotated with copy/truncate strategy. (After copy, prior to truncate.)
+	r := m.tracker.GetCurrentFile(fp)
+	if r == nil {
+		return false
+	}
+	m.set.Logger.Debug("Skipping duplicate file", zap.String("path", file.Name()))
+	// re-add the reader as Match() removes duplicates
+	m.tracker.Add(r)
+	if err := file.Close(); err != nil {
+		m.set.Logger.Debug("problem closing file", zap.Error(err))
+	}
+	return true
+}
+
+// newReaderFromKnownFile creates a reader from the files tracked in memory matching the
+// fingerprint. It returns nil if none matches.
+func (m *Manager) newReaderFromKnownFile(ctx context.Context, file *os.File, fp *fingerprint.Fingerprint) (*reader.Reader, error) {
 	// Check previous poll cycle for match
 	if oldReader := m.tracker.GetOpenFile(fp); oldReader != nil {
 		if oldReader.GetFileName() != file.Name() {
@@ -261,6 +302,22 @@ func (m *Manager) newReader(ctx context.Context, file *os.File, fp *fingerprint.
 		return r, nil
 	}
 
+	return nil, nil
+}
+
+// newReader creates a reader resuming from the archived metadata of the file, if any,
+// or a new reader otherwise.
+func (m *Manager) newReader(ctx context.Context, file *os.File, fp *fingerprint.Fingerprint, archived *reader.Metadata) (*reader.Reader, error) {
+	if archived != nil {
+		m.set.Logger.Info("Resuming file from the archive", zap.String("path", file.Name()), zap.Int64("offset", archived.Offset))
+		r, err := m.readerFactory.NewReaderFromMetadata(file, archived)
+		if err != nil {
+			return nil, err
+		}
+		m.telemetryBuilder.FileconsumerOpenFiles.Add(ctx, 1)
+		return r, nil
+	}
+
 	// If we don't match any previously known files, create a new reader from scratch
 	m.set.Logger.Info("Started watching file", zap.String("path", file.Name()))
 	r, err := m.readerFactory.NewReader(file, fp)
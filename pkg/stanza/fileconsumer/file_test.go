@@ -4,9 +4,11 @@
 package fileconsumer
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -15,9 +17,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
 	"go.opentelemetry.io/collector/featuregate"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/attrs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/emittest"
@@ -1582,6 +1588,152 @@ func TestReadGzipCompressedLogsFromEnd(t *testing.T) {
 	sink.ExpectToken(t, []byte("testlog4"))
 }
 
+// bzip2Streams holds precompressed bzip2 streams since the standard library only implements decompression
+var bzip2Streams = map[string][]byte{
+	"testlog1\ntestlog2\n": {
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x37, 0x04, 0xa3, 0xbc, 0x00, 0x00, 0x07, 0x49,
+		0x80, 0x00, 0x10, 0x30, 0x00, 0x02, 0x84, 0x8c, 0x00, 0x20, 0x00, 0x21, 0x28, 0x06, 0x42, 0x0c, 0x98, 0x8a,
+		0xac, 0x18, 0x89, 0x10, 0x84, 0x33, 0xc5, 0xdc, 0x91, 0x4e, 0x14, 0x24, 0x0d, 0xc1, 0x28, 0xef, 0x00,
+	},
+	"testlog3\n": {
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xbf, 0x42, 0xdd, 0xb2, 0x00, 0x00, 0x03, 0xc9,
+		0x80, 0x00, 0x10, 0x08, 0x00, 0x02, 0x84, 0x8c, 0x00, 0x20, 0x00, 0x31, 0x0c, 0x08, 0x21, 0xa1, 0xb4, 0x8b,
+		0x60, 0x80, 0x9e, 0x2e, 0xe4, 0x8a, 0x70, 0xa1, 0x21, 0x7e, 0x85, 0xbb, 0x64,
+	},
+	"testlog4\n": {
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x88, 0x8d, 0xa3, 0xc8, 0x00, 0x00, 0x03, 0xc9,
+		0x80, 0x00, 0x10, 0x04, 0x00, 0x02, 0x84, 0x8c, 0x00, 0x20, 0x00, 0x31, 0x0c, 0x08, 0x21, 0xa1, 0xb4, 0x8b,
+		0x60, 0x80, 0x9e, 0x2e, 0xe4, 0x8a, 0x70, 0xa1, 0x21, 0x11, 0x1b, 0x47, 0x90,
+	},
+}
+
+// compressStream returns the content compressed as a single stream of the format
+func compressStream(t *testing.T, compression string, content string) []byte {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch compression {
+	case reader.CompressionGzip:
+		writer = gzip.NewWriter(&buf)
+	case reader.CompressionZstd:
+		w, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		writer = w
+	case reader.CompressionXz:
+		w, err := xz.NewWriter(&buf)
+		require.NoError(t, err)
+		writer = w
+	case reader.CompressionBzip2:
+		stream, ok := bzip2Streams[content]
+		require.True(t, ok, "no bzip2 stream for %q", content)
+		return stream
+	}
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+// TestReadCompressedLogs tests that the streams appended to a compressed file are read
+// once each, for all the formats whether configured or detected
+func TestReadCompressedLogs(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{reader.CompressionGzip, reader.CompressionZstd, reader.CompressionXz, reader.CompressionBzip2} {
+		for _, compression := range []string{format, reader.CompressionAuto} {
+			t.Run(format+"/"+compression, func(t *testing.T) {
+				t.Parallel()
+
+				tempDir := t.TempDir()
+				cfg := NewConfig().includeDir(tempDir)
+				cfg.Compression = compression
+				cfg.StartAt = "beginning"
+				operator, sink := testManager(t, cfg)
+
+				temp := filetest.OpenTemp(t, tempDir)
+				appendToLog := func(t *testing.T, content string) {
+					_, err := temp.Write(compressStream(t, format, content))
+					require.NoError(t, err)
+				}
+
+				appendToLog(t, "testlog1\ntestlog2\n")
+				operator.poll(context.TODO())
+				sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+
+				// the offset is checkpointed after the last stream, so only the new stream is read
+				appendToLog(t, "testlog3\n")
+				operator.poll(context.TODO())
+				sink.ExpectToken(t, []byte("testlog3"))
+
+				appendToLog(t, "testlog4\n")
+				operator.poll(context.TODO())
+				sink.ExpectToken(t, []byte("testlog4"))
+				sink.ExpectNoCalls(t)
+			})
+		}
+	}
+}
+
+// TestPollUnchangedCompressedFile tests that polling a compressed file which was fully read
+// reads nothing and logs no error, and that the file is deleted if delete_after_read is set
+func TestPollUnchangedCompressedFile(t *testing.T) {
+	require.NoError(t, featuregate.GlobalRegistry().Set(allowFileDeletion.ID(), true))
+
+	for _, format := range []string{reader.CompressionGzip, reader.CompressionZstd, reader.CompressionXz, reader.CompressionBzip2} {
+		for _, deleteAfterRead := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/delete_after_read=%t", format, deleteAfterRead), func(t *testing.T) {
+				tempDir := t.TempDir()
+				cfg := NewConfig().includeDir(tempDir)
+				cfg.Compression = format
+				cfg.StartAt = "beginning"
+				cfg.DeleteAfterRead = deleteAfterRead
+				operator, sink := testManager(t, cfg)
+				core, observedLogs := observer.New(zap.WarnLevel)
+				operator.set.Logger = zap.New(core)
+				operator.readerFactory.TelemetrySettings.Logger = operator.set.Logger
+
+				temp := filetest.OpenTemp(t, tempDir)
+				_, err := temp.Write(compressStream(t, format, "testlog1\ntestlog2\n"))
+				require.NoError(t, err)
+				require.NoError(t, temp.Close())
+
+				operator.poll(context.TODO())
+				sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+
+				// the file is unchanged, so the next polls find nothing new
+				for i := 0; i < 3; i++ {
+					operator.poll(context.TODO())
+				}
+				sink.ExpectNoCalls(t)
+				assert.Empty(t, observedLogs.All())
+
+				_, err = os.Stat(temp.Name())
+				assert.Equal(t, deleteAfterRead, os.IsNotExist(err))
+			})
+		}
+	}
+}
+
+// TestReadAutoCompressionPlainFile tests that files without magic bytes are read as
+// uncompressed when the compression is detected
+func TestReadAutoCompressionPlainFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.Compression = reader.CompressionAuto
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\n")
+	operator.poll(context.TODO())
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	filetest.WriteString(t, temp, "testlog2\n")
+	operator.poll(context.TODO())
+	sink.ExpectToken(t, []byte("testlog2"))
+}
+
 func TestIncludeFileRecordNumber(t *testing.T) {
 	t.Parallel()
 
@@ -1656,3 +1808,62 @@ func TestIncludeFileRecordNumberWithHeaderConfiguredButMissing(t *testing.T) {
 		attrs.LogFileRecordNumber: int64(1),
 	})
 }
+
+// TestArchive tests that a file reappearing after the polls tracked in memory
+// is resumed from its archived offset when polls_to_archive is set
+func TestArchive(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Moving files which are still open is not supported on windows")
+	}
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		pollsToArchive int
+		expected       [][]byte
+	}{
+		{
+			name:           "archive disabled",
+			pollsToArchive: 0,
+			expected:       [][]byte{[]byte("testlog1"), []byte("testlog2")},
+		},
+		{
+			name:           "archive enabled",
+			pollsToArchive: 10,
+			expected:       [][]byte{[]byte("testlog2")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			cfg := NewConfig().includeDir(tempDir)
+			cfg.StartAt = "beginning"
+			cfg.PollsToArchive = tc.pollsToArchive
+			operator, sink := testManager(t, cfg)
+			operator.persister = testutil.NewUnscopedMockPersister()
+
+			temp := filetest.OpenTemp(t, tempDir)
+			filetest.WriteString(t, temp, "testlog1\n")
+			operator.poll(context.Background())
+			sink.ExpectToken(t, []byte("testlog1"))
+
+			// Move the file out of the included directory for longer than the polls tracked in memory
+			moved := filepath.Join(t.TempDir(), "moved.log")
+			require.NoError(t, os.Rename(temp.Name(), moved))
+			for i := 0; i < 5; i++ {
+				operator.poll(context.Background())
+			}
+			sink.ExpectNoCalls(t)
+
+			// Bring the file back with a new entry
+			filetest.WriteString(t, temp, "testlog2\n")
+			require.NoError(t, os.Rename(moved, temp.Name()))
+			operator.poll(context.Background())
+			sink.ExpectTokens(t, tc.expected...)
+			sink.ExpectNoCalls(t)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Supported compression formats of the files
+const (
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionXz    = "xz"
+	CompressionBzip2 = "bzip2"
+	// CompressionAuto detects the compression format of each file from its magic bytes
+	CompressionAuto = "auto"
+)
+
+var magicBytes = []struct {
+	compression string
+	magic       []byte
+}{
+	{compression: CompressionGzip, magic: []byte{0x1f, 0x8b}},
+	{compression: CompressionZstd, magic: []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{compression: CompressionXz, magic: []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{compression: CompressionBzip2, magic: []byte{'B', 'Z', 'h'}},
+}
+
+// maxMagicLen is the number of bytes needed to detect any of the compression formats
+const maxMagicLen = 6
+
+// IsValidCompression returns whether the compression format is supported, the empty string
+// meaning that the files are not compressed.
+func IsValidCompression(compression string) bool {
+	switch compression {
+	case "", CompressionGzip, CompressionZstd, CompressionXz, CompressionBzip2, CompressionAuto:
+		return true
+	}
+	return false
+}
+
+// detectCompression returns the compression format of a file starting with the passed in
+// bytes, or the empty string if the file is not compressed. It returns false if there are
+// not enough bytes to tell whether the file is compressed.
+func detectCompression(start []byte) (string, bool) {
+	for _, m := range magicBytes {
+		if len(start) >= len(m.magic) {
+			if bytes.HasPrefix(start, m.magic) {
+				return m.compression, true
+			}
+		} else if bytes.HasPrefix(m.magic, start) {
+			return "", false
+		}
+	}
+	return "", true
+}
+
+// newDecompressor returns a reader decompressing the concatenated streams of r.
+func newDecompressor(compression string, r io.Reader) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	case CompressionXz:
+		xzReader, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xzReader), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	}
+	return nil, fmt.Errorf("unsupported compression %q", compression)
+}
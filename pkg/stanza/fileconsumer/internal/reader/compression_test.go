@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCompression(t *testing.T) {
+	testCases := []struct {
+		name        string
+		start       []byte
+		compression string
+		ok          bool
+	}{
+		{name: "empty", start: []byte{}, ok: false},
+		{name: "gzip", start: []byte{0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00}, compression: CompressionGzip, ok: true},
+		{name: "zstd", start: []byte{0x28, 0xb5, 0x2f, 0xfd, 0x04, 0x00}, compression: CompressionZstd, ok: true},
+		{name: "xz", start: []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, compression: CompressionXz, ok: true},
+		{name: "bzip2", start: []byte("BZh91A"), compression: CompressionBzip2, ok: true},
+		{name: "plain", start: []byte("testlog1"), compression: "", ok: true},
+		{name: "short plain", start: []byte("t"), compression: "", ok: true},
+		{name: "partial gzip", start: []byte{0x1f}, ok: false},
+		{name: "partial xz", start: []byte{0xfd, '7', 'z'}, ok: false},
+		{name: "partial bzip2", start: []byte("BZ"), ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			compression, ok := detectCompression(tc.start)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.compression, compression)
+		})
+	}
+}
+
+func TestIsValidCompression(t *testing.T) {
+	for _, compression := range []string{"", CompressionGzip, CompressionZstd, CompressionXz, CompressionBzip2, CompressionAuto} {
+		assert.True(t, IsValidCompression(compression), compression)
+	}
+	assert.False(t, IsValidCompression("lz4"))
+}
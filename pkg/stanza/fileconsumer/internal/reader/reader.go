@@ -5,7 +5,6 @@ package reader // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"bufio"
-	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -63,9 +62,17 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		defer r.unlockFile()
 	}
 
+	if r.compression == CompressionAuto {
+		if !r.detectCompression() {
+			return
+		}
+	}
+
 	switch r.compression {
-	case "gzip":
-		// We need to create a gzip reader each time ReadToEnd is called because the underlying
+	case "":
+		r.reader = r.file
+	default:
+		// We need to create a decompressor each time ReadToEnd is called because the underlying
 		// SectionReader can only read a fixed window (from previous offset to EOF).
 		info, err := r.file.Stat()
 		if err != nil {
@@ -74,24 +81,31 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 		currentEOF := info.Size()
 
-		// use a gzip Reader with an underlying SectionReader to pick up at the last
-		// offset of a gzip compressed file
-		gzipReader, err := gzip.NewReader(io.NewSectionReader(r.file, r.Offset, currentEOF))
+		// Nothing was appended since the last read. Return before creating a decompressor,
+		// which fails on an empty stream for some formats.
+		if r.Offset >= currentEOF {
+			if r.deleteAtEOF {
+				r.delete()
+			}
+			return
+		}
+
+		// use a decompressor with an underlying SectionReader to pick up at the last offset
+		// of a compressed file, the streams appended since then being decompressed in turn
+		decompressor, err := newDecompressor(r.compression, io.NewSectionReader(r.file, r.Offset, currentEOF-r.Offset))
 		if err != nil {
 			if !errors.Is(err, io.EOF) {
-				r.set.Logger.Error("failed to create gzip reader", zap.Error(err))
+				r.set.Logger.Error("failed to create decompressor", zap.String("compression", r.compression), zap.Error(err))
 			}
 			return
-		} else {
-			r.reader = gzipReader
 		}
+		defer decompressor.Close()
+		r.reader = decompressor
 		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
 		// we need to set the offset to the end of the file.
 		defer func() {
 			r.Offset = currentEOF
 		}()
-	default:
-		r.reader = r.file
 	}
 
 	if _, err := r.file.Seek(r.Offset, 0); err != nil {
@@ -262,6 +276,23 @@ func (r *Reader) Read(dst []byte) (n int, err error) {
 	return
 }
 
+// detectCompression replaces the auto compression of the reader by the format detected
+// from the magic bytes of the file. It returns false if the file is too short to tell.
+func (r *Reader) detectCompression() bool {
+	start := make([]byte, maxMagicLen)
+	n, err := r.file.ReadAt(start, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.set.Logger.Error("failed to read magic bytes", zap.Error(err))
+		return false
+	}
+	compression, ok := detectCompression(start[:n])
+	if !ok {
+		return false
+	}
+	r.compression = compression
+	return true
+}
+
 func (r *Reader) NameEquals(other *Reader) bool {
 	return r.fileName == other.fileName
 }
@@ -5,6 +5,7 @@ package tracker // import "github.com/open-telemetry/opentelemetry-collector-con
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
@@ -23,6 +24,7 @@ type Tracker interface {
 	GetCurrentFile(fp *fingerprint.Fingerprint) *reader.Reader
 	GetOpenFile(fp *fingerprint.Fingerprint) *reader.Reader
 	GetClosedFile(fp *fingerprint.Fingerprint) *reader.Metadata
+	FindFiles(fps []*fingerprint.Fingerprint) []*reader.Metadata
 	GetMetadata() []*reader.Metadata
 	LoadMetadata(metadata []*reader.Metadata)
 	CurrentPollFiles() []*reader.Reader
@@ -57,7 +59,7 @@ func NewFileTracker(set component.TelemetrySettings, maxBatchFiles int, pollsToA
 		knownFiles[i] = fileset.New[*reader.Metadata](maxBatchFiles)
 	}
 	set.Logger = set.Logger.With(zap.String("tracker", "fileTracker"))
-	return &fileTracker{
+	t := &fileTracker{
 		set:               set,
 		maxBatchFiles:     maxBatchFiles,
 		currentPollFiles:  fileset.New[*reader.Reader](maxBatchFiles),
@@ -67,6 +69,10 @@ func NewFileTracker(set component.TelemetrySettings, maxBatchFiles int, pollsToA
 		persister:         persister,
 		archiveIndex:      0,
 	}
+	if t.archiveEnabled() {
+		t.restoreArchiveIndex()
+	}
+	return t
 }
 
 func (t *fileTracker) Add(reader *reader.Reader) {
@@ -161,14 +167,103 @@ func (t *fileTracker) archive(metadata *fileset.Fileset[*reader.Metadata]) {
 	//                   start
 	//                   index
 
-	if t.pollsToArchive <= 0 || t.persister == nil {
+	if !t.archiveEnabled() {
 		return
 	}
-	key := fmt.Sprintf("knownFiles%d", t.archiveIndex)
+	key := archiveKey(t.archiveIndex)
 	if err := checkpoint.SaveKey(context.Background(), t.persister, metadata.Get(), key); err != nil {
 		t.set.Logger.Error("error faced while saving to the archive", zap.Error(err))
 	}
 	t.archiveIndex = (t.archiveIndex + 1) % t.pollsToArchive // increment the index
+	t.saveArchiveIndex()
+}
+
+// FindFiles looks for the fingerprints in the archive, from the most recently archived poll
+// to the oldest one. It returns the metadata of the archived files matching the fingerprints,
+// in the same order, or nil for the fingerprints which are not archived. The matched files are
+// removed from the archive, as they are tracked again.
+func (t *fileTracker) FindFiles(fps []*fingerprint.Fingerprint) []*reader.Metadata {
+	matched := make([]*reader.Metadata, len(fps))
+	if !t.archiveEnabled() {
+		return matched
+	}
+
+	remaining := len(fps)
+	for i := 1; i <= t.pollsToArchive && remaining > 0; i++ {
+		index := (t.archiveIndex - i + t.pollsToArchive) % t.pollsToArchive
+		key := archiveKey(index)
+		archived, err := checkpoint.LoadKey(context.Background(), t.persister, key)
+		if err != nil {
+			t.set.Logger.Error("error faced while reading the archive", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		if len(archived) == 0 {
+			continue
+		}
+
+		archivedFiles := fileset.New[*reader.Metadata](len(archived))
+		archivedFiles.Add(archived...)
+		for j, fp := range fps {
+			if matched[j] != nil {
+				continue
+			}
+			if md := archivedFiles.Match(fp, fileset.StartsWith); md != nil {
+				matched[j] = md
+				remaining--
+			}
+		}
+
+		if archivedFiles.Len() < len(archived) {
+			if err = checkpoint.SaveKey(context.Background(), t.persister, archivedFiles.Get(), key); err != nil {
+				t.set.Logger.Error("error faced while saving to the archive", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	return matched
+}
+
+func (t *fileTracker) archiveEnabled() bool {
+	return t.pollsToArchive > 0 && t.persister != nil
+}
+
+// restoreArchiveIndex restores the index of the next archive slot, so that the archive
+// keeps rolling over the oldest polls after a restart.
+func (t *fileTracker) restoreArchiveIndex() {
+	encoded, err := t.persister.Get(context.Background(), archiveIndexKey)
+	if err != nil {
+		t.set.Logger.Error("error faced while reading the archive index", zap.Error(err))
+		return
+	}
+	if encoded == nil {
+		return
+	}
+	var index int
+	if err = json.Unmarshal(encoded, &index); err != nil {
+		t.set.Logger.Error("error faced while decoding the archive index", zap.Error(err))
+		return
+	}
+	if index < 0 || index >= t.pollsToArchive {
+		// polls_to_archive was reduced, start over from the first slot
+		index = 0
+	}
+	t.archiveIndex = index
+}
+
+func (t *fileTracker) saveArchiveIndex() {
+	encoded, err := json.Marshal(t.archiveIndex)
+	if err != nil {
+		t.set.Logger.Error("error faced while encoding the archive index", zap.Error(err))
+		return
+	}
+	if err = t.persister.Set(context.Background(), archiveIndexKey, encoded); err != nil {
+		t.set.Logger.Error("error faced while saving the archive index", zap.Error(err))
+	}
+}
+
+const archiveIndexKey = "knownFilesArchiveIndex"
+
+func archiveKey(index int) string {
+	return fmt.Sprintf("knownFiles%d", index)
 }
 
 // noStateTracker only tracks the current polled files. Once the poll is
@@ -214,6 +309,10 @@ func (t *noStateTracker) GetOpenFile(_ *fingerprint.Fingerprint) *reader.Reader
 
 func (t *noStateTracker) GetClosedFile(_ *fingerprint.Fingerprint) *reader.Metadata { return nil }
 
+func (t *noStateTracker) FindFiles(fps []*fingerprint.Fingerprint) []*reader.Metadata {
+	return make([]*reader.Metadata, len(fps))
+}
+
 func (t *noStateTracker) GetMetadata() []*reader.Metadata { return nil }
 
 func (t *noStateTracker) LoadMetadata(_ []*reader.Metadata) {}
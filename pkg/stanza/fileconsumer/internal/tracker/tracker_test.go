@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tracker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fileset"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func archiveFiles(tracker *fileTracker, fps ...string) {
+	files := fileset.New[*reader.Metadata](len(fps))
+	for i, fp := range fps {
+		files.Add(&reader.Metadata{Fingerprint: fingerprint.New([]byte(fp)), Offset: int64(i + 1)})
+	}
+	tracker.archive(files)
+}
+
+func TestFindFiles(t *testing.T) {
+	persister := testutil.NewUnscopedMockPersister()
+	tracker := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 3, persister).(*fileTracker)
+
+	archiveFiles(tracker, "foo", "bar")
+	archiveFiles(tracker, "baz")
+	require.Equal(t, 2, tracker.archiveIndex)
+
+	matched := tracker.FindFiles([]*fingerprint.Fingerprint{
+		fingerprint.New([]byte("bar and more")),
+		fingerprint.New([]byte("unknown")),
+		fingerprint.New([]byte("baz")),
+	})
+	require.Len(t, matched, 3)
+	require.Equal(t, int64(2), matched[0].Offset)
+	require.Nil(t, matched[1])
+	require.Equal(t, int64(1), matched[2].Offset)
+
+	// matched files are removed from the archive
+	matched = tracker.FindFiles([]*fingerprint.Fingerprint{fingerprint.New([]byte("bar"))})
+	require.Nil(t, matched[0])
+	matched = tracker.FindFiles([]*fingerprint.Fingerprint{fingerprint.New([]byte("foo"))})
+	require.Equal(t, int64(1), matched[0].Offset)
+}
+
+func TestFindFilesRollOver(t *testing.T) {
+	persister := testutil.NewUnscopedMockPersister()
+	tracker := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 2, persister).(*fileTracker)
+
+	archiveFiles(tracker, "foo")
+	archiveFiles(tracker, "bar")
+	// the oldest poll is overwritten
+	archiveFiles(tracker, "baz")
+
+	matched := tracker.FindFiles([]*fingerprint.Fingerprint{
+		fingerprint.New([]byte("foo")),
+		fingerprint.New([]byte("bar")),
+		fingerprint.New([]byte("baz")),
+	})
+	require.Nil(t, matched[0])
+	require.NotNil(t, matched[1])
+	require.NotNil(t, matched[2])
+}
+
+func TestArchiveIndexRestored(t *testing.T) {
+	persister := testutil.NewUnscopedMockPersister()
+	tracker := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 5, persister).(*fileTracker)
+	archiveFiles(tracker, "foo")
+	archiveFiles(tracker, "bar")
+
+	restarted := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 5, persister).(*fileTracker)
+	require.Equal(t, 2, restarted.archiveIndex)
+	matched := restarted.FindFiles([]*fingerprint.Fingerprint{fingerprint.New([]byte("foo"))})
+	require.NotNil(t, matched[0])
+
+	// the index is reset if polls_to_archive was reduced
+	reduced := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 1, persister).(*fileTracker)
+	require.Equal(t, 0, reduced.archiveIndex)
+}
+
+func TestFindFilesArchiveDisabled(t *testing.T) {
+	tracker := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 0, testutil.NewUnscopedMockPersister())
+	matched := tracker.FindFiles([]*fingerprint.Fingerprint{fingerprint.New([]byte("foo"))})
+	require.Equal(t, []*reader.Metadata{nil}, matched)
+}
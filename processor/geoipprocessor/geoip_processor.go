@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/otel/attribute"
@@ -70,6 +71,27 @@ func ipFromAttributes(attributes []attribute.Key, resource pcommon.Map) (net.IP,
 	return nil, errIPNotFound
 }
 
+// start starts the background work of the providers.
+func (g *geoIPProcessor) start(ctx context.Context, _ component.Host) error {
+	for _, geoProvider := range g.providers {
+		if starter, ok := geoProvider.(provider.Starter); ok {
+			if err := starter.Start(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// shutdown closes the providers.
+func (g *geoIPProcessor) shutdown(ctx context.Context) error {
+	var errs error
+	for _, geoProvider := range g.providers {
+		errs = errors.Join(errs, geoProvider.Close(ctx))
+	}
+	return errs
+}
+
 // geoLocation fetches geolocation information for the given IP address using the configured providers.
 // It returns a set of attributes containing the geolocation data, or an error if the location could not be determined.
 func (g *geoIPProcessor) geoLocation(ctx context.Context, ip net.IP) (attribute.Set, error) {
@@ -5,12 +5,14 @@ package geoipprocessor
 
 import (
 	"context"
+	"errors"
 	"net"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processortest"
@@ -36,6 +38,7 @@ type providerFactoryMock struct {
 
 type providerMock struct {
 	LocationF func(context.Context, net.IP) (attribute.Set, error)
+	CloseF    func(context.Context) error
 }
 
 var (
@@ -60,6 +63,13 @@ func (pm *providerMock) Location(ctx context.Context, ip net.IP) (attribute.Set,
 	return pm.LocationF(ctx, ip)
 }
 
+func (pm *providerMock) Close(ctx context.Context) error {
+	if pm.CloseF == nil {
+		return nil
+	}
+	return pm.CloseF(ctx)
+}
+
 var baseMockProvider = providerMock{
 	LocationF: func(context.Context, net.IP) (attribute.Set, error) {
 		return attribute.Set{}, nil
@@ -231,3 +241,41 @@ func TestProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessorShutdownClosesProviders(t *testing.T) {
+	closed := 0
+	errClose := errors.New("close error")
+	providers := []provider.GeoIPProvider{
+		&providerMock{CloseF: func(context.Context) error {
+			closed++
+			return errClose
+		}},
+		&providerMock{CloseF: func(context.Context) error {
+			closed++
+			return nil
+		}},
+	}
+	processor := newGeoIPProcessor(&Config{}, defaultResourceAttributes, providers, processortest.NewNopSettings())
+
+	require.ErrorIs(t, processor.shutdown(context.Background()), errClose)
+	require.Equal(t, 2, closed)
+}
+
+type startedProviderMock struct {
+	providerMock
+	started int
+}
+
+func (pm *startedProviderMock) Start(context.Context) error {
+	pm.started++
+	return nil
+}
+
+func TestProcessorStartStartsProviders(t *testing.T) {
+	started := &startedProviderMock{}
+	providers := []provider.GeoIPProvider{&providerMock{}, started}
+	processor := newGeoIPProcessor(&Config{}, defaultResourceAttributes, providers, processortest.NewNopSettings())
+
+	require.NoError(t, processor.start(context.Background(), componenttest.NewNopHost()))
+	require.Equal(t, 1, started.started)
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package csvprovider // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/csvprovider"
+
+import (
+	"errors"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+// Config defines configuration for the CSV provider.
+type Config struct {
+	// Path of the local CSV file mapping IP ranges to their location metadata.
+	Path string `mapstructure:"path"`
+
+	// Columns names the columns of the file in order. If empty, the first row
+	// of the file is used as the header.
+	Columns []string `mapstructure:"columns"`
+
+	// NetworkColumn is the column holding the IP range in CIDR notation, e.g. 10.1.0.0/16.
+	// It is ignored if RangeStartColumn and RangeEndColumn are set.
+	NetworkColumn string `mapstructure:"network_column"`
+
+	// RangeStartColumn and RangeEndColumn are the columns holding the first and the last
+	// IP addresses of the range, either in textual or in decimal integer form.
+	RangeStartColumn string `mapstructure:"range_start_column"`
+	RangeEndColumn   string `mapstructure:"range_end_column"`
+
+	// Attributes maps column names to the attribute keys their values are added as.
+	Attributes map[string]string `mapstructure:"attributes"`
+
+	// ReloadInterval is the interval between two checks of the file for changes.
+	// A value of 0 disables the reloading of the file.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+var _ provider.Config = (*Config)(nil)
+
+// Validate implements provider.Config.
+func (c *Config) Validate() error {
+	if c.Path == "" {
+		return errors.New("a local CSV file path must be provided")
+	}
+	if (c.RangeStartColumn == "") != (c.RangeEndColumn == "") {
+		return errors.New("range_start_column and range_end_column must be set together")
+	}
+	if c.RangeStartColumn == "" && c.NetworkColumn == "" {
+		return errors.New("either network_column or range_start_column and range_end_column must be set")
+	}
+	if len(c.Attributes) == 0 {
+		return errors.New("at least one column must be mapped to an attribute")
+	}
+	if c.ReloadInterval < 0 {
+		return errors.New("reload_interval must not be negative")
+	}
+	return nil
+}
+
+// rangeColumns returns the columns holding the bounds of the IP ranges, end being empty
+// when the ranges are defined in CIDR notation.
+func (c *Config) rangeColumns() (start string, end string) {
+	if c.RangeStartColumn != "" {
+		return c.RangeStartColumn, c.RangeEndColumn
+	}
+	return c.NetworkColumn, ""
+}
@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package csvprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{NetworkColumn: "network", ReloadInterval: defaultReloadInterval}, cfg)
+}
+
+func TestCreateProvider(t *testing.T) {
+	factory := &Factory{}
+	cfg := &Config{
+		Path: "",
+	}
+
+	provider, err := factory.CreateGeoIPProvider(context.Background(), processortest.NewNopSettings(), cfg)
+
+	assert.ErrorContains(t, err, "could not load CSV file")
+	assert.Nil(t, provider)
+}
@@ -0,0 +1,340 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package csvprovider // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/csvprovider"
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+// ipRange is a row of the CSV file, holding the attributes of the IP addresses between start and end.
+type ipRange struct {
+	start netip.Addr
+	end   netip.Addr
+	attrs attribute.Set
+}
+
+// rangeTable holds the ranges of a CSV file sorted by their first address.
+type rangeTable struct {
+	ranges []ipRange
+	// maxEnd holds for each index the highest end address of the ranges up to that index,
+	// allowing lookups to stop as soon as no previous range can contain the address.
+	maxEnd  []netip.Addr
+	modTime time.Time
+	size    int64
+}
+
+type csvProvider struct {
+	cfg    *Config
+	logger *zap.Logger
+
+	// table is swapped by the reload goroutine while the lookups use the previous one.
+	table atomic.Pointer[rangeTable]
+
+	stopReload chan struct{}
+	reloadWG   sync.WaitGroup
+	closeOnce  sync.Once
+}
+
+var (
+	_ provider.GeoIPProvider = (*csvProvider)(nil)
+	_ provider.Starter       = (*csvProvider)(nil)
+)
+
+func newCSVProvider(cfg *Config, logger *zap.Logger) (*csvProvider, error) {
+	table, err := loadRangeTable(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not load CSV file: %w", err)
+	}
+	p := &csvProvider{cfg: cfg, logger: logger, stopReload: make(chan struct{})}
+	p.table.Store(table)
+	return p, nil
+}
+
+// Start implements provider.Starter, starting the reloading of the file if a reload interval is set.
+func (p *csvProvider) Start(context.Context) error {
+	if p.cfg.ReloadInterval > 0 {
+		p.reloadWG.Add(1)
+		go p.reloadPeriodically()
+	}
+	return nil
+}
+
+// Location implements provider.GeoIPProvider for CSV files. The attributes of the most specific range
+// containing the IP address are returned.
+func (p *csvProvider) Location(_ context.Context, ipAddress net.IP) (attribute.Set, error) {
+	addr, ok := netip.AddrFromSlice(ipAddress)
+	if !ok {
+		return attribute.Set{}, fmt.Errorf("invalid IP address: %v", ipAddress)
+	}
+
+	if r := p.table.Load().lookup(addr.Unmap()); r != nil {
+		return r.attrs, nil
+	}
+	return attribute.Set{}, provider.ErrNoMetadataFound
+}
+
+// Close implements provider.GeoIPProvider, stopping the reloading of the file. It can be called more than once.
+func (p *csvProvider) Close(context.Context) error {
+	p.closeOnce.Do(func() {
+		close(p.stopReload)
+	})
+	p.reloadWG.Wait()
+	return nil
+}
+
+// reloadPeriodically checks the file for changes at every reload interval until the provider is closed.
+func (p *csvProvider) reloadPeriodically() {
+	defer p.reloadWG.Done()
+
+	ticker := time.NewTicker(p.cfg.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.reloadIfChanged()
+		case <-p.stopReload:
+			return
+		}
+	}
+}
+
+// reloadIfChanged reloads the file if its size or modification time changed since it was last loaded.
+// If the file cannot be loaded, the previously loaded ranges are kept.
+func (p *csvProvider) reloadIfChanged() {
+	info, err := os.Stat(p.cfg.Path)
+	if err != nil {
+		p.logger.Warn("could not check CSV file for changes", zap.String("path", p.cfg.Path), zap.Error(err))
+		return
+	}
+	current := p.table.Load()
+	if info.ModTime().Equal(current.modTime) && info.Size() == current.size {
+		return
+	}
+
+	table, err := loadRangeTable(p.cfg)
+	if err != nil {
+		p.logger.Warn("could not reload CSV file, keeping the previous version", zap.String("path", p.cfg.Path), zap.Error(err))
+		return
+	}
+	p.table.Store(table)
+	p.logger.Info("reloaded CSV file", zap.String("path", p.cfg.Path), zap.Int("ranges", len(table.ranges)))
+}
+
+// lookup returns the smallest range containing the address, or nil if there is none.
+func (t *rangeTable) lookup(addr netip.Addr) *ipRange {
+	// index of the first range starting after the address
+	i, _ := slices.BinarySearchFunc(t.ranges, addr, func(r ipRange, a netip.Addr) int {
+		if r.start.Compare(a) <= 0 {
+			return -1
+		}
+		return 1
+	})
+
+	var match *ipRange
+	for i--; i >= 0 && t.maxEnd[i].Compare(addr) >= 0; i-- {
+		r := &t.ranges[i]
+		if r.end.Compare(addr) < 0 {
+			continue
+		}
+		// ranges are sorted by start, so a later match is only less specific if it ends after the current one
+		if match == nil || r.end.Compare(match.end) < 0 {
+			match = r
+		}
+	}
+	return match
+}
+
+func loadRangeTable(cfg *Config) (*rangeTable, error) {
+	f, err := os.Open(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+	// the number of fields is checked against the columns for each record
+	reader.FieldsPerRecord = -1
+
+	columns := cfg.Columns
+	if len(columns) == 0 {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		columns = slices.Clone(header)
+	}
+	index := make(map[string]int, len(columns))
+	for i, column := range columns {
+		index[strings.TrimSpace(column)] = i
+	}
+	columnIndex := func(name string) (int, error) {
+		if i, ok := index[name]; ok {
+			return i, nil
+		}
+		return 0, fmt.Errorf("column %q not found", name)
+	}
+
+	startColumn, endColumn := cfg.rangeColumns()
+	startIndex, err := columnIndex(startColumn)
+	if err != nil {
+		return nil, err
+	}
+	endIndex := -1
+	if endColumn != "" {
+		if endIndex, err = columnIndex(endColumn); err != nil {
+			return nil, err
+		}
+	}
+	type attributeColumn struct {
+		index int
+		key   string
+	}
+	attributeColumns := make([]attributeColumn, 0, len(cfg.Attributes))
+	for column, key := range cfg.Attributes {
+		i, err := columnIndex(column)
+		if err != nil {
+			return nil, err
+		}
+		attributeColumns = append(attributeColumns, attributeColumn{index: i, key: key})
+	}
+
+	table := &rangeTable{modTime: info.ModTime(), size: info.Size()}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			return nil, fmt.Errorf("line %d: expected %d columns, found %d", line, len(columns), len(record))
+		}
+
+		var r ipRange
+		if endIndex < 0 {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(record[startIndex]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			r.start, r.end = prefixRange(prefix.Masked())
+		} else {
+			if r.start, err = parseAddr(record[startIndex]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if r.end, err = parseAddr(record[endIndex]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if r.start.BitLen() != r.end.BitLen() || r.start.Compare(r.end) > 0 {
+				return nil, fmt.Errorf("line %d: invalid IP range %s-%s", line, r.start, r.end)
+			}
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(attributeColumns))
+		for _, column := range attributeColumns {
+			value := strings.TrimSpace(record[column.index])
+			if value == "" {
+				continue
+			}
+			attr, err := attributeValue(column.key, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			attrs = append(attrs, attr)
+		}
+		r.attrs = attribute.NewSet(attrs...)
+		table.ranges = append(table.ranges, r)
+	}
+
+	slices.SortStableFunc(table.ranges, func(a, b ipRange) int {
+		return a.start.Compare(b.start)
+	})
+	table.maxEnd = make([]netip.Addr, len(table.ranges))
+	for i, r := range table.ranges {
+		table.maxEnd[i] = r.end
+		if i > 0 && table.maxEnd[i-1].Compare(r.end) > 0 {
+			table.maxEnd[i] = table.maxEnd[i-1]
+		}
+	}
+	return table, nil
+}
+
+// prefixRange returns the first and the last addresses of a masked prefix.
+func prefixRange(prefix netip.Prefix) (netip.Addr, netip.Addr) {
+	start := prefix.Addr().Unmap()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4In6() {
+		bits -= 96
+	}
+	end := start.AsSlice()
+	for i := bits; i < len(end)*8; i++ {
+		end[i/8] |= 1 << (7 - i%8)
+	}
+	last, _ := netip.AddrFromSlice(end)
+	return start, last
+}
+
+// maxIPv4 is the highest IPv4 address in decimal form. Higher decimal values are IPv6 addresses.
+var maxIPv4 = big.NewInt(1<<32 - 1)
+
+// parseAddr parses an IP address in textual or in decimal integer form, as used by the IP2Location databases.
+func parseAddr(value string) (netip.Addr, error) {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.Unmap(), nil
+	}
+
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 128 {
+		return netip.Addr{}, fmt.Errorf("invalid IP address %q", value)
+	}
+	if n.Cmp(maxIPv4) <= 0 {
+		var b [4]byte
+		return netip.AddrFrom4([4]byte(n.FillBytes(b[:]))), nil
+	}
+	var b [16]byte
+	return netip.AddrFrom16([16]byte(n.FillBytes(b[:]))).Unmap(), nil
+}
+
+// attributeValue returns the attribute for a column value, parsing the location coordinates as floats.
+func attributeValue(key, value string) (attribute.KeyValue, error) {
+	switch key {
+	case conventions.AttributeGeoLocationLat, conventions.AttributeGeoLocationLon:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return attribute.KeyValue{}, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+		}
+		return attribute.Float64(key, f), nil
+	}
+	return attribute.String(key, value), nil
+}
@@ -0,0 +1,308 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package csvprovider
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+)
+
+var (
+	networksConfig = Config{
+		Path:          filepath.Join("testdata", "networks.csv"),
+		NetworkColumn: "network",
+		Attributes: map[string]string{
+			"site":                                 "network.site",
+			"rack":                                 "network.rack",
+			conventions.AttributeGeoCountryIsoCode: conventions.AttributeGeoCountryIsoCode,
+		},
+	}
+	dbIPColumns        = []string{"start", "end", "continent", "country", "region", "city", "lat", "lon"}
+	ip2LocationColumns = []string{"start", "end", "country", "country_name", "region", "city", "lat", "lon"}
+	geoAttributes      = map[string]string{
+		"country": conventions.AttributeGeoCountryIsoCode,
+		"city":    conventions.AttributeGeoCityName,
+		"lat":     conventions.AttributeGeoLocationLat,
+		"lon":     conventions.AttributeGeoLocationLon,
+	}
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		expectedErr string
+	}{
+		{
+			name:        "missing path",
+			cfg:         Config{NetworkColumn: "network", Attributes: map[string]string{"site": "site"}},
+			expectedErr: "a local CSV file path must be provided",
+		},
+		{
+			name:        "range end column missing",
+			cfg:         Config{Path: "db.csv", RangeStartColumn: "start", Attributes: map[string]string{"site": "site"}},
+			expectedErr: "range_start_column and range_end_column must be set together",
+		},
+		{
+			name:        "no range columns",
+			cfg:         Config{Path: "db.csv", Attributes: map[string]string{"site": "site"}},
+			expectedErr: "either network_column or range_start_column and range_end_column must be set",
+		},
+		{
+			name:        "no attributes",
+			cfg:         Config{Path: "db.csv", NetworkColumn: "network"},
+			expectedErr: "at least one column must be mapped to an attribute",
+		},
+		{
+			name:        "negative reload interval",
+			cfg:         Config{Path: "db.csv", NetworkColumn: "network", Attributes: map[string]string{"site": "site"}, ReloadInterval: -time.Second},
+			expectedErr: "reload_interval must not be negative",
+		},
+		{
+			name: "valid",
+			cfg:  networksConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestInvalidNewProvider(t *testing.T) {
+	_, err := newCSVProvider(&Config{Path: "no valid path"}, zap.NewNop())
+	require.ErrorContains(t, err, "could not load CSV file: open no valid path")
+
+	cfg := networksConfig
+	cfg.Attributes = map[string]string{"datacenter": "network.datacenter"}
+	_, err = newCSVProvider(&cfg, zap.NewNop())
+	require.EqualError(t, err, `could not load CSV file: column "datacenter" not found`)
+
+	path := filepath.Join(t.TempDir(), "networks.csv")
+	require.NoError(t, os.WriteFile(path, []byte("network,site\n10.0.0.0/8,ams1\n10.0.0.0,ams2\n"), 0o600))
+	cfg = Config{Path: path, NetworkColumn: "network", Attributes: map[string]string{"site": "network.site"}}
+	_, err = newCSVProvider(&cfg, zap.NewNop())
+	require.ErrorContains(t, err, "could not load CSV file: line 3: netip.ParsePrefix")
+
+	require.NoError(t, os.WriteFile(path, []byte("start,end,site\n10.0.0.255,10.0.0.0,ams1\n"), 0o600))
+	cfg = Config{Path: path, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: map[string]string{"site": "network.site"}}
+	_, err = newCSVProvider(&cfg, zap.NewNop())
+	require.EqualError(t, err, "could not load CSV file: line 2: invalid IP range 10.0.0.255-10.0.0.0")
+
+	require.NoError(t, os.WriteFile(path, []byte("start,end,site\n10.0.0.0,10.0.0.255,ams1\n10.0.1.0,10.0.1.255\n"), 0o600))
+	_, err = newCSVProvider(&cfg, zap.NewNop())
+	require.EqualError(t, err, "could not load CSV file: line 3: expected 3 columns, found 2")
+
+	cfg.Columns = []string{"start", "end", "site"}
+	require.NoError(t, os.WriteFile(path, []byte("10.0.0.0,10.0.0.255\n"), 0o600))
+	_, err = newCSVProvider(&cfg, zap.NewNop())
+	require.EqualError(t, err, "could not load CSV file: line 1: expected 3 columns, found 2")
+}
+
+func TestProviderLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		cfg                Config
+		sourceIP           net.IP
+		expectedAttributes attribute.Set
+		expectedErrMsg     string
+	}{
+		{
+			name:           "nil IP address",
+			cfg:            networksConfig,
+			expectedErrMsg: "invalid IP address: <nil>",
+		},
+		{
+			name:           "no IP metadata in file",
+			cfg:            networksConfig,
+			sourceIP:       net.IPv4(172, 16, 0, 1),
+			expectedErrMsg: "no geo IP metadata found",
+		},
+		{
+			name:     "most specific network",
+			cfg:      networksConfig,
+			sourceIP: net.IPv4(10, 1, 2, 3),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("network.site", "ams1"),
+				attribute.String("network.rack", "r13"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "NL"),
+			),
+		},
+		{
+			name:     "enclosing network",
+			cfg:      networksConfig,
+			sourceIP: net.IPv4(10, 1, 3, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("network.site", "ams1"),
+				attribute.String("network.rack", "r12"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "NL"),
+			),
+		},
+		{
+			name:     "empty values are skipped",
+			cfg:      networksConfig,
+			sourceIP: net.IPv4(10, 200, 0, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("network.site", "ams1"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "NL"),
+			),
+		},
+		{
+			name:     "IPv6 network",
+			cfg:      networksConfig,
+			sourceIP: net.ParseIP("2001:db8::1"),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("network.site", "fra1"),
+				attribute.String("network.rack", "r01"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "DE"),
+			),
+		},
+		{
+			name:     "DB-IP lite IPv4 range",
+			cfg:      Config{Path: filepath.Join("testdata", "dbip-city-lite.csv"), Columns: dbIPColumns, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: geoAttributes},
+			sourceIP: net.IPv4(1, 0, 2, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "CN"),
+				attribute.String(conventions.AttributeGeoCityName, "Wenzhou"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 26.0614),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 119.306),
+			),
+		},
+		{
+			name:     "DB-IP lite IPv6 range",
+			cfg:      Config{Path: filepath.Join("testdata", "dbip-city-lite.csv"), Columns: dbIPColumns, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: geoAttributes},
+			sourceIP: net.ParseIP("2001:200::1"),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "JP"),
+				attribute.String(conventions.AttributeGeoCityName, "Tokyo"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 35.6895),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 139.692),
+			),
+		},
+		{
+			name:     "IP2Location lite IPv4 range",
+			cfg:      Config{Path: filepath.Join("testdata", "ip2location-lite-db5.csv"), Columns: ip2LocationColumns, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: geoAttributes},
+			sourceIP: net.IPv4(1, 0, 0, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "US"),
+				attribute.String(conventions.AttributeGeoCityName, "Los Angeles"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 34.05223),
+				attribute.Float64(conventions.AttributeGeoLocationLon, -118.24368),
+			),
+		},
+		{
+			name:     "IP2Location lite IPv4-mapped range",
+			cfg:      Config{Path: filepath.Join("testdata", "ip2location-lite-db5.csv"), Columns: ip2LocationColumns, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: geoAttributes},
+			sourceIP: net.IPv4(1, 0, 4, 10),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "AU"),
+				attribute.String(conventions.AttributeGeoCityName, "Brisbane"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, -27.46794),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 153.02809),
+			),
+		},
+		{
+			name:     "IP2Location lite IPv6 range",
+			cfg:      Config{Path: filepath.Join("testdata", "ip2location-lite-db5.csv"), Columns: ip2LocationColumns, RangeStartColumn: "start", RangeEndColumn: "end", Attributes: geoAttributes},
+			sourceIP: net.ParseIP("2001:200:1::1"),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "JP"),
+				attribute.String(conventions.AttributeGeoCityName, "Tokyo"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 35.6895),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 139.69171),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := newCSVProvider(&tt.cfg, zap.NewNop())
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, provider.Close(context.Background()))
+			}()
+
+			actualAttributes, err := provider.Location(context.Background(), tt.sourceIP)
+			if tt.expectedErrMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAttributes, actualAttributes)
+		})
+	}
+}
+
+func TestProviderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networks.csv")
+	require.NoError(t, os.WriteFile(path, []byte("network,site\n10.0.0.0/8,ams1\n"), 0o600))
+
+	cfg := &Config{Path: path, NetworkColumn: "network", Attributes: map[string]string{"site": "network.site"}, ReloadInterval: 10 * time.Millisecond}
+	provider, err := newCSVProvider(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, provider.Start(context.Background()))
+	defer func() {
+		require.NoError(t, provider.Close(context.Background()))
+	}()
+
+	location := func() attribute.Set {
+		attrs, err := provider.Location(context.Background(), net.IPv4(10, 0, 0, 1))
+		require.NoError(t, err)
+		return attrs
+	}
+	assert.Equal(t, attribute.NewSet(attribute.String("network.site", "ams1")), location())
+
+	require.NoError(t, os.WriteFile(path, []byte("network,site\n10.0.0.0/8,fra01\n"), 0o600))
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Equal(c, attribute.NewSet(attribute.String("network.site", "fra01")), location())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestProviderReloadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networks.csv")
+	require.NoError(t, os.WriteFile(path, []byte("network,site\n10.0.0.0/8,ams1\n"), 0o600))
+
+	cfg := &Config{Path: path, NetworkColumn: "network", Attributes: map[string]string{"site": "network.site"}}
+	provider, err := newCSVProvider(cfg, zap.NewNop())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, provider.Close(context.Background()))
+	}()
+
+	require.NoError(t, os.WriteFile(path, []byte("network,site\nnot a network,lab\n"), 0o600))
+	provider.reloadIfChanged()
+
+	attrs, err := provider.Location(context.Background(), net.IPv4(10, 0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, attribute.NewSet(attribute.String("network.site", "ams1")), attrs, "invalid file keeps the previous ranges")
+}
+
+func TestProviderCloseTwice(t *testing.T) {
+	cfg := &Config{Path: filepath.Join("testdata", "networks.csv"), NetworkColumn: "network", Attributes: map[string]string{"site": "network.site"}, ReloadInterval: time.Minute}
+	provider, err := newCSVProvider(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, provider.Start(context.Background()))
+
+	require.NoError(t, provider.Close(context.Background()))
+	require.NoError(t, provider.Close(context.Background()))
+}
@@ -25,6 +25,14 @@ type Config interface {
 type GeoIPProvider interface {
 	// Location returns a set of attributes representing the geographical location for the given IP address. It requires a context for managing request lifetime.
 	Location(context.Context, net.IP) (attribute.Set, error)
+
+	// Close releases the resources of the provider, it is called when the processor is shut down.
+	Close(context.Context) error
+}
+
+// Starter is implemented by the providers doing background work, which is started when the processor starts.
+type Starter interface {
+	Start(context.Context) error
 }
 
 // GeoIPProviderFactory can create GeoIPProvider instances.
@@ -4,9 +4,14 @@
 package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
 
 import (
+	"errors"
 	"time"
+
+	"go.opentelemetry.io/collector/component"
 )
 
+var errStorageRequired = errors.New("the 'storage' option is required when 'store_on_disk' is enabled")
+
 // Config is the configuration for the processor.
 type Config struct {
 	// NumTraces is the max number of traces to keep in memory waiting for the duration.
@@ -24,12 +29,24 @@ type Config struct {
 	// DiscardOrphans instructs the processor to discard traces without the root span.
 	// This typically indicates that the trace is incomplete.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	DiscardOrphans bool `mapstructure:"discard_orphans"`
 
-	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
-	// Useful when the duration to wait for traces to complete is high.
+	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans
+	// to the storage extension defined by StorageID.
+	// Useful when the duration to wait for traces to complete is high, or when in-flight traces
+	// should survive a restart.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the ID of the storage extension, such as the file storage, used to store the spans
+	// when StoreOnDisk is enabled.
+	StorageID *component.ID `mapstructure:"storage"`
+}
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.StoreOnDisk && cfg.StorageID == nil {
+		return errStorageRequired
+	}
+	return nil
 }
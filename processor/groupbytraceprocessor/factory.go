@@ -5,7 +5,6 @@ package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -23,11 +22,6 @@ const (
 	defaultStoreOnDisk    = false
 )
 
-var (
-	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release")
-	errDiscardOrphansNotSupported = fmt.Errorf("option 'discard orphans' not supported in this release")
-)
-
 // NewFactory returns a new factory for the Filter processor.
 func NewFactory() processor.Factory {
 	return processor.NewFactory(
@@ -43,7 +37,6 @@ func createDefaultConfig() component.Config {
 		NumWorkers:   defaultNumWorkers,
 		WaitDuration: defaultWaitDuration,
 
-		// not supported for now
 		DiscardOrphans: defaultDiscardOrphans,
 		StoreOnDisk:    defaultStoreOnDisk,
 	}
@@ -57,18 +50,15 @@ func createTracesProcessor(
 	nextConsumer consumer.Traces,
 ) (processor.Traces, error) {
 	oCfg := cfg.(*Config)
-
-	var st storage
-	if oCfg.StoreOnDisk {
-		return nil, errDiskStorageNotSupported
-	}
-	if oCfg.DiscardOrphans {
-		return nil, errDiscardOrphansNotSupported
+	if err := oCfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	processor := newGroupByTraceProcessor(params, nextConsumer, *oCfg)
-	// the only supported storage for now
-	st = newMemoryStorage(processor.telemetryBuilder)
-	processor.st = st
+	if oCfg.StoreOnDisk {
+		processor.st = newDiskStorage(params, *oCfg.StorageID, oCfg.WaitDuration, processor.telemetryBuilder)
+	} else {
+		processor.st = newMemoryStorage(processor.telemetryBuilder)
+	}
 	return processor, nil
 }
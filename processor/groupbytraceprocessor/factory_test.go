@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -35,9 +37,10 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, p)
 }
 
-func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
+func TestCreateTestProcessorWithDiskStorage(t *testing.T) {
 	// prepare
 	f := NewFactory()
+	storageID := storagetest.NewStorageID("test")
 
 	// test
 	for _, tt := range []struct {
@@ -46,21 +49,37 @@ func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 	}{
 		{
 			&Config{
+				NumTraces:      10,
+				NumWorkers:     1,
 				DiscardOrphans: true,
 			},
-			errDiscardOrphansNotSupported,
+			nil,
+		},
+		{
+			&Config{
+				NumTraces:   10,
+				NumWorkers:  1,
+				StoreOnDisk: true,
+				StorageID:   &storageID,
+			},
+			nil,
 		},
 		{
 			&Config{
 				StoreOnDisk: true,
 			},
-			errDiskStorageNotSupported,
+			errStorageRequired,
 		},
 	} {
 		p, err := f.CreateTraces(context.Background(), processortest.NewNopSettings(), tt.config, consumertest.NewNop())
 
 		// verify
-		assert.ErrorIs(t, tt.expectedErr, err)
-		assert.Nil(t, p)
+		if tt.expectedErr != nil {
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, p)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
 	}
 }
@@ -71,6 +71,7 @@ func newGroupByTraceProcessor(set processor.Settings, nextConsumer consumer.Trac
 	eventMachine.onTraceExpired = sp.onTraceExpired
 	eventMachine.onTraceReleased = sp.onTraceReleased
 	eventMachine.onTraceRemoved = sp.onTraceRemoved
+	eventMachine.onTraceRestored = sp.onTraceRestored
 
 	return sp
 }
@@ -88,13 +89,23 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
 	// start these metrics, as it might take a while for them to receive their first event
 	sp.telemetryBuilder.ProcessorGroupbytraceTracesEvicted.Add(context.Background(), 0)
 	sp.telemetryBuilder.ProcessorGroupbytraceIncompleteReleases.Add(context.Background(), 0)
 	sp.telemetryBuilder.ProcessorGroupbytraceConfNumTraces.Record(context.Background(), (int64(sp.config.NumTraces)))
+	if err := sp.st.start(ctx, host); err != nil {
+		return err
+	}
 	sp.eventMachine.startInBackground()
-	return sp.st.start()
+
+	// traces persisted by a previous run are scheduled to be released at their original deadline
+	if rs, ok := sp.st.(restorableStorage); ok {
+		for _, trace := range rs.restored() {
+			sp.eventMachine.restore(trace)
+		}
+	}
+	return nil
 }
 
 // Shutdown is invoked during service shutdown.
@@ -120,7 +131,30 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 	// at this point, we determined that we haven't seen the trace yet, so, record the
 	// traceID in the map and the spans to the storage
 
-	// place the trace ID in the buffer, and check if an item had to be evicted
+	sp.putInBuffer(traceID, worker)
+
+	// we have the traceID in the memory, place the spans in the storage too
+	if err := sp.addSpans(traceID, trace.td); err != nil {
+		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
+	}
+
+	sp.scheduleExpiration(traceID, sp.config.WaitDuration, worker)
+	return nil
+}
+
+func (sp *groupByTraceProcessor) onTraceRestored(trace restoredTrace, worker *eventMachineWorker) error {
+	if worker.buffer.contains(trace.id) {
+		// spans for this trace were received before the trace could be restored
+		return nil
+	}
+
+	sp.putInBuffer(trace.id, worker)
+	sp.scheduleExpiration(trace.id, time.Until(trace.deadline), worker)
+	return nil
+}
+
+// putInBuffer places the trace ID in the buffer, removing the trace evicted from the buffer, if any, from the storage.
+func (sp *groupByTraceProcessor) putInBuffer(traceID pcommon.TraceID, worker *eventMachineWorker) {
 	evicted := worker.buffer.put(traceID)
 	if !evicted.IsEmpty() {
 		// delete from the storage
@@ -133,22 +167,18 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 		sp.logger.Info("trace evicted: in order to avoid this in the future, adjust the wait duration and/or number of traces to keep in memory",
 			zap.Stringer("traceID", evicted))
 	}
+}
 
-	// we have the traceID in the memory, place the spans in the storage too
-	if err := sp.addSpans(traceID, trace.td); err != nil {
-		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
-	}
-
-	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
+func (sp *groupByTraceProcessor) scheduleExpiration(traceID pcommon.TraceID, duration time.Duration, worker *eventMachineWorker) {
+	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", duration))
 
-	time.AfterFunc(sp.config.WaitDuration, func() {
+	time.AfterFunc(duration, func() {
 		// if the event machine has stopped, it will just discard the event
 		worker.fire(event{
 			typ:     traceExpired,
 			payload: traceID,
 		})
 	})
-	return nil
 }
 
 func (sp *groupByTraceProcessor) onTraceExpired(traceID pcommon.TraceID, worker *eventMachineWorker) error {
@@ -201,6 +231,12 @@ func (sp *groupByTraceProcessor) markAsReleased(traceID pcommon.TraceID, fire fu
 }
 
 func (sp *groupByTraceProcessor) onTraceReleased(rss []ptrace.ResourceSpans) error {
+	if sp.config.DiscardOrphans && !hasRootSpan(rss) {
+		// the trace is removed from the storage by the traceRemoved event fired along with this one
+		sp.logger.Debug("discarding trace without root span")
+		return nil
+	}
+
 	trace := ptrace.NewTraces()
 	for _, rs := range rss {
 		trs := trace.ResourceSpans().AppendEmpty()
@@ -236,3 +272,18 @@ func (sp *groupByTraceProcessor) addSpans(traceID pcommon.TraceID, trace ptrace.
 	sp.logger.Debug("creating trace at the storage", zap.Stringer("traceID", traceID))
 	return sp.st.createOrAppend(traceID, trace)
 }
+
+// hasRootSpan checks whether any of the spans in the trace has no parent.
+func hasRootSpan(rss []ptrace.ResourceSpans) bool {
+	for _, rs := range rss {
+		for i := 0; i < rs.ScopeSpans().Len(); i++ {
+			spans := rs.ScopeSpans().At(i).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				if spans.At(j).ParentSpanID().IsEmpty() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
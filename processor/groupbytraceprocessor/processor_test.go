@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/processor/processortest"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
 )
@@ -643,7 +644,7 @@ func (st *mockStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans,
 	return nil, nil
 }
 
-func (st *mockStorage) start() error {
+func (st *mockStorage) start(context.Context, component.Host) error {
 	if st.onStart != nil {
 		return st.onStart()
 	}
@@ -683,3 +684,174 @@ func simpleTracesWithID(traceID pcommon.TraceID) ptrace.Traces {
 	ils.Spans().AppendEmpty().SetTraceID(traceID)
 	return traces
 }
+
+func TestTracesAreRestoredAfterRestart(t *testing.T) {
+	// prepare
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+		NumWorkers:   2,
+		StoreOnDisk:  true,
+		StorageID:    &ext.ID,
+	}
+	ctx := context.Background()
+
+	set := processortest.NewNopSettings()
+	sink := &consumertest.TracesSink{}
+	p, err := createTracesProcessor(ctx, set, &config, sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx, host))
+	require.NoError(t, p.ConsumeTraces(ctx, simpleTraces()))
+	assert.Eventually(t, func() bool {
+		return p.(*groupByTraceProcessor).st.(*diskStorage).count() == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, p.Shutdown(ctx))
+	assert.Equal(t, 0, sink.SpanCount(), "trace must not be released before its deadline")
+
+	// test
+	config.WaitDuration = time.Nanosecond
+	p, err = createTracesProcessor(ctx, set, &config, sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx, host))
+	// the restored trace keeps its original deadline
+	require.Equal(t, 1, p.(*groupByTraceProcessor).st.(*diskStorage).count())
+	p.(*groupByTraceProcessor).st.(*diskStorage).Lock()
+	for _, trace := range p.(*groupByTraceProcessor).st.(*diskStorage).traces {
+		assert.Greater(t, time.Until(trace.deadline), time.Minute)
+	}
+	p.(*groupByTraceProcessor).st.(*diskStorage).Unlock()
+	require.NoError(t, p.Shutdown(ctx))
+	assert.Equal(t, 0, sink.SpanCount())
+}
+
+func TestRestoredTraceIsReleasedAtDeadline(t *testing.T) {
+	// prepare
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	wgReceived := &sync.WaitGroup{}
+	mockProcessor := &mockProcessor{
+		onTraces: func(_ context.Context, received ptrace.Traces) error {
+			assert.Equal(t, simpleTracesWithID(traceID), received)
+			wgReceived.Done()
+			return nil
+		},
+	}
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+		NumWorkers:   2,
+	}
+	p := newGroupByTraceProcessor(processortest.NewNopSettings(), mockProcessor, config)
+	backing := newMemoryStorage(p.telemetryBuilder)
+	require.NoError(t, backing.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	p.st = &restorableMockStorage{
+		mockStorage: mockStorage{
+			onCreateOrAppend: backing.createOrAppend,
+			onGet:            backing.get,
+			onDelete:         backing.delete,
+		},
+		traces: []restoredTrace{{id: traceID, deadline: time.Now()}},
+	}
+
+	// test
+	wgReceived.Add(1)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, nil))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// verify
+	wgReceived.Wait()
+}
+
+func TestOrphanTracesAreDiscarded(t *testing.T) {
+	// prepare
+	orphanID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	orphan := simpleTracesWithID(orphanID)
+	orphan.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(pcommon.SpanID([8]byte{1}))
+	complete := simpleTracesWithID(pcommon.TraceID([16]byte{2, 3, 4, 5}))
+
+	wgDeleted := &sync.WaitGroup{}
+	sink := &consumertest.TracesSink{}
+	config := Config{
+		WaitDuration:   time.Nanosecond,
+		NumTraces:      10,
+		NumWorkers:     1,
+		DiscardOrphans: true,
+	}
+	p := newGroupByTraceProcessor(processortest.NewNopSettings(), sink, config)
+	backing := newMemoryStorage(p.telemetryBuilder)
+	p.st = &mockStorage{
+		onCreateOrAppend: backing.createOrAppend,
+		onGet:            backing.get,
+		onDelete: func(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+			defer wgDeleted.Done()
+			return backing.delete(traceID)
+		},
+	}
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, nil))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// test
+	wgDeleted.Add(2)
+	require.NoError(t, p.ConsumeTraces(ctx, orphan))
+	require.NoError(t, p.ConsumeTraces(ctx, complete))
+
+	// verify
+	wgDeleted.Wait()
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, complete, sink.AllTraces()[0])
+	assert.Equal(t, 0, backing.count(), "orphan trace must be removed from the storage")
+}
+
+func TestOrphanTracesAreDiscardedFromDiskStorage(t *testing.T) {
+	// prepare
+	orphan := simpleTracesWithID(pcommon.TraceID([16]byte{1, 2, 3, 4}))
+	orphan.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(pcommon.SpanID([8]byte{1}))
+	complete := simpleTracesWithID(pcommon.TraceID([16]byte{2, 3, 4, 5}))
+
+	ext := storagetest.NewInMemoryStorageExtension("test")
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	config := Config{
+		WaitDuration:   time.Nanosecond,
+		NumTraces:      10,
+		NumWorkers:     1,
+		DiscardOrphans: true,
+		StoreOnDisk:    true,
+		StorageID:      &ext.ID,
+	}
+	ctx := context.Background()
+	sink := &consumertest.TracesSink{}
+	p, err := createTracesProcessor(ctx, processortest.NewNopSettings(), &config, sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx, host))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// test
+	require.NoError(t, p.ConsumeTraces(ctx, orphan))
+	require.NoError(t, p.ConsumeTraces(ctx, complete))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return sink.SpanCount() == 1 && p.(*groupByTraceProcessor).st.(*diskStorage).count() == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, complete, sink.AllTraces()[0])
+}
+
+type restorableMockStorage struct {
+	mockStorage
+	traces []restoredTrace
+}
+
+func (st *restorableMockStorage) restored() []restoredTrace {
+	return st.traces
+}
@@ -4,6 +4,9 @@
 package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
 
 import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
@@ -25,7 +28,7 @@ type storage interface {
 	delete(pcommon.TraceID) ([]ptrace.ResourceSpans, error)
 
 	// start gives the storage the opportunity to initialize any resources or procedures
-	start() error
+	start(ctx context.Context, host component.Host) error
 
 	// shutdown signals the storage that the processor is shutting down
 	shutdown() error
@@ -0,0 +1,415 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	extstorage "go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+const (
+	// numTracesKey is the storage key holding the number of traces in flight.
+	numTracesKey = "groupbytrace_num_traces"
+	// slotKeyPrefix prefixes the key holding the trace ID and the deadline of the trace in a slot
+	// of the index, the slots being numbered from 0 to the number of traces in flight.
+	slotKeyPrefix = "groupbytrace_trace_"
+
+	// slotEntrySize is the size of the entry of a trace in the index:
+	// the trace ID and the deadline as unix nanoseconds.
+	slotEntrySize = 16 + 8
+)
+
+var errInvalidIndex = errors.New("invalid trace index found in storage")
+
+// diskTrace is the information kept in memory for each trace stored by the extension.
+type diskTrace struct {
+	// the lock is held while reading or writing the chunks of the trace
+	sync.Mutex
+	deadline time.Time
+	// chunks is the number of batches written for the trace, each batch is stored
+	// under its own key to avoid reading the trace when appending spans to it.
+	chunks uint32
+	// deleted is set once the trace is deleted from the storage, appending spans creates it again.
+	deleted bool
+}
+
+// restoredTrace is a trace found in the storage when starting the processor.
+type restoredTrace struct {
+	id       pcommon.TraceID
+	deadline time.Time
+}
+
+// restorableStorage is implemented by storages that persist traces across restarts.
+type restorableStorage interface {
+	// restored returns the traces found in the storage at start.
+	restored() []restoredTrace
+}
+
+// diskStorage keeps the spans in a storage extension, such as the file storage,
+// holding only the trace IDs and their deadlines in memory.
+// The traces in flight are indexed by slot, the index being updated in the same batch
+// as the chunks of the traces created or deleted, so that it survives crashes.
+type diskStorage struct {
+	// the lock guards the traces map only, the storage is accessed without holding it
+	sync.Mutex
+	traces map[pcommon.TraceID]*diskTrace
+
+	// indexLock serializes the creations and deletions of traces, so that the
+	// changes of the index are written in the order they are made.
+	indexLock sync.Mutex
+	slots     []restoredTrace
+	slotOf    map[pcommon.TraceID]int
+
+	client       extstorage.Client
+	storageID    component.ID
+	componentID  component.ID
+	waitDuration time.Duration
+	marshaler    ptrace.ProtoMarshaler
+	unmarshaler  ptrace.ProtoUnmarshaler
+
+	logger                    *zap.Logger
+	telemetry                 *metadata.TelemetryBuilder
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+var (
+	_ storage           = (*diskStorage)(nil)
+	_ restorableStorage = (*diskStorage)(nil)
+)
+
+func newDiskStorage(
+	set processor.Settings,
+	storageID component.ID,
+	waitDuration time.Duration,
+	telemetry *metadata.TelemetryBuilder,
+) *diskStorage {
+	return &diskStorage{
+		traces:                    make(map[pcommon.TraceID]*diskTrace),
+		slotOf:                    make(map[pcommon.TraceID]int),
+		storageID:                 storageID,
+		componentID:               set.ID,
+		waitDuration:              waitDuration,
+		logger:                    set.Logger,
+		telemetry:                 telemetry,
+		metricsCollectionInterval: time.Second,
+	}
+}
+
+func (st *diskStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	content, err := st.marshaler.MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace: %w", err)
+	}
+
+	for {
+		st.Lock()
+		trace, ok := st.traces[traceID]
+		if !ok {
+			trace = &diskTrace{deadline: time.Now().Add(st.waitDuration)}
+			// the other operations on the trace wait for it to be written
+			trace.Lock()
+			st.traces[traceID] = trace
+			st.Unlock()
+			return st.create(traceID, trace, content)
+		}
+		st.Unlock()
+
+		trace.Lock()
+		if trace.deleted {
+			// the trace was deleted since it was looked up
+			trace.Unlock()
+			continue
+		}
+		err = st.client.Batch(context.Background(),
+			extstorage.SetOperation(chunkKey(traceID, trace.chunks), content),
+			extstorage.SetOperation(chunksKey(traceID), binary.BigEndian.AppendUint32(nil, trace.chunks+1)),
+		)
+		if err == nil {
+			trace.chunks++
+		}
+		trace.Unlock()
+		return err
+	}
+}
+
+// create writes the first chunk of a new trace along with its index entry, the trace must be locked.
+func (st *diskStorage) create(traceID pcommon.TraceID, trace *diskTrace, content []byte) error {
+	defer trace.Unlock()
+
+	st.indexLock.Lock()
+	defer st.indexLock.Unlock()
+
+	slot := len(st.slots)
+	entry := restoredTrace{id: traceID, deadline: trace.deadline}
+	err := st.client.Batch(context.Background(),
+		extstorage.SetOperation(chunkKey(traceID, 0), content),
+		extstorage.SetOperation(chunksKey(traceID), binary.BigEndian.AppendUint32(nil, 1)),
+		extstorage.SetOperation(slotKey(slot), encodeSlotEntry(entry)),
+		extstorage.SetOperation(numTracesKey, binary.BigEndian.AppendUint64(nil, uint64(slot+1))),
+	)
+	if err != nil {
+		trace.deleted = true
+		st.Lock()
+		delete(st.traces, traceID)
+		st.Unlock()
+		return err
+	}
+	st.slots = append(st.slots, entry)
+	st.slotOf[traceID] = slot
+	trace.chunks = 1
+	return nil
+}
+
+func (st *diskStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	trace, ok := st.traces[traceID]
+	st.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	trace.Lock()
+	defer trace.Unlock()
+	if trace.deleted {
+		return nil, nil
+	}
+	return st.read(traceID, trace.chunks)
+}
+
+func (st *diskStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	trace, ok := st.traces[traceID]
+	st.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	trace.Lock()
+	defer trace.Unlock()
+	if trace.deleted {
+		return nil, nil
+	}
+	result, err := st.read(traceID, trace.chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]extstorage.Operation, 0, trace.chunks+4)
+	for i := uint32(0); i < trace.chunks; i++ {
+		ops = append(ops, extstorage.DeleteOperation(chunkKey(traceID, i)))
+	}
+	ops = append(ops, extstorage.DeleteOperation(chunksKey(traceID)))
+
+	st.indexLock.Lock()
+	defer st.indexLock.Unlock()
+
+	// the trace in the last slot of the index is moved to the slot of the deleted trace
+	slot, last := st.slotOf[traceID], len(st.slots)-1
+	if slot != last {
+		ops = append(ops, extstorage.SetOperation(slotKey(slot), encodeSlotEntry(st.slots[last])))
+	}
+	ops = append(ops,
+		extstorage.DeleteOperation(slotKey(last)),
+		extstorage.SetOperation(numTracesKey, binary.BigEndian.AppendUint64(nil, uint64(last))),
+	)
+	if err := st.client.Batch(context.Background(), ops...); err != nil {
+		return nil, err
+	}
+	st.slots[slot] = st.slots[last]
+	st.slotOf[st.slots[slot].id] = slot
+	st.slots = st.slots[:last]
+	delete(st.slotOf, traceID)
+
+	trace.deleted = true
+	st.Lock()
+	delete(st.traces, traceID)
+	st.Unlock()
+	return result, nil
+}
+
+func (st *diskStorage) read(traceID pcommon.TraceID, chunks uint32) ([]ptrace.ResourceSpans, error) {
+	ops := make([]extstorage.Operation, 0, chunks)
+	for i := uint32(0); i < chunks; i++ {
+		ops = append(ops, extstorage.GetOperation(chunkKey(traceID, i)))
+	}
+	if err := st.client.Batch(context.Background(), ops...); err != nil {
+		return nil, err
+	}
+
+	var result []ptrace.ResourceSpans
+	for _, op := range ops {
+		if op.Value == nil {
+			// the chunk could have been lost if the storage was altered
+			continue
+		}
+		td, err := st.unmarshaler.UnmarshalTraces(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trace: %w", err)
+		}
+		for i := 0; i < td.ResourceSpans().Len(); i++ {
+			result = append(result, td.ResourceSpans().At(i))
+		}
+	}
+	if result == nil {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func (st *diskStorage) start(ctx context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[st.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", st.storageID)
+	}
+	storageExt, ok := ext.(extstorage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", st.storageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, st.componentID, "")
+	if err != nil {
+		return fmt.Errorf("failed to get storage client: %w", err)
+	}
+	st.client = client
+
+	if err := st.loadIndex(ctx); err != nil {
+		return err
+	}
+
+	go st.periodicMetrics()
+	return nil
+}
+
+func (st *diskStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	if st.client == nil {
+		return nil
+	}
+	return st.client.Close(context.Background())
+}
+
+func (st *diskStorage) restored() []restoredTrace {
+	st.Lock()
+	defer st.Unlock()
+
+	result := make([]restoredTrace, 0, len(st.traces))
+	for id, trace := range st.traces {
+		result = append(result, restoredTrace{id: id, deadline: trace.deadline})
+	}
+	return result
+}
+
+func (st *diskStorage) loadIndex(ctx context.Context) error {
+	content, err := st.client.Get(ctx, numTracesKey)
+	if err != nil {
+		return fmt.Errorf("failed to read trace index: %w", err)
+	}
+	if content == nil {
+		return nil
+	}
+	if len(content) != 8 {
+		return errInvalidIndex
+	}
+
+	slots := make([]extstorage.Operation, binary.BigEndian.Uint64(content))
+	for slot := range slots {
+		slots[slot] = extstorage.GetOperation(slotKey(slot))
+	}
+	if err = st.client.Batch(ctx, slots...); err != nil {
+		return fmt.Errorf("failed to read trace index: %w", err)
+	}
+	chunks := make([]extstorage.Operation, len(slots))
+	for slot, op := range slots {
+		if len(op.Value) != slotEntrySize {
+			return errInvalidIndex
+		}
+		entry := decodeSlotEntry(op.Value)
+		st.slots = append(st.slots, entry)
+		st.slotOf[entry.id] = slot
+		chunks[slot] = extstorage.GetOperation(chunksKey(entry.id))
+	}
+	if err = st.client.Batch(ctx, chunks...); err != nil {
+		return fmt.Errorf("failed to read trace index: %w", err)
+	}
+
+	st.Lock()
+	defer st.Unlock()
+	for slot, op := range chunks {
+		if len(op.Value) != 4 {
+			return errInvalidIndex
+		}
+		st.traces[st.slots[slot].id] = &diskTrace{
+			deadline: st.slots[slot].deadline,
+			chunks:   binary.BigEndian.Uint32(op.Value),
+		}
+	}
+	if len(st.traces) > 0 {
+		st.logger.Info("restored traces from storage", zap.Int("traces", len(st.traces)))
+	}
+	return nil
+}
+
+func (st *diskStorage) periodicMetrics() {
+	st.telemetry.ProcessorGroupbytraceNumTracesInMemory.Record(context.Background(), int64(st.count()))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *diskStorage) count() int {
+	st.Lock()
+	defer st.Unlock()
+	return len(st.traces)
+}
+
+func chunkKey(traceID pcommon.TraceID, chunk uint32) string {
+	return fmt.Sprintf("trace_%s_%d", traceID, chunk)
+}
+
+func chunksKey(traceID pcommon.TraceID) string {
+	return fmt.Sprintf("trace_%s_chunks", traceID)
+}
+
+func slotKey(slot int) string {
+	return slotKeyPrefix + strconv.Itoa(slot)
+}
+
+func encodeSlotEntry(entry restoredTrace) []byte {
+	content := make([]byte, 0, slotEntrySize)
+	content = append(content, entry.id[:]...)
+	return binary.BigEndian.AppendUint64(content, uint64(entry.deadline.UnixNano()))
+}
+
+func decodeSlotEntry(content []byte) restoredTrace {
+	var entry restoredTrace
+	copy(entry.id[:], content[:16])
+	entry.deadline = time.Unix(0, int64(binary.BigEndian.Uint64(content[16:])))
+	return entry
+}
@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+func newTestDiskStorage(t *testing.T, set processor.Settings, ext *storagetest.TestStorage) *diskStorage {
+	t.Helper()
+
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+	st := newDiskStorage(set, ext.ID, time.Minute, tel)
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	require.NoError(t, st.start(context.Background(), host))
+	return st
+}
+
+func TestDiskCreateAndGetTrace(t *testing.T) {
+	st := newTestDiskStorage(t, processortest.NewNopSettings(), storagetest.NewInMemoryStorageExtension("test"))
+	defer func() {
+		assert.NoError(t, st.shutdown())
+	}()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	// test
+	for _, traceID := range traceIDs {
+		assert.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+
+	// verify
+	assert.Equal(t, 2, st.count())
+	for _, traceID := range traceIDs {
+		expected := []ptrace.ResourceSpans{simpleTracesWithID(traceID).ResourceSpans().At(0)}
+
+		retrieved, err := st.get(traceID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved)
+	}
+
+	retrieved, err := st.get(pcommon.TraceID([16]byte{9}))
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestDiskAppendAndDeleteTrace(t *testing.T) {
+	st := newTestDiskStorage(t, processortest.NewNopSettings(), storagetest.NewInMemoryStorageExtension("test"))
+	defer func() {
+		assert.NoError(t, st.shutdown())
+	}()
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	first := simpleTracesWithID(traceID)
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).Resource().Attributes().PutStr("service.name", "second")
+
+	// test
+	require.NoError(t, st.createOrAppend(traceID, first))
+	require.NoError(t, st.createOrAppend(traceID, second))
+	deleted, err := st.delete(traceID)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.ResourceSpans{first.ResourceSpans().At(0), second.ResourceSpans().At(0)}, deleted)
+	assert.Equal(t, 0, st.count())
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestDiskRestoreTraces(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+
+	set := processortest.NewNopSettings()
+	st := newTestDiskStorage(t, set, ext)
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	deadline := st.restored()[0].deadline
+	require.NoError(t, st.shutdown())
+
+	// test
+	st = newTestDiskStorage(t, set, ext)
+	defer func() {
+		assert.NoError(t, st.shutdown())
+	}()
+
+	// verify
+	restored := st.restored()
+	require.Len(t, restored, 1)
+	assert.Equal(t, traceID, restored[0].id)
+	assert.True(t, deadline.Equal(restored[0].deadline))
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.ResourceSpans{simpleTracesWithID(traceID).ResourceSpans().At(0)}, retrieved)
+}
+
+func TestDiskIndexIsWrittenWithTheChunks(t *testing.T) {
+	set := processortest.NewNopSettings()
+	st := newTestDiskStorage(t, set, storagetest.NewInMemoryStorageExtension("test"))
+	defer func() {
+		assert.NoError(t, st.shutdown())
+	}()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1}),
+		pcommon.TraceID([16]byte{2}),
+		pcommon.TraceID([16]byte{3}),
+	}
+	for _, traceID := range traceIDs {
+		require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+	require.NoError(t, st.createOrAppend(traceIDs[2], simpleTracesWithID(traceIDs[2])))
+	_, err := st.delete(traceIDs[0])
+	require.NoError(t, err)
+
+	// test: the storage is read as it is after a crash, without shutting down
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+	restored := newDiskStorage(set, st.storageID, time.Minute, tel)
+	restored.client = st.client
+	require.NoError(t, restored.loadIndex(context.Background()))
+
+	// verify
+	assert.Equal(t, 2, restored.count())
+	retrieved, err := restored.get(traceIDs[1])
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 1)
+	retrieved, err = restored.get(traceIDs[2])
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 2)
+	retrieved, err = restored.get(traceIDs[0])
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestDiskConcurrentOperations(t *testing.T) {
+	st := newTestDiskStorage(t, processortest.NewNopSettings(), storagetest.NewInMemoryStorageExtension("test"))
+	defer func() {
+		assert.NoError(t, st.shutdown())
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				traceID := pcommon.TraceID([16]byte{byte(j % 10)})
+				assert.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+				_, err := st.get(traceID)
+				assert.NoError(t, err)
+				if j%3 == 0 {
+					_, err = st.delete(traceID)
+					assert.NoError(t, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// verify: the index matches the traces in memory
+	st.Lock()
+	defer st.Unlock()
+	assert.Len(t, st.slots, len(st.traces))
+	for slot, entry := range st.slots {
+		assert.Equal(t, slot, st.slotOf[entry.id])
+		assert.Contains(t, st.traces, entry.id)
+	}
+}
+
+func TestDiskStartWithMissingExtension(t *testing.T) {
+	set := processortest.NewNopSettings()
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+
+	st := newDiskStorage(set, storagetest.NewStorageID("missing"), time.Second, tel)
+	assert.ErrorContains(t, st.start(context.Background(), componenttest.NewNopHost()), "not found")
+
+	host := storagetest.NewStorageHost().WithNonStorageExtension("nonstorage")
+	st = newDiskStorage(set, storagetest.NewNonStorageID("nonstorage"), time.Second, tel)
+	assert.ErrorContains(t, st.start(context.Background(), host), "non-storage extension")
+	assert.NoError(t, st.shutdown())
+}
+
+func TestDiskStartWithInvalidIndex(t *testing.T) {
+	ext := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	set := processortest.NewNopSettings()
+	st := newTestDiskStorage(t, set, ext)
+	require.NoError(t, st.client.Set(context.Background(), numTracesKey, []byte{1, 2, 3}))
+	require.NoError(t, st.shutdown())
+
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+	st = newDiskStorage(set, ext.ID, time.Second, tel)
+	host := storagetest.NewStorageHost().WithExtension(ext.ID, ext)
+	assert.ErrorIs(t, st.start(context.Background(), host), errInvalidIndex)
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
@@ -78,7 +79,7 @@ func (st *memoryStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans
 	return st.content[traceID], nil
 }
 
-func (st *memoryStorage) start() error {
+func (st *memoryStorage) start(context.Context, component.Host) error {
 	go st.periodicMetrics()
 	return nil
 }
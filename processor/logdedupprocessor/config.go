@@ -36,6 +36,7 @@ var (
 	errInvalidLogCountAttribute = errors.New("log_count_attribute must be set")
 	errInvalidInterval          = errors.New("interval must be greater than 0")
 	errCannotExcludeBody        = errors.New("cannot exclude the entire body")
+	errMultipleDedupIdentities  = errors.New("only one of exclude_fields, dedup_fields and dedup_key can be set")
 )
 
 // Config is the config of the processor.
@@ -45,6 +46,17 @@ type Config struct {
 	Timezone          string        `mapstructure:"timezone"`
 	ExcludeFields     []string      `mapstructure:"exclude_fields"`
 	Conditions        []string      `mapstructure:"conditions"`
+
+	// DedupFields are the body and attribute fields identifying duplicate logs.
+	// By default, all the fields which are not excluded are used.
+	DedupFields []string `mapstructure:"dedup_fields"`
+	// DedupKey is an OTTL value expression evaluated on each log whose value
+	// identifies duplicate logs.
+	DedupKey string `mapstructure:"dedup_key"`
+	// EmitFirst emits the first occurrence of a log immediately, the aggregated
+	// log is only emitted at the end of the interval if duplicates were seen, and
+	// its count excludes the first occurrence.
+	EmitFirst bool `mapstructure:"emit_first"`
 }
 
 // createDefaultConfig returns the default config for the processor.
@@ -55,6 +67,7 @@ func createDefaultConfig() component.Config {
 		Timezone:          defaultTimezone,
 		ExcludeFields:     []string{},
 		Conditions:        []string{},
+		DedupFields:       []string{},
 	}
 }
 
@@ -73,7 +86,20 @@ func (c Config) Validate() error {
 		return fmt.Errorf("timezone is invalid: %w", err)
 	}
 
-	return c.validateExcludeFields()
+	identities := 0
+	for _, set := range []bool{len(c.ExcludeFields) > 0, len(c.DedupFields) > 0, c.DedupKey != ""} {
+		if set {
+			identities++
+		}
+	}
+	if identities > 1 {
+		return errMultipleDedupIdentities
+	}
+
+	if err := c.validateExcludeFields(); err != nil {
+		return err
+	}
+	return c.validateDedupFields()
 }
 
 // validateExcludeFields validates that all the exclude fields
@@ -102,3 +128,24 @@ func (c Config) validateExcludeFields() error {
 
 	return nil
 }
+
+// validateDedupFields validates the dedup fields
+func (c Config) validateDedupFields() error {
+	knownDedupFields := make(map[string]struct{})
+
+	for _, field := range c.DedupFields {
+		// Split and ensure the field starts with `body` or `attributes`
+		parts := strings.Split(field, fieldDelimiter)
+		if parts[0] != bodyField && parts[0] != attributeField {
+			return fmt.Errorf("a dedup_field must start with %s or %s", bodyField, attributeField)
+		}
+
+		if _, ok := knownDedupFields[field]; ok {
+			return fmt.Errorf("duplicate dedup_field %s", field)
+		}
+
+		knownDedupFields[field] = struct{}{}
+	}
+
+	return nil
+}
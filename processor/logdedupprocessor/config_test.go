@@ -16,6 +16,9 @@ func TestCreateDefaultProcessorConfig(t *testing.T) {
 	require.Equal(t, defaultLogCountAttribute, cfg.LogCountAttribute)
 	require.Equal(t, defaultTimezone, cfg.Timezone)
 	require.Equal(t, []string{}, cfg.ExcludeFields)
+	require.Equal(t, []string{}, cfg.DedupFields)
+	require.Empty(t, cfg.DedupKey)
+	require.False(t, cfg.EmitFirst)
 }
 
 func TestValidateConfig(t *testing.T) {
@@ -84,6 +87,59 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: errors.New("duplicate exclude_field"),
 		},
+		{
+			desc: "invalid dedup field",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				DedupFields:       []string{"severity_text"},
+			},
+			expectedErr: errors.New("a dedup_field must start with"),
+		},
+		{
+			desc: "invalid duplicate dedup field",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				DedupFields:       []string{"attributes.thing", "attributes.thing"},
+			},
+			expectedErr: errors.New("duplicate dedup_field"),
+		},
+		{
+			desc: "invalid exclude and dedup fields",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				ExcludeFields:     []string{"body.thing"},
+				DedupFields:       []string{"attributes.thing"},
+			},
+			expectedErr: errMultipleDedupIdentities,
+		},
+		{
+			desc: "invalid dedup fields and key",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				DedupFields:       []string{"attributes.thing"},
+				DedupKey:          `attributes["thing"]`,
+			},
+			expectedErr: errMultipleDedupIdentities,
+		},
+		{
+			desc: "valid dedup fields config",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Timezone:          defaultTimezone,
+				DedupFields:       []string{bodyField, "attributes.thing"},
+				EmitFirst:         true,
+			},
+			expectedErr: nil,
+		},
 		{
 			desc: "valid config",
 			cfg: &Config{
@@ -29,15 +29,19 @@ type logAggregator struct {
 	logCountAttribute string
 	timezone          *time.Location
 	telemetryBuilder  *metadata.TelemetryBuilder
+	// emitFirst is set if the first occurrence of each log was already emitted. The logs seen
+	// only once are then skipped, and the first occurrence is not included in the log count.
+	emitFirst bool
 }
 
 // newLogAggregator creates a new LogCounter.
-func newLogAggregator(logCountAttribute string, timezone *time.Location, telemetryBuilder *metadata.TelemetryBuilder) *logAggregator {
+func newLogAggregator(logCountAttribute string, timezone *time.Location, telemetryBuilder *metadata.TelemetryBuilder, emitFirst bool) *logAggregator {
 	return &logAggregator{
 		resources:         make(map[uint64]*resourceAggregator),
 		logCountAttribute: logCountAttribute,
 		timezone:          timezone,
 		telemetryBuilder:  telemetryBuilder,
+		emitFirst:         emitFirst,
 	}
 }
 
@@ -57,6 +61,14 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 				// Record aggregated logs records
 				l.telemetryBuilder.DedupProcessorAggregatedLogs.Record(ctx, logAggregator.count)
 
+				count := logAggregator.count
+				if l.emitFirst {
+					if count == 1 {
+						continue
+					}
+					count--
+				}
+
 				lr := sl.LogRecords().AppendEmpty()
 				logAggregator.logRecord.CopyTo(lr)
 
@@ -66,7 +78,7 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 
 				// Add attributes for log count and first/last observed timestamps
 				lr.Attributes().EnsureCapacity(lr.Attributes().Len() + 3)
-				lr.Attributes().PutInt(l.logCountAttribute, logAggregator.count)
+				lr.Attributes().PutInt(l.logCountAttribute, count)
 				firstTimestampStr := logAggregator.firstObservedTimestamp.In(l.timezone).Format(time.RFC3339)
 				lr.Attributes().PutStr(firstObservedTSAttr, firstTimestampStr)
 				lastTimestampStr := logAggregator.lastObservedTimestamp.In(l.timezone).Format(time.RFC3339)
@@ -75,18 +87,29 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 		}
 	}
 
+	if l.emitFirst {
+		// Drop the resources and scopes whose logs were all skipped
+		logs.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+			rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+				return sl.LogRecords().Len() == 0
+			})
+			return rl.ScopeLogs().Len() == 0
+		})
+	}
+
 	return logs
 }
 
-// Add adds the logRecord to the resource aggregator that is identified by the resource attributes
-func (l *logAggregator) Add(resource pcommon.Resource, scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+// Add adds the logRecord identified by logKey to the resource aggregator that is identified
+// by the resource attributes. It returns true if it is the first occurrence of the logRecord.
+func (l *logAggregator) Add(resource pcommon.Resource, scope pcommon.InstrumentationScope, logRecord plog.LogRecord, logKey uint64) bool {
 	key := getResourceKey(resource)
 	resourceAggregator, ok := l.resources[key]
 	if !ok {
-		resourceAggregator = newResourceAggregator(resource)
+		resourceAggregator = newResourceAggregator(resource, l.emitFirst)
 		l.resources[key] = resourceAggregator
 	}
-	resourceAggregator.Add(scope, logRecord)
+	return resourceAggregator.Add(scope, logRecord, logKey, l.emitFirst)
 }
 
 // Reset resets the counter.
@@ -100,8 +123,14 @@ type resourceAggregator struct {
 	scopeCounters map[uint64]*scopeAggregator
 }
 
-// newResourceAggregator creates a new ResourceCounter.
-func newResourceAggregator(resource pcommon.Resource) *resourceAggregator {
+// newResourceAggregator creates a new ResourceCounter. The resource is copied if the logs
+// it belongs to are emitted before the aggregated logs.
+func newResourceAggregator(resource pcommon.Resource, copyResource bool) *resourceAggregator {
+	if copyResource {
+		r := pcommon.NewResource()
+		resource.CopyTo(r)
+		resource = r
+	}
 	return &resourceAggregator{
 		resource:      resource,
 		scopeCounters: make(map[uint64]*scopeAggregator),
@@ -109,14 +138,14 @@ func newResourceAggregator(resource pcommon.Resource) *resourceAggregator {
 }
 
 // Add increments the counter that the logRecord matches.
-func (r *resourceAggregator) Add(scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+func (r *resourceAggregator) Add(scope pcommon.InstrumentationScope, logRecord plog.LogRecord, logKey uint64, copyScope bool) bool {
 	key := getScopeKey(scope)
 	scopeAggregator, ok := r.scopeCounters[key]
 	if !ok {
-		scopeAggregator = newScopeAggregator(scope)
+		scopeAggregator = newScopeAggregator(scope, copyScope)
 		r.scopeCounters[key] = scopeAggregator
 	}
-	scopeAggregator.Add(logRecord)
+	return scopeAggregator.Add(logRecord, logKey)
 }
 
 // scopeAggregator dimensions the counter by scope.
@@ -125,8 +154,14 @@ type scopeAggregator struct {
 	logCounters map[uint64]*logCounter
 }
 
-// newScopeAggregator creates a new ScopeCounter.
-func newScopeAggregator(scope pcommon.InstrumentationScope) *scopeAggregator {
+// newScopeAggregator creates a new ScopeCounter. The scope is copied if the logs
+// it belongs to are emitted before the aggregated logs.
+func newScopeAggregator(scope pcommon.InstrumentationScope, copyScope bool) *scopeAggregator {
+	if copyScope {
+		s := pcommon.NewInstrumentationScope()
+		scope.CopyTo(s)
+		scope = s
+	}
 	return &scopeAggregator{
 		scope:       scope,
 		logCounters: make(map[uint64]*logCounter),
@@ -134,14 +169,14 @@ func newScopeAggregator(scope pcommon.InstrumentationScope) *scopeAggregator {
 }
 
 // Add increments the counter that the logRecord matches.
-func (s *scopeAggregator) Add(logRecord plog.LogRecord) {
-	key := getLogKey(logRecord)
-	lc, ok := s.logCounters[key]
+func (s *scopeAggregator) Add(logRecord plog.LogRecord, logKey uint64) bool {
+	lc, ok := s.logCounters[logKey]
 	if !ok {
 		lc = newLogCounter(logRecord)
-		s.logCounters[key] = lc
+		s.logCounters[logKey] = lc
 	}
 	lc.Increment()
+	return !ok
 }
 
 // logCounter is a counter for a log record.
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+// logKeyFunc returns the key identifying the duplicates of a log record.
+type logKeyFunc func(ctx context.Context, tCtx ottllog.TransformContext) (uint64, error)
+
+// newLogKeyFunc returns the function identifying duplicate logs: the OTTL dedup key
+// if set, the dedup fields if set, or all the fields of the log records otherwise.
+func newLogKeyFunc(cfg *Config, settings component.TelemetrySettings) (logKeyFunc, error) {
+	switch {
+	case cfg.DedupKey != "":
+		parser, err := ottllog.NewParser(ottlfuncs.StandardConverters[ottllog.TransformContext](), settings)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := parser.ParseValueExpression(cfg.DedupKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup_key: %w", err)
+		}
+		return newExpressionKeyFunc(expr), nil
+	case len(cfg.DedupFields) > 0:
+		return newFieldsKeyFunc(cfg.DedupFields), nil
+	default:
+		return func(_ context.Context, tCtx ottllog.TransformContext) (uint64, error) {
+			return getLogKey(tCtx.GetLogRecord()), nil
+		}, nil
+	}
+}
+
+// newExpressionKeyFunc returns a function hashing the value of the expression.
+func newExpressionKeyFunc(expr *ottl.ValueExpression[ottllog.TransformContext]) logKeyFunc {
+	return func(ctx context.Context, tCtx ottllog.TransformContext) (uint64, error) {
+		v, err := expr.Eval(ctx, tCtx)
+		if err != nil {
+			return 0, err
+		}
+		switch val := v.(type) {
+		case pcommon.Value:
+			return pdatautil.Hash64(pdatautil.WithValue(val)), nil
+		case pcommon.Map:
+			return pdatautil.Hash64(pdatautil.WithMap(val)), nil
+		}
+		value := pcommon.NewValueEmpty()
+		if err = value.FromRaw(v); err != nil {
+			// types unknown to pcommon are hashed from their default format
+			value.SetStr(fmt.Sprint(v))
+		}
+		return pdatautil.Hash64(pdatautil.WithValue(value)), nil
+	}
+}
+
+// newFieldsKeyFunc returns a function hashing the values of the fields, the
+// absence of a field being part of the identity of the log.
+func newFieldsKeyFunc(fieldKeys []string) logKeyFunc {
+	fields := make([]*field, 0, len(fieldKeys))
+	for _, f := range fieldKeys {
+		fields = append(fields, &field{
+			keyParts: splitField(f),
+		})
+	}
+
+	return func(_ context.Context, tCtx ottllog.TransformContext) (uint64, error) {
+		logRecord := tCtx.GetLogRecord()
+		opts := make([]pdatautil.HashOption, 0, 2*len(fields))
+		for i, f := range fields {
+			value, ok := f.getField(logRecord.Body(), logRecord.Attributes())
+			if !ok {
+				opts = append(opts, pdatautil.WithString(fieldKeys[i]))
+				continue
+			}
+			opts = append(opts, pdatautil.WithString(fieldKeys[i]), pdatautil.WithValue(value))
+		}
+		return pdatautil.Hash64(opts...), nil
+	}
+}
+
+// getField returns the value of the field from the body or the attributes of the log record.
+func (f *field) getField(body pcommon.Value, attributes pcommon.Map) (pcommon.Value, bool) {
+	firstPart, remainingParts := f.keyParts[0], f.keyParts[1:]
+
+	switch firstPart {
+	case bodyField:
+		return getFieldFromValue(body, remainingParts)
+	case attributeField:
+		// Use all attributes
+		if len(remainingParts) == 0 {
+			value := pcommon.NewValueMap()
+			attributes.CopyTo(value.Map())
+			return value, true
+		}
+		value, ok := attributes.Get(remainingParts[0])
+		if !ok {
+			return pcommon.Value{}, false
+		}
+		return getFieldFromValue(value, remainingParts[1:])
+	}
+	return pcommon.Value{}, false
+}
+
+// getFieldFromValue recurses through the maps of the value to find the field.
+func getFieldFromValue(value pcommon.Value, keyParts []string) (pcommon.Value, bool) {
+	for _, part := range keyParts {
+		if value.Type() != pcommon.ValueTypeMap {
+			return pcommon.Value{}, false
+		}
+		var ok bool
+		if value, ok = value.Map().Get(part); !ok {
+			return pcommon.Value{}, false
+		}
+	}
+	return value, true
+}
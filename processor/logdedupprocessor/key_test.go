@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+)
+
+func newKeyTestLog(t *testing.T, body map[string]any, attrs map[string]any) ottllog.TransformContext {
+	logRecord := plog.NewLogRecord()
+	require.NoError(t, logRecord.Body().SetEmptyMap().FromRaw(body))
+	require.NoError(t, logRecord.Attributes().FromRaw(attrs))
+	return ottllog.NewTransformContext(logRecord, pcommon.NewInstrumentationScope(), pcommon.NewResource(), plog.NewScopeLogs(), plog.NewResourceLogs())
+}
+
+func Test_newLogKeyFunc(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		cfg       *Config
+		log1      ottllog.TransformContext
+		log2      ottllog.TransformContext
+		sameKey   bool
+		expectErr bool
+	}{
+		{
+			desc:    "all fields match",
+			cfg:     &Config{},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"id": 1}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"id": 1}),
+			sameKey: true,
+		},
+		{
+			desc:    "all fields differ",
+			cfg:     &Config{},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"id": 1}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"id": 2}),
+			sameKey: false,
+		},
+		{
+			desc:    "dedup fields match",
+			cfg:     &Config{DedupFields: []string{"body.msg", "attributes.code"}},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello", "ts": 1}, map[string]any{"code": 500, "id": 1}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello", "ts": 2}, map[string]any{"code": 500, "id": 2}),
+			sameKey: true,
+		},
+		{
+			desc:    "dedup fields differ",
+			cfg:     &Config{DedupFields: []string{"body.msg", "attributes.code"}},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"code": 500}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"code": 404}),
+			sameKey: false,
+		},
+		{
+			desc:    "missing dedup field",
+			cfg:     &Config{DedupFields: []string{"body.msg", "attributes.code"}},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"code": 500}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{}),
+			sameKey: false,
+		},
+		{
+			desc:    "nested dedup field",
+			cfg:     &Config{DedupFields: []string{"attributes.http.status"}},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"http": map[string]any{"status": 500, "path": "/a"}}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "bye"}, map[string]any{"http": map[string]any{"status": 500, "path": "/b"}}),
+			sameKey: true,
+		},
+		{
+			desc:    "dedup key match",
+			cfg:     &Config{DedupKey: `Concat([body["msg"], attributes["code"]], "-")`},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello", "ts": 1}, map[string]any{"code": 500, "id": 1}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello", "ts": 2}, map[string]any{"code": 500, "id": 2}),
+			sameKey: true,
+		},
+		{
+			desc:    "dedup key differ",
+			cfg:     &Config{DedupKey: `attributes["code"]`},
+			log1:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"code": 500}),
+			log2:    newKeyTestLog(t, map[string]any{"msg": "hello"}, map[string]any{"code": 404}),
+			sameKey: false,
+		},
+		{
+			desc:      "invalid dedup key",
+			cfg:       &Config{DedupKey: `attributes[`},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			keyFunc, err := newLogKeyFunc(tc.cfg, componenttest.NewNopTelemetrySettings())
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			key1, err := keyFunc(context.Background(), tc.log1)
+			require.NoError(t, err)
+			key2, err := keyFunc(context.Background(), tc.log2)
+			require.NoError(t, err)
+			if tc.sameKey {
+				require.Equal(t, key1, key2)
+			} else {
+				require.NotEqual(t, key1, key2)
+			}
+		})
+	}
+}
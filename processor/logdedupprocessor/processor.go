@@ -11,7 +11,6 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
@@ -27,6 +26,8 @@ type logDedupProcessor struct {
 	conditions   *ottl.ConditionSequence[ottllog.TransformContext]
 	aggregator   *logAggregator
 	remover      *fieldRemover
+	logKey       logKeyFunc
+	emitFirst    bool
 	nextConsumer consumer.Logs
 	logger       *zap.Logger
 	cancel       context.CancelFunc
@@ -46,10 +47,17 @@ func newProcessor(cfg *Config, nextConsumer consumer.Logs, settings processor.Se
 		return nil, fmt.Errorf("invalid timezone: %w", err)
 	}
 
+	logKey, err := newLogKeyFunc(cfg, settings.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+
 	return &logDedupProcessor{
 		emitInterval: cfg.Interval,
-		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone, telemetryBuilder),
+		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone, telemetryBuilder, cfg.EmitFirst),
 		remover:      newFieldRemover(cfg.ExcludeFields),
+		logKey:       logKey,
+		emitFirst:    cfg.EmitFirst,
 		nextConsumer: nextConsumer,
 		logger:       settings.Logger,
 	}, nil
@@ -96,26 +104,29 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 			logs := sl.LogRecords()
 
 			logs.RemoveIf(func(logRecord plog.LogRecord) bool {
-				if p.conditions == nil {
-					p.aggregateLog(logRecord, scope, resource)
-					return true
+				if p.conditions != nil {
+					logCtx := ottllog.NewTransformContext(logRecord, scope, resource, sl, rl)
+					logMatch, err := p.conditions.Eval(ctx, logCtx)
+					if err != nil {
+						p.logger.Error("error matching conditions", zap.Error(err))
+						return false
+					}
+					if !logMatch {
+						return false
+					}
 				}
 
-				logCtx := ottllog.NewTransformContext(logRecord, scope, resource, sl, rl)
-				logMatch, err := p.conditions.Eval(ctx, logCtx)
+				emit, err := p.aggregateLog(ctx, logRecord, sl, rl)
 				if err != nil {
-					p.logger.Error("error matching conditions", zap.Error(err))
+					p.logger.Error("error computing the dedup key", zap.Error(err))
 					return false
 				}
-				if logMatch {
-					p.aggregateLog(logRecord, scope, resource)
-				}
-				return logMatch
+				return !emit
 			})
 		}
 	}
 
-	// immediately consume any logs that didn't match any conditions
+	// immediately consume any logs that didn't match any conditions, or seen for the first time
 	if pl.LogRecordCount() > 0 {
 		err := p.nextConsumer.ConsumeLogs(ctx, pl)
 		if err != nil {
@@ -126,9 +137,23 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 	return nil
 }
 
-func (p *logDedupProcessor) aggregateLog(logRecord plog.LogRecord, scope pcommon.InstrumentationScope, resource pcommon.Resource) {
+// aggregateLog adds the log record to the aggregator, it returns true if the log record
+// must be emitted immediately as it is the first occurrence of the log.
+func (p *logDedupProcessor) aggregateLog(ctx context.Context, logRecord plog.LogRecord, sl plog.ScopeLogs, rl plog.ResourceLogs) (bool, error) {
+	if p.emitFirst {
+		// The log record is emitted unchanged, aggregate a copy of it.
+		aggregated := plog.NewLogRecord()
+		logRecord.CopyTo(aggregated)
+		logRecord = aggregated
+	}
+
 	p.remover.RemoveFields(logRecord)
-	p.aggregator.Add(resource, scope, logRecord)
+	key, err := p.logKey(ctx, ottllog.NewTransformContext(logRecord, sl.Scope(), rl.Resource(), sl, rl))
+	if err != nil {
+		return false, err
+	}
+	first := p.aggregator.Add(rl.Resource(), sl.Scope(), logRecord, key)
+	return p.emitFirst && first, nil
 }
 
 // handleExportInterval sends metrics at the configured interval.
@@ -40,6 +40,18 @@ func Test_newProcessor(t *testing.T) {
 			expected:    nil,
 			expectedErr: errors.New("invalid timezone"),
 		},
+		{
+			desc: "DedupKey error",
+			cfg: &Config{
+				LogCountAttribute: defaultLogCountAttribute,
+				Interval:          defaultInterval,
+				Conditions:        []string{},
+				Timezone:          defaultTimezone,
+				DedupKey:          `attributes["code"`,
+			},
+			expected:    nil,
+			expectedErr: errors.New("invalid dedup_key"),
+		},
 		{
 			desc: "valid config",
 			cfg: &Config{
@@ -308,3 +320,67 @@ func TestProcessorConsumeMultipleConditions(t *testing.T) {
 	err = p.Shutdown(context.Background())
 	require.NoError(t, err)
 }
+
+func TestProcessorEmitFirst(t *testing.T) {
+	logsSink := &consumertest.LogsSink{}
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          1 * time.Second,
+		Timezone:          defaultTimezone,
+		Conditions:        []string{},
+		DedupFields:       []string{bodyField, "attributes.code"},
+		EmitFirst:         true,
+	}
+
+	// Create a processor
+	p, err := createLogsProcessor(context.Background(), processortest.NewNopSettings(), cfg, logsSink)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	// Create logs payload, the request_id is not part of the identity of the logs
+	logs := plog.NewLogs()
+	logRecords := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 3; i++ {
+		lr := logRecords.AppendEmpty()
+		lr.Body().SetStr("payment failed")
+		lr.Attributes().PutInt("code", 500)
+		lr.Attributes().PutInt("request_id", int64(i))
+	}
+	lr := logRecords.AppendEmpty()
+	lr.Body().SetStr("payment failed")
+	lr.Attributes().PutInt("code", 404)
+
+	// Consume the payload
+	err = p.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	// The first occurrence of each log is emitted immediately and unchanged
+	allSinkLogs := logsSink.AllLogs()
+	require.Len(t, allSinkLogs, 1)
+	emitted := allSinkLogs[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, emitted.Len())
+	require.Equal(t, map[string]any{"code": int64(500), "request_id": int64(0)}, emitted.At(0).Attributes().AsRaw())
+	require.Equal(t, map[string]any{"code": int64(404)}, emitted.At(1).Attributes().AsRaw())
+
+	// Wait for the aggregated logs to be emitted
+	require.Eventually(t, func() bool {
+		return len(logsSink.AllLogs()) > 1
+	}, 3*time.Second, 200*time.Millisecond)
+
+	// Only the logs seen more than once are aggregated, the first occurrence is not counted again
+	aggregated := logsSink.AllLogs()[1]
+	require.Equal(t, 1, aggregated.LogRecordCount())
+	attrs := aggregated.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+	count, ok := attrs.Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(2), count.Int())
+	code, ok := attrs.Get("code")
+	require.True(t, ok)
+	require.Equal(t, int64(500), code.Int())
+
+	// Cleanup
+	err = p.Shutdown(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type ContextID string
+
+const (
+	resource ContextID = "resource"
+	record   ContextID = "record"
+)
+
+func (c *ContextID) UnmarshalText(text []byte) error {
+	str := ContextID(strings.ToLower(string(text)))
+	switch str {
+	case resource, record:
+		*c = str
+		return nil
+	default:
+		return fmt.Errorf("unknown context %s, available values: %s, %s", str, resource, record)
+	}
+}
+
+// Config holds the configuration for the lookup processor.
+type Config struct {
+	// LookupTables is the ID of the lookup_tables extension holding the tables.
+	LookupTables *component.ID `mapstructure:"lookup_tables"`
+
+	// Lookups defines how the telemetry flowing through the processor is enriched
+	// with the rows of the tables.
+	Lookups []LookupConfig `mapstructure:"lookups"`
+}
+
+// LookupConfig defines how the telemetry is enriched with the rows of a table.
+type LookupConfig struct {
+	// Table is the name of the lookup table.
+	Table string `mapstructure:"table"`
+
+	// Context is the attributes the key is read from and the columns are added to.
+	// Available values: resource (default) or record.
+	Context ContextID `mapstructure:"context"`
+
+	// Key is the attribute holding the key to look up.
+	Key string `mapstructure:"key"`
+
+	// Attributes restricts the columns added as attributes. All the columns of the row are added if empty.
+	Attributes []string `mapstructure:"attributes"`
+
+	// Prefix is prepended to the column names to build the attribute keys.
+	Prefix string `mapstructure:"prefix"`
+
+	// Overwrite replaces the existing attributes with the values of the row.
+	Overwrite bool `mapstructure:"overwrite"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if len(cfg.Lookups) > 0 && cfg.LookupTables == nil {
+		return errors.New("lookup_tables must be specified")
+	}
+	for i, lookup := range cfg.Lookups {
+		if lookup.Table == "" {
+			return fmt.Errorf("lookups[%d]: table must be specified", i)
+		}
+		if lookup.Key == "" {
+			return fmt.Errorf("lookups[%d]: key must be specified", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	lookupTablesID := component.MustNewID("lookup_tables")
+	sqlLookupTablesID := component.MustNewIDWithName("lookup_tables", "sql")
+
+	tests := []struct {
+		id                    component.ID
+		expected              component.Config
+		validateErrorMessage  string
+		unmarshalErrorMessage string
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: &Config{},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "file"),
+			expected: &Config{
+				LookupTables: &lookupTablesID,
+				Lookups: []LookupConfig{
+					{Table: "owners", Key: "service.name", Prefix: "owner."},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "sql"),
+			expected: &Config{
+				LookupTables: &sqlLookupTablesID,
+				Lookups: []LookupConfig{
+					{Table: "hosts", Context: record, Key: "host.name", Attributes: []string{"site"}, Overwrite: true},
+				},
+			},
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "missing_lookup_tables"),
+			validateErrorMessage: "lookup_tables must be specified",
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "missing_table"),
+			validateErrorMessage: "lookups[0]: table must be specified",
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "missing_lookup_key"),
+			validateErrorMessage: "lookups[0]: key must be specified",
+		},
+		{
+			id:                    component.NewIDWithName(metadata.Type, "invalid_context"),
+			unmarshalErrorMessage: "unknown context span, available values: resource, record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+
+			if tt.unmarshalErrorMessage != "" {
+				assert.ErrorContains(t, sub.Unmarshal(cfg), tt.unmarshalErrorMessage)
+				return
+			}
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.validateErrorMessage != "" {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.validateErrorMessage)
+				return
+			}
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{}, cfg)
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	cfg := ownersConfig()
+
+	tp, err := factory.CreateTraces(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.True(t, tp.Capabilities().MutatesData)
+
+	mp, err := factory.CreateMetrics(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.True(t, mp.Capabilities().MutatesData)
+
+	lp, err := factory.CreateLogs(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.True(t, lp.Capabilities().MutatesData)
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+)
+
+// lookup is a configured lookup with its resolved table.
+type lookup struct {
+	LookupConfig
+	table *lookuptable.Table
+}
+
+type lookupProcessor struct {
+	config *Config
+
+	resourceLookups []lookup
+	recordLookups   []lookup
+}
+
+func newLookupProcessor(config *Config) *lookupProcessor {
+	return &lookupProcessor{config: config}
+}
+
+// Start resolves the tables of the lookups from the lookup_tables extension.
+func (p *lookupProcessor) Start(_ context.Context, host component.Host) error {
+	if len(p.config.Lookups) == 0 {
+		return nil
+	}
+	provider, err := lookuptable.GetProvider(host, *p.config.LookupTables)
+	if err != nil {
+		return err
+	}
+	for i, cfg := range p.config.Lookups {
+		table, ok := provider.Table(cfg.Table)
+		if !ok {
+			return fmt.Errorf("lookups[%d]: unknown table %q in extension %q", i, cfg.Table, p.config.LookupTables)
+		}
+		l := lookup{LookupConfig: cfg, table: table}
+		if cfg.Context == record {
+			p.recordLookups = append(p.recordLookups, l)
+		} else {
+			p.resourceLookups = append(p.resourceLookups, l)
+		}
+	}
+	return nil
+}
+
+// processAttributes adds the columns of the rows matching the lookups to the attributes.
+func processAttributes(lookups []lookup, attributes pcommon.Map) {
+	for _, l := range lookups {
+		key, ok := attributes.Get(l.Key)
+		if !ok {
+			continue
+		}
+		row, ok := l.table.Lookup(key.AsString())
+		if !ok {
+			continue
+		}
+		if len(l.Attributes) == 0 {
+			row.Range(func(column string, value pcommon.Value) bool {
+				putAttribute(attributes, l.Prefix+column, value, l.Overwrite)
+				return true
+			})
+			continue
+		}
+		for _, column := range l.Attributes {
+			if value, ok := row.Get(column); ok {
+				putAttribute(attributes, l.Prefix+column, value, l.Overwrite)
+			}
+		}
+	}
+}
+
+func putAttribute(attributes pcommon.Map, key string, value pcommon.Value, overwrite bool) {
+	if _, exists := attributes.Get(key); exists && !overwrite {
+		return
+	}
+	value.CopyTo(attributes.PutEmpty(key))
+}
+
+func (p *lookupProcessor) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		processAttributes(p.resourceLookups, rs.Resource().Attributes())
+		if len(p.recordLookups) == 0 {
+			continue
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				processAttributes(p.recordLookups, spans.At(k).Attributes())
+			}
+		}
+	}
+	return td, nil
+}
+
+func (p *lookupProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		processAttributes(p.resourceLookups, rl.Resource().Attributes())
+		if len(p.recordLookups) == 0 {
+			continue
+		}
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			logRecords := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < logRecords.Len(); k++ {
+				processAttributes(p.recordLookups, logRecords.At(k).Attributes())
+			}
+		}
+	}
+	return ld, nil
+}
+
+func (p *lookupProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		processAttributes(p.resourceLookups, rm.Resource().Attributes())
+		if len(p.recordLookups) == 0 {
+			continue
+		}
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				p.processMetricAttributes(metrics.At(k))
+			}
+		}
+	}
+	return md, nil
+}
+
+func (p *lookupProcessor) processMetricAttributes(m pmetric.Metric) {
+	// This is a lot of repeated code, but since there is no single parent superclass
+	// between metric data types, we can't use polymorphism.
+	//exhaustive:enforce
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			processAttributes(p.recordLookups, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			processAttributes(p.recordLookups, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			processAttributes(p.recordLookups, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			processAttributes(p.recordLookups, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			processAttributes(p.recordLookups, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeEmpty:
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+)
+
+var lookupTablesID = component.MustNewID("lookup_tables")
+
+func ownersConfig() *Config {
+	return &Config{
+		LookupTables: &lookupTablesID,
+		Lookups: []LookupConfig{
+			{Table: "owners", Key: "service.name", Prefix: "owner."},
+			{Table: "hosts", Context: record, Key: "host.name", Attributes: []string{"site", "rack"}},
+		},
+	}
+}
+
+// lookupTablesExtension provides the tables of the tests, like the lookup_tables extension.
+type lookupTablesExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	tables map[string]*lookuptable.Table
+}
+
+func (e *lookupTablesExtension) Table(name string) (*lookuptable.Table, bool) {
+	table, ok := e.tables[name]
+	return table, ok
+}
+
+type lookupTablesHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *lookupTablesHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func newTable(rows map[string]map[string]any) *lookuptable.Table {
+	table := lookuptable.NewTable()
+	tableRows := make(map[string]pcommon.Map, len(rows))
+	for key, raw := range rows {
+		row := pcommon.NewMap()
+		_ = row.FromRaw(raw)
+		tableRows[key] = row
+	}
+	table.Replace(tableRows)
+	return table
+}
+
+// newHost returns a host with a lookup_tables extension holding the owners and hosts tables.
+func newHost() component.Host {
+	return &lookupTablesHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			lookupTablesID: &lookupTablesExtension{
+				tables: map[string]*lookuptable.Table{
+					"owners": newTable(map[string]map[string]any{
+						"checkout": {"team": "payments", "tier": "1"},
+						"cart":     {"team": "shopping", "tier": "2"},
+					}),
+					"hosts": newTable(map[string]map[string]any{
+						"node-1": {"site": "ams1", "rack": "r12", "cores": 64},
+						"node-2": {"site": "fra1", "rack": "r01", "cores": 32},
+					}),
+				},
+			},
+		},
+	}
+}
+
+func TestProcessAttributes(t *testing.T) {
+	row := pcommon.NewMap()
+	row.PutStr("team", "payments")
+	row.PutInt("tier", 1)
+	table := lookuptable.NewTable()
+	table.Replace(map[string]pcommon.Map{"checkout": row, "42": row})
+
+	tests := []struct {
+		name     string
+		lookup   LookupConfig
+		input    map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "all columns",
+			lookup:   LookupConfig{Key: "service.name"},
+			input:    map[string]any{"service.name": "checkout"},
+			expected: map[string]any{"service.name": "checkout", "team": "payments", "tier": int64(1)},
+		},
+		{
+			name:     "selected columns with prefix",
+			lookup:   LookupConfig{Key: "service.name", Attributes: []string{"team", "missing"}, Prefix: "owner."},
+			input:    map[string]any{"service.name": "checkout"},
+			expected: map[string]any{"service.name": "checkout", "owner.team": "payments"},
+		},
+		{
+			name:     "existing attributes are kept",
+			lookup:   LookupConfig{Key: "service.name"},
+			input:    map[string]any{"service.name": "checkout", "team": "platform"},
+			expected: map[string]any{"service.name": "checkout", "team": "platform", "tier": int64(1)},
+		},
+		{
+			name:     "existing attributes are overwritten",
+			lookup:   LookupConfig{Key: "service.name", Overwrite: true},
+			input:    map[string]any{"service.name": "checkout", "team": "platform"},
+			expected: map[string]any{"service.name": "checkout", "team": "payments", "tier": int64(1)},
+		},
+		{
+			name:     "non string key",
+			lookup:   LookupConfig{Key: "service.id", Attributes: []string{"team"}},
+			input:    map[string]any{"service.id": 42},
+			expected: map[string]any{"service.id": int64(42), "team": "payments"},
+		},
+		{
+			name:     "key not in table",
+			lookup:   LookupConfig{Key: "service.name"},
+			input:    map[string]any{"service.name": "cart"},
+			expected: map[string]any{"service.name": "cart"},
+		},
+		{
+			name:     "missing key attribute",
+			lookup:   LookupConfig{Key: "service.name"},
+			input:    map[string]any{"host.name": "node-1"},
+			expected: map[string]any{"host.name": "node-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := pcommon.NewMap()
+			require.NoError(t, attributes.FromRaw(tt.input))
+			processAttributes([]lookup{{LookupConfig: tt.lookup, table: table}}, attributes)
+			assert.Equal(t, tt.expected, attributes.AsRaw())
+		})
+	}
+}
+
+func TestProcessTraces(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	p, err := NewFactory().CreateTraces(context.Background(), processortest.NewNopSettings(), ownersConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newHost()))
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().Attributes().PutStr("host.name", "node-1")
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	actual := sink.AllTraces()[0].ResourceSpans().At(0)
+	assert.Equal(t, map[string]any{"service.name": "checkout", "owner.team": "payments", "owner.tier": "1"}, actual.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"host.name": "node-1", "site": "ams1", "rack": "r12"}, actual.ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw())
+}
+
+func TestProcessLogs(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	p, err := NewFactory().CreateLogs(context.Background(), processortest.NewNopSettings(), ownersConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newHost()))
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "cart")
+	rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().PutStr("host.name", "node-2")
+	require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+
+	actual := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"service.name": "cart", "owner.team": "shopping", "owner.tier": "2"}, actual.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"host.name": "node-2", "site": "fra1", "rack": "r01"}, actual.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+}
+
+func TestProcessMetrics(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	p, err := NewFactory().CreateMetrics(context.Background(), processortest.NewNopSettings(), ownersConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newHost()))
+	defer func() { require.NoError(t, p.Shutdown(context.Background())) }()
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("host.name", "node-1")
+	metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes().PutStr("host.name", "node-2")
+	metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().PutStr("host.name", "node-1")
+	metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("host.name", "node-2")
+	metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes().PutStr("host.name", "node-1")
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	actual := sink.AllMetrics()[0].ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{"service.name": "checkout", "owner.team": "payments", "owner.tier": "1"}, actual.Resource().Attributes().AsRaw())
+	actualMetrics := actual.ScopeMetrics().At(0).Metrics()
+	sites := []string{"ams1", "fra1", "ams1", "fra1", "ams1"}
+	for i, attributes := range []pcommon.Map{
+		actualMetrics.At(0).Gauge().DataPoints().At(0).Attributes(),
+		actualMetrics.At(1).Sum().DataPoints().At(0).Attributes(),
+		actualMetrics.At(2).Histogram().DataPoints().At(0).Attributes(),
+		actualMetrics.At(3).ExponentialHistogram().DataPoints().At(0).Attributes(),
+		actualMetrics.At(4).Summary().DataPoints().At(0).Attributes(),
+	} {
+		site, ok := attributes.Get("site")
+		require.True(t, ok)
+		assert.Equal(t, sites[i], site.Str())
+	}
+}
+
+func TestStartWithUnknownTable(t *testing.T) {
+	cfg := &Config{
+		LookupTables: &lookupTablesID,
+		Lookups:      []LookupConfig{{Table: "regions", Key: "cloud.region"}},
+	}
+	p := newLookupProcessor(cfg)
+	assert.EqualError(t, p.Start(context.Background(), newHost()), `lookups[0]: unknown table "regions" in extension "lookup_tables"`)
+
+	missingID := component.MustNewIDWithName("lookup_tables", "missing")
+	cfg.LookupTables = &missingID
+	assert.EqualError(t, p.Start(context.Background(), newHost()), `lookup tables extension "lookup_tables/missing" not found`)
+}
@@ -3,6 +3,13 @@
 
 package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
 
+import (
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configopaque"
+)
+
 type Config struct {
 	// AllowAllKeys is a flag to allow all span attribute keys. Setting this
 	// to true disables the AllowedKeys list. The list of BlockedValues is
@@ -23,6 +30,31 @@ type Config struct {
 	// allowed span attributes. Values that match are masked
 	BlockedValues []string `mapstructure:"blocked_values"`
 
+	// MaskMode controls what the parts of the values matching the blocked
+	// values are replaced with. Possible values are `mask` (the default),
+	// replacing them with asterisks, `hmac`, replacing them with the hex
+	// encoded keyed hash of the match, and `tokenize`, replacing them with a
+	// keyed token preserving their format. Hashes and tokens are
+	// deterministic, so records containing the same value can be correlated.
+	MaskMode string `mapstructure:"mask_mode"`
+
+	// HashFunction is the hash function of the HMAC used by the `hmac` and
+	// `tokenize` mask modes. Possible values are `sha256` (the default) and
+	// `sha512`.
+	HashFunction string `mapstructure:"hash_function"`
+
+	// HMACKey is the secret key of the HMAC used by the `hmac` and `tokenize`
+	// mask modes.
+	HMACKey configopaque.String `mapstructure:"hmac_key"`
+
+	// ApplyToLogBody masks the blocked values in the log bodies, including
+	// the string values of map and slice bodies.
+	ApplyToLogBody bool `mapstructure:"apply_to_log_body"`
+
+	// ApplyToSpanEvents applies the allowed, ignored and blocked lists to the
+	// attributes of the span events.
+	ApplyToSpanEvents bool `mapstructure:"apply_to_span_events"`
+
 	// Summary controls the verbosity level of the diagnostic attributes that
 	// the processor adds to the spans when it redacts or masks other
 	// attributes. In some contexts a list of redacted attributes leaks
@@ -30,3 +62,33 @@ type Config struct {
 	// configuration. Possible values are `debug`, `info`, and `silent`.
 	Summary string `mapstructure:"summary"`
 }
+
+const (
+	maskModeMask     = "mask"
+	maskModeHMAC     = "hmac"
+	maskModeTokenize = "tokenize"
+
+	hashFunctionSHA256 = "sha256"
+	hashFunctionSHA512 = "sha512"
+)
+
+var errMissingHMACKey = errors.New("hmac_key must be set when mask_mode is hmac or tokenize")
+
+func (cfg *Config) Validate() error {
+	switch cfg.MaskMode {
+	case "", maskModeMask:
+	case maskModeHMAC, maskModeTokenize:
+		if cfg.HMACKey == "" {
+			return errMissingHMACKey
+		}
+	default:
+		return fmt.Errorf("invalid mask_mode %q, must be %q, %q or %q", cfg.MaskMode, maskModeMask, maskModeHMAC, maskModeTokenize)
+	}
+
+	switch cfg.HashFunction {
+	case "", hashFunctionSHA256, hashFunctionSHA512:
+	default:
+		return fmt.Errorf("invalid hash_function %q, must be %q or %q", cfg.HashFunction, hashFunctionSHA256, hashFunctionSHA512)
+	}
+	return nil
+}
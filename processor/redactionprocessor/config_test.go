@@ -36,6 +36,18 @@ func TestLoadConfig(t *testing.T) {
 			id:       component.NewIDWithName(metadata.Type, "empty"),
 			expected: createDefaultConfig(),
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "hmac"),
+			expected: &Config{
+				AllowAllKeys:      true,
+				BlockedValues:     []string{`[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`},
+				MaskMode:          maskModeHMAC,
+				HashFunction:      hashFunctionSHA512,
+				HMACKey:           "0123456789abcdef",
+				ApplyToLogBody:    true,
+				ApplyToSpanEvents: true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -55,3 +67,48 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *Config
+		err    string
+	}{
+		{
+			name:   "default",
+			config: &Config{},
+		},
+		{
+			name:   "tokenize",
+			config: &Config{MaskMode: maskModeTokenize, HMACKey: "key"},
+		},
+		{
+			name:   "hmac without key",
+			config: &Config{MaskMode: maskModeHMAC},
+			err:    errMissingHMACKey.Error(),
+		},
+		{
+			name:   "invalid mask mode",
+			config: &Config{MaskMode: "encrypt"},
+			err:    `invalid mask_mode "encrypt"`,
+		},
+		{
+			name:   "invalid hash function",
+			config: &Config{MaskMode: maskModeHMAC, HMACKey: "key", HashFunction: "md5"},
+			err:    `invalid hash_function "md5"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
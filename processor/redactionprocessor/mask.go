@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"unicode"
+)
+
+const maskedValue = "****"
+
+// newMaskFunc returns the function replacing the parts of the values matching
+// a blocked value, according to the mask mode of the configuration.
+func newMaskFunc(config *Config) func(string) string {
+	newHash := sha256.New
+	if config.HashFunction == hashFunctionSHA512 {
+		newHash = sha512.New
+	}
+	key := []byte(config.HMACKey)
+
+	switch config.MaskMode {
+	case maskModeHMAC:
+		return func(match string) string {
+			mac := hmac.New(newHash, key)
+			mac.Write([]byte(match))
+			return hex.EncodeToString(mac.Sum(nil))
+		}
+	case maskModeTokenize:
+		return func(match string) string {
+			return tokenize(newHash, key, match)
+		}
+	default:
+		return func(string) string {
+			return maskedValue
+		}
+	}
+}
+
+// tokenize replaces each digit and letter of the match with a digit or a letter
+// of the same case derived from the HMAC of the match, keeping the other
+// characters such as separators. The token has the same format as the match
+// and is the same for all the occurrences of the match, but the match cannot
+// be recovered from it.
+func tokenize(newHash func() hash.Hash, key []byte, match string) string {
+	var stream []byte
+	var block uint32
+	next := func() byte {
+		if len(stream) == 0 {
+			// expand the key stream with the HMAC of a block counter and the match
+			mac := hmac.New(newHash, key)
+			_ = binary.Write(mac, binary.BigEndian, block)
+			mac.Write([]byte(match))
+			stream = mac.Sum(nil)
+			block++
+		}
+		b := stream[0]
+		stream = stream[1:]
+		return b
+	}
+
+	var token strings.Builder
+	token.Grow(len(match))
+	for _, r := range match {
+		switch {
+		case unicode.IsDigit(r):
+			token.WriteByte('0' + next()%10)
+		case unicode.IsUpper(r):
+			token.WriteByte('A' + next()%26)
+		case unicode.IsLetter(r):
+			token.WriteByte('a' + next()%26)
+		default:
+			token.WriteRune(r)
+		}
+	}
+	return token.String()
+}
@@ -24,8 +24,11 @@ type redaction struct {
 	allowList map[string]string
 	// Attribute keys ignored in a span
 	ignoreList map[string]string
-	// Attribute values blocked in a span
-	blockRegexList map[string]*regexp.Regexp
+	// Attribute values blocked in a span, in the order of the configuration
+	// so that the values matching several of them are always masked the same
+	blockRegexList []*regexp.Regexp
+	// Replaces the parts of the values matching a blocked value
+	mask func(string) string
 	// Redaction processor configuration
 	config *Config
 	// Logger
@@ -46,6 +49,7 @@ func newRedaction(ctx context.Context, config *Config, logger *zap.Logger) (*red
 		allowList:      allowList,
 		ignoreList:     ignoreList,
 		blockRegexList: blockRegexList,
+		mask:           newMaskFunc(config),
 		config:         config,
 		logger:         logger,
 	}, nil
@@ -93,6 +97,12 @@ func (s *redaction) processResourceSpan(ctx context.Context, rs ptrace.ResourceS
 
 			// Attributes can also be part of span
 			s.processAttrs(ctx, spanAttrs)
+
+			if s.config.ApplyToSpanEvents {
+				for l := 0; l < span.Events().Len(); l++ {
+					s.processAttrs(ctx, span.Events().At(l).Attributes())
+				}
+			}
 		}
 	}
 }
@@ -109,6 +119,10 @@ func (s *redaction) processResourceLog(ctx context.Context, rl plog.ResourceLogs
 		for k := 0; k < ils.LogRecords().Len(); k++ {
 			log := ils.LogRecords().At(k)
 			s.processAttrs(ctx, log.Attributes())
+
+			if s.config.ApplyToLogBody {
+				s.maskValue(log.Body())
+			}
 		}
 	}
 }
@@ -187,20 +201,8 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 		}
 
 		// Mask any blocked values for the other attributes
-		strVal := value.Str()
-		var matched bool
-		for _, compiledRE := range s.blockRegexList {
-			match := compiledRE.MatchString(strVal)
-			if match {
-				if !matched {
-					matched = true
-					toBlock = append(toBlock, k)
-				}
-
-				maskedValue := compiledRE.ReplaceAllString(strVal, "****")
-				value.SetStr(maskedValue)
-				strVal = maskedValue
-			}
+		if s.maskString(value) {
+			toBlock = append(toBlock, k)
 		}
 		return true
 	})
@@ -215,6 +217,59 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 	s.addMetaAttrs(ignoring, attributes, "", ignoredKeyCount)
 }
 
+// maskString masks the parts of a string value matching the blocked values,
+// returning whether any of them matched. The matches of all the blocked values
+// are found in the original value, and overlapping matches are masked once.
+func (s *redaction) maskString(value pcommon.Value) bool {
+	strVal := value.Str()
+	var matches [][]int
+	for _, compiledRE := range s.blockRegexList {
+		matches = append(matches, compiledRE.FindAllStringIndex(strVal, -1)...)
+	}
+	if len(matches) == 0 {
+		return false
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i][0] == matches[j][0] {
+			return matches[i][1] > matches[j][1]
+		}
+		return matches[i][0] < matches[j][0]
+	})
+
+	var masked strings.Builder
+	end := 0
+	for i := 0; i < len(matches); {
+		start, stop := matches[i][0], matches[i][1]
+		for i++; i < len(matches) && matches[i][0] < stop; i++ {
+			stop = max(stop, matches[i][1])
+		}
+		masked.WriteString(strVal[end:start])
+		masked.WriteString(s.mask(strVal[start:stop]))
+		end = stop
+	}
+	masked.WriteString(strVal[end:])
+	value.SetStr(masked.String())
+	return true
+}
+
+// maskValue masks the blocked values in a log body, recursing through the
+// values of maps and slices
+func (s *redaction) maskValue(value pcommon.Value) {
+	switch value.Type() {
+	case pcommon.ValueTypeStr:
+		s.maskString(value)
+	case pcommon.ValueTypeMap:
+		value.Map().Range(func(_ string, v pcommon.Value) bool {
+			s.maskValue(v)
+			return true
+		})
+	case pcommon.ValueTypeSlice:
+		for i := 0; i < value.Slice().Len(); i++ {
+			s.maskValue(value.Slice().At(i))
+		}
+	}
+}
+
 // addMetaAttrs adds diagnostic information about redacted or masked attribute keys
 func (s *redaction) addMetaAttrs(redactedAttrs []string, attributes pcommon.Map, valuesAttr, countAttr string) {
 	redactedCount := int64(len(redactedAttrs))
@@ -283,15 +338,15 @@ func makeIgnoreList(c *Config) map[string]string {
 }
 
 // makeBlockRegexList precompiles all the blocked regex patterns
-func makeBlockRegexList(_ context.Context, config *Config) (map[string]*regexp.Regexp, error) {
-	blockRegexList := make(map[string]*regexp.Regexp, len(config.BlockedValues))
+func makeBlockRegexList(_ context.Context, config *Config) ([]*regexp.Regexp, error) {
+	blockRegexList := make([]*regexp.Regexp, 0, len(config.BlockedValues))
 	for _, pattern := range config.BlockedValues {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			// TODO: Placeholder for an error metric in the next PR
 			return nil, fmt.Errorf("error compiling regex in block list: %w", err)
 		}
-		blockRegexList[pattern] = re
+		blockRegexList = append(blockRegexList, re)
 	}
 	return blockRegexList, nil
 }
@@ -510,6 +510,164 @@ func TestProcessAttrsAppliedTwice(t *testing.T) {
 	assert.Equal(t, int64(2), val.Int())
 }
 
+// TestMaskModeHMAC validates that the blocked values are replaced with their
+// keyed hash, the same value always giving the same hash
+func TestMaskModeHMAC(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"[a-z]+@example\\.com"},
+		MaskMode:      maskModeHMAC,
+		HMACKey:       "secret",
+	}
+	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("user", "jane@example.com")
+	attrs.PutStr("message", "sent to jane@example.com")
+	processor.processAttrs(context.TODO(), attrs)
+
+	const hash = "fb817989d942e7ffb3d4b8b204f7abca29f4c25c3fa46574da84c50f30d07513"
+	val, _ := attrs.Get("user")
+	assert.Equal(t, hash, val.Str())
+	val, _ = attrs.Get("message")
+	assert.Equal(t, "sent to "+hash, val.Str())
+
+	config.HashFunction = hashFunctionSHA512
+	processor, err = newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	attrs.PutStr("user", "jane@example.com")
+	processor.processAttrs(context.TODO(), attrs)
+	val, _ = attrs.Get("user")
+	assert.Len(t, val.Str(), 128)
+}
+
+// TestMaskModeHMACMultiplePatterns validates that the matches of all the blocked
+// values are found in the original value, and not in the hashes of the previous matches
+func TestMaskModeHMACMultiplePatterns(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"[a-z]+@example\\.com", "[0-9]{4}", "example\\.com"},
+		MaskMode:      maskModeHMAC,
+		HMACKey:       "secret",
+	}
+	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("message", "jane@example.com paid with 1234")
+	processor.processAttrs(context.TODO(), attrs)
+
+	const hash = "fb817989d942e7ffb3d4b8b204f7abca29f4c25c3fa46574da84c50f30d07513"
+	val, _ := attrs.Get("message")
+	assert.Equal(t, hash+" paid with "+processor.mask("1234"), val.Str())
+}
+
+// TestMaskModeTokenize validates that the blocked values are replaced with
+// deterministic tokens of the same format
+func TestMaskModeTokenize(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"4[0-9]{3}-[0-9]{4}-[0-9]{4}-[0-9]{4}", "[A-Za-z]+@example\\.com"},
+		MaskMode:      maskModeTokenize,
+		HMACKey:       "secret",
+	}
+	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("card", "4111-1111-1111-1111")
+	attrs.PutStr("same_card", "4111-1111-1111-1111")
+	attrs.PutStr("other_card", "4111-1111-1111-1112")
+	attrs.PutStr("email", "Jane@example.com")
+	processor.processAttrs(context.TODO(), attrs)
+
+	card, _ := attrs.Get("card")
+	assert.Regexp(t, "^[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{4}$", card.Str())
+	assert.NotEqual(t, "4111-1111-1111-1111", card.Str())
+	sameCard, _ := attrs.Get("same_card")
+	assert.Equal(t, card.Str(), sameCard.Str())
+	otherCard, _ := attrs.Get("other_card")
+	assert.NotEqual(t, card.Str(), otherCard.Str())
+	email, _ := attrs.Get("email")
+	assert.Regexp(t, "^[A-Z][a-z]{3}@[a-z]{7}\\.[a-z]{3}$", email.Str())
+
+	// another key gives other tokens
+	token := card.Str()
+	config.HMACKey = "other secret"
+	processor, err = newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	attrs.PutStr("card", "4111-1111-1111-1111")
+	processor.processAttrs(context.TODO(), attrs)
+	otherKeyCard, _ := attrs.Get("card")
+	assert.NotEqual(t, token, otherKeyCard.Str())
+}
+
+// TestApplyToLogBody validates that the blocked values are masked in the log
+// bodies only when enabled
+func TestApplyToLogBody(t *testing.T) {
+	for _, apply := range []bool{false, true} {
+		config := &Config{
+			AllowAllKeys:   true,
+			BlockedValues:  []string{"4[0-9]{12}(?:[0-9]{3})?"},
+			ApplyToLogBody: apply,
+		}
+		processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+		require.NoError(t, err)
+
+		inLogs := plog.NewLogs()
+		records := inLogs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		records.AppendEmpty().Body().SetStr("paid with 4111111111111111")
+		require.NoError(t, records.AppendEmpty().Body().SetEmptyMap().FromRaw(map[string]any{
+			"card":  "4111111111111111",
+			"cards": []any{"4111111111111111", 42},
+		}))
+
+		outLogs, err := processor.processLogs(context.TODO(), inLogs)
+		require.NoError(t, err)
+
+		records = outLogs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		if !apply {
+			assert.Equal(t, "paid with 4111111111111111", records.At(0).Body().Str())
+			continue
+		}
+		assert.Equal(t, "paid with ****", records.At(0).Body().Str())
+		assert.Equal(t, map[string]any{
+			"card":  "****",
+			"cards": []any{"****", int64(42)},
+		}, records.At(1).Body().Map().AsRaw())
+	}
+}
+
+// TestApplyToSpanEvents validates that the event attributes are redacted and
+// masked like the span attributes when enabled
+func TestApplyToSpanEvents(t *testing.T) {
+	config := &Config{
+		AllowedKeys:       []string{"message"},
+		BlockedValues:     []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Summary:           "info",
+		ApplyToSpanEvents: true,
+	}
+	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	inTraces := ptrace.NewTraces()
+	span := inTraces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	event := span.Events().AppendEmpty()
+	event.Attributes().PutStr("message", "card 4111111111111111 declined")
+	event.Attributes().PutStr("email", "jane@example.com")
+
+	outTraces, err := processor.processTraces(context.TODO(), inTraces)
+	require.NoError(t, err)
+
+	attrs := outTraces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().At(0).Attributes()
+	assert.Equal(t, map[string]any{
+		"message":        "card **** declined",
+		redactedKeyCount: int64(1),
+		maskedValueCount: int64(1),
+	}, attrs.AsRaw())
+}
+
 // runTest transforms the test input data and passes it through the processor
 func runTest(
 	t *testing.T,
@@ -5,46 +5,48 @@ package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import "sync"
 
-// channelSet is a collection of byte channels where adding, removing, and writing to
-// the channels is synchronized.
+// channelSet is a collection of subscriptions where adding, removing, and writing to
+// their channels is synchronized.
 type channelSet struct {
 	i       int
 	mu      sync.RWMutex
-	chanmap map[int]chan []byte
+	chanmap map[int]*subscription
 }
 
 func newChannelSet() *channelSet {
 	return &channelSet{
-		chanmap: map[int]chan []byte{},
+		chanmap: map[int]*subscription{},
 	}
 }
 
-// add adds the channel to the channelSet and returns a key (just an int) used to
-// remove the channel later.
-func (c *channelSet) add(ch chan []byte) int {
+// add adds the subscription to the channelSet and returns a key (just an int) used to
+// remove the subscription later.
+func (c *channelSet) add(s *subscription) int {
 	c.mu.Lock()
 	idx := c.i
-	c.chanmap[idx] = ch
+	c.chanmap[idx] = s
 	c.i++
 	c.mu.Unlock()
 	return idx
 }
 
-// writeBytes writes the passed in bytes to all of the channels in the
-// channelSet.
-func (c *channelSet) writeBytes(bytes []byte) {
+// write calls the passed in function for each subscription of the channelSet and
+// writes the bytes it returns, if any, to the channel of the subscription.
+func (c *channelSet) write(f func(s *subscription) []byte) {
 	c.mu.RLock()
-	for _, ch := range c.chanmap {
-		ch <- bytes
+	for _, s := range c.chanmap {
+		if bytes := f(s); len(bytes) > 0 {
+			s.ch <- bytes
+		}
 	}
 	c.mu.RUnlock()
 }
 
-// closeAndRemove closes then removes the channel associated with the passed in
-// key. Panics if an invalid key is passed in.
+// closeAndRemove closes the channel of the subscription associated with the passed
+// in key then removes it. Panics if an invalid key is passed in.
 func (c *channelSet) closeAndRemove(key int) {
 	c.mu.Lock()
-	close(c.chanmap[key])
+	close(c.chanmap[key].ch)
 	delete(c.chanmap, key)
 	c.mu.Unlock()
 }
@@ -62,8 +64,8 @@ func (c *channelSet) shutdown() {
 		i++
 	}
 
-	for key := range keys {
-		close(c.chanmap[key])
+	for _, key := range keys {
+		close(c.chanmap[key].ch)
 		delete(c.chanmap, key)
 	}
 }
@@ -12,13 +12,15 @@ import (
 
 func TestChannelset(t *testing.T) {
 	cs := newChannelSet()
-	ch := make(chan []byte)
-	key := cs.add(ch)
+	s := &subscription{ch: make(chan []byte)}
+	key := cs.add(s)
 	go func() {
-		cs.writeBytes([]byte("hello"))
+		cs.write(func(*subscription) []byte {
+			return []byte("hello")
+		})
 	}()
 	assert.Eventually(t, func() bool {
-		return assert.Equal(t, []byte("hello"), <-ch)
+		return assert.Equal(t, []byte("hello"), <-s.ch)
 	}, time.Second, time.Millisecond*10)
 	cs.closeAndRemove(key)
 }
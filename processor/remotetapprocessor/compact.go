@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/remotetapprocessor"
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+// The compact format writes a single line of space separated key=value pairs per span,
+// log record or metric data point, followed by the attributes of the record.
+
+func marshalCompactTraces(td ptrace.Traces) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		service := serviceName(rs.Resource())
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				w := newLineWriter(&buf)
+				w.field("time", formatTimestamp(span.StartTimestamp()))
+				w.field("service", service)
+				w.field("trace_id", span.TraceID().String())
+				w.field("span_id", span.SpanID().String())
+				w.field("name", span.Name())
+				w.field("kind", span.Kind().String())
+				w.field("duration", span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()).String())
+				w.field("status", span.Status().Code().String())
+				w.attributes(span.Attributes())
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func marshalCompactLogs(ld plog.Logs) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		service := serviceName(rl.Resource())
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			logs := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				ts := lr.Timestamp()
+				if ts == 0 {
+					ts = lr.ObservedTimestamp()
+				}
+				severity := lr.SeverityText()
+				if severity == "" {
+					severity = lr.SeverityNumber().String()
+				}
+				w := newLineWriter(&buf)
+				w.field("time", formatTimestamp(ts))
+				w.field("service", service)
+				w.field("severity", severity)
+				if !lr.TraceID().IsEmpty() {
+					w.field("trace_id", lr.TraceID().String())
+					w.field("span_id", lr.SpanID().String())
+				}
+				w.field("body", lr.Body().AsString())
+				w.attributes(lr.Attributes())
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func marshalCompactMetrics(md pmetric.Metrics) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		service := serviceName(rm.Resource())
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				writeCompactMetric(&buf, service, metrics.At(k))
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeCompactMetric(buf *bytes.Buffer, service string, metric pmetric.Metric) {
+	header := func(ts pcommon.Timestamp) *lineWriter {
+		w := newLineWriter(buf)
+		w.field("time", formatTimestamp(ts))
+		w.field("service", service)
+		w.field("metric", metric.Name())
+		w.field("type", metric.Type().String())
+		return w
+	}
+	numberDataPoints := func(dps pmetric.NumberDataPointSlice) {
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			w := header(dp.Timestamp())
+			switch dp.ValueType() {
+			case pmetric.NumberDataPointValueTypeInt:
+				w.field("value", strconv.FormatInt(dp.IntValue(), 10))
+			case pmetric.NumberDataPointValueTypeDouble:
+				w.field("value", strconv.FormatFloat(dp.DoubleValue(), 'g', -1, 64))
+			}
+			w.attributes(dp.Attributes())
+		}
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		numberDataPoints(metric.Gauge().DataPoints())
+	case pmetric.MetricTypeSum:
+		numberDataPoints(metric.Sum().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			w := header(dp.Timestamp())
+			w.field("count", strconv.FormatUint(dp.Count(), 10))
+			w.field("sum", strconv.FormatFloat(dp.Sum(), 'g', -1, 64))
+			w.attributes(dp.Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			w := header(dp.Timestamp())
+			w.field("count", strconv.FormatUint(dp.Count(), 10))
+			w.field("sum", strconv.FormatFloat(dp.Sum(), 'g', -1, 64))
+			w.attributes(dp.Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			w := header(dp.Timestamp())
+			w.field("count", strconv.FormatUint(dp.Count(), 10))
+			w.field("sum", strconv.FormatFloat(dp.Sum(), 'g', -1, 64))
+			w.attributes(dp.Attributes())
+		}
+	default:
+		header(0)
+	}
+}
+
+func serviceName(resource pcommon.Resource) string {
+	if v, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		return v.AsString()
+	}
+	return ""
+}
+
+func formatTimestamp(ts pcommon.Timestamp) string {
+	return ts.AsTime().UTC().Format(time.RFC3339Nano)
+}
+
+// lineWriter appends a line of key=value pairs to a buffer, starting a new line
+// if the buffer is not empty.
+type lineWriter struct {
+	buf   *bytes.Buffer
+	first bool
+}
+
+func newLineWriter(buf *bytes.Buffer) *lineWriter {
+	if buf.Len() > 0 {
+		buf.WriteByte('\n')
+	}
+	return &lineWriter{buf: buf, first: true}
+}
+
+func (w *lineWriter) field(key, value string) {
+	if !w.first {
+		w.buf.WriteByte(' ')
+	}
+	w.first = false
+	w.buf.WriteString(key)
+	w.buf.WriteByte('=')
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = strconv.Quote(value)
+	}
+	w.buf.WriteString(value)
+}
+
+func (w *lineWriter) attributes(attrs pcommon.Map) {
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		w.field(k, v.AsString())
+		return true
+	})
+}
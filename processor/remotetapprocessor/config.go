@@ -17,7 +17,7 @@ type Config struct {
 	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	// Limit is a float that indicates the maximum number of messages repeated
-	// through the websocket by this processor in messages per second. Defaults to 1.
+	// through each websocket by this processor in messages per second. Defaults to 1.
 	Limit rate.Limit `mapstructure:"limit"`
 }
 
@@ -20,7 +20,6 @@ import (
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
 	"golang.org/x/net/websocket"
-	"golang.org/x/time/rate"
 )
 
 type wsprocessor struct {
@@ -29,7 +28,6 @@ type wsprocessor struct {
 	server            *http.Server
 	shutdownWG        sync.WaitGroup
 	cs                *channelSet
-	limiter           *rate.Limiter
 }
 
 var (
@@ -43,7 +41,6 @@ func newProcessor(settings processor.Settings, config *Config) *wsprocessor {
 		config:            config,
 		telemetrySettings: settings.TelemetrySettings,
 		cs:                newChannelSet(),
-		limiter:           rate.NewLimiter(config.Limit, int(config.Limit)),
 	}
 }
 
@@ -54,7 +51,7 @@ func (w *wsprocessor) Start(ctx context.Context, host component.Host) error {
 	if err != nil {
 		return fmt.Errorf("failed to bind to address %s: %w", w.config.Endpoint, err)
 	}
-	w.server, err = w.config.ServerConfig.ToServer(ctx, host, w.telemetrySettings, websocket.Server{Handler: w.handleConn})
+	w.server, err = w.config.ServerConfig.ToServer(ctx, host, w.telemetrySettings, http.HandlerFunc(w.handleRequest))
 	if err != nil {
 		return err
 	}
@@ -68,15 +65,28 @@ func (w *wsprocessor) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
-func (w *wsprocessor) handleConn(conn *websocket.Conn) {
+// handleRequest parses the subscription described by the query parameters of the
+// request before upgrading it to a websocket, so that invalid subscriptions are
+// rejected with a meaningful error.
+func (w *wsprocessor) handleRequest(rw http.ResponseWriter, req *http.Request) {
+	sub, err := newSubscription(req.URL.Query(), w.config.Limit, w.telemetrySettings)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	websocket.Server{Handler: func(conn *websocket.Conn) {
+		w.handleConn(conn, sub)
+	}}.ServeHTTP(rw, req)
+}
+
+func (w *wsprocessor) handleConn(conn *websocket.Conn, sub *subscription) {
 	err := conn.SetDeadline(time.Time{})
 	if err != nil {
 		w.telemetrySettings.Logger.Debug("Error setting deadline", zap.Error(err))
 		return
 	}
-	ch := make(chan []byte)
-	idx := w.cs.add(ch)
-	for bytes := range ch {
+	idx := w.cs.add(sub)
+	for bytes := range sub.ch {
 		_, err := conn.Write(bytes)
 		if err != nil {
 			w.telemetrySettings.Logger.Debug("websocket write error: %w", zap.Error(err))
@@ -103,41 +113,68 @@ func (w *wsprocessor) Shutdown(ctx context.Context) error {
 	return err
 }
 
-func (w *wsprocessor) ConsumeMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
-	if w.limiter.Allow() {
-		b, err := metricMarshaler.MarshalMetrics(md)
+func (w *wsprocessor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	w.cs.write(func(s *subscription) []byte {
+		if !s.wants(signalMetrics) {
+			return nil
+		}
+		filtered := s.filterMetrics(ctx, md)
+		if filtered.MetricCount() == 0 || !s.limiter.Allow() {
+			return nil
+		}
+		if s.format == formatCompact {
+			return marshalCompactMetrics(filtered)
+		}
+		b, err := metricMarshaler.MarshalMetrics(filtered)
 		if err != nil {
 			w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-		} else {
-			w.cs.writeBytes(b)
 		}
-	}
+		return b
+	})
 
 	return md, nil
 }
 
-func (w *wsprocessor) ConsumeLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
-	if w.limiter.Allow() {
-		b, err := logMarshaler.MarshalLogs(ld)
+func (w *wsprocessor) ConsumeLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	w.cs.write(func(s *subscription) []byte {
+		if !s.wants(signalLogs) {
+			return nil
+		}
+		filtered := s.filterLogs(ctx, ld)
+		if filtered.LogRecordCount() == 0 || !s.limiter.Allow() {
+			return nil
+		}
+		if s.format == formatCompact {
+			return marshalCompactLogs(filtered)
+		}
+		b, err := logMarshaler.MarshalLogs(filtered)
 		if err != nil {
 			w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-		} else {
-			w.cs.writeBytes(b)
 		}
-	}
+		return b
+	})
 
 	return ld, nil
 }
 
-func (w *wsprocessor) ConsumeTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
-	if w.limiter.Allow() {
-		b, err := traceMarshaler.MarshalTraces(td)
+func (w *wsprocessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	w.cs.write(func(s *subscription) []byte {
+		if !s.wants(signalTraces) {
+			return nil
+		}
+		filtered := s.filterTraces(ctx, td)
+		if filtered.SpanCount() == 0 || !s.limiter.Allow() {
+			return nil
+		}
+		if s.format == formatCompact {
+			return marshalCompactTraces(filtered)
+		}
+		b, err := traceMarshaler.MarshalTraces(filtered)
 		if err != nil {
 			w.telemetrySettings.Logger.Debug("Error serializing to JSON", zap.Error(err))
-		} else {
-			w.cs.writeBytes(b)
 		}
-	}
+		return b
+	})
 
 	return td, nil
 }
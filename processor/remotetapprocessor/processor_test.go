@@ -5,10 +5,13 @@ package remotetapprocessor
 
 import (
 	"context"
+	"net/url"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -38,14 +41,15 @@ func TestConsumeMetrics(t *testing.T) {
 
 			processor := newProcessor(processortest.NewNopSettings(), conf)
 
-			ch := make(chan []byte)
-			idx := processor.cs.add(ch)
+			sub, err := newSubscription(url.Values{}, conf.Limit, componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+			idx := processor.cs.add(sub)
 			receiveNum := 0
 			wg := &sync.WaitGroup{}
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				for range ch {
+				for range sub.ch {
 					receiveNum++
 				}
 			}()
@@ -86,14 +90,15 @@ func TestConsumeLogs(t *testing.T) {
 
 			processor := newProcessor(processortest.NewNopSettings(), conf)
 
-			ch := make(chan []byte)
-			idx := processor.cs.add(ch)
+			sub, err := newSubscription(url.Values{}, conf.Limit, componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+			idx := processor.cs.add(sub)
 			receiveNum := 0
 			wg := &sync.WaitGroup{}
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				for range ch {
+				for range sub.ch {
 					receiveNum++
 				}
 			}()
@@ -135,14 +140,15 @@ func TestConsumeTraces(t *testing.T) {
 
 			processor := newProcessor(processortest.NewNopSettings(), conf)
 
-			ch := make(chan []byte)
-			idx := processor.cs.add(ch)
+			sub, err := newSubscription(url.Values{}, conf.Limit, componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+			idx := processor.cs.add(sub)
 			receiveNum := 0
 			wg := &sync.WaitGroup{}
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				for range ch {
+				for range sub.ch {
 					receiveNum++
 				}
 			}()
@@ -6,6 +6,8 @@ package remotetapprocessor
 import (
 	"context"
 	"net"
+	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -129,3 +131,51 @@ func TestSocketConnectionTraces(t *testing.T) {
 	err = rawConn.Close()
 	require.NoError(t, err)
 }
+
+func TestSocketConnectionFilteredCompactLogs(t *testing.T) {
+	cfg := &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: "localhost:12004",
+		},
+		Limit: 1,
+	}
+	logSink := &consumertest.LogsSink{}
+	processor, err := NewFactory().CreateLogs(context.Background(), processortest.NewNopSettings(), cfg,
+		logSink)
+	require.NoError(t, err)
+	err = processor.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	query := url.Values{
+		signalParam: {signalLogs},
+		filterParam: {`body == "bar"`},
+		formatParam: {formatCompact},
+	}
+	resp, err := http.Get("http://localhost:12004/?" + url.Values{formatParam: {"yaml"}}.Encode())
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	rawConn, err := net.Dial("tcp", "localhost:12004")
+	require.NoError(t, err)
+	wsConfig, err := websocket.NewConfig("ws://localhost:12004/?"+query.Encode(), "http://localhost:12004")
+	require.NoError(t, err)
+	wsConn, err := websocket.NewClient(wsConfig, rawConn)
+	require.NoError(t, err)
+	log := plog.NewLogs()
+	logs := log.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	logs.AppendEmpty().Body().SetStr("foo")
+	logs.AppendEmpty().Body().SetStr("bar")
+	var msg string
+	require.Eventuallyf(t, func() bool {
+		err = processor.ConsumeLogs(context.Background(), log)
+		require.NoError(t, err)
+		return websocket.Message.Receive(wsConn, &msg) == nil
+	}, 1*time.Second, 100*time.Millisecond, "received message")
+	require.Equal(t, `time=1970-01-01T00:00:00Z service="" severity=Unspecified body=bar`, msg)
+
+	err = processor.Shutdown(context.Background())
+	require.NoError(t, err)
+	err = rawConn.Close()
+	require.NoError(t, err)
+}
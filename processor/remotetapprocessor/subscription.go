@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package remotetapprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/remotetapprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"golang.org/x/time/rate"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+)
+
+const (
+	// query parameters of the websocket URL describing a subscription
+	signalParam = "signal"
+	filterParam = "filter"
+	formatParam = "format"
+
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+	signalLogs    = "logs"
+
+	formatOTLP    = "otlp"
+	formatCompact = "compact"
+)
+
+var errFilterWithoutSignal = errors.New("a filter requires the signal to be set")
+
+// subscription holds what a websocket client asked to receive: the signal, the
+// conditions the records must match and the format they are written in. Each
+// subscription is rate limited independently of the others.
+type subscription struct {
+	signal string
+	format string
+
+	spanConditions   *ottl.ConditionSequence[ottlspan.TransformContext]
+	metricConditions *ottl.ConditionSequence[ottlmetric.TransformContext]
+	logConditions    *ottl.ConditionSequence[ottllog.TransformContext]
+
+	limiter *rate.Limiter
+	ch      chan []byte
+}
+
+// newSubscription parses the query parameters of the websocket URL. All the signals
+// are streamed as OTLP JSON when no parameter is set. Several filters may be set, a
+// record is streamed if it matches any of them.
+func newSubscription(query url.Values, limit rate.Limit, set component.TelemetrySettings) (*subscription, error) {
+	s := &subscription{
+		signal:  query.Get(signalParam),
+		format:  query.Get(formatParam),
+		limiter: rate.NewLimiter(limit, max(1, int(limit))),
+		ch:      make(chan []byte),
+	}
+
+	switch s.format {
+	case "":
+		s.format = formatOTLP
+	case formatOTLP, formatCompact:
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected %q or %q", s.format, formatOTLP, formatCompact)
+	}
+
+	filters := query[filterParam]
+	var err error
+	switch s.signal {
+	case "":
+		if len(filters) > 0 {
+			return nil, errFilterWithoutSignal
+		}
+	case signalTraces:
+		if len(filters) > 0 {
+			s.spanConditions, err = filterottl.NewBoolExprForSpan(filters, filterottl.StandardSpanFuncs(), ottl.IgnoreError, set)
+		}
+	case signalMetrics:
+		if len(filters) > 0 {
+			s.metricConditions, err = filterottl.NewBoolExprForMetric(filters, filterottl.StandardMetricFuncs(), ottl.IgnoreError, set)
+		}
+	case signalLogs:
+		if len(filters) > 0 {
+			s.logConditions, err = filterottl.NewBoolExprForLog(filters, filterottl.StandardLogFuncs(), ottl.IgnoreError, set)
+		}
+	default:
+		return nil, fmt.Errorf("unknown signal %q, expected %q, %q or %q", s.signal, signalTraces, signalMetrics, signalLogs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	return s, nil
+}
+
+func (s *subscription) wants(signal string) bool {
+	return s.signal == "" || s.signal == signal
+}
+
+// filterTraces returns the spans matching the conditions of the subscription.
+func (s *subscription) filterTraces(ctx context.Context, td ptrace.Traces) ptrace.Traces {
+	if s.spanConditions == nil {
+		return td
+	}
+	filtered := ptrace.NewTraces()
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		filteredRS := ptrace.NewResourceSpans()
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			filteredSS := ptrace.NewScopeSpans()
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				if ok, _ := s.spanConditions.Eval(ctx, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs)); ok {
+					span.CopyTo(filteredSS.Spans().AppendEmpty())
+				}
+			}
+			if filteredSS.Spans().Len() > 0 {
+				ss.Scope().CopyTo(filteredSS.Scope())
+				filteredSS.SetSchemaUrl(ss.SchemaUrl())
+				filteredSS.MoveTo(filteredRS.ScopeSpans().AppendEmpty())
+			}
+		}
+		if filteredRS.ScopeSpans().Len() > 0 {
+			rs.Resource().CopyTo(filteredRS.Resource())
+			filteredRS.SetSchemaUrl(rs.SchemaUrl())
+			filteredRS.MoveTo(filtered.ResourceSpans().AppendEmpty())
+		}
+	}
+	return filtered
+}
+
+// filterMetrics returns the metrics matching the conditions of the subscription.
+func (s *subscription) filterMetrics(ctx context.Context, md pmetric.Metrics) pmetric.Metrics {
+	if s.metricConditions == nil {
+		return md
+	}
+	filtered := pmetric.NewMetrics()
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		filteredRM := pmetric.NewResourceMetrics()
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			filteredSM := pmetric.NewScopeMetrics()
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				metric := sm.Metrics().At(k)
+				if ok, _ := s.metricConditions.Eval(ctx, ottlmetric.NewTransformContext(metric, sm.Metrics(), sm.Scope(), rm.Resource(), sm, rm)); ok {
+					metric.CopyTo(filteredSM.Metrics().AppendEmpty())
+				}
+			}
+			if filteredSM.Metrics().Len() > 0 {
+				sm.Scope().CopyTo(filteredSM.Scope())
+				filteredSM.SetSchemaUrl(sm.SchemaUrl())
+				filteredSM.MoveTo(filteredRM.ScopeMetrics().AppendEmpty())
+			}
+		}
+		if filteredRM.ScopeMetrics().Len() > 0 {
+			rm.Resource().CopyTo(filteredRM.Resource())
+			filteredRM.SetSchemaUrl(rm.SchemaUrl())
+			filteredRM.MoveTo(filtered.ResourceMetrics().AppendEmpty())
+		}
+	}
+	return filtered
+}
+
+// filterLogs returns the log records matching the conditions of the subscription.
+func (s *subscription) filterLogs(ctx context.Context, ld plog.Logs) plog.Logs {
+	if s.logConditions == nil {
+		return ld
+	}
+	filtered := plog.NewLogs()
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		filteredRL := plog.NewResourceLogs()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			filteredSL := plog.NewScopeLogs()
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				if ok, _ := s.logConditions.Eval(ctx, ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource(), sl, rl)); ok {
+					lr.CopyTo(filteredSL.LogRecords().AppendEmpty())
+				}
+			}
+			if filteredSL.LogRecords().Len() > 0 {
+				sl.Scope().CopyTo(filteredSL.Scope())
+				filteredSL.SetSchemaUrl(sl.SchemaUrl())
+				filteredSL.MoveTo(filteredRL.ScopeLogs().AppendEmpty())
+			}
+		}
+		if filteredRL.ScopeLogs().Len() > 0 {
+			rl.Resource().CopyTo(filteredRL.Resource())
+			filteredRL.SetSchemaUrl(rl.SchemaUrl())
+			filteredRL.MoveTo(filtered.ResourceLogs().AppendEmpty())
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package remotetapprocessor
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestNewSubscription(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		signal string
+		format string
+		err    string
+	}{
+		{
+			name:   "defaults",
+			format: formatOTLP,
+		},
+		{
+			name:   "filtered traces",
+			query:  `signal=traces&format=compact&filter=` + url.QueryEscape(`resource.attributes["service.name"] == "checkout"`),
+			signal: signalTraces,
+			format: formatCompact,
+		},
+		{
+			name:   "several filters",
+			query:  `signal=logs&filter=` + url.QueryEscape(`severity_number >= SEVERITY_NUMBER_WARN`) + `&filter=` + url.QueryEscape(`IsMatch(body, "timeout")`),
+			signal: signalLogs,
+			format: formatOTLP,
+		},
+		{
+			name:   "filtered metrics",
+			query:  `signal=metrics&filter=` + url.QueryEscape(`name == "http.server.duration"`),
+			signal: signalMetrics,
+			format: formatOTLP,
+		},
+		{
+			name:  "unknown signal",
+			query: "signal=profiles",
+			err:   `unknown signal "profiles"`,
+		},
+		{
+			name:  "unknown format",
+			query: "format=yaml",
+			err:   `unknown format "yaml"`,
+		},
+		{
+			name:  "filter without signal",
+			query: "filter=" + url.QueryEscape(`name == "foo"`),
+			err:   errFilterWithoutSignal.Error(),
+		},
+		{
+			name:  "invalid filter",
+			query: "signal=traces&filter=" + url.QueryEscape(`name ==`),
+			err:   "invalid filter",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			s, err := newSubscription(query, 1, componenttest.NewNopTelemetrySettings())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.signal, s.signal)
+			assert.Equal(t, tt.format, s.format)
+		})
+	}
+}
+
+func TestSubscriptionFractionalLimit(t *testing.T) {
+	s, err := newSubscription(url.Values{}, 0.5, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.limiter.Burst())
+	assert.True(t, s.limiter.Allow())
+	assert.False(t, s.limiter.Allow())
+}
+
+func TestSubscriptionFilterTraces(t *testing.T) {
+	query := url.Values{
+		signalParam: {signalTraces},
+		filterParam: {`resource.attributes["service.name"] == "checkout"`, `name == "rare"`},
+	}
+	s, err := newSubscription(query, 1, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for _, service := range []string{"frontend", "checkout"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName("common")
+		spans.AppendEmpty().SetName("rare")
+	}
+
+	filtered := s.filterTraces(context.Background(), td)
+	require.Equal(t, 3, filtered.SpanCount())
+	require.Equal(t, 2, filtered.ResourceSpans().Len())
+	frontend := filtered.ResourceSpans().At(0)
+	assert.Equal(t, 1, frontend.ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, "rare", frontend.ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, 2, filtered.ResourceSpans().At(1).ScopeSpans().At(0).Spans().Len())
+	assert.Equal(t, 4, td.SpanCount())
+}
+
+func TestSubscriptionFilterMetrics(t *testing.T) {
+	query := url.Values{
+		signalParam: {signalMetrics},
+		filterParam: {`name == "bar"`},
+	}
+	s, err := newSubscription(query, 1, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetName("foo")
+	metrics.AppendEmpty().SetName("bar")
+
+	filtered := s.filterMetrics(context.Background(), md)
+	require.Equal(t, 1, filtered.MetricCount())
+	assert.Equal(t, "bar", filtered.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+}
+
+func TestSubscriptionFilterLogs(t *testing.T) {
+	query := url.Values{
+		signalParam: {signalLogs},
+		filterParam: {`severity_number >= SEVERITY_NUMBER_WARN`},
+	}
+	s, err := newSubscription(query, 1, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	logs.AppendEmpty().SetSeverityNumber(plog.SeverityNumberInfo)
+	logs.AppendEmpty().SetSeverityNumber(plog.SeverityNumberError)
+
+	filtered := s.filterLogs(context.Background(), ld)
+	require.Equal(t, 1, filtered.LogRecordCount())
+	assert.Equal(t, plog.SeverityNumberError, filtered.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).SeverityNumber())
+
+	none := s.filterLogs(context.Background(), plog.NewLogs())
+	assert.Equal(t, 0, none.LogRecordCount())
+}
+
+func TestMarshalCompact(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	span := spans.AppendEmpty()
+	span.SetName("GET /cart")
+	span.SetTraceID(pcommon.TraceID{1})
+	span.SetSpanID(pcommon.SpanID{2})
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(1500 * time.Millisecond)))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Attributes().PutInt("http.status_code", 500)
+	spans.AppendEmpty().SetName("empty")
+	assert.Equal(t,
+		`time=2024-05-01T10:00:00Z service=checkout trace_id=01000000000000000000000000000000 span_id=0200000000000000 name="GET /cart" kind=Server duration=1.5s status=Error http.status_code=500`+"\n"+
+			`time=1970-01-01T00:00:00Z service=checkout trace_id="" span_id="" name=empty kind=Unspecified duration=0s status=Unset`,
+		string(marshalCompactTraces(td)))
+
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(start))
+	lr.SetSeverityText("WARN")
+	lr.Body().SetStr(`payment "declined"`)
+	lr.Attributes().PutStr("user", "42")
+	assert.Equal(t,
+		`time=2024-05-01T10:00:00Z service="" severity=WARN body="payment \"declined\"" user=42`,
+		string(marshalCompactLogs(ld)))
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	dp := sum.SetEmptySum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetIntValue(12)
+	dp.Attributes().PutStr("route", "/cart")
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(start))
+	hdp.SetCount(3)
+	hdp.SetSum(0.25)
+	assert.Equal(t,
+		`time=2024-05-01T10:00:00Z service="" metric=requests type=Sum value=12 route=/cart`+"\n"+
+			`time=2024-05-01T10:00:00Z service="" metric=latency type=Histogram count=3 sum=0.25`,
+		string(marshalCompactMetrics(md)))
+}
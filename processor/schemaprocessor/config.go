@@ -24,9 +24,16 @@ type Config struct {
 	// PreCache is a list of schema URLs that are downloaded
 	// and cached at the start of the collector runtime
 	// in order to avoid fetching data that later on could
-	// block processing of signals. (Optional field)
+	// block processing of signals. Entries using the `file://`
+	// scheme are read from the local file system and used for the
+	// schema URL declared within the file. (Optional field)
 	Prefetch []string `mapstructure:"prefetch"`
 
+	// CacheDirectory is a local directory that is checked for
+	// schema files before fetching them over the network, any
+	// fetched schema file is stored within it. (Optional field)
+	CacheDirectory string `mapstructure:"cache_directory"`
+
 	// Targets define what schema families should be
 	// translated to, allowing older and newer formats
 	// to conform to the target schema identifier.
@@ -35,6 +42,12 @@ type Config struct {
 
 func (c *Config) Validate() error {
 	for _, schemaURL := range c.Prefetch {
+		if translation.IsFileURL(schemaURL) {
+			if _, err := translation.FilePathFromURL(schemaURL); err != nil {
+				return err
+			}
+			continue
+		}
 		_, _, err := translation.GetFamilyAndVersion(schemaURL)
 		if err != nil {
 			return err
@@ -34,7 +34,9 @@ func TestLoadConfig(t *testing.T) {
 		ClientConfig: confighttp.NewDefaultClientConfig(),
 		Prefetch: []string{
 			"https://opentelemetry.io/schemas/1.9.0",
+			"file:///etc/otelcol/schemas/example.yaml",
 		},
+		CacheDirectory: "/var/lib/otelcol/schemas",
 		Targets: []string{
 			"https://opentelemetry.io/schemas/1.4.2",
 			"https://example.com/otel/schemas/1.2.0",
@@ -86,3 +88,32 @@ func TestConfigurationValidation(t *testing.T) {
 		assert.ErrorIs(t, component.ValidateConfig(cfg), tc.expectError, tc.scenario)
 	}
 }
+
+func TestConfigurationPrefetchValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		scenario    string
+		prefetch    []string
+		expectError bool
+	}{
+		{scenario: "Remote schema url", prefetch: []string{"https://opentelemetry.io/schemas/1.9.0"}},
+		{scenario: "Local schema file", prefetch: []string{"file:///etc/otelcol/schemas/1.9.0.yaml"}},
+		{scenario: "Empty local schema file", prefetch: []string{"file://"}, expectError: true},
+		{scenario: "Incomplete schema url", prefetch: []string{"opentelemetry.io/schemas/1.9.0"}, expectError: true},
+	}
+
+	for _, tc := range tests {
+		cfg := &Config{
+			Prefetch: tc.prefetch,
+			Targets:  []string{"https://opentelemetry.io/schemas/1.9.0"},
+		}
+
+		err := component.ValidateConfig(cfg)
+		if tc.expectError {
+			assert.Error(t, err, tc.scenario)
+			continue
+		}
+		assert.NoError(t, err, tc.scenario)
+	}
+}
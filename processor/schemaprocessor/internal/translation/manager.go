@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// minRetryBackoff and maxRetryBackoff bound the time to wait before retrying
+	// to load a schema file that could not be loaded, the backoff doubles on each failure.
+	minRetryBackoff = 10 * time.Second
+	maxRetryBackoff = 10 * time.Minute
+	// defaultLoadTimeout bounds the time to retrieve a schema file. The file is retrieved
+	// independently of the requests waiting for it, so it is not bound by their context.
+	defaultLoadTimeout = time.Minute
+)
+
+var errNilProviders = errors.New("no providers defined")
+
+// Manager is responsible for ensuring that schemas are kept up to date
+// with the most recent version that are requested.
+type Manager interface {
+	// RequestTranslation will provide either the defined Translation
+	// if it is a known target, or, return a noop variation.
+	// In the event that a matched Translation, on a missed version
+	// there is a potential to block during this process.
+	// Otherwise, the translation will allow concurrent reads.
+	RequestTranslation(ctx context.Context, schemaURL string) Translation
+
+	// SetProviders will update the list of providers used by the manager
+	// to look up schema URLs, the providers are tried in the order provided.
+	SetProviders(providers ...Provider) error
+}
+
+type manager struct {
+	log *zap.Logger
+
+	rw           sync.RWMutex
+	providers    []Provider
+	match        map[string]*Version
+	translations map[string]*translator
+	failures     map[string]*loadFailure
+
+	// loads ensures that a schema file is only retrieved once at a time,
+	// without holding the lock while it is retrieved.
+	loads       singleflight.Group
+	loadTimeout time.Duration
+	now         func() time.Time
+}
+
+// loadFailure records when a schema file that could not be loaded can be retried.
+type loadFailure struct {
+	retryAt time.Time
+	backoff time.Duration
+}
+
+var _ Manager = (*manager)(nil)
+
+// NewManager creates a manager that will allow for management
+// of schema, the options allow for additional properties to be
+// added to manager to enable additional locations of where to check
+// for translations file.
+func NewManager(targets []string, log *zap.Logger, providers ...Provider) (Manager, error) {
+	match := make(map[string]*Version, len(targets))
+	for _, target := range targets {
+		family, version, err := GetFamilyAndVersion(target)
+		if err != nil {
+			return nil, err
+		}
+		match[family] = version
+	}
+
+	return &manager{
+		log:          log,
+		providers:    providers,
+		match:        match,
+		translations: make(map[string]*translator),
+		failures:     make(map[string]*loadFailure),
+		loadTimeout:  defaultLoadTimeout,
+		now:          time.Now,
+	}, nil
+}
+
+func (m *manager) RequestTranslation(ctx context.Context, schemaURL string) Translation {
+	family, version, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		m.log.Debug("No valid schema url was provided, using no-op schema",
+			zap.String("schema-url", schemaURL),
+		)
+		return nopTranslation{}
+	}
+
+	target, match := m.match[family]
+	if !match {
+		m.log.Debug("Not a known target, providing Nop Translation",
+			zap.String("schema-url", schemaURL),
+		)
+		return nopTranslation{}
+	}
+
+	// The newest schema file of both versions contains the
+	// revisions required to translate in either direction.
+	fileVersion := target
+	if version.GreaterThan(target) {
+		fileVersion = version
+	}
+	fileSchemaURL := joinSchemaFamilyAndVersion(family, fileVersion)
+
+	m.rw.RLock()
+	t, exist := m.translations[fileSchemaURL]
+	failure, failed := m.failures[fileSchemaURL]
+	m.rw.RUnlock()
+	if exist {
+		return t
+	}
+	if failed && m.now().Before(failure.retryAt) {
+		return nopTranslation{}
+	}
+
+	loaded := m.loads.DoChan(fileSchemaURL, func() (any, error) {
+		// The schema file is shared by all the requests waiting for it,
+		// so cancelling the first of them must not fail the others.
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.loadTimeout)
+		defer cancel()
+		return m.load(loadCtx, family, target, fileSchemaURL), nil
+	})
+	select {
+	case res := <-loaded:
+		return res.Val.(Translation)
+	case <-ctx.Done():
+		return nopTranslation{}
+	}
+}
+
+// load retrieves the schema file and creates the translator to the target version.
+// The file is not retrieved again before the backoff expires when it cannot be loaded.
+func (m *manager) load(ctx context.Context, family string, target *Version, fileSchemaURL string) Translation {
+	m.rw.RLock()
+	t, exist := m.translations[fileSchemaURL]
+	m.rw.RUnlock()
+	// Another request could have loaded the schema file before this one started.
+	if exist {
+		return t
+	}
+
+	content, err := m.retrieve(ctx, fileSchemaURL)
+	if err != nil {
+		m.log.Error("Failed to retrieve schema file",
+			zap.String("schema-url", fileSchemaURL),
+			zap.Error(err),
+		)
+		m.recordFailure(fileSchemaURL)
+		return nopTranslation{}
+	}
+
+	targetSchemaURL := joinSchemaFamilyAndVersion(family, target)
+	t, err = newTranslatorFromReader(
+		m.log.Named("translator").With(
+			zap.String("family", family),
+			zap.Stringer("target", target),
+		),
+		targetSchemaURL,
+		strings.NewReader(content),
+	)
+	if err != nil {
+		log := m.log.Error
+		if errors.Is(err, ErrUnsupportedVersion) {
+			// The data is passed through unmodified until the schema file defines the target.
+			log = m.log.Debug
+		}
+		log("Failed to create translator from schema file",
+			zap.String("schema-url", fileSchemaURL),
+			zap.Error(err),
+		)
+		m.recordFailure(fileSchemaURL)
+		return nopTranslation{}
+	}
+
+	m.rw.Lock()
+	m.translations[fileSchemaURL] = t
+	delete(m.failures, fileSchemaURL)
+	m.rw.Unlock()
+	return t
+}
+
+func (m *manager) recordFailure(fileSchemaURL string) {
+	m.rw.Lock()
+	defer m.rw.Unlock()
+
+	failure, ok := m.failures[fileSchemaURL]
+	if !ok {
+		failure = &loadFailure{backoff: minRetryBackoff}
+		m.failures[fileSchemaURL] = failure
+	} else {
+		failure.backoff = min(2*failure.backoff, maxRetryBackoff)
+	}
+	failure.retryAt = m.now().Add(failure.backoff)
+}
+
+func (m *manager) SetProviders(providers ...Provider) error {
+	if len(providers) == 0 {
+		return errNilProviders
+	}
+	m.rw.Lock()
+	m.providers = append([]Provider(nil), providers...)
+	// The schema files that could not be loaded may be provided now.
+	clear(m.failures)
+	m.rw.Unlock()
+	return nil
+}
+
+func (m *manager) retrieve(ctx context.Context, schemaURL string) (string, error) {
+	m.rw.RLock()
+	providers := m.providers
+	m.rw.RUnlock()
+
+	if len(providers) == 0 {
+		return "", errNilProviders
+	}
+	for _, p := range providers {
+		content, err := p.Retrieve(ctx, schemaURL)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return content, err
+	}
+	return "", fmt.Errorf("schema url %q: %w", schemaURL, ErrNotFound)
+}
+
+func joinSchemaFamilyAndVersion(family string, version *Version) string {
+	return strings.TrimSuffix(family, "/") + "/" + version.String()
+}
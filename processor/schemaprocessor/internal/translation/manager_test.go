@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/fixture"
+)
+
+type countingProvider struct {
+	Provider
+	requests []string
+}
+
+func (cp *countingProvider) Retrieve(ctx context.Context, schemaURL string) (string, error) {
+	cp.requests = append(cp.requests, schemaURL)
+	return cp.Provider.Retrieve(ctx, schemaURL)
+}
+
+func newTestFileProvider(t *testing.T) Provider {
+	t.Helper()
+
+	p, err := NewFileProvider(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewManagerInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewManager([]string{"example.com/schemas/1.0.0"}, zaptest.NewLogger(t))
+	assert.ErrorIs(t, err, ErrInvalidFamily)
+}
+
+func TestManagerRequestTranslation(t *testing.T) {
+	t.Parallel()
+
+	provider := &countingProvider{Provider: newTestFileProvider(t)}
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		provider,
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		schemaURL string
+		expectNop bool
+	}{
+		{name: "invalid schema url", schemaURL: "not a url", expectNop: true},
+		{name: "unknown family", schemaURL: "https://opentelemetry.io/schemas/1.9.0", expectNop: true},
+		{name: "older version", schemaURL: "https://example.com/schemas/1.0.0"},
+		{name: "target version", schemaURL: "https://example.com/schemas/1.1.0"},
+		{name: "newer version", schemaURL: "https://example.com/schemas/1.2.0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tn := m.RequestTranslation(context.Background(), tc.schemaURL)
+			if tc.expectNop {
+				assert.IsType(t, nopTranslation{}, tn)
+				return
+			}
+			assert.IsType(t, &translator{}, tn)
+		})
+	}
+
+	// Older versions and the target are translated using the target schema file,
+	// newer versions require the newer schema file.
+	assert.Equal(t, []string{
+		"https://example.com/schemas/1.1.0",
+		"https://example.com/schemas/1.2.0",
+	}, provider.requests)
+}
+
+func TestManagerMissingSchemaFile(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManager([]string{"https://example.com/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	tn := m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0")
+	assert.IsType(t, nopTranslation{}, tn, "Must return nop translation without providers")
+
+	require.NoError(t, m.SetProviders(newTestFileProvider(t)))
+	tn = m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0")
+	assert.IsType(t, &translator{}, tn, "Must use the updated providers")
+
+	assert.ErrorIs(t, m.SetProviders(), errNilProviders)
+}
+
+func TestManagerProviderFallback(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "example.com", "schemas"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.com", "schemas", "1.2.0"), content, 0o600))
+
+	empty, err := NewFileProvider()
+	require.NoError(t, err)
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.2.0"},
+		zaptest.NewLogger(t),
+		empty,
+		NewCacheProvider(dir, nil, zaptest.NewLogger(t)),
+	)
+	require.NoError(t, err)
+	assert.IsType(t, &translator{}, m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0"))
+}
+
+func TestManagerConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		newTestFileProvider(t),
+	)
+	require.NoError(t, err)
+
+	fixture.ParallelRaceCompute(t, 10, func() error {
+		m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0")
+		return nil
+	})
+}
+
+type providerFunc func(ctx context.Context, schemaURL string) (string, error)
+
+func (f providerFunc) Retrieve(ctx context.Context, schemaURL string) (string, error) {
+	return f(ctx, schemaURL)
+}
+
+func TestManagerRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	var requests int
+	retrieveErr := errors.New("connection refused")
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		providerFunc(func(context.Context, string) (string, error) {
+			requests++
+			if retrieveErr != nil {
+				return "", retrieveErr
+			}
+			return string(content), nil
+		}),
+	)
+	require.NoError(t, err)
+	now := time.Now()
+	m.(*manager).now = func() time.Time { return now }
+
+	request := func() Translation {
+		return m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0")
+	}
+
+	assert.IsType(t, nopTranslation{}, request())
+	assert.IsType(t, nopTranslation{}, request())
+	assert.Equal(t, 1, requests, "Must not retrieve the schema file again before the backoff expires")
+
+	now = now.Add(minRetryBackoff)
+	assert.IsType(t, nopTranslation{}, request())
+	assert.Equal(t, 2, requests)
+
+	now = now.Add(minRetryBackoff)
+	assert.IsType(t, nopTranslation{}, request())
+	assert.Equal(t, 2, requests, "Must double the backoff")
+
+	retrieveErr = nil
+	now = now.Add(minRetryBackoff)
+	assert.IsType(t, &translator{}, request())
+	assert.IsType(t, &translator{}, request())
+	assert.Equal(t, 3, requests)
+}
+
+func TestManagerRetrieveWithoutLock(t *testing.T) {
+	t.Parallel()
+
+	fileProvider := newTestFileProvider(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		providerFunc(func(ctx context.Context, schemaURL string) (string, error) {
+			if schemaURL == "https://example.com/schemas/1.2.0" {
+				close(started)
+				<-release
+			}
+			return fileProvider.Retrieve(ctx, schemaURL)
+		}),
+	)
+	require.NoError(t, err)
+
+	done := make(chan Translation)
+	go func() {
+		done <- m.RequestTranslation(context.Background(), "https://example.com/schemas/1.2.0")
+	}()
+	<-started
+
+	// Other schema files are loaded while a slow schema file is retrieved.
+	assert.IsType(t, &translator{}, m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0"))
+
+	close(release)
+	assert.IsType(t, &translator{}, <-done)
+}
+
+func TestManagerLoadIsDetachedFromTheRequest(t *testing.T) {
+	t.Parallel()
+
+	fileProvider := newTestFileProvider(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		providerFunc(func(ctx context.Context, schemaURL string) (string, error) {
+			close(started)
+			<-release
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+			return fileProvider.Retrieve(ctx, schemaURL)
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan Translation)
+	go func() {
+		first <- m.RequestTranslation(ctx, "https://example.com/schemas/1.0.0")
+	}()
+	<-started
+	second := make(chan Translation)
+	go func() {
+		second <- m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0")
+	}()
+
+	// The first request gives up, the schema file is still loaded for the second one.
+	cancel()
+	assert.IsType(t, nopTranslation{}, <-first)
+	close(release)
+	assert.IsType(t, &translator{}, <-second)
+}
+
+func TestManagerLoadTimeout(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	m, err := NewManager(
+		[]string{"https://example.com/schemas/1.1.0"},
+		zaptest.NewLogger(t),
+		providerFunc(func(ctx context.Context, _ string) (string, error) {
+			requests++
+			<-ctx.Done()
+			return "", ctx.Err()
+		}),
+	)
+	require.NoError(t, err)
+	m.(*manager).loadTimeout = time.Millisecond
+
+	assert.IsType(t, nopTranslation{}, m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0"))
+	assert.IsType(t, nopTranslation{}, m.RequestTranslation(context.Background(), "https://example.com/schemas/1.0.0"))
+	assert.Equal(t, 1, requests, "Must not retrieve the schema file again before the backoff expires")
+}
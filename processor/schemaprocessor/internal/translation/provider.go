@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	schema "go.opentelemetry.io/otel/schema/v1.0"
+	"go.uber.org/zap"
+)
+
+// ErrNotFound is returned by a Provider that is unable
+// to find the schema file for the requested schema URL,
+// allowing the next configured provider to be tried.
+var ErrNotFound = errors.New("schema file not found")
+
+// Provider allows for retrieving the content of a schema file
+// for a given schema URL from different sources.
+type Provider interface {
+	// Retrieve returns the content of the schema file
+	// that is published for the schema URL.
+	Retrieve(ctx context.Context, schemaURL string) (string, error)
+}
+
+type httpProvider struct {
+	client *http.Client
+}
+
+var _ Provider = (*httpProvider)(nil)
+
+// NewHTTPProvider returns a Provider that fetches
+// the schema file from the schema URL using the client.
+func NewHTTPProvider(client *http.Client) Provider {
+	return &httpProvider{client: client}
+}
+
+func (hp *httpProvider) Retrieve(ctx context.Context, schemaURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := hp.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d fetching %q", resp.StatusCode, schemaURL)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+type schemaFile struct {
+	content  string
+	versions map[Version]struct{}
+}
+
+type fileProvider struct {
+	// files maps the schema family to the schema files loaded for it.
+	files map[string][]schemaFile
+}
+
+var _ Provider = (*fileProvider)(nil)
+
+// NewFileProvider reads the schema files referenced by the provided
+// paths (either plain paths or `file://` URLs) and serves their content
+// for any schema URL of the family declared in the `schema_url` field
+// of the file, as long as the file defines the requested version.
+func NewFileProvider(paths ...string) (Provider, error) {
+	fp := &fileProvider{files: make(map[string][]schemaFile, len(paths))}
+	for _, p := range paths {
+		filename, err := FilePathFromURL(p)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read schema file %q: %w", filename, err)
+		}
+		parsed, err := schema.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse schema file %q: %w", filename, err)
+		}
+		family, _, err := GetFamilyAndVersion(parsed.SchemaURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema url in schema file %q: %w", filename, err)
+		}
+		sf := schemaFile{
+			content:  string(content),
+			versions: make(map[Version]struct{}, len(parsed.Versions)),
+		}
+		for key := range parsed.Versions {
+			ver, err := NewVersion(string(key))
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %q in schema file %q: %w", key, filename, err)
+			}
+			sf.versions[*ver] = struct{}{}
+		}
+		fp.files[family] = append(fp.files[family], sf)
+	}
+	return fp, nil
+}
+
+func (fp *fileProvider) Retrieve(_ context.Context, schemaURL string) (string, error) {
+	family, version, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		return "", err
+	}
+	for _, sf := range fp.files[family] {
+		if _, ok := sf.versions[*version]; ok {
+			return sf.content, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+type cacheProvider struct {
+	dir  string
+	next Provider
+	log  *zap.Logger
+}
+
+var _ Provider = (*cacheProvider)(nil)
+
+// NewCacheProvider returns a Provider that looks up schema files in the
+// local directory before retrieving them from next. Files retrieved from
+// next are stored in the directory so they are available on restarts.
+// The file for a schema URL is stored under `<dir>/<host>/<path>`.
+// Failing to store a file does not fail its retrieval, and cached files
+// that cannot be parsed are retrieved again.
+func NewCacheProvider(dir string, next Provider, log *zap.Logger) Provider {
+	return &cacheProvider{dir: dir, next: next, log: log}
+}
+
+func (cp *cacheProvider) Retrieve(ctx context.Context, schemaURL string) (string, error) {
+	filename, err := cp.filename(schemaURL)
+	if err != nil {
+		return "", err
+	}
+	if content, err := os.ReadFile(filename); err == nil {
+		if _, err = schema.Parse(bytes.NewReader(content)); err == nil {
+			return string(content), nil
+		}
+		cp.log.Warn("Ignoring invalid cached schema file",
+			zap.String("schema-url", schemaURL),
+			zap.String("file", filename),
+			zap.Error(err),
+		)
+	}
+	if cp.next == nil {
+		return "", ErrNotFound
+	}
+	content, err := cp.next.Retrieve(ctx, schemaURL)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomically(filename, []byte(content)); err != nil {
+		cp.log.Warn("Failed to cache schema file",
+			zap.String("schema-url", schemaURL),
+			zap.String("file", filename),
+			zap.Error(err),
+		)
+	}
+	return content, nil
+}
+
+// writeFileAtomically writes the content to a temporary file renamed to filename,
+// so that a partially written file is never read.
+func writeFileAtomically(filename string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o700); err != nil {
+		return fmt.Errorf("unable to create cache directory: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create cached schema file: %w", err)
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filename)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("unable to write cached schema file: %w", err)
+	}
+	return nil
+}
+
+func (cp *cacheProvider) filename(schemaURL string) (string, error) {
+	u, err := url.Parse(schemaURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" || u.Path == "" {
+		return "", fmt.Errorf("unable to cache schema url %q: %w", schemaURL, ErrInvalidFamily)
+	}
+	// The host is used as a directory name, it must not reference any other directory.
+	if u.Host == "." || strings.Contains(u.Host, "..") || strings.ContainsAny(u.Host, `/\`) {
+		return "", fmt.Errorf("unable to cache schema url %q: invalid host: %w", schemaURL, ErrInvalidFamily)
+	}
+	// Cleaning the path as an absolute path ensures that
+	// the file is always contained within the cache directory.
+	return filepath.Join(cp.dir, u.Host, filepath.FromSlash(path.Clean("/"+u.Path))), nil
+}
+
+// IsFileURL reports if the provided value references
+// a schema file on the local file system.
+func IsFileURL(s string) bool {
+	return strings.HasPrefix(s, "file://")
+}
+
+// FilePathFromURL returns the local path of a `file://` URL,
+// any other value is considered to already be a path.
+func FilePathFromURL(s string) (string, error) {
+	if !IsFileURL(s) {
+		return s, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	p := u.Path
+	if u.Host != "" {
+		// Supports relative paths written as `file://./schema.yaml`
+		p = u.Host + p
+	}
+	if p == "" {
+		return "", fmt.Errorf("empty file path in %q", s)
+	}
+	return filepath.FromSlash(p), nil
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/1.2.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPProvider(srv.Client())
+	data, err := p.Retrieve(context.Background(), srv.URL+"/schemas/1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, string(content), data)
+
+	_, err = p.Retrieve(context.Background(), srv.URL+"/schemas/1.0.0")
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	abs, err := filepath.Abs(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		filepath.Join("testdata", "schema.yaml"),
+		"file://" + filepath.ToSlash(abs),
+	} {
+		p, err := NewFileProvider(path)
+		require.NoError(t, err)
+
+		for _, schemaURL := range []string{
+			"https://example.com/schemas/1.2.0",
+			"https://example.com/schemas/1.1.0",
+		} {
+			data, err := p.Retrieve(context.Background(), schemaURL)
+			require.NoError(t, err)
+			assert.Contains(t, data, "schema_url: https://example.com/schemas/1.2.0")
+		}
+
+		_, err = p.Retrieve(context.Background(), "https://example.com/schemas/1.3.0")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = p.Retrieve(context.Background(), "https://opentelemetry.io/schemas/1.2.0")
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+
+	_, err = NewFileProvider(filepath.Join("testdata", "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestCacheProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	next := &countingProvider{Provider: newTestFileProvider(t)}
+	p := NewCacheProvider(dir, next, zaptest.NewLogger(t))
+
+	for i := 0; i < 2; i++ {
+		data, err := p.Retrieve(context.Background(), "https://example.com/schemas/1.2.0")
+		require.NoError(t, err)
+		assert.Contains(t, data, "schema_url: https://example.com/schemas/1.2.0")
+	}
+	assert.Len(t, next.requests, 1, "Must only retrieve the schema file once")
+	assert.FileExists(t, filepath.Join(dir, "example.com", "schemas", "1.2.0"))
+
+	_, err := p.Retrieve(context.Background(), "https://example.com/schemas/1.3.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewCacheProvider(dir, nil, zaptest.NewLogger(t)).Retrieve(context.Background(), "https://example.com/schemas/1.1.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = p.Retrieve(context.Background(), "https://example.com")
+	assert.ErrorIs(t, err, ErrInvalidFamily)
+
+	for _, schemaURL := range []string{
+		"https://../schemas/1.2.0",
+		"https://./schemas/1.2.0",
+	} {
+		_, err = p.Retrieve(context.Background(), schemaURL)
+		assert.ErrorIs(t, err, ErrInvalidFamily, schemaURL)
+	}
+	_, err = p.Retrieve(context.Background(), `https://..\/schemas/1.2.0`)
+	assert.Error(t, err)
+	assert.Len(t, next.requests, 2, "Must not retrieve schema files that cannot be cached")
+}
+
+func TestCacheProviderInvalidCache(t *testing.T) {
+	t.Parallel()
+
+	// a cache directory that cannot be written does not fail the retrieval
+	notDir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notDir, nil, 0o600))
+	next := &countingProvider{Provider: newTestFileProvider(t)}
+	data, err := NewCacheProvider(notDir, next, zaptest.NewLogger(t)).Retrieve(context.Background(), "https://example.com/schemas/1.2.0")
+	require.NoError(t, err)
+	assert.Contains(t, data, "schema_url: https://example.com/schemas/1.2.0")
+
+	// a truncated cached file is retrieved again and replaced
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "example.com", "schemas", "1.2.0")
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o700))
+	require.NoError(t, os.WriteFile(filename, []byte("file_format: 1.1.0\nschema_url: [https://exa"), 0o600))
+	next = &countingProvider{Provider: newTestFileProvider(t)}
+	p := NewCacheProvider(dir, next, zaptest.NewLogger(t))
+	for i := 0; i < 2; i++ {
+		data, err = p.Retrieve(context.Background(), "https://example.com/schemas/1.2.0")
+		require.NoError(t, err)
+		assert.Contains(t, data, "schema_url: https://example.com/schemas/1.2.0")
+	}
+	assert.Len(t, next.requests, 1, "Must only retrieve the schema file again once")
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(content))
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "Must not leave temporary files")
+}
+
+func TestFilePathFromURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		in       string
+		expected string
+		err      bool
+	}{
+		{in: "schema.yaml", expected: "schema.yaml"},
+		{in: "file:///etc/schema.yaml", expected: filepath.FromSlash("/etc/schema.yaml")},
+		{in: "file://./schema.yaml", expected: filepath.FromSlash("./schema.yaml")},
+		{in: "file://", err: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			p, err := FilePathFromURL(tc.in)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	schema "go.opentelemetry.io/otel/schema/v1.0"
+	"go.opentelemetry.io/otel/schema/v1.0/ast"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/alias"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/changelist"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/migrate"
+)
+
+// ErrUnsupportedVersion is returned when the schema version is not defined
+// within the schema file used for the translation. Incoming data with such
+// a version is passed through unmodified.
+var ErrUnsupportedVersion = errors.New("unsupported schema version")
+
+// Translation defines the complete abstraction of schema translation file
+// that is defined as part of the https://opentelemetry.io/docs/reference/specification/schemas/file_format_v1.0.0/
+// Each instance of Translation is "Target Aware", meaning that given a schemaURL as an input
+// it will convert from the given input version to the target version.
+type Translation interface {
+	// SupportedVersion checks to see if the provided version is defined as part
+	// of this translation since it is useful to know it the translation is missing
+	// updates.
+	SupportedVersion(v *Version) bool
+
+	// ApplyAllResourceChanges will modify the resource part of the incoming signals
+	// and update the resource schema URL to the target schema URL.
+	ApplyAllResourceChanges(in alias.Resource, inSchemaURL string) error
+
+	// ApplyScopeSpanChanges will modify all spans and span events within the scope
+	// and update the scope schema URL to the target schema URL.
+	ApplyScopeSpanChanges(in ptrace.ScopeSpans, inSchemaURL string) error
+
+	// ApplyScopeLogChanges will modify all logs within the scope
+	// and update the scope schema URL to the target schema URL.
+	ApplyScopeLogChanges(in plog.ScopeLogs, inSchemaURL string) error
+
+	// ApplyScopeMetricChanges will modify all metrics within the scope
+	// and update the scope schema URL to the target schema URL.
+	ApplyScopeMetricChanges(in pmetric.ScopeMetrics, inSchemaURL string) error
+}
+
+// translator is the internal implementation of Translation,
+// it holds all the revisions of the schema file sorted by version
+// so that it can select the revisions needed to reach the target.
+type translator struct {
+	targetSchemaURL string
+	target          *Version
+	indexes         map[Version]int // map from version to index in revisions containing the pertinent Version
+	revisions       []*RevisionV1
+
+	log *zap.Logger
+}
+
+var _ Translation = (*translator)(nil)
+
+func newTranslatorFromReader(log *zap.Logger, targetSchemaURL string, content io.Reader) (*translator, error) {
+	sch, err := schema.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return newTranslatorFromSchema(log, targetSchemaURL, sch)
+}
+
+func newTranslatorFromSchema(log *zap.Logger, targetSchemaURL string, sch *ast.Schema) (*translator, error) {
+	_, target, err := GetFamilyAndVersion(targetSchemaURL)
+	if err != nil {
+		return nil, err
+	}
+	t := &translator{
+		targetSchemaURL: targetSchemaURL,
+		target:          target,
+		indexes:         make(map[Version]int, len(sch.Versions)),
+		revisions:       make([]*RevisionV1, 0, len(sch.Versions)),
+		log:             log,
+	}
+	for key, def := range sch.Versions {
+		ver, err := NewVersion(string(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q in schema file: %w", key, err)
+		}
+		t.revisions = append(t.revisions, NewRevision(ver, def))
+	}
+	sort.SliceStable(t.revisions, func(i, j int) bool {
+		return t.revisions[i].Version().LessThan(t.revisions[j].Version())
+	})
+	for i, rev := range t.revisions {
+		t.indexes[*rev.Version()] = i
+	}
+	if !t.SupportedVersion(target) {
+		return nil, fmt.Errorf("target version %s is not defined in schema file: %w", target, ErrUnsupportedVersion)
+	}
+	return t, nil
+}
+
+func (t *translator) SupportedVersion(v *Version) bool {
+	_, ok := t.indexes[*v]
+	return ok
+}
+
+// revisionsFrom returns the revisions that need to be applied, in order,
+// to convert data published with the provided version into the target version
+// along with the direction the revisions are to be applied in.
+func (t *translator) revisionsFrom(from *Version) (migrate.StateSelector, []*RevisionV1, error) {
+	start, ok := t.indexes[*from]
+	if !ok {
+		return migrate.StateSelectorApply, nil, fmt.Errorf("version %s: %w", from, ErrUnsupportedVersion)
+	}
+	end := t.indexes[*t.target]
+	if start <= end {
+		// The revision defined for a version holds the changes made
+		// from the previous version so the starting revision is skipped.
+		return migrate.StateSelectorApply, t.revisions[start+1 : end+1], nil
+	}
+	revisions := make([]*RevisionV1, 0, start-end)
+	for i := start; i > end; i-- {
+		revisions = append(revisions, t.revisions[i])
+	}
+	return migrate.StateSelectorRollback, revisions, nil
+}
+
+// revisionsFromURL returns the revisions to apply to data published with inSchemaURL,
+// and false when the data must be passed through unmodified since its version is unsupported.
+func (t *translator) revisionsFromURL(inSchemaURL string) (migrate.StateSelector, []*RevisionV1, bool, error) {
+	_, ver, err := GetFamilyAndVersion(inSchemaURL)
+	if err != nil {
+		return migrate.StateSelectorApply, nil, false, err
+	}
+	ss, revisions, err := t.revisionsFrom(ver)
+	if errors.Is(err, ErrUnsupportedVersion) {
+		t.log.Debug("Schema version is not defined in the schema file, passing data through",
+			zap.String("schema-url", inSchemaURL),
+		)
+		return ss, nil, false, nil
+	}
+	return ss, revisions, err == nil, err
+}
+
+func (t *translator) ApplyAllResourceChanges(in alias.Resource, inSchemaURL string) error {
+	ss, revisions, ok, err := t.revisionsFromURL(inSchemaURL)
+	if !ok {
+		return err
+	}
+	for _, rev := range revisions {
+		if err := do(ss, in.Resource(), rev.all, rev.resources); err != nil {
+			return err
+		}
+	}
+	in.SetSchemaUrl(t.targetSchemaURL)
+	return nil
+}
+
+func (t *translator) ApplyScopeSpanChanges(in ptrace.ScopeSpans, inSchemaURL string) error {
+	ss, revisions, ok, err := t.revisionsFromURL(inSchemaURL)
+	if !ok {
+		return err
+	}
+	for _, rev := range revisions {
+		for i := 0; i < in.Spans().Len(); i++ {
+			span := in.Spans().At(i)
+			if err := t.applySpan(ss, rev, span); err != nil {
+				return err
+			}
+		}
+	}
+	in.SetSchemaUrl(t.targetSchemaURL)
+	return nil
+}
+
+func (t *translator) applySpan(ss migrate.StateSelector, rev *RevisionV1, span ptrace.Span) error {
+	applyEvents := func() error {
+		for e := 0; e < span.Events().Len(); e++ {
+			if err := rev.all.Do(ss, span.Events().At(e)); err != nil {
+				return err
+			}
+		}
+		return rev.spanEvents.Do(ss, span)
+	}
+	if ss == migrate.StateSelectorRollback {
+		if err := applyEvents(); err != nil {
+			return err
+		}
+		return do(ss, span, rev.all, rev.spans)
+	}
+	if err := do(ss, span, rev.all, rev.spans); err != nil {
+		return err
+	}
+	return applyEvents()
+}
+
+func (t *translator) ApplyScopeLogChanges(in plog.ScopeLogs, inSchemaURL string) error {
+	ss, revisions, ok, err := t.revisionsFromURL(inSchemaURL)
+	if !ok {
+		return err
+	}
+	for _, rev := range revisions {
+		for i := 0; i < in.LogRecords().Len(); i++ {
+			if err := do(ss, in.LogRecords().At(i), rev.all, rev.logs); err != nil {
+				return err
+			}
+		}
+	}
+	in.SetSchemaUrl(t.targetSchemaURL)
+	return nil
+}
+
+func (t *translator) ApplyScopeMetricChanges(in pmetric.ScopeMetrics, inSchemaURL string) error {
+	ss, revisions, ok, err := t.revisionsFromURL(inSchemaURL)
+	if !ok {
+		return err
+	}
+	for _, rev := range revisions {
+		for i := 0; i < in.Metrics().Len(); i++ {
+			metric := in.Metrics().At(i)
+			if metric.Type() == pmetric.MetricTypeEmpty {
+				continue
+			}
+			if err := do(ss, metric, rev.all, rev.metrics); err != nil {
+				return err
+			}
+		}
+	}
+	in.SetSchemaUrl(t.targetSchemaURL)
+	return nil
+}
+
+// do applies the change lists to the signal in the order provided,
+// when rolling back the changes, the order of the change lists is reversed
+// so that the section `all` is always undone last.
+func do(ss migrate.StateSelector, signal any, lists ...*changelist.ChangeList) error {
+	for i := range lists {
+		list := lists[i]
+		if ss == migrate.StateSelectorRollback {
+			list = lists[len(lists)-1-i]
+		}
+		if err := list.Do(ss, signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nopTranslation is used when the incoming data does not match
+// any of the configured targets and is passed through unmodified.
+type nopTranslation struct{}
+
+var _ Translation = (*nopTranslation)(nil)
+
+func (nopTranslation) SupportedVersion(_ *Version) bool {
+	return false
+}
+
+func (nopTranslation) ApplyAllResourceChanges(_ alias.Resource, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeSpanChanges(_ ptrace.ScopeSpans, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeLogChanges(_ plog.ScopeLogs, _ string) error {
+	return nil
+}
+
+func (nopTranslation) ApplyScopeMetricChanges(_ pmetric.ScopeMetrics, _ string) error {
+	return nil
+}
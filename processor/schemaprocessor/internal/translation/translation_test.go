@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestTranslator(t *testing.T, target string) *translator {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	tn, err := newTranslatorFromReader(zaptest.NewLogger(t), target, f)
+	require.NoError(t, err, "Must not error when creating translator")
+	return tn
+}
+
+func TestTranslatorSupportedVersion(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.1.0")
+	assert.True(t, tn.SupportedVersion(&Version{1, 0, 0}))
+	assert.True(t, tn.SupportedVersion(&Version{1, 2, 0}))
+	assert.False(t, tn.SupportedVersion(&Version{1, 3, 0}))
+}
+
+func TestTranslatorUnknownTargetVersion(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = newTranslatorFromReader(zaptest.NewLogger(t), "https://example.com/schemas/1.9.0", f)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestTranslatorRevisionsFrom(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.1.0")
+	for _, tc := range []struct {
+		name     string
+		from     *Version
+		expected []*Version
+	}{
+		{name: "same version", from: &Version{1, 1, 0}, expected: []*Version{}},
+		{name: "upgrade", from: &Version{1, 0, 0}, expected: []*Version{{1, 1, 0}}},
+		{name: "downgrade", from: &Version{1, 2, 0}, expected: []*Version{{1, 2, 0}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, revisions, err := tn.revisionsFrom(tc.from)
+			require.NoError(t, err)
+			versions := make([]*Version, 0, len(revisions))
+			for _, rev := range revisions {
+				versions = append(versions, rev.Version())
+			}
+			assert.Equal(t, tc.expected, versions)
+		})
+	}
+
+	_, _, err := tn.revisionsFrom(&Version{0, 9, 0})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestTranslatorResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.2.0")
+	rs := ptrace.NewResourceSpans()
+	rs.SetSchemaUrl("https://example.com/schemas/1.0.0")
+	rs.Resource().Attributes().PutStr("service.namespace", "shop")
+	rs.Resource().Attributes().PutStr("telemetry.auto.version", "1.0.0")
+
+	require.NoError(t, tn.ApplyAllResourceChanges(rs, rs.SchemaUrl()))
+	assert.Equal(t, "https://example.com/schemas/1.2.0", rs.SchemaUrl())
+	assert.Equal(t, map[string]any{
+		"service.namespace.name":       "shop",
+		"telemetry.auto_instr.version": "1.0.0",
+	}, rs.Resource().Attributes().AsRaw())
+}
+
+func TestTranslatorSpanChanges(t *testing.T) {
+	t.Parallel()
+
+	newScopeSpans := func() ptrace.ScopeSpans {
+		ss := ptrace.NewScopeSpans()
+		span := ss.Spans().AppendEmpty()
+		span.SetName("GET /")
+		span.Attributes().PutInt("http.status", 200)
+		span.Attributes().PutStr("service.namespace", "shop")
+		span.Events().AppendEmpty().SetName("stacktrace")
+		return ss
+	}
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.2.0")
+	ss := newScopeSpans()
+	require.NoError(t, tn.ApplyScopeSpanChanges(ss, "https://example.com/schemas/1.0.0"))
+	assert.Equal(t, "https://example.com/schemas/1.2.0", ss.SchemaUrl())
+	span := ss.Spans().At(0)
+	assert.Equal(t, map[string]any{
+		"http.response.status_code": int64(200),
+		"service.namespace.name":    "shop",
+	}, span.Attributes().AsRaw())
+	assert.Equal(t, "stack_trace", span.Events().At(0).Name())
+
+	// Rolling back the data should produce the original input
+	tn = newTestTranslator(t, "https://example.com/schemas/1.0.0")
+	require.NoError(t, tn.ApplyScopeSpanChanges(ss, "https://example.com/schemas/1.2.0"))
+	expected := newScopeSpans()
+	expected.SetSchemaUrl("https://example.com/schemas/1.0.0")
+	assert.Equal(t, expected, ss)
+}
+
+func TestTranslatorMetricChanges(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.2.0")
+	sm := pmetric.NewScopeMetrics()
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("container.cpu.usage.total")
+	m.SetEmptySum().DataPoints().AppendEmpty().Attributes().PutStr("service.namespace", "shop")
+	sm.Metrics().AppendEmpty().SetName("empty")
+
+	require.NoError(t, tn.ApplyScopeMetricChanges(sm, "https://example.com/schemas/1.1.0"))
+	assert.Equal(t, "https://example.com/schemas/1.2.0", sm.SchemaUrl())
+	assert.Equal(t, "cpu.usage.total", m.Name())
+	assert.Equal(t, map[string]any{
+		"service.namespace.name": "shop",
+	}, m.Sum().DataPoints().At(0).Attributes().AsRaw())
+	assert.Equal(t, "empty", sm.Metrics().At(1).Name())
+}
+
+func TestTranslatorLogChanges(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.1.0")
+	sl := plog.NewScopeLogs()
+	sl.LogRecords().AppendEmpty().Attributes().PutStr("exception.stacktrace", "panic")
+
+	require.NoError(t, tn.ApplyScopeLogChanges(sl, "https://example.com/schemas/1.2.0"))
+	assert.Equal(t, "https://example.com/schemas/1.1.0", sl.SchemaUrl())
+	assert.Equal(t, map[string]any{
+		"process.stacktrace": "panic",
+	}, sl.LogRecords().At(0).Attributes().AsRaw())
+}
+
+func TestTranslatorUnsupportedIncomingVersion(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslator(t, "https://example.com/schemas/1.2.0")
+	sl := plog.NewScopeLogs()
+	sl.SetSchemaUrl("https://example.com/schemas/0.1.0")
+	sl.LogRecords().AppendEmpty().Attributes().PutStr("exception.stacktrace", "panic")
+
+	// The data is passed through unmodified.
+	require.NoError(t, tn.ApplyScopeLogChanges(sl, sl.SchemaUrl()))
+	assert.Equal(t, "https://example.com/schemas/0.1.0", sl.SchemaUrl())
+	assert.Equal(t, map[string]any{
+		"exception.stacktrace": "panic",
+	}, sl.LogRecords().At(0).Attributes().AsRaw())
+}
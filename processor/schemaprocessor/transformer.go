@@ -8,16 +8,25 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/alias"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
 )
 
 type transformer struct {
-	targets []string
-	log     *zap.Logger
+	targets        []string
+	prefetch       []string
+	cacheDirectory string
+	httpConfig     confighttp.ClientConfig
+	log            *zap.Logger
+	telemetry      component.TelemetrySettings
+	manager        translation.Manager
 }
 
 func newTransformer(
@@ -29,29 +38,139 @@ func newTransformer(
 	if !ok {
 		return nil, errors.New("invalid configuration provided")
 	}
+	m, err := translation.NewManager(cfg.Targets, set.Logger.Named("schema-manager"))
+	if err != nil {
+		return nil, err
+	}
 	return &transformer{
-		log:     set.Logger,
-		targets: cfg.Targets,
+		log:            set.Logger,
+		targets:        cfg.Targets,
+		prefetch:       cfg.Prefetch,
+		cacheDirectory: cfg.CacheDirectory,
+		httpConfig:     cfg.ClientConfig,
+		telemetry:      set.TelemetrySettings,
+		manager:        m,
 	}, nil
 }
 
-func (t transformer) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+func (t transformer) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	for rl := 0; rl < ld.ResourceLogs().Len(); rl++ {
+		rLog := ld.ResourceLogs().At(rl)
+		resourceSchemaURL := rLog.SchemaUrl()
+		if err := t.applyResourceChanges(ctx, rLog, resourceSchemaURL); err != nil {
+			return ld, err
+		}
+		for sl := 0; sl < rLog.ScopeLogs().Len(); sl++ {
+			logs := rLog.ScopeLogs().At(sl)
+			schemaURL := scopeSchemaURL(logs.SchemaUrl(), resourceSchemaURL)
+			if schemaURL == "" {
+				continue
+			}
+			tr := t.manager.RequestTranslation(ctx, schemaURL)
+			if err := tr.ApplyScopeLogChanges(logs, schemaURL); err != nil {
+				return ld, err
+			}
+		}
+	}
 	return ld, nil
 }
 
-func (t transformer) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+func (t transformer) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	for rm := 0; rm < md.ResourceMetrics().Len(); rm++ {
+		rMetric := md.ResourceMetrics().At(rm)
+		resourceSchemaURL := rMetric.SchemaUrl()
+		if err := t.applyResourceChanges(ctx, rMetric, resourceSchemaURL); err != nil {
+			return md, err
+		}
+		for sm := 0; sm < rMetric.ScopeMetrics().Len(); sm++ {
+			metrics := rMetric.ScopeMetrics().At(sm)
+			schemaURL := scopeSchemaURL(metrics.SchemaUrl(), resourceSchemaURL)
+			if schemaURL == "" {
+				continue
+			}
+			tr := t.manager.RequestTranslation(ctx, schemaURL)
+			if err := tr.ApplyScopeMetricChanges(metrics, schemaURL); err != nil {
+				return md, err
+			}
+		}
+	}
 	return md, nil
 }
 
-func (t transformer) processTraces(_ context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+func (t transformer) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	for rt := 0; rt < td.ResourceSpans().Len(); rt++ {
+		rSpan := td.ResourceSpans().At(rt)
+		resourceSchemaURL := rSpan.SchemaUrl()
+		if err := t.applyResourceChanges(ctx, rSpan, resourceSchemaURL); err != nil {
+			return td, err
+		}
+		for ss := 0; ss < rSpan.ScopeSpans().Len(); ss++ {
+			spans := rSpan.ScopeSpans().At(ss)
+			schemaURL := scopeSchemaURL(spans.SchemaUrl(), resourceSchemaURL)
+			if schemaURL == "" {
+				continue
+			}
+			tr := t.manager.RequestTranslation(ctx, schemaURL)
+			if err := tr.ApplyScopeSpanChanges(spans, schemaURL); err != nil {
+				return td, err
+			}
+		}
+	}
 	return td, nil
 }
 
+func (t transformer) applyResourceChanges(ctx context.Context, res alias.Resource, schemaURL string) error {
+	if schemaURL == "" {
+		return nil
+	}
+	return t.manager.RequestTranslation(ctx, schemaURL).ApplyAllResourceChanges(res, schemaURL)
+}
+
+// scopeSchemaURL returns the schema URL that applies to the scope data,
+// the scope inherits the schema URL of the resource when it has none set.
+func scopeSchemaURL(scope, resource string) string {
+	if scope != "" {
+		return scope
+	}
+	return resource
+}
+
 // start will load the remote file definition if it isn't already cached
 // and resolve the schema translation file
-func (t *transformer) start(_ context.Context, _ component.Host) error {
-	for _, target := range t.targets {
-		t.log.Info("Fetching remote schema url", zap.String("schema-url", target))
+func (t *transformer) start(ctx context.Context, host component.Host) error {
+	var (
+		files   []string
+		remotes []string
+	)
+	for _, schemaURL := range t.prefetch {
+		if translation.IsFileURL(schemaURL) {
+			files = append(files, schemaURL)
+			continue
+		}
+		remotes = append(remotes, schemaURL)
+	}
+
+	fileProvider, err := translation.NewFileProvider(files...)
+	if err != nil {
+		return err
+	}
+	client, err := t.httpConfig.ToClient(ctx, host, t.telemetry)
+	if err != nil {
+		return err
+	}
+	var remoteProvider translation.Provider = translation.NewHTTPProvider(client)
+	if t.cacheDirectory != "" {
+		remoteProvider = translation.NewCacheProvider(t.cacheDirectory, remoteProvider, t.log.Named("schema-cache"))
+	}
+	if err := t.manager.SetProviders(fileProvider, remoteProvider); err != nil {
+		return err
+	}
+
+	// Requesting the targets ensures the schema files needed
+	// for the most common translations are loaded ahead of time.
+	for _, schemaURL := range append(remotes, t.targets...) {
+		t.log.Info("Fetching remote schema url", zap.String("schema-url", schemaURL))
+		t.manager.RequestTranslation(ctx, schemaURL)
 	}
 	return nil
 }
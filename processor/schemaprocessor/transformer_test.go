@@ -6,11 +6,13 @@ package schemaprocessor
 import (
 	"context"
 	_ "embed"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -86,3 +88,92 @@ func TestTransformerProcessing(t *testing.T) {
 		assert.Equal(t, in, out, "Must return the same data (subject to change)")
 	})
 }
+
+func newTranslatingTransformer(t *testing.T) *transformer {
+	abs, err := filepath.Abs(filepath.Join("testdata", "schema.yaml"))
+	require.NoError(t, err)
+
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Prefetch = []string{"file://" + filepath.ToSlash(abs)}
+	cfg.Targets = []string{"https://example.com/schemas/1.2.0"}
+	require.NoError(t, cfg.Validate())
+
+	trans, err := newTransformer(context.Background(), cfg, processor.Settings{
+		TelemetrySettings: componenttest.NewNopTelemetrySettings(),
+	})
+	require.NoError(t, err, "Must not error when creating transformer")
+	require.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()))
+	return trans
+}
+
+func TestTransformerTranslation(t *testing.T) {
+	t.Parallel()
+
+	trans := newTranslatingTransformer(t)
+	t.Run("metrics", func(t *testing.T) {
+		in := pmetric.NewMetrics()
+		rm := in.ResourceMetrics().AppendEmpty()
+		rm.SetSchemaUrl("https://example.com/schemas/1.0.0")
+		rm.Resource().Attributes().PutStr("telemetry.auto.version", "1.0.0")
+		sm := rm.ScopeMetrics().AppendEmpty()
+		m := sm.Metrics().AppendEmpty()
+		m.SetName("container.cpu.usage.total")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("service.namespace", "shop")
+
+		out, err := trans.processMetrics(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing metrics")
+		rm = out.ResourceMetrics().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.2.0", rm.SchemaUrl())
+		assert.Equal(t, map[string]any{"telemetry.auto_instr.version": "1.0.0"}, rm.Resource().Attributes().AsRaw())
+		sm = rm.ScopeMetrics().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.2.0", sm.SchemaUrl(), "Must set the schema url of the scope")
+		assert.Equal(t, "cpu.usage.total", sm.Metrics().At(0).Name())
+		assert.Equal(t, map[string]any{"service.namespace.name": "shop"}, sm.Metrics().At(0).Gauge().DataPoints().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		in := ptrace.NewTraces()
+		rs := in.ResourceSpans().AppendEmpty()
+		rs.SetSchemaUrl("https://example.com/schemas/1.0.0")
+		ss := rs.ScopeSpans().AppendEmpty()
+		// The scope schema url takes priority over the resource schema url
+		ss.SetSchemaUrl("https://example.com/schemas/1.1.0")
+		s := ss.Spans().AppendEmpty()
+		s.SetName("http.request")
+		s.Attributes().PutInt("http.status", 200)
+		s.Events().AppendEmpty().SetName("stacktrace")
+
+		out, err := trans.processTraces(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing traces")
+		ss = out.ResourceSpans().At(0).ScopeSpans().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.2.0", ss.SchemaUrl())
+		assert.Equal(t, map[string]any{"http.response.status_code": int64(200)}, ss.Spans().At(0).Attributes().AsRaw())
+		assert.Equal(t, "stacktrace", ss.Spans().At(0).Events().At(0).Name(), "Must not apply changes from older versions")
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl("https://example.com/schemas/1.1.0")
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.LogRecords().AppendEmpty().Attributes().PutStr("process.stacktrace", "panic")
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		sl = out.ResourceLogs().At(0).ScopeLogs().At(0)
+		assert.Equal(t, map[string]any{"exception.stacktrace": "panic"}, sl.LogRecords().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("unknown family", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl("https://opentelemetry.io/schemas/1.9.0")
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().PutStr("process.stacktrace", "panic")
+		expected := plog.NewLogs()
+		in.CopyTo(expected)
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		assert.Equal(t, expected, out, "Must not modify data of other schema families")
+	})
+}
@@ -4,8 +4,14 @@
 package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -43,6 +49,9 @@ const (
 	// OTTLCondition sample traces which match user provided OpenTelemetry Transformation Language
 	// conditions.
 	OTTLCondition PolicyType = "ottl_condition"
+	// AdaptiveRate samples a target number of traces per second, adjusting the sampling
+	// probability of each key so that the rare keys are sampled more often than the frequent ones.
+	AdaptiveRate PolicyType = "adaptive_rate"
 )
 
 // sharedPolicyCfg holds the common configuration to all policies that are used in derivative policy configurations
@@ -72,6 +81,8 @@ type sharedPolicyCfg struct {
 	BooleanAttributeCfg BooleanAttributeCfg `mapstructure:"boolean_attribute"`
 	// Configs for OTTL condition filter sampling policy evaluator
 	OTTLConditionCfg OTTLConditionCfg `mapstructure:"ottl_condition"`
+	// Configs for adaptive rate sampling policy evaluator.
+	AdaptiveRateCfg AdaptiveRateCfg `mapstructure:"adaptive_rate"`
 }
 
 // CompositeSubPolicyCfg holds the common configuration to all policies under composite policy.
@@ -194,6 +205,20 @@ type RateLimitingCfg struct {
 	SpansPerSecond int64 `mapstructure:"spans_per_second"`
 }
 
+// AdaptiveRateCfg holds the configurable settings to create an adaptive rate
+// sampling policy evaluator.
+type AdaptiveRateCfg struct {
+	// TracesPerSecond is the number of traces per second the policy aims to sample across all keys.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+	// KeyAttributes are the attributes of the root span, or of its resource, whose values
+	// identify the traces sharing the same sampling probability.
+	KeyAttributes []string `mapstructure:"key_attributes"`
+	// KeyBySpanName adds the name of the root span to the key of the traces.
+	KeyBySpanName bool `mapstructure:"key_by_span_name"`
+	// AdjustmentInterval is how often the sampling probability of each key is adjusted. Defaults to 10s.
+	AdjustmentInterval time.Duration `mapstructure:"adjustment_interval"`
+}
+
 // SpanCountCfg holds the configurable settings to create a Span Count filter sampling
 // policy evaluator
 type SpanCountCfg struct {
@@ -237,6 +262,32 @@ type DecisionCacheConfig struct {
 	NonSampledCacheSize int `mapstructure:"non_sampled_cache_size"`
 }
 
+// PeersCfg holds the configuration used to share traces between the instances
+// of a collector tier, so that each trace is sampled by a single instance.
+type PeersCfg struct {
+	// Endpoint is the endpoint, including the port, under which the resolver finds this instance.
+	// Traces are only shared with the peers when it is set.
+	Endpoint string `mapstructure:"endpoint"`
+	// Config holds the settings of the exporters used to forward spans to the peers,
+	// and of the resolver used to find the peers. The instance itself must be one of them.
+	tracerouter.Config `mapstructure:",squash"`
+}
+
+// Validate checks that the endpoint of the instance includes the port,
+// and that the peers can be resolved when it is set.
+func (cfg *PeersCfg) Validate() error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(cfg.Endpoint); err != nil {
+		return fmt.Errorf("invalid peers endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if cfg.Resolver == (tracerouter.ResolverSettings{}) {
+		return errors.New("a peers resolver is required when the peers endpoint is set")
+	}
+	return nil
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	// DecisionWait is the desired wait time from the arrival of the first span of
@@ -253,4 +304,10 @@ type Config struct {
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
 	// DecisionCache holds configuration for the decision cache(s)
 	DecisionCache DecisionCacheConfig `mapstructure:"decision_cache"`
+	// StorageID is the storage extension used to persist the traces kept in memory
+	// and the decision caches, so that they are restored after a restart.
+	// The state is only kept in memory if not set.
+	StorageID *component.ID `mapstructure:"storage"`
+	// Peers holds the configuration to share traces with other instances of the processor.
+	Peers PeersCfg `mapstructure:"peers"`
 }
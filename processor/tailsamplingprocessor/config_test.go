@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
 )
@@ -30,12 +31,17 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, sub.Unmarshal(cfg))
 
+	peers := factory.CreateDefaultConfig().(*Config).Peers
+	peers.Endpoint = "collector-1:4317"
+	peers.Resolver.Static = &tracerouter.StaticResolver{Hostnames: []string{"collector-1:4317", "collector-2:4317"}}
+
 	assert.Equal(t,
 		&Config{
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
 			DecisionCache:           DecisionCacheConfig{SampledCacheSize: 1_000, NonSampledCacheSize: 10_000},
+			Peers:                   peers,
 			PolicyCfgs: []PolicyCfg{
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
@@ -117,6 +123,18 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-12",
+						Type: AdaptiveRate,
+						AdaptiveRateCfg: AdaptiveRateCfg{
+							TracesPerSecond:    100,
+							KeyAttributes:      []string{"service.name"},
+							KeyBySpanName:      true,
+							AdjustmentInterval: 30 * time.Second,
+						},
+					},
+				},
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
 						Name: "and-policy-1",
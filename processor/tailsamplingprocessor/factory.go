@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/processor"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
 )
 
@@ -28,6 +29,9 @@ func createDefaultConfig() component.Config {
 	return &Config{
 		DecisionWait: 30 * time.Second,
 		NumTraces:    50000,
+		Peers: PeersCfg{
+			Config: tracerouter.NewDefaultConfig(),
+		},
 	}
 }
 
@@ -4,7 +4,9 @@
 package cache // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 
 import (
+	"encoding"
 	"encoding/binary"
+	"encoding/json"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -19,7 +21,11 @@ type lruDecisionCache[V any] struct {
 	cache *lru.Cache[uint64, V]
 }
 
-var _ Cache[any] = (*lruDecisionCache[any])(nil)
+var (
+	_ Cache[any]                 = (*lruDecisionCache[any])(nil)
+	_ encoding.BinaryMarshaler   = (*lruDecisionCache[any])(nil)
+	_ encoding.BinaryUnmarshaler = (*lruDecisionCache[any])(nil)
+)
 
 // NewLRUDecisionCache returns a new lruDecisionCache.
 // The size parameter indicates the amount of keys the cache will hold before it
@@ -46,3 +52,33 @@ func (c *lruDecisionCache[V]) Delete(_ pcommon.TraceID) {}
 func rightHalfTraceID(id pcommon.TraceID) uint64 {
 	return binary.LittleEndian.Uint64(id[8:])
 }
+
+type lruEntry[V any] struct {
+	Key   uint64 `json:"k"`
+	Value V      `json:"v"`
+}
+
+// MarshalBinary encodes the content of the cache, from the least to the most recently used entry.
+func (c *lruDecisionCache[V]) MarshalBinary() ([]byte, error) {
+	keys := c.cache.Keys()
+	entries := make([]lruEntry[V], 0, len(keys))
+	for _, key := range keys {
+		if v, ok := c.cache.Peek(key); ok {
+			entries = append(entries, lruEntry[V]{Key: key, Value: v})
+		}
+	}
+	return json.Marshal(entries)
+}
+
+// UnmarshalBinary adds the entries encoded by MarshalBinary to the cache,
+// evicting the least recently used entries if the cache is smaller.
+func (c *lruDecisionCache[V]) UnmarshalBinary(data []byte) error {
+	var entries []lruEntry[V]
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		_ = c.cache.Add(entry.Key, entry.Value)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@
 package cache
 
 import (
+	"encoding"
 	"encoding/hex"
 	"testing"
 
@@ -81,3 +82,36 @@ func traceIDFromHex(idStr string) (pcommon.TraceID, error) {
 	_, err := hex.Decode(id[:], []byte(idStr))
 	return id, err
 }
+
+func TestMarshalUnmarshal(t *testing.T) {
+	c, err := NewLRUDecisionCache[bool](3)
+	require.NoError(t, err)
+	id1, err := traceIDFromHex("12341234123412341234123412341231")
+	require.NoError(t, err)
+	id2, err := traceIDFromHex("12341234123412341234123412341232")
+	require.NoError(t, err)
+	id3, err := traceIDFromHex("12341234123412341234123412341233")
+	require.NoError(t, err)
+
+	c.Put(id1, true)
+	c.Put(id2, false)
+	c.Put(id3, true)
+	data, err := c.(encoding.BinaryMarshaler).MarshalBinary()
+	require.NoError(t, err)
+
+	// restoring in a smaller cache keeps the most recently used entries
+	restored, err := NewLRUDecisionCache[bool](2)
+	require.NoError(t, err)
+	require.NoError(t, restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(data))
+
+	_, ok := restored.Get(id1)
+	assert.False(t, ok)
+	v, ok := restored.Get(id2)
+	assert.True(t, ok)
+	assert.False(t, v)
+	v, ok = restored.Get(id3)
+	assert.True(t, ok)
+	assert.True(t, v)
+
+	assert.Error(t, restored.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("invalid")))
+}
@@ -0,0 +1,257 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+const (
+	defaultAdjustmentInterval = 10 * time.Second
+
+	// rateSmoothing is the weight of the last interval in the estimated rate of each key.
+	rateSmoothing = 0.5
+	// minKeyRate is the estimated rate, in traces per second, under which a key is forgotten.
+	minKeyRate = 0.001
+)
+
+var errInvalidTargetRate = errors.New("traces_per_second must be greater than zero")
+
+// AdaptiveRateSettings holds the settings of the adaptive rate policy.
+type AdaptiveRateSettings struct {
+	// TracesPerSecond is the number of traces per second to sample across all keys.
+	TracesPerSecond float64
+	// KeyAttributes are the attributes of the root span, or of its resource, identifying the key of a trace.
+	KeyAttributes []string
+	// KeyBySpanName adds the name of the root span to the key of a trace.
+	KeyBySpanName bool
+	// AdjustmentInterval is how often the sampling probability of each key is adjusted.
+	AdjustmentInterval time.Duration
+}
+
+type keyRate struct {
+	count       int64
+	rate        float64
+	probability float64
+}
+
+type adaptiveRate struct {
+	logger   *zap.Logger
+	settings AdaptiveRateSettings
+	now      func() time.Time
+
+	lastAdjustment time.Time
+	keys           map[string]*keyRate
+}
+
+var _ PolicyEvaluator = (*adaptiveRate)(nil)
+
+// NewAdaptiveRate creates a policy evaluator that adjusts the sampling probability of each key
+// to sample the target number of traces per second, sharing the budget fairly between the keys:
+// keys with a low rate are sampled more often than the keys with a high rate. The threshold used
+// to sample a trace is kept in its TraceData.SamplingThreshold.
+func NewAdaptiveRate(settings component.TelemetrySettings, cfg AdaptiveRateSettings) (PolicyEvaluator, error) {
+	if cfg.TracesPerSecond <= 0 {
+		return nil, errInvalidTargetRate
+	}
+	if cfg.AdjustmentInterval <= 0 {
+		cfg.AdjustmentInterval = defaultAdjustmentInterval
+	}
+	return &adaptiveRate{
+		logger:         settings.Logger,
+		settings:       cfg,
+		now:            time.Now,
+		lastAdjustment: time.Now(),
+		keys:           make(map[string]*keyRate),
+	}, nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (a *adaptiveRate) Evaluate(_ context.Context, traceID pcommon.TraceID, trace *TraceData) (Decision, error) {
+	a.logger.Debug("Evaluating spans in adaptive rate filter")
+
+	if now := a.now(); now.Sub(a.lastAdjustment) >= a.settings.AdjustmentInterval {
+		a.adjust(now.Sub(a.lastAdjustment))
+		a.lastAdjustment = now
+	}
+
+	trace.Lock()
+	defer trace.Unlock()
+
+	key := a.key(trace.ReceivedBatches)
+	kr, ok := a.keys[key]
+	if !ok {
+		// keys are sampled until their rate is known
+		kr = &keyRate{probability: 1}
+		a.keys[key] = kr
+	}
+	kr.count++
+
+	threshold, err := otelsampling.ProbabilityToThreshold(math.Max(kr.probability, otelsampling.MinSamplingProbability))
+	if err != nil {
+		return Error, err
+	}
+	if !threshold.ShouldSample(traceRandomness(traceID, trace.ReceivedBatches)) {
+		return NotSampled, nil
+	}
+	trace.SamplingThreshold = threshold
+	return Sampled, nil
+}
+
+// adjust estimates the rate of each key over the last interval and shares the target rate
+// between them: the keys under the fair share are always sampled, and the sampling probability
+// of the others is set so that each of them is sampled at the fair share.
+func (a *adaptiveRate) adjust(elapsed time.Duration) {
+	rates := make([]float64, 0, len(a.keys))
+	for key, kr := range a.keys {
+		lastRate := float64(kr.count) / elapsed.Seconds()
+		if kr.rate == 0 {
+			kr.rate = lastRate
+		} else {
+			kr.rate = rateSmoothing*lastRate + (1-rateSmoothing)*kr.rate
+		}
+		kr.count = 0
+		if kr.rate < minKeyRate {
+			delete(a.keys, key)
+			continue
+		}
+		rates = append(rates, kr.rate)
+	}
+
+	share := fairShare(rates, a.settings.TracesPerSecond)
+	for _, kr := range a.keys {
+		kr.probability = math.Min(1, share/kr.rate)
+	}
+}
+
+// fairShare returns the highest rate allowed for each key so that the sum of the
+// rates of all keys, each capped to that value, does not exceed the target.
+func fairShare(rates []float64, target float64) float64 {
+	sort.Float64s(rates)
+	remaining := target
+	for i, rate := range rates {
+		share := remaining / float64(len(rates)-i)
+		if rate > share {
+			return share
+		}
+		remaining -= rate
+	}
+	return math.Inf(1)
+}
+
+// key identifies the traces sharing the same sampling probability, from the root span
+// of the trace or, if it has not been received, from its first span.
+func (a *adaptiveRate) key(td ptrace.Traces) string {
+	rs, span, ok := rootSpan(td)
+	if !ok {
+		return ""
+	}
+	values := make([]string, 0, len(a.settings.KeyAttributes)+1)
+	for _, attr := range a.settings.KeyAttributes {
+		v, ok := span.Attributes().Get(attr)
+		if !ok {
+			v, ok = rs.Resource().Attributes().Get(attr)
+		}
+		if ok {
+			values = append(values, v.AsString())
+		} else {
+			values = append(values, "")
+		}
+	}
+	if a.settings.KeyBySpanName {
+		values = append(values, span.Name())
+	}
+	return strings.Join(values, "\x00")
+}
+
+func rootSpan(td ptrace.Traces) (ptrace.ResourceSpans, ptrace.Span, bool) {
+	var (
+		firstResource ptrace.ResourceSpans
+		first         ptrace.Span
+		found         bool
+	)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.ParentSpanID().IsEmpty() {
+					return rs, span, true
+				}
+				if !found {
+					firstResource, first, found = rs, span, true
+				}
+			}
+		}
+	}
+	return firstResource, first, found
+}
+
+// traceRandomness returns the randomness found in the tracestate of the spans, so that the decision
+// is consistent with the samplers which already used it, or the randomness of the trace ID otherwise.
+func traceRandomness(traceID pcommon.TraceID, td ptrace.Traces) otelsampling.Randomness {
+	rnd := otelsampling.TraceIDToRandomness(traceID)
+	forEachSpan(td, func(span ptrace.Span) bool {
+		ts, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw())
+		if err != nil {
+			return true
+		}
+		if r, ok := ts.OTelValue().RValueRandomness(); ok {
+			rnd = r
+			return false
+		}
+		return true
+	})
+	return rnd
+}
+
+// RecordThreshold sets the threshold in the tracestate of the spans, unless they were already
+// sampled with a higher threshold, so that their adjusted count accounts for the sampling.
+func RecordThreshold(logger *zap.Logger, td ptrace.Traces, threshold otelsampling.Threshold) {
+	forEachSpan(td, func(span ptrace.Span) bool {
+		ts, err := otelsampling.NewW3CTraceState(span.TraceState().AsRaw())
+		if err != nil {
+			logger.Debug("Invalid tracestate", zap.Error(err))
+			return true
+		}
+		if err = ts.OTelValue().UpdateTValueWithSampling(threshold); err != nil {
+			return true
+		}
+		var w strings.Builder
+		if err = ts.Serialize(&w); err != nil {
+			logger.Debug("Failed to serialize tracestate", zap.Error(err))
+			return true
+		}
+		span.TraceState().FromRaw(w.String())
+		return true
+	})
+}
+
+// forEachSpan calls f for each span of the traces until it returns false.
+func forEachSpan(td ptrace.Traces, f func(span ptrace.Span) bool) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		ilss := td.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if !f(spans.At(k)) {
+					return
+				}
+			}
+		}
+	}
+}
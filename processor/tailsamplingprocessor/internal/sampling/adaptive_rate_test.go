@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+)
+
+func newAdaptiveRateTraceData(service, name, traceState string) *TraceData {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", service)
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(name)
+	span.TraceState().FromRaw(traceState)
+	child := rs.ScopeSpans().At(0).Spans().AppendEmpty()
+	child.SetName("child")
+	child.SetParentSpanID([8]byte{1})
+	spanCount := &atomic.Int64{}
+	spanCount.Store(2)
+	return &TraceData{
+		ReceivedBatches: traces,
+		SpanCount:       spanCount,
+	}
+}
+
+func randomTraceID(r *rand.Rand) pcommon.TraceID {
+	var id pcommon.TraceID
+	_, _ = r.Read(id[:])
+	return id
+}
+
+func TestNewAdaptiveRateInvalidTarget(t *testing.T) {
+	_, err := NewAdaptiveRate(componenttest.NewNopTelemetrySettings(), AdaptiveRateSettings{})
+	assert.ErrorIs(t, err, errInvalidTargetRate)
+}
+
+func TestFairShare(t *testing.T) {
+	tests := []struct {
+		name     string
+		rates    []float64
+		target   float64
+		expected float64
+	}{
+		{
+			name:     "no keys",
+			target:   10,
+			expected: math.Inf(1),
+		},
+		{
+			name:     "under target",
+			rates:    []float64{1, 2, 3},
+			target:   10,
+			expected: math.Inf(1),
+		},
+		{
+			name:     "evenly shared",
+			rates:    []float64{100, 100},
+			target:   10,
+			expected: 5,
+		},
+		{
+			name:     "rare keys keep their rate",
+			rates:    []float64{100, 1, 2},
+			target:   13,
+			expected: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, fairShare(tt.rates, tt.target), 1e-9)
+		})
+	}
+}
+
+func TestAdaptiveRate(t *testing.T) {
+	evaluator, err := NewAdaptiveRate(componenttest.NewNopTelemetrySettings(), AdaptiveRateSettings{
+		TracesPerSecond: 11,
+		KeyAttributes:   []string{"service.name"},
+		KeyBySpanName:   true,
+	})
+	require.NoError(t, err)
+	policy := evaluator.(*adaptiveRate)
+	now := time.Now()
+	policy.now = func() time.Time { return now }
+	policy.lastAdjustment = now
+
+	r := rand.New(rand.NewSource(42))
+	ctx := context.Background()
+
+	// the rate of the keys is not known yet, all traces are sampled
+	for i := 0; i < 1000; i++ {
+		decision, err := policy.Evaluate(ctx, randomTraceID(r), newAdaptiveRateTraceData("frontend", "GET /", ""))
+		require.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+	}
+	for i := 0; i < 10; i++ {
+		decision, err := policy.Evaluate(ctx, randomTraceID(r), newAdaptiveRateTraceData("frontend", "POST /checkout", ""))
+		require.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+	}
+
+	// 100 traces per second for the hot key and 1 for the rare key, the rare key keeps its rate
+	// and the hot key gets the remaining 10 traces per second
+	now = now.Add(defaultAdjustmentInterval)
+	sampled := 0
+	for i := 0; i < 1000; i++ {
+		trace := newAdaptiveRateTraceData("frontend", "GET /", "")
+		decision, err := policy.Evaluate(ctx, randomTraceID(r), trace)
+		require.NoError(t, err)
+		if decision != Sampled {
+			continue
+		}
+		sampled++
+		assert.InDelta(t, 10, trace.SamplingThreshold.AdjustedCount(), 0.01)
+	}
+	assert.InDelta(t, 100, sampled, 30)
+
+	for i := 0; i < 10; i++ {
+		trace := newAdaptiveRateTraceData("frontend", "POST /checkout", "")
+		decision, err := policy.Evaluate(ctx, randomTraceID(r), trace)
+		require.NoError(t, err)
+		assert.Equal(t, Sampled, decision)
+		assert.Equal(t, otelsampling.AlwaysSampleThreshold, trace.SamplingThreshold)
+	}
+}
+
+func TestAdaptiveRateTraceState(t *testing.T) {
+	evaluator, err := NewAdaptiveRate(componenttest.NewNopTelemetrySettings(), AdaptiveRateSettings{
+		TracesPerSecond: 5,
+		KeyBySpanName:   true,
+	})
+	require.NoError(t, err)
+	policy := evaluator.(*adaptiveRate)
+	// a single key sampled at 50%
+	policy.keys[policy.key(newAdaptiveRateTraceData("frontend", "GET /", "").ReceivedBatches)] = &keyRate{rate: 10, probability: 0.5}
+
+	tests := []struct {
+		name       string
+		traceID    pcommon.TraceID
+		traceState string
+		decision   Decision
+		expected   string
+	}{
+		{
+			name:     "low trace ID randomness",
+			traceID:  pcommon.TraceID{15: 0xff},
+			decision: NotSampled,
+		},
+		{
+			name:     "high trace ID randomness",
+			traceID:  pcommon.TraceID{9: 0xff},
+			decision: Sampled,
+			expected: "ot=th:8",
+		},
+		{
+			name:       "explicit randomness",
+			traceID:    pcommon.TraceID{15: 0xff},
+			traceState: "ot=rv:ffffffffffffff",
+			decision:   Sampled,
+			expected:   "ot=rv:ffffffffffffff;th:8",
+		},
+		{
+			name:       "higher threshold is kept",
+			traceID:    pcommon.TraceID{9: 0xff},
+			traceState: "ot=th:c,vendor=value",
+			decision:   Sampled,
+			expected:   "ot=th:c,vendor=value",
+		},
+		{
+			name:       "lower threshold is updated",
+			traceID:    pcommon.TraceID{9: 0xff},
+			traceState: "ot=th:4,vendor=value",
+			decision:   Sampled,
+			expected:   "ot=th:8,vendor=value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace := newAdaptiveRateTraceData("frontend", "GET /", tt.traceState)
+			decision, err := policy.Evaluate(context.Background(), tt.traceID, trace)
+			require.NoError(t, err)
+			assert.Equal(t, tt.decision, decision)
+			if decision == Sampled {
+				RecordThreshold(zap.NewNop(), trace.ReceivedBatches, trace.SamplingThreshold)
+				assert.Equal(t, tt.expected, trace.ReceivedBatches.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceState().AsRaw())
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 )
 
 // TraceData stores the sampling related trace data.
@@ -26,6 +28,10 @@ type TraceData struct {
 	ReceivedBatches ptrace.Traces
 	// FinalDecision.
 	FinalDecision Decision
+	// SamplingThreshold is the threshold used by a policy sampling the trace with a probability.
+	// It is only kept when that policy alone samples the trace, and is then recorded in the
+	// tracestate of all the spans of the trace, including the late ones.
+	SamplingThreshold otelsampling.Threshold
 }
 
 // Decision gives the status of sampling decision.
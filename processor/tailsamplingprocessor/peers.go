@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+)
+
+// forwardedAttribute is set on the resource of the spans forwarded to a peer, and removed
+// by the peer when it receives them. The peer processes forwarded spans itself, even if its
+// view of the peers is different, which can happen while the peers are resolved again,
+// so that spans are never forwarded in a loop.
+const forwardedAttribute = "tail_sampling.forwarded"
+
+// traceRouter sends traces to the peer responsible for their trace ID.
+type traceRouter interface {
+	component.Component
+	// Endpoint returns the endpoint of the peer responsible for the trace ID,
+	// or an empty string if the peers are not known yet.
+	Endpoint(traceID pcommon.TraceID) string
+	// ConsumeTraces sends the traces to the peer at endpoint.
+	ConsumeTraces(ctx context.Context, endpoint string, td ptrace.Traces) error
+}
+
+func newTraceRouter(set processor.Settings, cfg PeersCfg) (traceRouter, error) {
+	return tracerouter.NewRouter(exporter.Settings{
+		ID:                set.ID,
+		TelemetrySettings: set.TelemetrySettings,
+		BuildInfo:         set.BuildInfo,
+	}, cfg.Config)
+}
+
+// forwardToPeers sends the spans of the traces that another peer is responsible for
+// to that peer, and returns the spans that have to be processed by this instance.
+// The spans that cannot be forwarded are processed by this instance.
+func (tsp *tailSamplingSpanProcessor) forwardToPeers(ctx context.Context, td ptrace.Traces) ptrace.Traces {
+	local := ptrace.NewTraces()
+	forward := map[string]ptrace.Traces{}
+	resourceSpans := td.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		if _, ok := rs.Resource().Attributes().Get(forwardedAttribute); ok {
+			forwarded := local.ResourceSpans().AppendEmpty()
+			rs.CopyTo(forwarded)
+			forwarded.Resource().Attributes().Remove(forwardedAttribute)
+			continue
+		}
+		var localSpans []spanAndScope
+		forwardSpans := map[string][]spanAndScope{}
+		for id, spans := range tsp.groupSpansByTraceKey(rs) {
+			if endpoint := tsp.router.Endpoint(id); endpoint == "" || endpoint == tsp.peerEndpoint {
+				localSpans = append(localSpans, spans...)
+			} else {
+				forwardSpans[endpoint] = append(forwardSpans[endpoint], spans...)
+			}
+		}
+		if len(localSpans) > 0 {
+			appendToTraces(local, rs, localSpans)
+		}
+		for endpoint, spans := range forwardSpans {
+			peerTraces, ok := forward[endpoint]
+			if !ok {
+				peerTraces = ptrace.NewTraces()
+				forward[endpoint] = peerTraces
+			}
+			appendToTraces(peerTraces, rs, spans)
+			peerSpans := peerTraces.ResourceSpans()
+			peerSpans.At(peerSpans.Len()-1).Resource().Attributes().PutBool(forwardedAttribute, true)
+		}
+	}
+
+	for endpoint, peerTraces := range forward {
+		if err := tsp.router.ConsumeTraces(ctx, endpoint, peerTraces); err != nil {
+			tsp.logger.Warn("Error forwarding spans to peer, processing them locally",
+				zap.String("endpoint", endpoint), zap.Error(err))
+			peerSpans := peerTraces.ResourceSpans()
+			for i := 0; i < peerSpans.Len(); i++ {
+				peerSpans.At(i).Resource().Attributes().Remove(forwardedAttribute)
+			}
+			peerSpans.MoveAndAppendTo(local.ResourceSpans())
+		}
+	}
+	return local
+}
@@ -0,0 +1,320 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/tracerouter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+const (
+	localEndpoint = "local:4317"
+	peerEndpoint  = "peer:4317"
+)
+
+type mockTraceRouter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	owners map[pcommon.TraceID]string
+	sinks  map[string]*consumertest.TracesSink
+	err    error
+}
+
+var _ traceRouter = (*mockTraceRouter)(nil)
+
+func (m *mockTraceRouter) Endpoint(traceID pcommon.TraceID) string {
+	return m.owners[traceID]
+}
+
+func (m *mockTraceRouter) ConsumeTraces(ctx context.Context, endpoint string, td ptrace.Traces) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.sinks == nil {
+		m.sinks = map[string]*consumertest.TracesSink{}
+	}
+	if m.sinks[endpoint] == nil {
+		m.sinks[endpoint] = new(consumertest.TracesSink)
+	}
+	return m.sinks[endpoint].ConsumeTraces(ctx, td)
+}
+
+func newPeersTestProcessor(t *testing.T, router traceRouter, next *consumertest.TracesSink) *tailSamplingSpanProcessor {
+	return newPeersTestProcessorWithEndpoint(t, router, next, localEndpoint)
+}
+
+func newPeersTestProcessorWithEndpoint(t *testing.T, router traceRouter, next *consumertest.TracesSink, endpoint string) *tailSamplingSpanProcessor {
+	t.Helper()
+
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		Peers:        PeersCfg{Endpoint: endpoint},
+	}
+	policies := []*policy{
+		{name: "mock-policy", evaluator: &mockPolicyEvaluator{NextDecision: sampling.Sampled}, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))},
+	}
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), next, cfg,
+		withDecisionBatcher(newSyncIDBatcher()),
+		withPolicies(policies),
+		withTraceRouter(router),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	})
+	return p.(*tailSamplingSpanProcessor)
+}
+
+func TestSpansAreForwardedToPeers(t *testing.T) {
+	localID := uInt64ToTraceID(1)
+	peerID := uInt64ToTraceID(2)
+	unknownID := uInt64ToTraceID(3)
+	router := &mockTraceRouter{owners: map[pcommon.TraceID]string{
+		localID: localEndpoint,
+		peerID:  peerEndpoint,
+	}}
+	sink := new(consumertest.TracesSink)
+	tsp := newPeersTestProcessor(t, router, sink)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "test")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for _, id := range []pcommon.TraceID{localID, peerID, unknownID} {
+		spans.AppendEmpty().SetTraceID(id)
+	}
+
+	// test
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	// verify
+	require.Len(t, router.sinks, 1)
+	require.Len(t, router.sinks[peerEndpoint].AllTraces(), 1)
+	forwarded := router.sinks[peerEndpoint].AllTraces()[0]
+	require.Equal(t, 1, forwarded.SpanCount())
+	assert.Equal(t, peerID, forwarded.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+	serviceName, _ := forwarded.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "test", serviceName.Str())
+	_, ok := forwarded.ResourceSpans().At(0).Resource().Attributes().Get(forwardedAttribute)
+	assert.True(t, ok)
+
+	// spans of traces without a known owner are kept, as the peers may not be resolved yet
+	assert.EqualValues(t, 2, tsp.numTracesOnMap.Load())
+	_, ok = tsp.idToTrace.Load(localID)
+	assert.True(t, ok)
+	_, ok = tsp.idToTrace.Load(unknownID)
+	assert.True(t, ok)
+}
+
+func TestForwardedSpansAreNotForwardedAgain(t *testing.T) {
+	peerID := uInt64ToTraceID(2)
+	router := &mockTraceRouter{owners: map[pcommon.TraceID]string{peerID: peerEndpoint}}
+	sink := new(consumertest.TracesSink)
+	tsp := newPeersTestProcessor(t, router, sink)
+
+	td := simpleTracesWithID(peerID)
+	td.ResourceSpans().At(0).Resource().Attributes().PutBool(forwardedAttribute, true)
+
+	// test
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	// verify
+	assert.Empty(t, router.sinks)
+	assertNotMarkedForwarded(t, tsp, peerID)
+	_, ok := td.ResourceSpans().At(0).Resource().Attributes().Get(forwardedAttribute)
+	assert.True(t, ok, "the received traces are not modified")
+}
+
+func TestNewTraceRouter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config).Peers
+	cfg.Endpoint = localEndpoint
+	_, err := newTraceRouter(processortest.NewNopSettings(), cfg)
+	require.Error(t, err, "a resolver is required")
+
+	cfg.Resolver.Static = &tracerouter.StaticResolver{Hostnames: []string{localEndpoint, peerEndpoint}}
+	cfg.Protocol.OTLP.Headers = map[string]configopaque.String{"authorization": "token"}
+	router, err := newTraceRouter(processortest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Contains(t, []string{localEndpoint, peerEndpoint}, router.Endpoint(uInt64ToTraceID(1)))
+	require.NoError(t, router.Shutdown(context.Background()))
+
+	// the configured headers are not modified
+	assert.Equal(t, map[string]configopaque.String{"authorization": "token"}, cfg.Protocol.OTLP.Headers)
+}
+
+func TestSpansAreProcessedLocallyWhenForwardingFails(t *testing.T) {
+	peerID := uInt64ToTraceID(2)
+	router := &mockTraceRouter{
+		owners: map[pcommon.TraceID]string{peerID: peerEndpoint},
+		err:    errors.New("peer unavailable"),
+	}
+	sink := new(consumertest.TracesSink)
+	tsp := newPeersTestProcessor(t, router, sink)
+
+	// test
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(peerID)))
+
+	// verify
+	assertNotMarkedForwarded(t, tsp, peerID)
+}
+
+// assertNotMarkedForwarded checks the trace is processed by this instance, without the forwarded marker.
+func assertNotMarkedForwarded(t *testing.T, tsp *tailSamplingSpanProcessor, traceID pcommon.TraceID) {
+	t.Helper()
+	d, ok := tsp.idToTrace.Load(traceID)
+	require.True(t, ok)
+	trace := d.(*sampling.TraceData)
+	trace.Lock()
+	defer trace.Unlock()
+	batches := trace.ReceivedBatches.ResourceSpans()
+	for i := 0; i < batches.Len(); i++ {
+		_, marked := batches.At(i).Resource().Attributes().Get(forwardedAttribute)
+		assert.False(t, marked)
+	}
+}
+
+func TestPeersConfigValidate(t *testing.T) {
+	static := tracerouter.ResolverSettings{Static: &tracerouter.StaticResolver{Hostnames: []string{localEndpoint}}}
+	assert.NoError(t, (&PeersCfg{}).Validate())
+	assert.NoError(t, (&PeersCfg{Endpoint: localEndpoint, Config: tracerouter.Config{Resolver: static}}).Validate())
+	assert.ErrorContains(t, (&PeersCfg{Endpoint: "local", Config: tracerouter.Config{Resolver: static}}).Validate(), "invalid peers endpoint")
+	assert.ErrorContains(t, (&PeersCfg{Endpoint: localEndpoint}).Validate(), "a peers resolver is required")
+
+	k8s := tracerouter.ResolverSettings{K8sSvc: &tracerouter.K8sSvcResolver{Service: "sampling.observability"}}
+	assert.NoError(t, (&PeersCfg{Endpoint: localEndpoint, Config: tracerouter.Config{Resolver: k8s}}).Validate())
+	cloudMap := tracerouter.ResolverSettings{AWSCloudMap: &tracerouter.AWSCloudMapResolver{NamespaceName: "observability", ServiceName: "sampling"}}
+	assert.NoError(t, (&PeersCfg{Endpoint: localEndpoint, Config: tracerouter.Config{Resolver: cloudMap}}).Validate())
+}
+
+// peerServer records the traces forwarded to a peer.
+type peerServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	mu        sync.Mutex
+	traces    []ptrace.Traces
+	forwarded []bool
+}
+
+func (s *peerServer) Export(_ context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	_, forwarded := req.Traces().ResourceSpans().At(0).Resource().Attributes().Get(forwardedAttribute)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traces = append(s.traces, req.Traces())
+	s.forwarded = append(s.forwarded, forwarded)
+	return ptraceotlp.NewExportResponse(), nil
+}
+
+func startPeerServer(t *testing.T, address string) (*peerServer, int32) {
+	lis, err := net.Listen("tcp", address)
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	peer := &peerServer{}
+	ptraceotlp.RegisterGRPCServer(srv, peer)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+	return peer, int32(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func TestSpansAreForwardedToK8sPeers(t *testing.T) {
+	// the peer listens on another loopback address, with the same port as this instance
+	peer, port := startPeerServer(t, "127.0.0.2:0")
+	local := fmt.Sprintf("127.0.0.1:%d", port)
+	remote := fmt.Sprintf("127.0.0.2:%d", port)
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "sampling", Namespace: "observability"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "127.0.0.1"}, {IP: "127.0.0.2"}},
+		}},
+	}
+	clt := fake.NewSimpleClientset(endpoints)
+	res, err := tracerouter.NewK8sResolver(clt, zap.NewNop(), tracerouter.K8sSvcResolver{
+		Service: "sampling.observability",
+		Ports:   []int32{port},
+	}, tracerouter.NopResolverTelemetry())
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig().(*Config).Peers
+	cfg.Endpoint = local
+	cfg.Resolver.K8sSvc = &tracerouter.K8sSvcResolver{Service: "sampling.observability"}
+	require.NoError(t, cfg.Validate())
+	otlpCfg := cfg.Protocol.OTLP
+	otlpCfg.TLSSetting.Insecure = true
+	otlpCfg.QueueConfig.Enabled = false
+	router := tracerouter.NewRouterWithResolver(exportertest.NewNopSettings(), otlpCfg, res)
+
+	sink := new(consumertest.TracesSink)
+	tsp := newPeersTestProcessorWithEndpoint(t, router, sink, local)
+
+	// find a trace owned by each of the instances
+	var localID, remoteID pcommon.TraceID
+	for i := uint64(1); localID.IsEmpty() || remoteID.IsEmpty(); i++ {
+		id := uInt64ToTraceID(i)
+		switch router.Endpoint(id) {
+		case local:
+			localID = id
+		case remote:
+			remoteID = id
+		}
+	}
+
+	// test
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(localID)))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(remoteID)))
+
+	// verify
+	_, ok := tsp.idToTrace.Load(localID)
+	assert.True(t, ok)
+	_, ok = tsp.idToTrace.Load(remoteID)
+	assert.False(t, ok, "the trace owned by the peer is forwarded to it")
+	peer.mu.Lock()
+	require.Len(t, peer.traces, 1)
+	assert.Equal(t, remoteID, peer.traces[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+	assert.True(t, peer.forwarded[0])
+	peer.mu.Unlock()
+
+	// test: the peer leaves the service
+	endpoints.Subsets[0].Addresses = endpoints.Subsets[0].Addresses[:1]
+	_, err = clt.CoreV1().Endpoints("observability").Update(context.Background(), endpoints, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify: this instance owns all the traces
+	assert.Eventually(t, func() bool {
+		return router.Endpoint(remoteID) == local
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(remoteID)))
+	_, ok = tsp.idToTrace.Load(remoteID)
+	assert.True(t, ok)
+}
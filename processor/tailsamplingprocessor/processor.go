@@ -5,6 +5,7 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"runtime"
@@ -14,6 +15,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
@@ -22,6 +24,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
@@ -59,6 +62,18 @@ type tailSamplingSpanProcessor struct {
 	nonSampledIDCache cache.Cache[bool]
 	deleteChan        chan pcommon.TraceID
 	numTracesOnMap    *atomic.Uint64
+
+	storageID        *component.ID
+	componentID      component.ID
+	storageClient    storage.Client
+	traceIndex       traceIndex
+	dirtyTraces      dirtyTraces
+	snapshotInterval time.Duration
+	stopSnapshots    chan struct{}
+	snapshotsDone    chan struct{}
+
+	router       traceRouter
+	peerEndpoint string
 }
 
 // spanAndScope a structure for holding information about span and its instrumentation scope.
@@ -116,6 +131,11 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		logger:            telemetrySettings.Logger,
 		numTracesOnMap:    &atomic.Uint64{},
 		deleteChan:        make(chan pcommon.TraceID, cfg.NumTraces),
+		storageID:         cfg.StorageID,
+		componentID:       set.ID,
+		snapshotInterval:  cfg.DecisionWait,
+		traceIndex:        traceIndex{traces: make(map[pcommon.TraceID]*persistedTrace)},
+		dirtyTraces:       dirtyTraces{traces: make(map[pcommon.TraceID]bool)},
 	}
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 
@@ -127,6 +147,17 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		tsp.tickerFrequency = time.Second
 	}
 
+	if tsp.snapshotInterval <= 0 {
+		tsp.snapshotInterval = time.Second
+	}
+
+	if tsp.router == nil && cfg.Peers.Endpoint != "" {
+		if tsp.router, err = newTraceRouter(set, cfg.Peers); err != nil {
+			return nil, err
+		}
+	}
+	tsp.peerEndpoint = cfg.Peers.Endpoint
+
 	if tsp.policies == nil {
 		policyNames := map[string]bool{}
 		tsp.policies = make([]*policy, len(cfg.PolicyCfgs))
@@ -198,6 +229,13 @@ func withSampledDecisionCache(c cache.Cache[bool]) Option {
 	}
 }
 
+// withTraceRouter sets the router used to forward spans to the peer responsible for them.
+func withTraceRouter(router traceRouter) Option {
+	return func(tsp *tailSamplingSpanProcessor) {
+		tsp.router = router
+	}
+}
+
 // withSampledDecisionCache sets the cache which the processor uses to store recently sampled trace IDs.
 func withNonSampledDecisionCache(c cache.Cache[bool]) Option {
 	return func(tsp *tailSamplingSpanProcessor) {
@@ -252,6 +290,14 @@ func getSharedPolicyEvaluator(settings component.TelemetrySettings, cfg *sharedP
 	case OTTLCondition:
 		ottlfCfg := cfg.OTTLConditionCfg
 		return sampling.NewOTTLConditionFilter(settings, ottlfCfg.SpanConditions, ottlfCfg.SpanEventConditions, ottlfCfg.ErrorMode)
+	case AdaptiveRate:
+		arCfg := cfg.AdaptiveRateCfg
+		return sampling.NewAdaptiveRate(settings, sampling.AdaptiveRateSettings{
+			TracesPerSecond:    arCfg.TracesPerSecond,
+			KeyAttributes:      arCfg.KeyAttributes,
+			KeyBySpanName:      arCfg.KeyBySpanName,
+			AdjustmentInterval: arCfg.AdjustmentInterval,
+		})
 
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
@@ -288,11 +334,14 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		// Sampled or not, remove the batches
 		trace.Lock()
 		allSpans := trace.ReceivedBatches
+		threshold := trace.SamplingThreshold
 		trace.FinalDecision = decision
 		trace.ReceivedBatches = ptrace.NewTraces()
 		trace.Unlock()
+		tsp.markTrace(id, false)
 
 		if decision == sampling.Sampled {
+			tsp.recordThreshold(allSpans, threshold)
 			tsp.releaseSampledTrace(context.Background(), id, allSpans)
 		}
 	}
@@ -316,6 +365,9 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		sampling.InvertNotSampled: false,
 	}
 
+	// the number of policies keeping the trace
+	sampledBy := 0
+
 	ctx := context.Background()
 	// Check all policies before making a final decision
 	for _, p := range tsp.policies {
@@ -333,6 +385,9 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 			}
 
 			samplingDecision[decision] = true
+			if decision == sampling.Sampled || decision == sampling.InvertSampled {
+				sampledBy++
+			}
 		}
 	}
 
@@ -346,11 +401,22 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		finalDecision = sampling.Sampled
 	}
 
+	// The threshold of a policy sampling the trace with a probability is dropped when another policy
+	// keeps the trace as well, the adjusted count of its spans would otherwise be too high.
+	if finalDecision != sampling.Sampled || sampledBy != 1 {
+		trace.Lock()
+		trace.SamplingThreshold = otelsampling.AlwaysSampleThreshold
+		trace.Unlock()
+	}
+
 	return finalDecision
 }
 
 // ConsumeTraces is required by the processor.Traces interface.
-func (tsp *tailSamplingSpanProcessor) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+func (tsp *tailSamplingSpanProcessor) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	if tsp.router != nil {
+		td = tsp.forwardToPeers(ctx, td)
+	}
 	resourceSpans := td.ResourceSpans()
 	for i := 0; i < resourceSpans.Len(); i++ {
 		tsp.processTraces(resourceSpans.At(i))
@@ -439,11 +505,13 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 		// The only thing we really care about here is the final decision.
 		actualData.Lock()
 		finalDecision := actualData.FinalDecision
+		threshold := actualData.SamplingThreshold
 
 		if finalDecision == sampling.Unspecified {
 			// If the final decision hasn't been made, add the new spans under the lock.
 			appendToTraces(actualData.ReceivedBatches, resourceSpans, spans)
 			actualData.Unlock()
+			tsp.markTrace(id, false)
 		} else {
 			actualData.Unlock()
 
@@ -452,6 +520,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 				// Forward the spans to the policy destinations
 				traceTd := ptrace.NewTraces()
 				appendToTraces(traceTd, resourceSpans, spans)
+				tsp.recordThreshold(traceTd, threshold)
 				tsp.releaseSampledTrace(tsp.ctx, id, traceTd)
 			case sampling.NotSampled:
 				tsp.nonSampledIDCache.Put(id, true)
@@ -471,16 +540,30 @@ func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.storageID != nil {
+		if err := tsp.startStorage(ctx, host); err != nil {
+			return err
+		}
+	}
+	if tsp.router != nil {
+		if err := tsp.router.Start(ctx, host); err != nil {
+			return err
+		}
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	if tsp.router != nil {
+		err = tsp.router.Shutdown(ctx)
+	}
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
-	return nil
+	return errors.Join(err, tsp.shutdownStorage(ctx))
 }
 
 func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletionTime time.Time) {
@@ -495,10 +578,18 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletio
 		tsp.logger.Debug("Attempt to delete traceID not on table")
 		return
 	}
+	tsp.markTrace(traceID, true)
 
 	tsp.telemetry.ProcessorTailSamplingSamplingTraceRemovalAge.Record(tsp.ctx, int64(deletionTime.Sub(trace.ArrivalTime)/time.Second))
 }
 
+// recordThreshold sets the threshold used to sample the trace in the tracestate of its spans.
+func (tsp *tailSamplingSpanProcessor) recordThreshold(td ptrace.Traces, threshold otelsampling.Threshold) {
+	if threshold != otelsampling.AlwaysSampleThreshold {
+		sampling.RecordThreshold(tsp.logger, td, threshold)
+	}
+}
+
 // releaseSampledTrace sends the trace data to the next consumer.
 // It additionally adds the trace ID to the cache of sampled trace IDs.
 // It does not (yet) delete the spans from the internal map.
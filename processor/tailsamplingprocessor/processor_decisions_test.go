@@ -8,13 +8,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -474,3 +478,76 @@ func TestLateSpanUsesNonSampledDecisionCache(t *testing.T) {
 	require.EqualValues(t, 1, mpe.EvaluationCount)
 	require.EqualValues(t, 0, nextConsumer.SpanCount(), "original final decision not honored")
 }
+
+// mockThresholdPolicyEvaluator samples the traces with a threshold, like the adaptive_rate policy.
+type mockThresholdPolicyEvaluator struct {
+	threshold otelsampling.Threshold
+}
+
+func (m *mockThresholdPolicyEvaluator) Evaluate(_ context.Context, _ pcommon.TraceID, trace *sampling.TraceData) (sampling.Decision, error) {
+	trace.Lock()
+	defer trace.Unlock()
+	trace.SamplingThreshold = m.threshold
+	return sampling.Sampled, nil
+}
+
+func TestSamplingThresholdRecordedWhenOnlyPolicySampling(t *testing.T) {
+	threshold, err := otelsampling.ProbabilityToThreshold(0.5)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		otherPolicy   sampling.Decision
+		expectedState string
+	}{
+		{
+			name:          "only policy sampling",
+			otherPolicy:   sampling.NotSampled,
+			expectedState: "ot=th:8",
+		},
+		{
+			name:        "another policy sampling",
+			otherPolicy: sampling.Sampled,
+		},
+		{
+			name:        "another inverted policy sampling",
+			otherPolicy: sampling.InvertSampled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				DecisionWait: defaultTestDecisionWait,
+				NumTraces:    defaultNumTraces,
+			}
+			nextConsumer := new(consumertest.TracesSink)
+			idb := newSyncIDBatcher()
+			policies := []*policy{
+				{name: "threshold", evaluator: &mockThresholdPolicyEvaluator{threshold: threshold}, attribute: metric.WithAttributes(attribute.String("policy", "threshold"))},
+				{name: "other", evaluator: &mockPolicyEvaluator{NextDecision: tt.otherPolicy}, attribute: metric.WithAttributes(attribute.String("policy", "other"))},
+			}
+			p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies))
+			require.NoError(t, err)
+			require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, p.Shutdown(context.Background()))
+			}()
+			tsp := p.(*tailSamplingSpanProcessor)
+
+			id := uInt64ToTraceID(1)
+			require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+			tsp.policyTicker.OnTick()
+			tsp.policyTicker.OnTick()
+
+			// a late span of the same trace
+			require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+
+			// verify
+			require.Len(t, nextConsumer.AllTraces(), 2)
+			for _, td := range nextConsumer.AllTraces() {
+				span := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+				assert.Equal(t, tt.expectedState, span.TraceState().AsRaw())
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+
+import (
+	"context"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+const (
+	sampledDecisionsKey    = "sampled_decisions"
+	nonSampledDecisionsKey = "non_sampled_decisions"
+	// numTracesKey is the storage key holding the number of traces in the index.
+	numTracesKey = "num_traces"
+	// traceSlotKeyPrefix prefixes the key holding the ID of the trace in a slot of the index,
+	// the slots being numbered from 0 to the number of traces.
+	traceSlotKeyPrefix = "trace_slot_"
+	// traceKeyPrefix prefixes the key holding the header of a trace, followed by the hex encoded
+	// trace ID. The chunks holding the spans of the trace add their number to this key.
+	traceKeyPrefix = "trace_"
+
+	// traceHeaderSize is the size of the header of a trace: the trace ID, the arrival and decision
+	// times, the span count, the sampling threshold, the final decision and the number of chunks.
+	traceHeaderSize = 16 + 8 + 8 + 8 + 8 + 4 + 4
+)
+
+var errInvalidSnapshot = errors.New("invalid snapshot found in storage")
+
+// dirtyTraces holds the traces changed since the last snapshot, and whether they were dropped.
+// Marking a trace is all the work done when spans are received, the traces are written by the snapshots.
+type dirtyTraces struct {
+	sync.Mutex
+	traces map[pcommon.TraceID]bool
+}
+
+// traceIndex holds the traces written to the storage by slot, so that they can be found on
+// start. Adding or removing a trace writes a few keys only, as the trace in the last slot
+// is moved to the slot a removed trace frees. It is only used by the snapshots.
+type traceIndex struct {
+	sync.Mutex
+	slots  []pcommon.TraceID
+	traces map[pcommon.TraceID]*persistedTrace
+}
+
+// persistedTrace is the state of a trace written to the storage.
+type persistedTrace struct {
+	trace *sampling.TraceData
+	slot  int
+	// chunks is the number of chunks written, each holding the resource spans received
+	// between two snapshots, and batches the number of resource spans they hold.
+	chunks  int
+	batches int
+}
+
+// startStorage gets the storage client and restores the traces and decisions found in it.
+func (tsp *tailSamplingSpanProcessor) startStorage(ctx context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[*tsp.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", tsp.storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", tsp.storageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, tsp.componentID, "")
+	if err != nil {
+		return fmt.Errorf("failed to get storage client: %w", err)
+	}
+	tsp.storageClient = client
+
+	if err = tsp.restore(ctx); err != nil {
+		return err
+	}
+
+	tsp.stopSnapshots = make(chan struct{})
+	tsp.snapshotsDone = make(chan struct{})
+	go tsp.periodicSnapshots()
+	return nil
+}
+
+// shutdownStorage stops the periodic snapshots of the decision caches, takes the last one and closes the storage client.
+func (tsp *tailSamplingSpanProcessor) shutdownStorage(ctx context.Context) error {
+	if tsp.storageClient == nil {
+		return nil
+	}
+	if tsp.stopSnapshots != nil {
+		close(tsp.stopSnapshots)
+		<-tsp.snapshotsDone
+	}
+	return errors.Join(tsp.snapshot(ctx), tsp.storageClient.Close(ctx))
+}
+
+func (tsp *tailSamplingSpanProcessor) periodicSnapshots() {
+	defer close(tsp.snapshotsDone)
+
+	ticker := time.NewTicker(tsp.snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := tsp.snapshot(context.Background()); err != nil {
+				tsp.logger.Warn("Failed to snapshot the sampling state", zap.Error(err))
+			}
+		case <-tsp.stopSnapshots:
+			return
+		}
+	}
+}
+
+// snapshot writes the traces changed since the last snapshot and the decision caches to the storage.
+func (tsp *tailSamplingSpanProcessor) snapshot(ctx context.Context) error {
+	ops, err := tsp.snapshotTraces()
+	if err != nil {
+		return err
+	}
+	for key, c := range map[string]cache.Cache[bool]{
+		sampledDecisionsKey:    tsp.sampledIDCache,
+		nonSampledDecisionsKey: tsp.nonSampledIDCache,
+	} {
+		m, ok := c.(encoding.BinaryMarshaler)
+		if !ok {
+			continue
+		}
+		content, err := m.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to marshal decision cache: %w", err)
+		}
+		ops = append(ops, storage.SetOperation(key, content))
+	}
+	return tsp.storageClient.Batch(ctx, ops...)
+}
+
+// markTrace records that a trace changed, or was dropped, so that the next snapshot writes it.
+func (tsp *tailSamplingSpanProcessor) markTrace(id pcommon.TraceID, dropped bool) {
+	if tsp.storageClient == nil {
+		return
+	}
+	tsp.dirtyTraces.Lock()
+	tsp.dirtyTraces.traces[id] = dropped
+	tsp.dirtyTraces.Unlock()
+}
+
+// snapshotTraces returns the operations writing the traces changed since the last snapshot. Only
+// the header of a trace and the resource spans it received since the last snapshot are written.
+func (tsp *tailSamplingSpanProcessor) snapshotTraces() ([]storage.Operation, error) {
+	tsp.dirtyTraces.Lock()
+	dirty := tsp.dirtyTraces.traces
+	tsp.dirtyTraces.traces = make(map[pcommon.TraceID]bool)
+	tsp.dirtyTraces.Unlock()
+
+	tsp.traceIndex.Lock()
+	defer tsp.traceIndex.Unlock()
+	numTraces := len(tsp.traceIndex.slots)
+	var ops []storage.Operation
+	for id, dropped := range dirty {
+		var trace *sampling.TraceData
+		if d, ok := tsp.idToTrace.Load(id); ok && !dropped {
+			trace = d.(*sampling.TraceData)
+		}
+		p, ok := tsp.traceIndex.traces[id]
+		if ok && p.trace != trace {
+			// the trace was dropped, and possibly received again since
+			ops = tsp.traceIndex.remove(id, ops)
+			ok = false
+		}
+		if trace == nil {
+			continue
+		}
+		if !ok {
+			p = tsp.traceIndex.add(id, trace)
+			ops = append(ops, storage.SetOperation(traceSlotKey(p.slot), id[:]))
+		}
+		var err error
+		if ops, err = p.write(id, ops); err != nil {
+			return nil, err
+		}
+	}
+	if len(tsp.traceIndex.slots) != numTraces {
+		ops = append(ops, storage.SetOperation(numTracesKey, binary.BigEndian.AppendUint64(nil, uint64(len(tsp.traceIndex.slots)))))
+	}
+	return ops, nil
+}
+
+func (ti *traceIndex) add(id pcommon.TraceID, trace *sampling.TraceData) *persistedTrace {
+	p := &persistedTrace{trace: trace, slot: len(ti.slots)}
+	ti.slots = append(ti.slots, id)
+	ti.traces[id] = p
+	return p
+}
+
+// remove appends the operations deleting a trace from the storage, and moving the trace
+// in the last slot of the index to the slot it frees.
+func (ti *traceIndex) remove(id pcommon.TraceID, ops []storage.Operation) []storage.Operation {
+	p := ti.traces[id]
+	ops = append(ops, storage.DeleteOperation(traceKey(id)))
+	for chunk := 0; chunk < p.chunks; chunk++ {
+		ops = append(ops, storage.DeleteOperation(traceChunkKey(id, chunk)))
+	}
+	last := len(ti.slots) - 1
+	if p.slot != last {
+		moved := ti.slots[last]
+		ti.slots[p.slot] = moved
+		ti.traces[moved].slot = p.slot
+		ops = append(ops, storage.SetOperation(traceSlotKey(p.slot), moved[:]))
+	}
+	ti.slots = ti.slots[:last]
+	delete(ti.traces, id)
+	return append(ops, storage.DeleteOperation(traceSlotKey(last)))
+}
+
+// write appends the operations writing the header of the trace, and a chunk with the resource spans
+// received since the last snapshot. Once the trace is decided, its chunks are deleted and only its
+// decision is kept for the late spans.
+func (p *persistedTrace) write(id pcommon.TraceID, ops []storage.Operation) ([]storage.Operation, error) {
+	p.trace.Lock()
+	defer p.trace.Unlock()
+
+	if p.trace.FinalDecision == sampling.Unspecified {
+		rss := p.trace.ReceivedBatches.ResourceSpans()
+		if rss.Len() > p.batches {
+			chunk := ptrace.NewTraces()
+			for i := p.batches; i < rss.Len(); i++ {
+				rss.At(i).CopyTo(chunk.ResourceSpans().AppendEmpty())
+			}
+			var marshaler ptrace.ProtoMarshaler
+			content, err := marshaler.MarshalTraces(chunk)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal trace: %w", err)
+			}
+			ops = append(ops, storage.SetOperation(traceChunkKey(id, p.chunks), content))
+			p.chunks++
+			p.batches = rss.Len()
+		}
+	} else {
+		for chunk := 0; chunk < p.chunks; chunk++ {
+			ops = append(ops, storage.DeleteOperation(traceChunkKey(id, chunk)))
+		}
+		p.chunks, p.batches = 0, 0
+	}
+	return append(ops, storage.SetOperation(traceKey(id), marshalTraceHeader(id, p.trace, p.chunks))), nil
+}
+
+func traceSlotKey(slot int) string {
+	return traceSlotKeyPrefix + strconv.Itoa(slot)
+}
+
+func traceKey(id pcommon.TraceID) string {
+	return traceKeyPrefix + hex.EncodeToString(id[:])
+}
+
+func traceChunkKey(id pcommon.TraceID, chunk int) string {
+	return traceKey(id) + "_" + strconv.Itoa(chunk)
+}
+
+// marshalTraceHeader encodes the state of the trace, its spans being written in chunks.
+func marshalTraceHeader(id pcommon.TraceID, trace *sampling.TraceData, chunks int) []byte {
+	content := make([]byte, 0, traceHeaderSize)
+	content = append(content, id[:]...)
+	content = binary.BigEndian.AppendUint64(content, uint64(trace.ArrivalTime.UnixNano()))
+	content = binary.BigEndian.AppendUint64(content, uint64(unixNanoOrZero(trace.DecisionTime)))
+	content = binary.BigEndian.AppendUint64(content, uint64(trace.SpanCount.Load()))
+	content = binary.BigEndian.AppendUint64(content, trace.SamplingThreshold.Unsigned())
+	content = binary.BigEndian.AppendUint32(content, uint32(trace.FinalDecision))
+	return binary.BigEndian.AppendUint32(content, uint32(chunks))
+}
+
+// restore adds the traces and decisions found in the storage. The traces still
+// waiting for a decision are evaluated after decision_wait, as new traces would be.
+func (tsp *tailSamplingSpanProcessor) restore(ctx context.Context) error {
+	numTraces := storage.GetOperation(numTracesKey)
+	sampled := storage.GetOperation(sampledDecisionsKey)
+	nonSampled := storage.GetOperation(nonSampledDecisionsKey)
+	if err := tsp.storageClient.Batch(ctx, numTraces, sampled, nonSampled); err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	for _, op := range []struct {
+		content []byte
+		cache   cache.Cache[bool]
+	}{
+		{sampled.Value, tsp.sampledIDCache},
+		{nonSampled.Value, tsp.nonSampledIDCache},
+	} {
+		u, ok := op.cache.(encoding.BinaryUnmarshaler)
+		if op.content == nil || !ok {
+			continue
+		}
+		if err := u.UnmarshalBinary(op.content); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidSnapshot, err)
+		}
+	}
+
+	restored, err := tsp.readTraces(ctx, numTraces.Value)
+	if err != nil {
+		return err
+	}
+	// restore the oldest traces first, so that they are also the first ones to be dropped
+	sort.Slice(restored, func(i, j int) bool {
+		return restored[i].trace.ArrivalTime.Before(restored[j].trace.ArrivalTime)
+	})
+	for _, r := range restored {
+		if _, loaded := tsp.idToTrace.LoadOrStore(r.id, r.trace); loaded {
+			continue
+		}
+		tsp.numTracesOnMap.Add(1)
+		if r.trace.FinalDecision == sampling.Unspecified {
+			tsp.decisionBatcher.AddToCurrentBatch(r.id)
+		}
+		select {
+		case tsp.deleteChan <- r.id:
+		default:
+			tsp.dropTrace(<-tsp.deleteChan, time.Now())
+			tsp.deleteChan <- r.id
+		}
+	}
+	if len(restored) > 0 {
+		tsp.logger.Info("Restored traces from storage", zap.Int("traces", len(restored)))
+	}
+	return nil
+}
+
+// readTraces reads the index and the traces it holds.
+func (tsp *tailSamplingSpanProcessor) readTraces(ctx context.Context, numTraces []byte) ([]restoredTrace, error) {
+	if numTraces == nil {
+		return nil, nil
+	}
+	if len(numTraces) != 8 {
+		return nil, errInvalidSnapshot
+	}
+	ops := make([]storage.Operation, binary.BigEndian.Uint64(numTraces))
+	for slot := range ops {
+		ops[slot] = storage.GetOperation(traceSlotKey(slot))
+	}
+	if err := tsp.storageClient.Batch(ctx, ops...); err != nil {
+		return nil, fmt.Errorf("failed to read the trace index: %w", err)
+	}
+	ids := make([]pcommon.TraceID, len(ops))
+	for slot, op := range ops {
+		if len(op.Value) != 16 {
+			return nil, fmt.Errorf("trace slot %d: %w", slot, errInvalidSnapshot)
+		}
+		ids[slot] = pcommon.TraceID(op.Value)
+		ops[slot] = storage.GetOperation(traceKey(ids[slot]))
+	}
+	if err := tsp.storageClient.Batch(ctx, ops...); err != nil {
+		return nil, fmt.Errorf("failed to read the traces: %w", err)
+	}
+
+	result := make([]restoredTrace, 0, len(ops))
+	var chunkOps []storage.Operation
+	for slot, op := range ops {
+		r, chunks, err := unmarshalTraceHeader(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("trace %s: %w", ids[slot], err)
+		}
+		if r.id != ids[slot] {
+			return nil, fmt.Errorf("trace slot %d: %w", slot, errInvalidSnapshot)
+		}
+		p := tsp.traceIndex.add(r.id, r.trace)
+		p.chunks = chunks
+		for chunk := 0; chunk < chunks; chunk++ {
+			chunkOps = append(chunkOps, storage.GetOperation(traceChunkKey(r.id, chunk)))
+		}
+		result = append(result, r)
+	}
+	if err := tsp.storageClient.Batch(ctx, chunkOps...); err != nil {
+		return nil, fmt.Errorf("failed to read the traces: %w", err)
+	}
+
+	var unmarshaler ptrace.ProtoUnmarshaler
+	for _, r := range result {
+		p := tsp.traceIndex.traces[r.id]
+		for chunk := 0; chunk < p.chunks; chunk++ {
+			op := chunkOps[0]
+			chunkOps = chunkOps[1:]
+			if op.Value == nil {
+				return nil, fmt.Errorf("trace %s chunk %d: %w", r.id, chunk, errInvalidSnapshot)
+			}
+			batches, err := unmarshaler.UnmarshalTraces(op.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", errInvalidSnapshot, err)
+			}
+			batches.ResourceSpans().MoveAndAppendTo(r.trace.ReceivedBatches.ResourceSpans())
+		}
+		p.batches = r.trace.ReceivedBatches.ResourceSpans().Len()
+	}
+	return result, nil
+}
+
+type restoredTrace struct {
+	id    pcommon.TraceID
+	trace *sampling.TraceData
+}
+
+// unmarshalTraceHeader decodes the state of a trace and returns the number of chunks holding its spans.
+func unmarshalTraceHeader(content []byte) (restoredTrace, int, error) {
+	if len(content) != traceHeaderSize {
+		return restoredTrace{}, 0, errInvalidSnapshot
+	}
+	var id pcommon.TraceID
+	copy(id[:], content[:16])
+	spanCount := &atomic.Int64{}
+	spanCount.Store(int64(binary.BigEndian.Uint64(content[32:40])))
+	threshold, err := otelsampling.UnsignedToThreshold(binary.BigEndian.Uint64(content[40:48]))
+	if err != nil {
+		return restoredTrace{}, 0, fmt.Errorf("%w: %w", errInvalidSnapshot, err)
+	}
+	trace := &sampling.TraceData{
+		ArrivalTime:       time.Unix(0, int64(binary.BigEndian.Uint64(content[16:24]))),
+		SpanCount:         spanCount,
+		FinalDecision:     sampling.Decision(binary.BigEndian.Uint32(content[48:52])),
+		SamplingThreshold: threshold,
+		ReceivedBatches:   ptrace.NewTraces(),
+	}
+	if decisionTime := int64(binary.BigEndian.Uint64(content[24:32])); decisionTime != 0 {
+		trace.DecisionTime = time.Unix(0, decisionTime)
+	}
+	return restoredTrace{id: id, trace: trace}, int(binary.BigEndian.Uint32(content[52:56])), nil
+}
+
+func unixNanoOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	otelsampling "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/sampling"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+func newStorageTestProcessor(t *testing.T, storageExt *storagetest.TestStorage, next *consumertest.TracesSink, mpe *mockPolicyEvaluator) *tailSamplingSpanProcessor {
+	t.Helper()
+
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		DecisionCache: DecisionCacheConfig{
+			SampledCacheSize:    100,
+			NonSampledCacheSize: 100,
+		},
+		StorageID: &storageExt.ID,
+	}
+	set := processortest.NewNopSettings()
+	set.ID = component.MustNewID("tail_sampling")
+	policies := []*policy{
+		{name: "mock-policy", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy"))},
+	}
+	p, err := newTracesProcessor(context.Background(), set, next, cfg,
+		withDecisionBatcher(newSyncIDBatcher()),
+		withPolicies(policies),
+		withTickerFrequency(time.Hour),
+	)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), storagetest.NewStorageHost().WithExtension(storageExt.ID, storageExt)))
+	return p.(*tailSamplingSpanProcessor)
+}
+
+func TestStateIsRestoredAfterRestart(t *testing.T) {
+	storageExt := storagetest.NewFileBackedStorageExtension("test", t.TempDir())
+	sampledID := uInt64ToTraceID(1)
+	notSampledID := uInt64ToTraceID(2)
+	pendingID := uInt64ToTraceID(3)
+
+	// decide on the first two traces and leave the last one pending
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	sink := new(consumertest.TracesSink)
+	tsp := newStorageTestProcessor(t, storageExt, sink, mpe)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(sampledID)))
+	tsp.policyTicker.OnTick()
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(notSampledID)))
+	tsp.policyTicker.OnTick()
+	mpe.NextDecision = sampling.NotSampled
+	tsp.policyTicker.OnTick()
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(pendingID)))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	require.Equal(t, 2, mpe.EvaluationCount)
+	require.Len(t, sink.AllTraces(), 1)
+
+	// restart
+	mpe = &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	sink = new(consumertest.TracesSink)
+	tsp = newStorageTestProcessor(t, storageExt, sink, mpe)
+	defer func() {
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}()
+	assert.EqualValues(t, 3, tsp.numTracesOnMap.Load())
+
+	// the pending trace is evaluated once decision_wait elapses
+	tsp.policyTicker.OnTick()
+	require.Empty(t, sink.AllTraces())
+	tsp.policyTicker.OnTick()
+	require.Equal(t, 1, mpe.EvaluationCount)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, simpleTracesWithID(pendingID), sink.AllTraces()[0])
+
+	// late spans get the decision taken before the restart
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(sampledID)))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(notSampledID)))
+	require.Len(t, sink.AllTraces(), 2)
+	assert.Equal(t, simpleTracesWithID(sampledID), sink.AllTraces()[1])
+	_, ok := tsp.sampledIDCache.Get(sampledID)
+	assert.True(t, ok)
+	_, ok = tsp.nonSampledIDCache.Get(notSampledID)
+	assert.True(t, ok)
+	require.Equal(t, 1, mpe.EvaluationCount)
+}
+
+func TestStorageStartErrors(t *testing.T) {
+	storageID := storagetest.NewStorageID("missing")
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		StorageID:    &storageID,
+	}
+	p, err := newTracesProcessor(context.Background(), processortest.NewNopSettings(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Start(context.Background(), componenttest.NewNopHost()), "storage extension 'test_storage/missing' not found")
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	nonStorageID := storagetest.NewNonStorageID("non")
+	cfg.StorageID = &nonStorageID
+	p, err = newTracesProcessor(context.Background(), processortest.NewNopSettings(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.ErrorContains(t, p.Start(context.Background(), storagetest.NewStorageHost().WithNonStorageExtension("non")), "non-storage extension")
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestMarshalTraceHeader(t *testing.T) {
+	threshold, err := otelsampling.TValueToThreshold("8")
+	require.NoError(t, err)
+	expected := newTraceData(sampling.Sampled)
+	expected.SamplingThreshold = threshold
+	content := marshalTraceHeader(uInt64ToTraceID(1), expected, 2)
+
+	r, chunks, err := unmarshalTraceHeader(content)
+	require.NoError(t, err)
+	assert.Equal(t, 2, chunks)
+	assert.Equal(t, uInt64ToTraceID(1), r.id)
+	assert.True(t, expected.ArrivalTime.Equal(r.trace.ArrivalTime))
+	assert.True(t, expected.DecisionTime.Equal(r.trace.DecisionTime))
+	assert.Equal(t, expected.FinalDecision, r.trace.FinalDecision)
+	assert.Equal(t, threshold, r.trace.SamplingThreshold)
+	assert.Equal(t, int64(1), r.trace.SpanCount.Load())
+	assert.Equal(t, 0, r.trace.ReceivedBatches.SpanCount())
+
+	_, _, err = unmarshalTraceHeader(content[:traceHeaderSize-1])
+	assert.ErrorIs(t, err, errInvalidSnapshot)
+	_, _, err = unmarshalTraceHeader(append(content, 0))
+	assert.ErrorIs(t, err, errInvalidSnapshot)
+}
+
+func TestTracesAreWrittenBySnapshots(t *testing.T) {
+	storageExt := storagetest.NewInMemoryStorageExtension("test")
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	tsp := newStorageTestProcessor(t, storageExt, new(consumertest.TracesSink), mpe)
+	defer func() {
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}()
+	get := func(key string) []byte {
+		value, err := tsp.storageClient.Get(context.Background(), key)
+		require.NoError(t, err)
+		return value
+	}
+	getHeader := func(id pcommon.TraceID) (*sampling.TraceData, int) {
+		content := get(traceKey(id))
+		if content == nil {
+			return nil, 0
+		}
+		r, chunks, err := unmarshalTraceHeader(content)
+		require.NoError(t, err)
+		return r.trace, chunks
+	}
+	getChunk := func(id pcommon.TraceID, chunk int) ptrace.Traces {
+		content := get(traceChunkKey(id, chunk))
+		require.NotNil(t, content)
+		var unmarshaler ptrace.ProtoUnmarshaler
+		td, err := unmarshaler.UnmarshalTraces(content)
+		require.NoError(t, err)
+		return td
+	}
+
+	// the traces are not written as they are received
+	id := uInt64ToTraceID(1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	trace, _ := getHeader(id)
+	assert.Nil(t, trace)
+
+	// a snapshot indexes the trace and writes its spans in a chunk
+	require.NoError(t, tsp.snapshot(context.Background()))
+	trace, chunks := getHeader(id)
+	require.NotNil(t, trace)
+	assert.Equal(t, 1, chunks)
+	assert.Equal(t, 2, getChunk(id, 0).SpanCount())
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, get(numTracesKey))
+	assert.Equal(t, id[:], get(traceSlotKey(0)))
+
+	// the next snapshot only writes the spans received since the last one
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(id)))
+	require.NoError(t, tsp.snapshot(context.Background()))
+	trace, chunks = getHeader(id)
+	assert.Equal(t, int64(3), trace.SpanCount.Load())
+	assert.Equal(t, 2, chunks)
+	assert.Equal(t, 2, getChunk(id, 0).SpanCount())
+	assert.Equal(t, 1, getChunk(id, 1).SpanCount())
+
+	// the chunks are deleted once the trace is decided, the decision is kept for the late spans
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+	require.NoError(t, tsp.snapshot(context.Background()))
+	trace, chunks = getHeader(id)
+	require.NotNil(t, trace)
+	assert.Equal(t, sampling.Sampled, trace.FinalDecision)
+	assert.Equal(t, 0, chunks)
+	assert.Nil(t, get(traceChunkKey(id, 0)))
+	assert.Nil(t, get(traceChunkKey(id, 1)))
+
+	// the trace is deleted once dropped from memory, the last trace of the index takes its slot
+	other := uInt64ToTraceID(2)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), simpleTracesWithID(other)))
+	require.NoError(t, tsp.snapshot(context.Background()))
+	tsp.dropTrace(id, time.Now())
+	require.NoError(t, tsp.snapshot(context.Background()))
+	trace, _ = getHeader(id)
+	assert.Nil(t, trace)
+	trace, _ = getHeader(other)
+	assert.NotNil(t, trace)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1}, get(numTracesKey))
+	assert.Equal(t, other[:], get(traceSlotKey(0)))
+	assert.Nil(t, get(traceSlotKey(1)))
+}
+
+func newTraceData(decision sampling.Decision) *sampling.TraceData {
+	spanCount := &atomic.Int64{}
+	spanCount.Store(1)
+	trace := &sampling.TraceData{
+		ArrivalTime:     time.Now(),
+		SpanCount:       spanCount,
+		ReceivedBatches: simpleTracesWithID(pcommon.NewTraceIDEmpty()),
+		FinalDecision:   decision,
+	}
+	if decision != sampling.Unspecified {
+		trace.DecisionTime = time.Now()
+	}
+	return trace
+}
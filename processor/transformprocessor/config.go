@@ -41,16 +41,21 @@ type Config struct {
 	LogStatements    []common.ContextStatements `mapstructure:"log_statements"`
 
 	FlattenData bool `mapstructure:"flatten_data"`
-	logger      *zap.Logger
+
+	// LookupTables is the ID of the lookup_tables extension holding the tables of the Lookup function.
+	LookupTables *component.ID `mapstructure:"lookup_tables"`
+
+	logger *zap.Logger
 }
 
 var _ component.Config = (*Config)(nil)
 
 func (c *Config) Validate() error {
 	var errors error
+	lookupTables := c.lookupTables()
 
 	if len(c.TraceStatements) > 0 {
-		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()))
+		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions(lookupTables)), common.WithSpanEventParser(traces.SpanEventFunctions(lookupTables)), common.WithTraceLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -63,7 +68,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.MetricStatements) > 0 {
-		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()))
+		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions(lookupTables)), common.WithDataPointParser(metrics.DataPointFunctions(lookupTables)), common.WithMetricLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -76,7 +81,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.LogStatements) > 0 {
-		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions()))
+		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions(lookupTables)), common.WithLogLookupTables(lookupTables))
 		if err != nil {
 			return err
 		}
@@ -94,3 +99,11 @@ func (c *Config) Validate() error {
 
 	return errors
 }
+
+// lookupTables returns the lookup tables of the configured extension, or nil if none is configured.
+func (c *Config) lookupTables() *common.LookupTables {
+	if c.LookupTables == nil {
+		return nil
+	}
+	return common.NewLookupTables(*c.LookupTables)
+}
@@ -20,6 +20,8 @@ import (
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
+	lookupTablesID := component.MustNewID("lookup_tables")
+
 	tests := []struct {
 		id       component.ID
 		expected component.Config
@@ -147,6 +149,26 @@ func TestLoadConfig(t *testing.T) {
 			id:       component.NewIDWithName(metadata.Type, "bad_syntax_multi_signal"),
 			errorLen: 3,
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "lookup"),
+			expected: &Config{
+				ErrorMode:        ottl.PropagateError,
+				TraceStatements:  []common.ContextStatements{},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements: []common.ContextStatements{
+					{
+						Context: "log",
+						Statements: []string{
+							`merge_maps(attributes, Lookup("hosts", attributes["host.name"]), "insert")`,
+						},
+					},
+				},
+				LookupTables: &lookupTablesID,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "lookup_without_tables"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
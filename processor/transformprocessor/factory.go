@@ -49,7 +49,8 @@ func createLogsProcessor(
 ) (processor.Logs, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings)
+	lookupTables := oCfg.lookupTables()
+	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings, lookupTables)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -59,7 +60,7 @@ func createLogsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorOptions(lookupTables)...)
 }
 
 func createTracesProcessor(
@@ -70,7 +71,8 @@ func createTracesProcessor(
 ) (processor.Traces, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	lookupTables := oCfg.lookupTables()
+	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings, lookupTables)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -80,7 +82,7 @@ func createTracesProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorOptions(lookupTables)...)
 }
 
 func createMetricsProcessor(
@@ -92,7 +94,8 @@ func createMetricsProcessor(
 	oCfg := cfg.(*Config)
 	oCfg.logger = set.Logger
 
-	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	lookupTables := oCfg.lookupTables()
+	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings, lookupTables)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -102,5 +105,13 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorOptions(lookupTables)...)
+}
+
+func processorOptions(lookupTables *common.LookupTables) []processorhelper.Option {
+	options := []processorhelper.Option{processorhelper.WithCapabilities(processorCapabilities)}
+	if lookupTables != nil {
+		options = append(options, processorhelper.WithStart(lookupTables.Start))
+	}
+	return options
 }
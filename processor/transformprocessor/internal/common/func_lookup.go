@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package common // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// LookupTables holds the lookup tables used by the Lookup functions of a processor. The tables
+// are resolved from the lookup_tables extension referenced by the processor when it is started.
+type LookupTables struct {
+	id     component.ID
+	names  map[string]struct{}
+	tables map[string]*lookuptable.Table
+}
+
+// NewLookupTables returns the lookup tables of the extension with the given ID.
+func NewLookupTables(id component.ID) *LookupTables {
+	return &LookupTables{id: id, names: map[string]struct{}{}}
+}
+
+// Start resolves the tables used by the statements from the extension.
+func (t *LookupTables) Start(_ context.Context, host component.Host) error {
+	provider, err := lookuptable.GetProvider(host, t.id)
+	if err != nil {
+		return err
+	}
+	tables := make(map[string]*lookuptable.Table, len(t.names))
+	for name := range t.names {
+		table, ok := provider.Table(name)
+		if !ok {
+			return fmt.Errorf("lookup table %q not found in extension %q", name, t.id)
+		}
+		tables[name] = table
+	}
+	t.tables = tables
+	return nil
+}
+
+type LookupArguments[K any] struct {
+	Table string
+	Key   ottl.StringLikeGetter[K]
+}
+
+func NewLookupFactory[K any](lookupTables *LookupTables) ottl.Factory[K] {
+	return ottl.NewFactory("Lookup", &LookupArguments[K]{}, func(fCtx ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		return createLookupFunction[K](fCtx, oArgs, lookupTables)
+	})
+}
+
+func createLookupFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments, lookupTables *LookupTables) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*LookupArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("LookupFactory args must be of type *LookupArguments[K]")
+	}
+
+	if lookupTables == nil {
+		return nil, errors.New("the Lookup function requires lookup_tables to be specified")
+	}
+
+	return lookup(lookupTables, args.Table, args.Key), nil
+}
+
+// lookup returns the row of the key in the table, or an empty map if the key is not in the table.
+// The table is resolved when the processor is started, as the statements are parsed before.
+func lookup[K any](lookupTables *LookupTables, tableName string, key ottl.StringLikeGetter[K]) ottl.ExprFunc[K] {
+	lookupTables.names[tableName] = struct{}{}
+	return func(ctx context.Context, tCtx K) (any, error) {
+		table, ok := lookupTables.tables[tableName]
+		if !ok {
+			return nil, fmt.Errorf("lookup table %q is not loaded", tableName)
+		}
+		k, err := key.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		if k == nil {
+			return pcommon.NewMap(), nil
+		}
+		row, ok := table.Lookup(*k)
+		if !ok {
+			return pcommon.NewMap(), nil
+		}
+		// the row is shared with the other evaluations, the statement gets a copy it can modify
+		result := pcommon.NewMap()
+		row.CopyTo(result)
+		return result, nil
+	}
+}
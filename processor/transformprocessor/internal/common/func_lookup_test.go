@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var lookupTablesID = component.MustNewID("lookup_tables")
+
+// lookupTablesExtension provides the tables of the tests, like the lookup_tables extension.
+type lookupTablesExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	tables map[string]*lookuptable.Table
+}
+
+func (e *lookupTablesExtension) Table(name string) (*lookuptable.Table, bool) {
+	table, ok := e.tables[name]
+	return table, ok
+}
+
+type lookupTablesHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *lookupTablesHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func Test_LookupTables(t *testing.T) {
+	owners := lookuptable.NewTable()
+	host := &lookupTablesHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			lookupTablesID: &lookupTablesExtension{tables: map[string]*lookuptable.Table{"owners": owners}},
+		},
+	}
+	key := &ottl.StandardStringLikeGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "checkout", nil
+		},
+	}
+
+	lookupTables := NewLookupTables(lookupTablesID)
+	exprFunc := lookup[any](lookupTables, "owners", key)
+	_, err := exprFunc(context.Background(), nil)
+	assert.EqualError(t, err, `lookup table "owners" is not loaded`)
+	require.NoError(t, lookupTables.Start(context.Background(), host))
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, pcommon.NewMap(), result)
+
+	lookup[any](lookupTables, "regions", key)
+	assert.EqualError(t, lookupTables.Start(context.Background(), host), `lookup table "regions" not found in extension "lookup_tables"`)
+
+	missing := NewLookupTables(component.MustNewIDWithName("lookup_tables", "missing"))
+	assert.EqualError(t, missing.Start(context.Background(), host), `lookup tables extension "lookup_tables/missing" not found`)
+
+	_, err = createLookupFunction[any](ottl.FunctionContext{}, &LookupArguments[any]{Table: "owners", Key: key}, nil)
+	assert.EqualError(t, err, "the Lookup function requires lookup_tables to be specified")
+}
+
+func Test_lookup(t *testing.T) {
+	row := pcommon.NewMap()
+	row.PutStr("team", "payments")
+	owners := lookuptable.NewTable()
+	owners.Replace(map[string]pcommon.Map{"checkout": row, "42": row})
+	lookupTables := &LookupTables{
+		names:  map[string]struct{}{},
+		tables: map[string]*lookuptable.Table{"owners": owners},
+	}
+
+	tests := []struct {
+		name     string
+		table    string
+		key      any
+		expected any
+		err      string
+	}{
+		{
+			name:     "string key",
+			table:    "owners",
+			key:      "checkout",
+			expected: row,
+		},
+		{
+			name:     "int key",
+			table:    "owners",
+			key:      int64(42),
+			expected: row,
+		},
+		{
+			name:     "missing key",
+			table:    "owners",
+			key:      "cart",
+			expected: pcommon.NewMap(),
+		},
+		{
+			name:     "nil key",
+			table:    "owners",
+			expected: pcommon.NewMap(),
+		},
+		{
+			name:  "missing table",
+			table: "regions",
+			key:   "checkout",
+			err:   `lookup table "regions" is not loaded`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &ottl.StandardStringLikeGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.key, nil
+				},
+			}
+			exprFunc := lookup[any](lookupTables, tt.table, key)
+			result, err := exprFunc(context.Background(), nil)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_lookup_returnsCopy(t *testing.T) {
+	row := pcommon.NewMap()
+	row.PutStr("team", "payments")
+	owners := lookuptable.NewTable()
+	owners.Replace(map[string]pcommon.Map{"checkout": row})
+	lookupTables := &LookupTables{
+		names:  map[string]struct{}{},
+		tables: map[string]*lookuptable.Table{"owners": owners},
+	}
+
+	key := &ottl.StandardStringLikeGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "checkout", nil
+		},
+	}
+	result, err := lookup[any](lookupTables, "owners", key)(context.Background(), nil)
+	require.NoError(t, err)
+	result.(pcommon.Map).PutStr("team", "cart")
+
+	actual, ok := owners.Lookup("checkout")
+	require.True(t, ok)
+	team, _ := actual.Get("team")
+	assert.Equal(t, "payments", team.Str())
+}
@@ -10,10 +10,16 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
-func ResourceFunctions() map[string]ottl.Factory[ottlresource.TransformContext] {
-	return ottlfuncs.StandardFuncs[ottlresource.TransformContext]()
+func ResourceFunctions(lookupTables *LookupTables) map[string]ottl.Factory[ottlresource.TransformContext] {
+	functions := ottlfuncs.StandardFuncs[ottlresource.TransformContext]()
+	lookupFactory := NewLookupFactory[ottlresource.TransformContext](lookupTables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
 }
 
-func ScopeFunctions() map[string]ottl.Factory[ottlscope.TransformContext] {
-	return ottlfuncs.StandardFuncs[ottlscope.TransformContext]()
+func ScopeFunctions(lookupTables *LookupTables) map[string]ottl.Factory[ottlscope.TransformContext] {
+	functions := ottlfuncs.StandardFuncs[ottlscope.TransformContext]()
+	lookupFactory := NewLookupFactory[ottlscope.TransformContext](lookupTables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
 }
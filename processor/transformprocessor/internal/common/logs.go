@@ -80,20 +80,18 @@ func WithLogErrorMode(errorMode ottl.ErrorMode) LogParserCollectionOption {
 	}
 }
 
-func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithLogLookupTables makes the lookup tables available to the Lookup function of the statements.
+func WithLogLookupTables(lookupTables *LookupTables) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.lookupTables = lookupTables
+		return nil
 	}
+}
+
+func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
 	lpc := &LogParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -104,6 +102,17 @@ func NewLogParserCollection(settings component.TelemetrySettings, options ...Log
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	lpc.resourceParser = rp
+	lpc.scopeParser = sp
+
 	return lpc, nil
 }
 
@@ -206,20 +206,18 @@ func WithMetricErrorMode(errorMode ottl.ErrorMode) MetricParserCollectionOption
 	}
 }
 
-func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithMetricLookupTables makes the lookup tables available to the Lookup function of the statements.
+func WithMetricLookupTables(lookupTables *LookupTables) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.lookupTables = lookupTables
+		return nil
 	}
+}
+
+func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
 	mpc := &MetricParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -230,6 +228,17 @@ func NewMetricParserCollection(settings component.TelemetrySettings, options ...
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	mpc.resourceParser = rp
+	mpc.scopeParser = sp
+
 	return mpc, nil
 }
 
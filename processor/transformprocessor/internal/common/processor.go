@@ -178,6 +178,7 @@ type parserCollection struct {
 	resourceParser ottl.Parser[ottlresource.TransformContext]
 	scopeParser    ottl.Parser[ottlscope.TransformContext]
 	errorMode      ottl.ErrorMode
+	lookupTables   *LookupTables
 }
 
 type baseContext interface {
@@ -134,20 +134,18 @@ func WithTraceErrorMode(errorMode ottl.ErrorMode) TraceParserCollectionOption {
 	}
 }
 
-func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithTraceLookupTables makes the lookup tables available to the Lookup function of the statements.
+func WithTraceLookupTables(lookupTables *LookupTables) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.lookupTables = lookupTables
+		return nil
 	}
+}
+
+func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
 	tpc := &TraceParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -158,6 +156,17 @@ func NewTraceParserCollection(settings component.TelemetrySettings, options ...T
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	tpc.resourceParser = rp
+	tpc.scopeParser = sp
+
 	return tpc, nil
 }
 
@@ -7,9 +7,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
-func LogFunctions() map[string]ottl.Factory[ottllog.TransformContext] {
+func LogFunctions(lookupTables *common.LookupTables) map[string]ottl.Factory[ottllog.TransformContext] {
 	// No logs-only functions yet.
-	return ottlfuncs.StandardFuncs[ottllog.TransformContext]()
+	functions := ottlfuncs.StandardFuncs[ottllog.TransformContext]()
+	lookupFactory := common.NewLookupFactory[ottllog.TransformContext](lookupTables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
 }
@@ -11,11 +11,13 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
 func Test_LogFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottllog.TransformContext]()
-	actual := LogFunctions()
+	expected["Lookup"] = common.NewLookupFactory[ottllog.TransformContext](nil)
+	actual := LogFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -9,10 +9,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/lookuptable"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
@@ -55,7 +58,7 @@ func Test_ProcessLogs_ResourceContext(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.statement, func(t *testing.T) {
 			td := constructLogs()
-			processor, err := NewProcessor([]common.ContextStatements{{Context: "resource", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings())
+			processor, err := NewProcessor([]common.ContextStatements{{Context: "resource", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings(), nil)
 			assert.NoError(t, err)
 
 			_, err = processor.ProcessLogs(context.Background(), td)
@@ -96,7 +99,7 @@ func Test_ProcessLogs_ScopeContext(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.statement, func(t *testing.T) {
 			td := constructLogs()
-			processor, err := NewProcessor([]common.ContextStatements{{Context: "scope", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings())
+			processor, err := NewProcessor([]common.ContextStatements{{Context: "scope", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings(), nil)
 			assert.NoError(t, err)
 
 			_, err = processor.ProcessLogs(context.Background(), td)
@@ -350,7 +353,7 @@ func Test_ProcessLogs_LogContext(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.statement, func(t *testing.T) {
 			td := constructLogs()
-			processor, err := NewProcessor([]common.ContextStatements{{Context: "log", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings())
+			processor, err := NewProcessor([]common.ContextStatements{{Context: "log", Statements: []string{tt.statement}}}, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings(), nil)
 			assert.NoError(t, err)
 
 			_, err = processor.ProcessLogs(context.Background(), td)
@@ -467,7 +470,7 @@ func Test_ProcessLogs_MixContext(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			td := constructLogs()
-			processor, err := NewProcessor(tt.contextStatments, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings())
+			processor, err := NewProcessor(tt.contextStatments, ottl.IgnoreError, false, componenttest.NewNopTelemetrySettings(), nil)
 			assert.NoError(t, err)
 
 			_, err = processor.ProcessLogs(context.Background(), td)
@@ -481,6 +484,86 @@ func Test_ProcessLogs_MixContext(t *testing.T) {
 	}
 }
 
+// lookupTablesExtension provides the tables of the tests, like the lookup_tables extension.
+type lookupTablesExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	tables map[string]*lookuptable.Table
+}
+
+func (e *lookupTablesExtension) Table(name string) (*lookuptable.Table, bool) {
+	table, ok := e.tables[name]
+	return table, ok
+}
+
+type lookupTablesHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *lookupTablesHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func Test_ProcessLogs_Lookup(t *testing.T) {
+	row := pcommon.NewMap()
+	row.PutStr("datacenter", "ams1")
+	hosts := lookuptable.NewTable()
+	hosts.Replace(map[string]pcommon.Map{"localhost": row})
+	lookupTablesID := component.MustNewID("lookup_tables")
+	host := &lookupTablesHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			lookupTablesID: &lookupTablesExtension{tables: map[string]*lookuptable.Table{"hosts": hosts}},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		context   common.ContextID
+		want      func(td plog.Logs)
+	}{
+		{
+			statement: `merge_maps(attributes, Lookup("hosts", attributes["host.name"]), "insert")`,
+			context:   "resource",
+			want: func(td plog.Logs) {
+				td.ResourceLogs().At(0).Resource().Attributes().PutStr("datacenter", "ams1")
+			},
+		},
+		{
+			statement: `set(attributes["datacenter"], Lookup("hosts", resource.attributes["host.name"])["datacenter"])`,
+			context:   "log",
+			want: func(td plog.Logs) {
+				td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().PutStr("datacenter", "ams1")
+				td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Attributes().PutStr("datacenter", "ams1")
+			},
+		},
+		{
+			statement: `merge_maps(attributes, Lookup("hosts", body), "insert")`,
+			context:   "log",
+			want:      func(_ plog.Logs) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			td := constructLogs()
+			lookupTables := common.NewLookupTables(lookupTablesID)
+			processor, err := NewProcessor([]common.ContextStatements{{Context: tt.context, Statements: []string{tt.statement}}}, ottl.PropagateError, false, componenttest.NewNopTelemetrySettings(), lookupTables)
+			require.NoError(t, err)
+			require.NoError(t, lookupTables.Start(context.Background(), host))
+
+			_, err = processor.ProcessLogs(context.Background(), td)
+			require.NoError(t, err)
+
+			exTd := constructLogs()
+			tt.want(exTd)
+
+			assert.Equal(t, exTd, td)
+		})
+	}
+}
+
 func Test_ProcessTraces_Error(t *testing.T) {
 	tests := []struct {
 		statement string
@@ -500,7 +583,7 @@ func Test_ProcessTraces_Error(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(string(tt.context), func(t *testing.T) {
 			td := constructLogs()
-			processor, err := NewProcessor([]common.ContextStatements{{Context: tt.context, Statements: []string{`set(attributes["test"], ParseJSON(1))`}}}, ottl.PropagateError, false, componenttest.NewNopTelemetrySettings())
+			processor, err := NewProcessor([]common.ContextStatements{{Context: tt.context, Statements: []string{`set(attributes["test"], ParseJSON(1))`}}}, ottl.PropagateError, false, componenttest.NewNopTelemetrySettings(), nil)
 			assert.NoError(t, err)
 
 			_, err = processor.ProcessLogs(context.Background(), td)
@@ -12,6 +12,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
 func Test_DataPointFunctions(t *testing.T) {
@@ -36,8 +37,9 @@ func Test_DataPointFunctions(t *testing.T) {
 			expected := ottlfuncs.StandardFuncs[ottldatapoint.TransformContext]()
 			expected["convert_summary_sum_val_to_sum"] = newConvertSummarySumValToSumFactory()
 			expected["convert_summary_count_val_to_sum"] = newConvertSummaryCountValToSumFactory()
+			expected["Lookup"] = common.NewLookupFactory[ottldatapoint.TransformContext](nil)
 
-			actual := DataPointFunctions()
+			actual := DataPointFunctions(nil)
 
 			require.Equal(t, len(expected), len(actual))
 			for k := range actual {
@@ -59,8 +61,9 @@ func Test_MetricFunctions(t *testing.T) {
 	expected["copy_metric"] = newCopyMetricFactory()
 	expected["scale_metric"] = newScaleMetricFactory()
 	expected["convert_exponential_histogram_to_histogram"] = newconvertExponentialHistToExplicitHistFactory()
+	expected["Lookup"] = common.NewLookupFactory[ottlmetric.TransformContext](nil)
 
-	actual := MetricFunctions()
+	actual := MetricFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -21,8 +21,8 @@ type Processor struct {
 	logger   *zap.Logger
 }
 
-func NewProcessor(contextStatements []common.ContextStatements, errorMode ottl.ErrorMode, settings component.TelemetrySettings) (*Processor, error) {
-	pc, err := common.NewMetricParserCollection(settings, common.WithMetricParser(MetricFunctions()), common.WithDataPointParser(DataPointFunctions()), common.WithMetricErrorMode(errorMode))
+func NewProcessor(contextStatements []common.ContextStatements, errorMode ottl.ErrorMode, settings component.TelemetrySettings, lookupTables *common.LookupTables) (*Processor, error) {
+	pc, err := common.NewMetricParserCollection(settings, common.WithMetricParser(MetricFunctions(lookupTables)), common.WithDataPointParser(DataPointFunctions(lookupTables)), common.WithMetricErrorMode(errorMode), common.WithMetricLookupTables(lookupTables))
 	if err != nil {
 		return nil, err
 	}
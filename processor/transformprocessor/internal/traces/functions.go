@@ -8,17 +8,23 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
-func SpanFunctions() map[string]ottl.Factory[ottlspan.TransformContext] {
+func SpanFunctions(lookupTables *common.LookupTables) map[string]ottl.Factory[ottlspan.TransformContext] {
 	// No trace-only functions yet.
 	m := ottlfuncs.StandardFuncs[ottlspan.TransformContext]()
 	isRootSpanFactory := ottlfuncs.NewIsRootSpanFactory()
 	m[isRootSpanFactory.Name()] = isRootSpanFactory
+	lookupFactory := common.NewLookupFactory[ottlspan.TransformContext](lookupTables)
+	m[lookupFactory.Name()] = lookupFactory
 	return m
 }
 
-func SpanEventFunctions() map[string]ottl.Factory[ottlspanevent.TransformContext] {
+func SpanEventFunctions(lookupTables *common.LookupTables) map[string]ottl.Factory[ottlspanevent.TransformContext] {
 	// No trace-only functions yet.
-	return ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
+	functions := ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
+	lookupFactory := common.NewLookupFactory[ottlspanevent.TransformContext](lookupTables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
 }
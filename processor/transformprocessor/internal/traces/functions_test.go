@@ -12,13 +12,15 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
 func Test_SpanFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottlspan.TransformContext]()
 	isRootSpanFactory := ottlfuncs.NewIsRootSpanFactory()
 	expected[isRootSpanFactory.Name()] = isRootSpanFactory
-	actual := SpanFunctions()
+	expected["Lookup"] = common.NewLookupFactory[ottlspan.TransformContext](nil)
+	actual := SpanFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -27,7 +29,8 @@ func Test_SpanFunctions(t *testing.T) {
 
 func Test_SpanEventFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
-	actual := SpanEventFunctions()
+	expected["Lookup"] = common.NewLookupFactory[ottlspanevent.TransformContext](nil)
+	actual := SpanEventFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
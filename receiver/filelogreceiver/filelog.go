@@ -4,6 +4,8 @@
 package filelogreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filelogreceiver"
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/receiver"
 
@@ -54,6 +56,14 @@ type FileLogConfig struct {
 	adapter.BaseConfig `mapstructure:",squash"`
 }
 
+// Validate checks the receiver configuration is valid
+func (f *FileLogConfig) Validate() error {
+	if f.InputConfig.PollsToArchive > 0 && f.StorageID == nil {
+		return errors.New("'polls_to_archive' requires 'storage' to be set")
+	}
+	return nil
+}
+
 // InputConfig unmarshals the input operator
 func (f ReceiverType) InputConfig(cfg component.Config) operator.Config {
 	return operator.NewConfig(&cfg.(*FileLogConfig).InputConfig)
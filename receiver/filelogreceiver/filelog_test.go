@@ -59,6 +59,16 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, testdataConfigYaml(), cfg)
 }
 
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	cfg.InputConfig.PollsToArchive = 10
+	assert.EqualError(t, component.ValidateConfig(cfg), "'polls_to_archive' requires 'storage' to be set")
+
+	storageID := component.MustNewID("file_storage")
+	cfg.StorageID = &storageID
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
 func TestCreateWithInvalidInputConfig(t *testing.T) {
 	t.Parallel()
 
@@ -4,6 +4,7 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"errors"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -32,6 +33,23 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+// DeadLetter configures the topic the messages that cannot be processed are produced to,
+// instead of blocking the partition or being dropped.
+type DeadLetter struct {
+	// Whether the messages that cannot be processed are produced to the dead-letter topic (default disabled).
+	Enabled bool `mapstructure:"enabled"`
+
+	// The name of the dead-letter topic.
+	Topic string `mapstructure:"topic"`
+
+	// The number of times the message is retried when the pipeline returns a retryable error,
+	// before it is produced to the dead-letter topic (default 3). Permanent errors are not retried.
+	MaxRetries int `mapstructure:"max_retries"`
+
+	// The time to wait between retries (default 1s).
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+}
+
 type HeaderExtraction struct {
 	ExtractHeaders bool     `mapstructure:"extract_headers"`
 	Headers        []string `mapstructure:"headers"`
@@ -79,6 +97,9 @@ type Config struct {
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
 
+	// Controls where the messages that cannot be processed are produced to
+	DeadLetter DeadLetter `mapstructure:"dead_letter"`
+
 	// The minimum bytes per fetch from Kafka (default "1")
 	MinFetchSize int32 `mapstructure:"min_fetch_size"`
 	// The default bytes per fetch from Kafka (default "1048576")
@@ -96,5 +117,17 @@ var _ component.Config = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if !cfg.DeadLetter.Enabled {
+		return nil
+	}
+	if cfg.DeadLetter.Topic == "" {
+		return errors.New("dead_letter.topic must be specified when the dead-letter topic is enabled")
+	}
+	if cfg.DeadLetter.Topic == cfg.Topic {
+		return errors.New("dead_letter.topic must be different from the consumed topic")
+	}
+	if cfg.DeadLetter.MaxRetries < 0 {
+		return errors.New("dead_letter.max_retries must not be negative")
+	}
 	return nil
 }
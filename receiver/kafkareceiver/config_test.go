@@ -62,6 +62,10 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				DeadLetter: DeadLetter{
+					MaxRetries:   3,
+					RetryBackoff: time.Second,
+				},
 				MinFetchSize:     1,
 				DefaultFetchSize: 1048576,
 				MaxFetchSize:     0,
@@ -98,6 +102,12 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				DeadLetter: DeadLetter{
+					Enabled:      true,
+					Topic:        "logs_dlq",
+					MaxRetries:   5,
+					RetryBackoff: 2 * time.Second,
+				},
 				MinFetchSize:     1,
 				DefaultFetchSize: 1048576,
 				MaxFetchSize:     0,
@@ -119,3 +129,47 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDeadLetter(t *testing.T) {
+	tests := []struct {
+		name       string
+		deadLetter DeadLetter
+		err        string
+	}{
+		{
+			name:       "disabled",
+			deadLetter: DeadLetter{Topic: "logs"},
+		},
+		{
+			name:       "valid",
+			deadLetter: DeadLetter{Enabled: true, Topic: "logs_dlq", MaxRetries: 3},
+		},
+		{
+			name:       "missing topic",
+			deadLetter: DeadLetter{Enabled: true},
+			err:        "dead_letter.topic must be specified when the dead-letter topic is enabled",
+		},
+		{
+			name:       "consumed topic",
+			deadLetter: DeadLetter{Enabled: true, Topic: "logs"},
+			err:        "dead_letter.topic must be different from the consumed topic",
+		},
+		{
+			name:       "negative retries",
+			deadLetter: DeadLetter{Enabled: true, Topic: "logs_dlq", MaxRetries: -1},
+			err:        "dead_letter.max_retries must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Topic = "logs"
+			cfg.DeadLetter = tt.deadLetter
+			if tt.err == "" {
+				assert.NoError(t, component.ValidateConfig(cfg))
+			} else {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
+)
+
+const (
+	// The reasons a message is produced to the dead-letter topic.
+	deadLetterReasonUnmarshalFailed  = "unmarshal_failed"
+	deadLetterReasonRejected         = "rejected"
+	deadLetterReasonRetriesExhausted = "retries_exhausted"
+
+	attrReason = "reason"
+
+	// The headers recording where the message comes from and why it was dead-lettered.
+	headerDeadLetterTopic     = "dlq.original.topic"
+	headerDeadLetterPartition = "dlq.original.partition"
+	headerDeadLetterOffset    = "dlq.original.offset"
+	headerDeadLetterReason    = "dlq.reason"
+	headerDeadLetterError     = "dlq.error"
+	headerDeadLetterTimestamp = "dlq.timestamp"
+)
+
+// deadLetterQueue retries the messages the pipeline fails to consume, and produces the messages
+// that cannot be processed to the dead-letter topic. A nil deadLetterQueue consumes the messages
+// once and never dead-letters them.
+type deadLetterQueue struct {
+	id               component.ID
+	producer         sarama.SyncProducer
+	topic            string
+	maxRetries       int
+	retryBackoff     time.Duration
+	logger           *zap.Logger
+	telemetryBuilder *metadata.TelemetryBuilder
+}
+
+func newDeadLetterQueue(id component.ID, config DeadLetter, producer sarama.SyncProducer, logger *zap.Logger, telemetryBuilder *metadata.TelemetryBuilder) *deadLetterQueue {
+	return &deadLetterQueue{
+		id:               id,
+		producer:         producer,
+		topic:            config.Topic,
+		maxRetries:       config.MaxRetries,
+		retryBackoff:     config.RetryBackoff,
+		logger:           logger,
+		telemetryBuilder: telemetryBuilder,
+	}
+}
+
+func createDeadLetterProducer(ctx context.Context, config Config) (sarama.SyncProducer, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = config.ClientID
+	saramaConfig.Metadata.Full = config.Metadata.Full
+	saramaConfig.Metadata.Retry.Max = config.Metadata.Retry.Max
+	saramaConfig.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	// These setting are required by the sarama.SyncProducer implementation.
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+
+	if config.ResolveCanonicalBootstrapServersOnly {
+		saramaConfig.Net.ResolveCanonicalBootstrapServers = true
+	}
+	if config.ProtocolVersion != "" {
+		var err error
+		if saramaConfig.Version, err = sarama.ParseKafkaVersion(config.ProtocolVersion); err != nil {
+			return nil, err
+		}
+	}
+	if err := kafka.ConfigureAuthentication(ctx, config.Authentication, saramaConfig); err != nil {
+		return nil, err
+	}
+	return sarama.NewSyncProducer(config.Brokers, saramaConfig)
+}
+
+// consume calls the consume function until it succeeds, it returns a permanent error,
+// or the retries are exhausted.
+func (q *deadLetterQueue) consume(ctx context.Context, consume func(context.Context) error) error {
+	err := consume(ctx)
+	if q == nil {
+		return err
+	}
+	for retry := 0; err != nil && !consumererror.IsPermanent(err) && retry < q.maxRetries; retry++ {
+		q.logger.Debug("Retrying message", zap.Int("retry", retry+1), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(q.retryBackoff):
+		}
+		err = consume(ctx)
+	}
+	return err
+}
+
+// consumeErrorReason returns the reason of a message failing to be consumed after its retries.
+func consumeErrorReason(err error) string {
+	if consumererror.IsPermanent(err) {
+		return deadLetterReasonRejected
+	}
+	return deadLetterReasonRetriesExhausted
+}
+
+// send produces the message to the dead-letter topic. It returns whether the message was
+// produced, in which case it can be marked as consumed.
+func (q *deadLetterQueue) send(ctx context.Context, message *sarama.ConsumerMessage, reason string, cause error) bool {
+	if q == nil {
+		return false
+	}
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+6)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(headerDeadLetterTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(headerDeadLetterPartition), Value: []byte(strconv.FormatInt(int64(message.Partition), 10))},
+		sarama.RecordHeader{Key: []byte(headerDeadLetterOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(headerDeadLetterReason), Value: []byte(reason)},
+		sarama.RecordHeader{Key: []byte(headerDeadLetterError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(headerDeadLetterTimestamp), Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))},
+	)
+	producerMessage := &sarama.ProducerMessage{
+		Topic:     q.topic,
+		Value:     sarama.ByteEncoder(message.Value),
+		Headers:   headers,
+		Timestamp: message.Timestamp,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	attrs := metric.WithAttributes(attribute.String(attrInstanceName, q.id.String()), attribute.String(attrReason, reason))
+	if _, _, err := q.producer.SendMessage(producerMessage); err != nil {
+		q.logger.Error("failed to produce message to the dead-letter topic",
+			zap.String("topic", q.topic), zap.String("reason", reason), zap.Error(err))
+		q.telemetryBuilder.KafkaReceiverDeadLetterFailedMessages.Add(ctx, 1, attrs)
+		return false
+	}
+	q.logger.Warn("Message produced to the dead-letter topic",
+		zap.String("topic", q.topic),
+		zap.String("original_topic", message.Topic),
+		zap.Int32("partition", message.Partition),
+		zap.Int64("offset", message.Offset),
+		zap.String("reason", reason),
+		zap.Error(cause))
+	q.telemetryBuilder.KafkaReceiverDeadLetterMessages.Add(ctx, 1, attrs)
+	return true
+}
+
+func (q *deadLetterQueue) close() error {
+	if q == nil || q.producer == nil {
+		return nil
+	}
+	return q.producer.Close()
+}
@@ -0,0 +1,448 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
+)
+
+// markingConsumerGroupSession records the marked messages and the commits.
+type markingConsumerGroupSession struct {
+	testConsumerGroupSession
+	mu      sync.Mutex
+	marked  []int64
+	commits int
+}
+
+func (s *markingConsumerGroupSession) MarkMessage(message *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, message.Offset)
+}
+
+func (s *markingConsumerGroupSession) Commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits++
+}
+
+func deadLetterHeaders(message *sarama.ProducerMessage) map[string]string {
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return headers
+}
+
+func assertDeadLetterMetric(t *testing.T, tel componentTestTelemetry, id component.ID, name string, reason string) {
+	description := "Number of messages produced to the dead-letter topic"
+	if name == "otelcol_kafka_receiver_dead_letter_failed_messages" {
+		description = "Number of messages failed to be produced to the dead-letter topic"
+	}
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tel.reader.Collect(context.Background(), &md))
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        name,
+		Unit:        "1",
+		Description: description,
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{
+					Value:      1,
+					Attributes: attribute.NewSet(attribute.String("name", id.String()), attribute.String("reason", reason)),
+				},
+			},
+		},
+	}, tel.getMetric(name, md), metricdatatest.IgnoreTimestamp())
+}
+
+func TestDeadLetterQueue_consume(t *testing.T) {
+	retryableErr := errors.New("temporary failure")
+	tests := []struct {
+		name          string
+		queue         *deadLetterQueue
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "disabled",
+			errs:          []error{retryableErr},
+			expectedCalls: 1,
+			expectedErr:   retryableErr,
+		},
+		{
+			name:          "success after retries",
+			queue:         &deadLetterQueue{maxRetries: 3, logger: zap.NewNop()},
+			errs:          []error{retryableErr, retryableErr, nil},
+			expectedCalls: 3,
+		},
+		{
+			name:          "retries exhausted",
+			queue:         &deadLetterQueue{maxRetries: 2, logger: zap.NewNop()},
+			errs:          []error{retryableErr, retryableErr, retryableErr, nil},
+			expectedCalls: 3,
+			expectedErr:   retryableErr,
+		},
+		{
+			name:          "permanent error",
+			queue:         &deadLetterQueue{maxRetries: 3, logger: zap.NewNop()},
+			errs:          []error{consumererror.NewPermanent(retryableErr), nil},
+			expectedCalls: 1,
+			expectedErr:   consumererror.NewPermanent(retryableErr),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.queue.consume(context.Background(), func(context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+
+	assert.Equal(t, deadLetterReasonRejected, consumeErrorReason(consumererror.NewPermanent(retryableErr)))
+	assert.Equal(t, deadLetterReasonRetriesExhausted, consumeErrorReason(retryableErr))
+}
+
+func TestLogsConsumerGroupHandler_deadLetter_unmarshal(t *testing.T) {
+	tel := setupTestTelemetry()
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(tel.NewSettings().TelemetrySettings)
+	require.NoError(t, err)
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+
+	timestamp := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(message *sarama.ProducerMessage) error {
+		assert.Equal(t, "otlp_logs_dlq", message.Topic)
+		assert.Equal(t, timestamp, message.Timestamp)
+		value, err := message.Value.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("!@#"), value)
+		key, err := message.Key.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("key"), key)
+
+		headers := deadLetterHeaders(message)
+		assert.NotEmpty(t, headers["dlq.timestamp"])
+		delete(headers, "dlq.timestamp")
+		assert.Equal(t, map[string]string{
+			"tenant":                 "acme",
+			"dlq.original.topic":     "otlp_logs",
+			"dlq.original.partition": "5",
+			"dlq.original.offset":    "42",
+			"dlq.reason":             "unmarshal_failed",
+			"dlq.error":              headers["dlq.error"],
+		}, headers)
+		assert.NotEmpty(t, headers["dlq.error"])
+		return nil
+	})
+
+	id := receivertest.NewNopSettings().ID
+	c := logsConsumerGroupHandler{
+		id:               id,
+		unmarshaler:      newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		logger:           zap.NewNop(),
+		ready:            make(chan bool),
+		nextConsumer:     consumertest.NewNop(),
+		obsrecv:          obsrecv,
+		headerExtractor:  &nopHeaderExtractor{},
+		telemetryBuilder: telemetryBuilder,
+		messageMarking:   MessageMarking{After: true},
+		deadLetterQueue:  newDeadLetterQueue(id, DeadLetter{Topic: "otlp_logs_dlq"}, producer, zap.NewNop(), telemetryBuilder),
+	}
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(session, groupClaim))
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{
+		Topic:     "otlp_logs",
+		Partition: 5,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("!@#"),
+		Timestamp: timestamp,
+		Headers:   []*sarama.RecordHeader{{Key: []byte("tenant"), Value: []byte("acme")}},
+	}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, []int64{42}, session.marked)
+	assert.Equal(t, 2, session.commits, "the dead-lettered message and the end of the claim are committed")
+	assertDeadLetterMetric(t, tel, id, "otelcol_kafka_receiver_dead_letter_messages", "unmarshal_failed")
+	require.NoError(t, producer.Close())
+	require.NoError(t, tel.Shutdown(context.Background()))
+}
+
+func TestTracesConsumerGroupHandler_deadLetter_nextConsumer(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	bts, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		consumeErr    error
+		expectedCalls int
+		reason        string
+	}{
+		{
+			name:          "rejected",
+			consumeErr:    consumererror.NewPermanent(errors.New("invalid span")),
+			expectedCalls: 1,
+			reason:        "rejected",
+		},
+		{
+			name:          "retries exhausted",
+			consumeErr:    errors.New("exporter queue is full"),
+			expectedCalls: 3,
+			reason:        "retries_exhausted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tel := setupTestTelemetry()
+			telemetryBuilder, err := metadata.NewTelemetryBuilder(tel.NewSettings().TelemetrySettings)
+			require.NoError(t, err)
+			obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+			require.NoError(t, err)
+
+			producer := mocks.NewSyncProducer(t, nil)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(message *sarama.ProducerMessage) error {
+				headers := deadLetterHeaders(message)
+				assert.Equal(t, tt.reason, headers["dlq.reason"])
+				assert.Equal(t, tt.consumeErr.Error(), headers["dlq.error"])
+				return nil
+			})
+
+			calls := 0
+			id := receivertest.NewNopSettings().ID
+			c := tracesConsumerGroupHandler{
+				id:          id,
+				unmarshaler: newPdataTracesUnmarshaler(&ptrace.ProtoUnmarshaler{}, defaultEncoding),
+				logger:      zap.NewNop(),
+				ready:       make(chan bool),
+				nextConsumer: consumerFunc(func() error {
+					calls++
+					return tt.consumeErr
+				}),
+				obsrecv:           obsrecv,
+				headerExtractor:   &nopHeaderExtractor{},
+				telemetryBuilder:  telemetryBuilder,
+				autocommitEnabled: true,
+				deadLetterQueue:   newDeadLetterQueue(id, DeadLetter{Topic: "otlp_spans_dlq", MaxRetries: 2}, producer, zap.NewNop(), telemetryBuilder),
+			}
+
+			session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+			groupClaim := &testConsumerGroupClaim{
+				messageChan: make(chan *sarama.ConsumerMessage),
+			}
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				assert.NoError(t, c.ConsumeClaim(session, groupClaim))
+				wg.Done()
+			}()
+			groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: "otlp_spans", Offset: 7, Value: bts}
+			close(groupClaim.messageChan)
+			wg.Wait()
+
+			assert.Equal(t, tt.expectedCalls, calls)
+			assert.Equal(t, []int64{7, 7}, session.marked, "the message is marked before processing and after dead-lettering")
+			assert.Equal(t, 0, session.commits)
+
+			assertDeadLetterMetric(t, tel, id, "otelcol_kafka_receiver_dead_letter_messages", tt.reason)
+			require.NoError(t, producer.Close())
+			require.NoError(t, tel.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestTracesConsumerGroupHandler_deadLetter_retryUnmarshals(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("original")
+	bts, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+
+	tel := setupTestTelemetry()
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(tel.NewSettings().TelemetrySettings)
+	require.NoError(t, err)
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+
+	// The consumer modifies the traces before failing, the retry must get the traces of the message.
+	var received []string
+	next := mutatingTracesConsumer(func(traces ptrace.Traces) error {
+		span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+		received = append(received, span.Name())
+		if len(received) == 1 {
+			span.SetName("modified")
+			return errors.New("exporter queue is full")
+		}
+		return nil
+	})
+
+	id := receivertest.NewNopSettings().ID
+	c := tracesConsumerGroupHandler{
+		id:                id,
+		unmarshaler:       newPdataTracesUnmarshaler(&ptrace.ProtoUnmarshaler{}, defaultEncoding),
+		logger:            zap.NewNop(),
+		ready:             make(chan bool),
+		nextConsumer:      next,
+		obsrecv:           obsrecv,
+		headerExtractor:   &nopHeaderExtractor{},
+		telemetryBuilder:  telemetryBuilder,
+		autocommitEnabled: true,
+		deadLetterQueue:   newDeadLetterQueue(id, DeadLetter{Topic: "otlp_spans_dlq", MaxRetries: 2}, mocks.NewSyncProducer(t, nil), zap.NewNop(), telemetryBuilder),
+	}
+
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(session, groupClaim))
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: "otlp_spans", Offset: 7, Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, []string{"original", "original"}, received)
+	require.NoError(t, tel.Shutdown(context.Background()))
+}
+
+func TestMetricsConsumerGroupHandler_deadLetter_producerError(t *testing.T) {
+	tel := setupTestTelemetry()
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(tel.NewSettings().TelemetrySettings)
+	require.NoError(t, err)
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndFail(sarama.ErrNotEnoughReplicas)
+
+	id := receivertest.NewNopSettings().ID
+	c := metricsConsumerGroupHandler{
+		id:               id,
+		unmarshaler:      newPdataMetricsUnmarshaler(&pmetric.ProtoUnmarshaler{}, defaultEncoding),
+		logger:           zap.NewNop(),
+		ready:            make(chan bool),
+		nextConsumer:     consumertest.NewNop(),
+		obsrecv:          obsrecv,
+		headerExtractor:  &nopHeaderExtractor{},
+		telemetryBuilder: telemetryBuilder,
+		deadLetterQueue:  newDeadLetterQueue(id, DeadLetter{Topic: "otlp_metrics_dlq"}, producer, zap.NewNop(), telemetryBuilder),
+	}
+
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		assert.Error(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assertDeadLetterMetric(t, tel, id, "otelcol_kafka_receiver_dead_letter_failed_messages", "unmarshal_failed")
+	var md metricdata.ResourceMetrics
+	require.NoError(t, tel.reader.Collect(context.Background(), &md))
+	assert.Empty(t, tel.getMetric("otelcol_kafka_receiver_dead_letter_messages", md).Name)
+	require.NoError(t, producer.Close())
+	require.NoError(t, tel.Shutdown(context.Background()))
+}
+
+func TestLogsReceiver_deadLetterProducerLifecycle(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DeadLetter.Enabled = true
+	cfg.DeadLetter.Topic = "otlp_logs_dlq"
+	c, err := newLogsReceiver(*cfg, receivertest.NewNopSettings(), consumertest.NewNop())
+	require.NoError(t, err)
+	c.consumerGroup = &testConsumerGroup{}
+	producer := &closeRecordingProducer{SyncProducer: mocks.NewSyncProducer(t, nil)}
+	c.deadLetterProducer = producer
+
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	require.NotNil(t, c.deadLetterQueue)
+	assert.Equal(t, "otlp_logs_dlq", c.deadLetterQueue.topic)
+	assert.Equal(t, 3, c.deadLetterQueue.maxRetries)
+	require.NoError(t, c.Shutdown(context.Background()))
+	assert.True(t, producer.closed)
+}
+
+type closeRecordingProducer struct {
+	sarama.SyncProducer
+	closed bool
+}
+
+func (p *closeRecordingProducer) Close() error {
+	p.closed = true
+	return p.SyncProducer.Close()
+}
+
+// consumerFunc is a traces consumer returning the error of a function.
+type consumerFunc func() error
+
+func (f consumerFunc) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{}
+}
+
+func (f consumerFunc) ConsumeTraces(context.Context, ptrace.Traces) error {
+	return f()
+}
+
+// mutatingTracesConsumer is a traces consumer calling a function with the traces, which it may modify.
+type mutatingTracesConsumer func(ptrace.Traces) error
+
+func (f mutatingTracesConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+func (f mutatingTracesConsumer) ConsumeTraces(_ context.Context, traces ptrace.Traces) error {
+	return f(traces)
+}
@@ -41,6 +41,9 @@ const (
 	// default from sarama.NewConfig()
 	defaultAutoCommitInterval = 1 * time.Second
 
+	defaultDeadLetterMaxRetries   = 3
+	defaultDeadLetterRetryBackoff = time.Second
+
 	// default from sarama.NewConfig()
 	defaultMinFetchSize = int32(1)
 	// default from sarama.NewConfig()
@@ -96,6 +99,10 @@ func createDefaultConfig() component.Config {
 		HeaderExtraction: HeaderExtraction{
 			ExtractHeaders: false,
 		},
+		DeadLetter: DeadLetter{
+			MaxRetries:   defaultDeadLetterMaxRetries,
+			RetryBackoff: defaultDeadLetterRetryBackoff,
+		},
 		MinFetchSize:     defaultMinFetchSize,
 		DefaultFetchSize: defaultDefaultFetchSize,
 		MaxFetchSize:     defaultMaxFetchSize,
@@ -26,6 +26,8 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 type TelemetryBuilder struct {
 	meter                                    metric.Meter
 	KafkaReceiverCurrentOffset               metric.Int64Gauge
+	KafkaReceiverDeadLetterFailedMessages    metric.Int64Counter
+	KafkaReceiverDeadLetterMessages          metric.Int64Counter
 	KafkaReceiverMessages                    metric.Int64Counter
 	KafkaReceiverOffsetLag                   metric.Int64Gauge
 	KafkaReceiverPartitionClose              metric.Int64Counter
@@ -61,6 +63,18 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("1"),
 	)
 	errs = errors.Join(errs, err)
+	builder.KafkaReceiverDeadLetterFailedMessages, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_kafka_receiver_dead_letter_failed_messages",
+		metric.WithDescription("Number of messages failed to be produced to the dead-letter topic"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.KafkaReceiverDeadLetterMessages, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_kafka_receiver_dead_letter_messages",
+		metric.WithDescription("Number of messages produced to the dead-letter topic"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
 	builder.KafkaReceiverMessages, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_kafka_receiver_messages",
 		metric.WithDescription("Number of received messages"),
@@ -13,6 +13,7 @@ import (
 	"github.com/IBM/sarama"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -55,6 +56,9 @@ type kafkaTracesConsumer struct {
 	minFetchSize      int32
 	defaultFetchSize  int32
 	maxFetchSize      int32
+
+	deadLetterProducer sarama.SyncProducer
+	deadLetterQueue    *deadLetterQueue
 }
 
 // kafkaMetricsConsumer uses sarama to consume and handle messages from kafka.
@@ -77,6 +81,9 @@ type kafkaMetricsConsumer struct {
 	minFetchSize      int32
 	defaultFetchSize  int32
 	maxFetchSize      int32
+
+	deadLetterProducer sarama.SyncProducer
+	deadLetterQueue    *deadLetterQueue
 }
 
 // kafkaLogsConsumer uses sarama to consume and handle messages from kafka.
@@ -99,6 +106,9 @@ type kafkaLogsConsumer struct {
 	minFetchSize      int32
 	defaultFetchSize  int32
 	maxFetchSize      int32
+
+	deadLetterProducer sarama.SyncProducer
+	deadLetterQueue    *deadLetterQueue
 }
 
 var (
@@ -195,6 +205,9 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 			return err
 		}
 	}
+	if err = c.startDeadLetterQueue(ctx); err != nil {
+		return err
+	}
 	consumerGroup := &tracesConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -205,6 +218,7 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetterQueue:   c.deadLetterQueue,
 	}
 	if c.headerExtraction {
 		consumerGroup.headerExtractor = &headerExtractor{
@@ -218,6 +232,21 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 	return nil
 }
 
+func (c *kafkaTracesConsumer) startDeadLetterQueue(ctx context.Context) error {
+	if !c.config.DeadLetter.Enabled {
+		return nil
+	}
+	// deadLetterProducer may be set in tests to inject fake implementation.
+	if c.deadLetterProducer == nil {
+		var err error
+		if c.deadLetterProducer, err = createDeadLetterProducer(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	c.deadLetterQueue = newDeadLetterQueue(c.settings.ID, c.config.DeadLetter, c.deadLetterProducer, c.settings.Logger, c.telemetryBuilder)
+	return nil
+}
+
 func (c *kafkaTracesConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
 	for {
@@ -244,7 +273,7 @@ func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetterQueue.close())
 }
 
 func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -303,6 +332,9 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 			return err
 		}
 	}
+	if err = c.startDeadLetterQueue(ctx); err != nil {
+		return err
+	}
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -313,6 +345,7 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetterQueue:   c.deadLetterQueue,
 	}
 	if c.headerExtraction {
 		metricsConsumerGroup.headerExtractor = &headerExtractor{
@@ -326,6 +359,21 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 	return nil
 }
 
+func (c *kafkaMetricsConsumer) startDeadLetterQueue(ctx context.Context) error {
+	if !c.config.DeadLetter.Enabled {
+		return nil
+	}
+	// deadLetterProducer may be set in tests to inject fake implementation.
+	if c.deadLetterProducer == nil {
+		var err error
+		if c.deadLetterProducer, err = createDeadLetterProducer(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	c.deadLetterQueue = newDeadLetterQueue(c.settings.ID, c.config.DeadLetter, c.deadLetterProducer, c.settings.Logger, c.telemetryBuilder)
+	return nil
+}
+
 func (c *kafkaMetricsConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
 	for {
@@ -352,7 +400,7 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetterQueue.close())
 }
 
 func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -414,6 +462,9 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 			return err
 		}
 	}
+	if err = c.startDeadLetterQueue(ctx); err != nil {
+		return err
+	}
 	logsConsumerGroup := &logsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -424,6 +475,7 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
 		telemetryBuilder:  c.telemetryBuilder,
+		deadLetterQueue:   c.deadLetterQueue,
 	}
 	if c.headerExtraction {
 		logsConsumerGroup.headerExtractor = &headerExtractor{
@@ -437,6 +489,21 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 	return nil
 }
 
+func (c *kafkaLogsConsumer) startDeadLetterQueue(ctx context.Context) error {
+	if !c.config.DeadLetter.Enabled {
+		return nil
+	}
+	// deadLetterProducer may be set in tests to inject fake implementation.
+	if c.deadLetterProducer == nil {
+		var err error
+		if c.deadLetterProducer, err = createDeadLetterProducer(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	c.deadLetterQueue = newDeadLetterQueue(c.settings.ID, c.config.DeadLetter, c.deadLetterProducer, c.settings.Logger, c.telemetryBuilder)
+	return nil
+}
+
 func (c *kafkaLogsConsumer) consumeLoop(ctx context.Context, handler sarama.ConsumerGroupHandler) {
 	defer c.consumeLoopWG.Done()
 	for {
@@ -463,7 +530,7 @@ func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetterQueue.close())
 }
 
 type tracesConsumerGroupHandler struct {
@@ -481,6 +548,7 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 type metricsConsumerGroupHandler struct {
@@ -498,6 +566,7 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 type logsConsumerGroupHandler struct {
@@ -515,6 +584,7 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 var (
@@ -568,6 +638,10 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedSpans.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.send(session.Context(), message, deadLetterReasonUnmarshalFailed, err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -576,9 +650,25 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 
 			c.headerExtractor.extractHeadersTraces(traces, message)
 			spanCount := traces.SpanCount()
-			err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
+			retry := false
+			err = c.deadLetterQueue.consume(session.Context(), func(ctx context.Context) error {
+				if retry {
+					// the next consumer may have modified the traces of the previous attempt.
+					var unmarshalErr error
+					if traces, unmarshalErr = c.unmarshaler.Unmarshal(message.Value); unmarshalErr != nil {
+						return consumererror.NewPermanent(unmarshalErr)
+					}
+					c.headerExtractor.extractHeadersTraces(traces, message)
+				}
+				retry = true
+				return c.nextConsumer.ConsumeTraces(ctx, traces)
+			})
 			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
 			if err != nil {
+				if c.deadLetterQueue.send(session.Context(), message, consumeErrorReason(err), err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -645,6 +735,10 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedMetricPoints.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.send(session.Context(), message, deadLetterReasonUnmarshalFailed, err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -653,9 +747,25 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			c.headerExtractor.extractHeadersMetrics(metrics, message)
 
 			dataPointCount := metrics.DataPointCount()
-			err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
+			retry := false
+			err = c.deadLetterQueue.consume(session.Context(), func(ctx context.Context) error {
+				if retry {
+					// the next consumer may have modified the metrics of the previous attempt.
+					var unmarshalErr error
+					if metrics, unmarshalErr = c.unmarshaler.Unmarshal(message.Value); unmarshalErr != nil {
+						return consumererror.NewPermanent(unmarshalErr)
+					}
+					c.headerExtractor.extractHeadersMetrics(metrics, message)
+				}
+				retry = true
+				return c.nextConsumer.ConsumeMetrics(ctx, metrics)
+			})
 			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
 			if err != nil {
+				if c.deadLetterQueue.send(session.Context(), message, consumeErrorReason(err), err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -722,6 +832,10 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedLogRecords.Add(ctx, 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.send(session.Context(), message, deadLetterReasonUnmarshalFailed, err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -729,9 +843,25 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			}
 			c.headerExtractor.extractHeadersLogs(logs, message)
 			logRecordCount := logs.LogRecordCount()
-			err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
+			retry := false
+			err = c.deadLetterQueue.consume(session.Context(), func(ctx context.Context) error {
+				if retry {
+					// the next consumer may have modified the logs of the previous attempt.
+					var unmarshalErr error
+					if logs, unmarshalErr = c.unmarshaler.Unmarshal(message.Value); unmarshalErr != nil {
+						return consumererror.NewPermanent(unmarshalErr)
+					}
+					c.headerExtractor.extractHeadersLogs(logs, message)
+				}
+				retry = true
+				return c.nextConsumer.ConsumeLogs(ctx, logs)
+			})
 			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
 			if err != nil {
+				if c.deadLetterQueue.send(session.Context(), message, consumeErrorReason(err), err) {
+					markDeadLetteredMessage(session, message, c.autocommitEnabled)
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -753,6 +883,15 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 	}
 }
 
+// markDeadLetteredMessage marks a message produced to the dead-letter topic as consumed,
+// so that the partition moves on to the next message.
+func markDeadLetteredMessage(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, autocommitEnabled bool) {
+	session.MarkMessage(message, "")
+	if !autocommitEnabled {
+		session.Commit()
+	}
+}
+
 func toSaramaInitialOffset(initialOffset string) (int64, error) {
 	switch initialOffset {
 	case offsetEarliest:
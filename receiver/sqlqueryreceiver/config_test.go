@@ -135,6 +135,35 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			fname: "config-logs-tracking-columns.yaml",
+			id:    component.NewIDWithName(metadata.Type, ""),
+			expected: &Config{
+				Config: sqlquery.Config{
+					ControllerConfig: scraperhelper.ControllerConfig{
+						CollectionInterval: 10 * time.Second,
+						InitialDelay:       time.Second,
+					},
+					Driver:     "mydriver",
+					DataSource: "host=localhost port=5432 user=me password=s3cr3t sslmode=disable",
+					Queries: []sqlquery.Query{
+						{
+							SQL:                 "select * from audit_log where (created_at, id) > (?, ?) order by created_at, id limit ?",
+							TrackingColumns:     []string{"created_at", "id"},
+							TrackingStartValues: []string{"2024-01-01T00:00:00Z", "0"},
+							MaxRowsPerQuery:     1000,
+							Logs: []sqlquery.LogsCfg{
+								{
+									BodyColumn:      "message",
+									TimestampColumn: "created_at",
+									SeverityColumn:  "level",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			fname:        "config-logs-missing-body-column.yaml",
 			id:           component.NewIDWithName(metadata.Type, ""),
@@ -192,3 +221,21 @@ func TestConfig_Validate_Multierr(t *testing.T) {
 	assert.ErrorContains(t, err, "metric config has unsupported data_type: 'xgauge'")
 	assert.ErrorContains(t, err, "metric config has unsupported aggregation: 'xcumulative'")
 }
+
+func TestConfig_Validate_TrackingColumns(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config-invalid-tracking-columns.yaml"))
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	err = component.ValidateConfig(cfg)
+
+	assert.ErrorContains(t, err, "only one of 'tracking_column' and 'tracking_columns' can be set")
+	assert.ErrorContains(t, err, "'tracking_start_values' must have one value per tracking column, got 1 values for 2 columns")
+	assert.ErrorContains(t, err, "'max_rows_per_query' requires 'tracking_column' or 'tracking_columns' to be set")
+}
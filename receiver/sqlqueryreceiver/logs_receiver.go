@@ -6,8 +6,12 @@ package sqlqueryreceiver // import "github.com/open-telemetry/opentelemetry-coll
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -139,27 +143,58 @@ func (receiver *logsReceiver) startCollecting() {
 }
 
 func (receiver *logsReceiver) collect() {
-	logsChannel := make(chan plog.Logs)
+	type result struct {
+		queryReceiver *logsQueryReceiver
+		logs          plog.Logs
+		hasMore       bool
+	}
+	resultsChannel := make(chan result)
 	for _, queryReceiver := range receiver.queryReceivers {
 		go func(queryReceiver *logsQueryReceiver) {
-			logs, err := queryReceiver.collect(context.Background())
+			logs, hasMore, err := queryReceiver.collect(context.Background())
 			if err != nil {
 				receiver.settings.Logger.Error("error collecting logs", zap.Error(err), zap.String("query", queryReceiver.ID()))
 			}
-			logsChannel <- logs
+			resultsChannel <- result{queryReceiver: queryReceiver, logs: logs, hasMore: hasMore}
 		}(queryReceiver)
 	}
 
 	allLogs := plog.NewLogs()
+	var pagedQueryReceivers []*logsQueryReceiver
 	for range receiver.queryReceivers {
-		logs := <-logsChannel
-		logs.ResourceLogs().MoveAndAppendTo(allLogs.ResourceLogs())
+		res := <-resultsChannel
+		res.logs.ResourceLogs().MoveAndAppendTo(allLogs.ResourceLogs())
+		if res.hasMore {
+			pagedQueryReceivers = append(pagedQueryReceivers, res.queryReceiver)
+		}
+	}
+	receiver.consume(allLogs)
+
+	// The remaining pages are consumed one at a time, so that a backfill
+	// doesn't have to hold all the rows in memory.
+	for _, queryReceiver := range pagedQueryReceivers {
+		for hasMore := true; hasMore; {
+			select {
+			case <-receiver.shutdownRequested:
+				return
+			default:
+			}
+			var logs plog.Logs
+			var err error
+			logs, hasMore, err = queryReceiver.collect(context.Background())
+			if err != nil {
+				receiver.settings.Logger.Error("error collecting logs", zap.Error(err), zap.String("query", queryReceiver.ID()))
+			}
+			receiver.consume(logs)
+		}
 	}
+}
 
-	logRecordCount := allLogs.LogRecordCount()
+func (receiver *logsReceiver) consume(logs plog.Logs) {
+	logRecordCount := logs.LogRecordCount()
 	if logRecordCount > 0 {
 		ctx := receiver.obsrecv.StartLogsOp(context.Background())
-		err := receiver.nextConsumer.ConsumeLogs(context.Background(), allLogs)
+		err := receiver.nextConsumer.ConsumeLogs(context.Background(), logs)
 		receiver.obsrecv.EndLogsOp(ctx, metadata.Type.String(), logRecordCount, err)
 		if err != nil {
 			receiver.settings.Logger.Error("failed to send logs: %w", zap.Error(err))
@@ -205,9 +240,9 @@ type logsQueryReceiver struct {
 	logger       *zap.Logger
 	telemetry    sqlquery.TelemetryConfig
 
-	db            *sql.DB
-	client        sqlquery.DbClient
-	trackingValue string
+	db             *sql.DB
+	client         sqlquery.DbClient
+	trackingValues []string
 	// TODO: Extract persistence into its own component
 	storageClient           storage.Client
 	trackingValueStorageKey string
@@ -231,7 +266,7 @@ func newLogsQueryReceiver(
 		telemetry:     telemetry,
 		storageClient: storageClient,
 	}
-	queryReceiver.trackingValue = queryReceiver.query.TrackingStartValue
+	queryReceiver.trackingValues = queryReceiver.query.TrackingStartParameters()
 	queryReceiver.trackingValueStorageKey = fmt.Sprintf("%s.%s", queryReceiver.id, "trackingValue")
 	return queryReceiver
 }
@@ -248,69 +283,102 @@ func (queryReceiver *logsQueryReceiver) start(ctx context.Context) error {
 	}
 	queryReceiver.client = queryReceiver.createClient(sqlquery.DbWrapper{Db: queryReceiver.db}, queryReceiver.query.SQL, queryReceiver.logger, queryReceiver.telemetry)
 
-	queryReceiver.trackingValue = queryReceiver.retrieveTrackingValue(ctx)
+	queryReceiver.trackingValues = queryReceiver.retrieveTrackingValues(ctx)
 
 	return nil
 }
 
-// retrieveTrackingValue retrieves the tracking value from storage, if storage is configured.
-// Otherwise, it returns the tracking value configured in `tracking_start_value`.
-func (queryReceiver *logsQueryReceiver) retrieveTrackingValue(ctx context.Context) string {
-	trackingValueFromConfig := queryReceiver.query.TrackingStartValue
+// retrieveTrackingValues retrieves the tracking values from storage, if storage is configured.
+// Otherwise, it returns the tracking values configured in `tracking_start_value` or `tracking_start_values`.
+// A single tracking value is stored as is, multiple tracking values are stored as a JSON array.
+func (queryReceiver *logsQueryReceiver) retrieveTrackingValues(ctx context.Context) []string {
+	trackingValuesFromConfig := queryReceiver.query.TrackingStartParameters()
 	if queryReceiver.storageClient == nil {
-		return trackingValueFromConfig
+		return trackingValuesFromConfig
 	}
 
 	storedTrackingValueBytes, err := queryReceiver.storageClient.Get(ctx, queryReceiver.trackingValueStorageKey)
 	if err != nil || storedTrackingValueBytes == nil {
-		return trackingValueFromConfig
+		return trackingValuesFromConfig
 	}
 
-	return string(storedTrackingValueBytes)
+	if queryReceiver.query.TrackingColumn != "" {
+		return []string{string(storedTrackingValueBytes)}
+	}
+	var storedTrackingValues []string
+	if err = json.Unmarshal(storedTrackingValueBytes, &storedTrackingValues); err != nil || len(storedTrackingValues) != len(trackingValuesFromConfig) {
+		queryReceiver.logger.Warn("Ignoring invalid stored tracking values", zap.String("query", queryReceiver.id), zap.ByteString("value", storedTrackingValueBytes))
+		return trackingValuesFromConfig
+	}
+	return storedTrackingValues
 }
 
-func (queryReceiver *logsQueryReceiver) collect(ctx context.Context) (plog.Logs, error) {
+// collect runs the query and converts the returned rows to logs. When `max_rows_per_query` is set,
+// it reports whether the query returned a full page, and so more rows are likely to be available.
+func (queryReceiver *logsQueryReceiver) collect(ctx context.Context) (plog.Logs, bool, error) {
 	logs := plog.NewLogs()
 
 	var rows []sqlquery.StringMap
 	var err error
 	observedAt := pcommon.NewTimestampFromTime(time.Now())
-	if queryReceiver.query.TrackingColumn != "" {
-		rows, err = queryReceiver.client.QueryRows(ctx, queryReceiver.trackingValue)
-	} else {
-		rows, err = queryReceiver.client.QueryRows(ctx)
+	args := make([]any, 0, len(queryReceiver.trackingValues)+1)
+	for _, trackingValue := range queryReceiver.trackingValues {
+		args = append(args, trackingValue)
 	}
+	if queryReceiver.query.MaxRowsPerQuery > 0 {
+		args = append(args, queryReceiver.query.MaxRowsPerQuery)
+	}
+	rows, err = queryReceiver.client.QueryRows(ctx, args...)
 	if err != nil {
-		return logs, fmt.Errorf("error getting rows: %w", err)
+		return logs, false, fmt.Errorf("error getting rows: %w", err)
 	}
 
 	var errs []error
 	scopeLogs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
-	for logsConfigIndex, logsConfig := range queryReceiver.query.Logs {
+	for _, logsConfig := range queryReceiver.query.Logs {
 		for _, row := range rows {
 			logRecord := scopeLogs.AppendEmpty()
 			errs = append(errs, rowToLog(row, logsConfig, logRecord))
 			logRecord.SetObservedTimestamp(observedAt)
-			if logsConfigIndex == 0 {
-				errs = append(errs, queryReceiver.storeTrackingValue(ctx, row))
-			}
 		}
 	}
-	return logs, errors.Join(errs...)
+
+	hasMore := false
+	if len(rows) > 0 {
+		previousTrackingValues := queryReceiver.trackingValues
+		errs = append(errs, queryReceiver.storeTrackingValues(ctx, rows[len(rows)-1]))
+		hasMore = queryReceiver.query.MaxRowsPerQuery > 0 && uint64(len(rows)) >= queryReceiver.query.MaxRowsPerQuery
+		if hasMore && slices.Equal(previousTrackingValues, queryReceiver.trackingValues) {
+			// the same page would be returned again
+			queryReceiver.logger.Warn("Tracking values did not change after a full page of rows, make sure the query orders and filters rows by the tracking columns", zap.String("query", queryReceiver.id))
+			hasMore = false
+		}
+	}
+	return logs, hasMore, errors.Join(errs...)
 }
 
-func (queryReceiver *logsQueryReceiver) storeTrackingValue(ctx context.Context, row sqlquery.StringMap) error {
-	if queryReceiver.query.TrackingColumn == "" {
+func (queryReceiver *logsQueryReceiver) storeTrackingValues(ctx context.Context, row sqlquery.StringMap) error {
+	trackingColumns := queryReceiver.query.TrackingColumnNames()
+	if len(trackingColumns) == 0 {
 		return nil
 	}
-	queryReceiver.trackingValue = row[queryReceiver.query.TrackingColumn]
-	if queryReceiver.storageClient != nil {
-		err := queryReceiver.storageClient.Set(ctx, queryReceiver.trackingValueStorageKey, []byte(queryReceiver.trackingValue))
-		if err != nil {
+	trackingValues := make([]string, len(trackingColumns))
+	for i, trackingColumn := range trackingColumns {
+		trackingValues[i] = row[trackingColumn]
+	}
+	queryReceiver.trackingValues = trackingValues
+	if queryReceiver.storageClient == nil {
+		return nil
+	}
+
+	storedTrackingValue := []byte(trackingValues[0])
+	if queryReceiver.query.TrackingColumn == "" {
+		var err error
+		if storedTrackingValue, err = json.Marshal(trackingValues); err != nil {
 			return err
 		}
 	}
-	return nil
+	return queryReceiver.storageClient.Set(ctx, queryReceiver.trackingValueStorageKey, storedTrackingValue)
 }
 
 func rowToLog(row sqlquery.StringMap, config sqlquery.LogsCfg, logRecord plog.LogRecord) error {
@@ -330,9 +398,85 @@ func rowToLog(row sqlquery.StringMap, config sqlquery.LogsCfg, logRecord plog.Lo
 			errs = append(errs, fmt.Errorf("rowToLog: attribute_column '%s' not found in result set", columnName))
 		}
 	}
+
+	if config.TimestampColumn != "" {
+		if value, found := row[config.TimestampColumn]; found {
+			timestamp, err := parseTimestamp(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rowToLog: failed to parse timestamp_column '%s', value was %q: %w", config.TimestampColumn, value, err))
+			} else {
+				logRecord.SetTimestamp(timestamp)
+			}
+		} else {
+			errs = append(errs, fmt.Errorf("rowToLog: timestamp_column '%s' not found in result set", config.TimestampColumn))
+		}
+	}
+
+	if config.SeverityColumn != "" {
+		if value, found := row[config.SeverityColumn]; found {
+			setSeverity(logRecord, value)
+		} else {
+			errs = append(errs, fmt.Errorf("rowToLog: severity_column '%s' not found in result set", config.SeverityColumn))
+		}
+	}
 	return errors.Join(errs...)
 }
 
+// timestampLayouts are the accepted layouts of the timestamp column. Time columns are rendered in RFC 3339,
+// the other layouts cover timestamps stored as text.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTimestamp parses a timestamp in one of the timestampLayouts, or an integer number of nanoseconds
+// since the Unix epoch like the `ts_column` of metrics. Timestamps without a time zone are in UTC.
+func parseTimestamp(value string) (pcommon.Timestamp, error) {
+	if nanos, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return pcommon.Timestamp(nanos), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return pcommon.NewTimestampFromTime(t), nil
+		}
+	}
+	return 0, errors.New("unsupported timestamp format")
+}
+
+var severityNumbers = map[string]plog.SeverityNumber{
+	"trace":         plog.SeverityNumberTrace,
+	"debug":         plog.SeverityNumberDebug,
+	"info":          plog.SeverityNumberInfo,
+	"information":   plog.SeverityNumberInfo,
+	"informational": plog.SeverityNumberInfo,
+	"notice":        plog.SeverityNumberInfo2,
+	"warn":          plog.SeverityNumberWarn,
+	"warning":       plog.SeverityNumberWarn,
+	"err":           plog.SeverityNumberError,
+	"error":         plog.SeverityNumberError,
+	"crit":          plog.SeverityNumberFatal,
+	"critical":      plog.SeverityNumberFatal,
+	"alert":         plog.SeverityNumberFatal2,
+	"emerg":         plog.SeverityNumberFatal3,
+	"emergency":     plog.SeverityNumberFatal3,
+	"fatal":         plog.SeverityNumberFatal,
+}
+
+// setSeverity sets the severity of the log record from a severity number between 1 and 24,
+// or from a severity text. The severity number is left unspecified for unknown texts.
+func setSeverity(logRecord plog.LogRecord, value string) {
+	if number, err := strconv.ParseInt(value, 10, 32); err == nil {
+		if number >= int64(plog.SeverityNumberTrace) && number <= int64(plog.SeverityNumberFatal4) {
+			logRecord.SetSeverityNumber(plog.SeverityNumber(number))
+		}
+		return
+	}
+	logRecord.SetSeverityText(value)
+	logRecord.SetSeverityNumber(severityNumbers[strings.ToLower(strings.TrimSpace(value))])
+}
+
 func (queryReceiver *logsQueryReceiver) shutdown(_ context.Context) error {
 	if queryReceiver.db == nil {
 		return nil
@@ -5,12 +5,20 @@ package sqlqueryreceiver // import "github.com/open-telemetry/opentelemetry-coll
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
 )
 
@@ -32,8 +40,9 @@ func TestLogsQueryReceiver_Collect(t *testing.T) {
 			},
 		},
 	}
-	logs, err := queryReceiver.collect(context.Background())
+	logs, hasMore, err := queryReceiver.collect(context.Background())
 	assert.NoError(t, err)
+	assert.False(t, hasMore)
 	assert.NotNil(t, logs)
 	assert.Equal(t, 2, logs.LogRecordCount())
 
@@ -69,8 +78,181 @@ func TestLogsQueryReceiver_MissingColumnInResultSet(t *testing.T) {
 			},
 		},
 	}
-	_, err := queryReceiver.collect(context.Background())
+	_, _, err := queryReceiver.collect(context.Background())
 	assert.ErrorContains(t, err, "rowToLog: attribute_column 'expected_column' not found in result set")
 	assert.ErrorContains(t, err, "rowToLog: attribute_column 'expected_column_2' not found in result set")
 	assert.ErrorContains(t, err, "rowToLog: body_column 'expected_body_column' not found in result set")
 }
+
+func TestLogsQueryReceiver_TrackingColumns(t *testing.T) {
+	fakeClient := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"ts": "2024-05-01 10:00:00", "id": "7", "body": "a"}, {"ts": "2024-05-01 10:00:00", "id": "9", "body": "b"}},
+			{{"ts": "2024-05-01 10:00:01", "id": "3", "body": "c"}},
+		},
+	}
+	query := sqlquery.Query{
+		TrackingColumns:     []string{"ts", "id"},
+		TrackingStartValues: []string{"2024-01-01 00:00:00", "0"},
+		MaxRowsPerQuery:     2,
+		Logs:                []sqlquery.LogsCfg{{BodyColumn: "body"}},
+	}
+	storageClient := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("sqlquery"), "")
+	queryReceiver := newLogsQueryReceiver("query-0", query, nil, nil, zap.NewNop(), sqlquery.TelemetryConfig{}, storageClient)
+	queryReceiver.client = fakeClient
+
+	logs, hasMore, err := queryReceiver.collect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, 2, logs.LogRecordCount())
+
+	logs, hasMore, err = queryReceiver.collect(context.Background())
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, 1, logs.LogRecordCount())
+
+	assert.Equal(t, [][]any{
+		{"2024-01-01 00:00:00", "0", uint64(2)},
+		{"2024-05-01 10:00:00", "9", uint64(2)},
+	}, fakeClient.Args)
+
+	stored, err := storageClient.Get(context.Background(), "query-0.trackingValue")
+	require.NoError(t, err)
+	assert.JSONEq(t, `["2024-05-01 10:00:01", "3"]`, string(stored))
+
+	// the tracking values are restored from storage
+	restarted := newLogsQueryReceiver("query-0", query, nil, nil, zap.NewNop(), sqlquery.TelemetryConfig{}, storageClient)
+	assert.Equal(t, []string{"2024-05-01 10:00:01", "3"}, restarted.retrieveTrackingValues(context.Background()))
+
+	require.NoError(t, storageClient.Set(context.Background(), "query-0.trackingValue", []byte("42")))
+	assert.Equal(t, []string{"2024-01-01 00:00:00", "0"}, restarted.retrieveTrackingValues(context.Background()))
+}
+
+func TestLogsQueryReceiver_TrackingColumnStorage(t *testing.T) {
+	fakeClient := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"id": "42", "body": "a"}, {"id": "63", "body": "b"}},
+		},
+	}
+	query := sqlquery.Query{
+		TrackingColumn:     "id",
+		TrackingStartValue: "10",
+		Logs:               []sqlquery.LogsCfg{{BodyColumn: "body"}},
+	}
+	storageClient := storagetest.NewInMemoryClient(component.KindReceiver, component.MustNewID("sqlquery"), "")
+	queryReceiver := newLogsQueryReceiver("query-0", query, nil, nil, zap.NewNop(), sqlquery.TelemetryConfig{}, storageClient)
+	queryReceiver.client = fakeClient
+
+	_, hasMore, err := queryReceiver.collect(context.Background())
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, [][]any{{"10"}}, fakeClient.Args)
+
+	stored, err := storageClient.Get(context.Background(), "query-0.trackingValue")
+	require.NoError(t, err)
+	assert.Equal(t, "63", string(stored))
+	assert.Equal(t, []string{"63"}, queryReceiver.retrieveTrackingValues(context.Background()))
+}
+
+func TestLogsQueryReceiver_UnchangedTrackingValues(t *testing.T) {
+	fakeClient := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"id": "10", "body": "a"}},
+		},
+	}
+	queryReceiver := logsQueryReceiver{
+		client: fakeClient,
+		logger: zap.NewNop(),
+		query: sqlquery.Query{
+			TrackingColumn:  "id",
+			MaxRowsPerQuery: 1,
+			Logs:            []sqlquery.LogsCfg{{BodyColumn: "body"}},
+		},
+		trackingValues: []string{"10"},
+	}
+	_, hasMore, err := queryReceiver.collect(context.Background())
+	require.NoError(t, err)
+	assert.False(t, hasMore, "paging must stop when the tracking values don't change")
+}
+
+func TestLogsReceiver_CollectPages(t *testing.T) {
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	opener := func(string, string) (*sql.DB, error) {
+		return nil, nil
+	}
+	receiver, err := newLogsReceiver(cfg, receivertest.NewNopSettings(), opener, nil, sink)
+	require.NoError(t, err)
+
+	pagedClient := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"id": "1", "body": "a"}, {"id": "2", "body": "b"}},
+			{{"id": "3", "body": "c"}, {"id": "4", "body": "d"}},
+			{{"id": "5", "body": "e"}},
+		},
+	}
+	otherClient := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"body": "x"}},
+		},
+	}
+	logsCfg := []sqlquery.LogsCfg{{BodyColumn: "body"}}
+	receiver.queryReceivers = []*logsQueryReceiver{
+		{client: pagedClient, logger: zap.NewNop(), query: sqlquery.Query{TrackingColumn: "id", MaxRowsPerQuery: 2, Logs: logsCfg}, trackingValues: []string{"0"}},
+		{client: otherClient, logger: zap.NewNop(), query: sqlquery.Query{Logs: logsCfg}},
+	}
+	receiver.collect()
+
+	allLogs := sink.AllLogs()
+	require.Len(t, allLogs, 3)
+	assert.Equal(t, 3, allLogs[0].LogRecordCount())
+	assert.Equal(t, 2, allLogs[1].LogRecordCount())
+	assert.Equal(t, 1, allLogs[2].LogRecordCount())
+	assert.Equal(t, "e", allLogs[2].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, 3, pagedClient.RequestCounter)
+}
+
+func TestRowToLog_TimestampAndSeverity(t *testing.T) {
+	config := sqlquery.LogsCfg{BodyColumn: "body", TimestampColumn: "ts", SeverityColumn: "level"}
+	tests := []struct {
+		row            sqlquery.StringMap
+		timestamp      time.Time
+		severityText   string
+		severityNumber plog.SeverityNumber
+	}{
+		{
+			row:            sqlquery.StringMap{"body": "a", "ts": "2024-05-01T10:00:00.5Z", "level": "WARNING"},
+			timestamp:      time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC),
+			severityText:   "WARNING",
+			severityNumber: plog.SeverityNumberWarn,
+		},
+		{
+			row:            sqlquery.StringMap{"body": "a", "ts": "2024-05-01 12:00:00+02", "level": "custom"},
+			timestamp:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			severityText:   "custom",
+			severityNumber: plog.SeverityNumberUnspecified,
+		},
+		{
+			row:            sqlquery.StringMap{"body": "a", "ts": "2024-05-01 10:00:00", "level": "17"},
+			timestamp:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			severityNumber: plog.SeverityNumberError,
+		},
+		{
+			row:            sqlquery.StringMap{"body": "a", "ts": "1714557600000000000", "level": "info"},
+			timestamp:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			severityText:   "info",
+			severityNumber: plog.SeverityNumberInfo,
+		},
+	}
+	for _, tt := range tests {
+		logRecord := plog.NewLogRecord()
+		require.NoError(t, rowToLog(tt.row, config, logRecord))
+		assert.Equal(t, pcommon.NewTimestampFromTime(tt.timestamp), logRecord.Timestamp(), tt.row["ts"])
+		assert.Equal(t, tt.severityText, logRecord.SeverityText())
+		assert.Equal(t, tt.severityNumber, logRecord.SeverityNumber())
+	}
+
+	err := rowToLog(sqlquery.StringMap{"body": "a", "ts": "yesterday"}, config, plog.NewLogRecord())
+	assert.ErrorContains(t, err, `rowToLog: failed to parse timestamp_column 'ts', value was "yesterday"`)
+	assert.ErrorContains(t, err, "rowToLog: severity_column 'level' not found in result set")
+}